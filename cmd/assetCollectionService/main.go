@@ -7,6 +7,7 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/service/assetservice/source"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/sirupsen/logrus"
 )
@@ -118,6 +119,7 @@ func NewAssetScraper(exchange string, secret string) source.AssetSource {
 }
 
 func main() {
+	metrics.Serve()
 
 	relDB, err := models.NewRelDataStore()
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"benchmarkedIndex/db"
 	"benchmarkedIndex/sftp"
 	"encoding/csv"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	"github.com/diadata-org/diadata/pkg/utils"
 	log "github.com/sirupsen/logrus"
 	"os"
@@ -11,6 +12,7 @@ import (
 )
 
 func main() {
+	metrics.Serve()
 
 	files := strings.Split(utils.Getenv("SFTP_FILELIST", ""), ";")
 	for _, file := range files {
@@ -0,0 +1,8 @@
+module github.com/diadata-org/diadata/blockchain/diaCVIOracleService
+
+go 1.14
+
+require (
+	github.com/diadata-org/diadata v1.4.1-rc-21
+	github.com/ethereum/go-ethereum v1.10.10
+)
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	diaOracleService "github.com/diadata-org/diadata/pkg/dia/scraper/blockchain-scrapers/blockchains/ethereum/diaOracleService"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func main() {
+	metrics.Serve()
+	key := utils.Getenv("PRIVATE_KEY", "")
+	key_password := utils.Getenv("PRIVATE_KEY_PASSWORD", "")
+	deployedContract := utils.Getenv("DEPLOYED_CONTRACT", "")
+	blockchainNode := utils.Getenv("BLOCKCHAIN_NODE", "")
+	frequencySeconds, err := strconv.Atoi(utils.Getenv("FREQUENCY_SECONDS", "3600"))
+	if err != nil {
+		log.Fatalf("Failed to parse frequencySeconds: %v", err)
+	}
+	chainId, err := strconv.ParseInt(utils.Getenv("CHAIN_ID", "1"), 10, 64)
+	if err != nil {
+		log.Fatalf("Failed to parse chainId: %v", err)
+	}
+	// Comma separated list of blockchain:address of the source tokens of bridged assets.
+	assets := strings.Split(utils.Getenv("ASSETS", "Ethereum:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), ",")
+
+	/*
+	 * Setup connection to contract, deploy if necessary
+	 */
+
+	conn, err := ethclient.Dial(blockchainNode)
+	if err != nil {
+		log.Fatalf("Failed to connect to the Ethereum client: %v", err)
+	}
+
+	auth, err := bind.NewTransactorWithChainID(strings.NewReader(key), key_password, big.NewInt(chainId))
+	if err != nil {
+		log.Fatalf("Failed to create authorized transactor: %v", err)
+	}
+
+	var contract *diaOracleService.DIAOracle
+	err = deployOrBindContract(deployedContract, conn, auth, &contract)
+	if err != nil {
+		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
+	}
+
+	/*
+	 * Update Oracle periodically with the collateral ratio of each asset
+	 */
+	ticker := time.NewTicker(time.Duration(frequencySeconds) * time.Second)
+	for ; true; <-ticker.C {
+		for _, asset := range assets {
+			parts := strings.Split(asset, ":")
+			if len(parts) != 2 {
+				log.Printf("Invalid asset %s, expected blockchain:address", asset)
+				continue
+			}
+			por, err := getProofOfReserveFromDia(parts[0], parts[1])
+			if err != nil {
+				log.Printf("Failed to retrieve proof of reserve of %s from DIA: %v", asset, err)
+				continue
+			}
+			// The collateral ratio is written with 8 decimals like prices.
+			err = updateOracle(conn, contract, auth, "PoR/"+por.Asset.Symbol, int64(por.CollateralRatio*100000000), por.Time.Unix())
+			if err != nil {
+				log.Printf("Failed to update Oracle: %v", err)
+			}
+		}
+	}
+}
+
+func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth *bind.TransactOpts, contract **diaOracleService.DIAOracle) error {
+	var err error
+	if deployedContract != "" {
+		*contract, err = diaOracleService.NewDIAOracle(common.HexToAddress(deployedContract), conn)
+		if err != nil {
+			return err
+		}
+	} else {
+		// deploy contract
+		var addr common.Address
+		var tx *types.Transaction
+		addr, tx, *contract, err = diaOracleService.DeployDIAOracle(auth, conn)
+		if err != nil {
+			log.Fatalf("could not deploy contract: %v", err)
+			return err
+		}
+		log.Printf("Contract pending deploy: 0x%x\n", addr)
+		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		time.Sleep(180000 * time.Millisecond)
+	}
+	return nil
+}
+
+func updateOracle(
+	client *ethclient.Client,
+	contract *diaOracleService.DIAOracle,
+	auth *bind.TransactOpts,
+	key string,
+	value int64,
+	timestamp int64) error {
+
+	gasPrice, err := client.SuggestGasPrice(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// Get 110% of the gas price
+	fGas := new(big.Float).SetInt(gasPrice)
+	fGas.Mul(fGas, big.NewFloat(1.1))
+	gasPrice, _ = fGas.Int(nil)
+	// Write values to smart contract
+	tx, err := contract.SetValue(&bind.TransactOpts{
+		From:     auth.From,
+		Signer:   auth.Signer,
+		GasLimit: 1000725,
+		GasPrice: gasPrice,
+	}, key, big.NewInt(value), big.NewInt(timestamp))
+	metrics.ObserveOracleUpdate(key, err)
+	if err != nil {
+		return err
+	}
+	log.Printf("key: %s\n", key)
+	log.Printf("Tx To: %s\n", tx.To().String())
+	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	return nil
+}
+
+// getProofOfReserveFromDia returns the latest proof of reserve of the asset with @address on @blockchain.
+func getProofOfReserveFromDia(blockchain string, address string) (*dia.ProofOfReserve, error) {
+	response, err := http.Get("https://api.diadata.org/v1/proofOfReserve/" + blockchain + "/" + address)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+	if 200 != response.StatusCode {
+		return nil, fmt.Errorf("Error on dia api with return code %d", response.StatusCode)
+	}
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var pors []dia.ProofOfReserve
+	err = json.Unmarshal(contents, &pors)
+	if err != nil {
+		return nil, err
+	}
+	if len(pors) == 0 {
+		return nil, fmt.Errorf("no proof of reserve of %s on %s", address, blockchain)
+	}
+	// Values are returned in descending order of time.
+	return &pors[0], nil
+}
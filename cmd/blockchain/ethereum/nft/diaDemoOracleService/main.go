@@ -12,7 +12,8 @@ import (
 	"strings"
 	"time"
 
-	diaNFTOracleService "github.com/diadata-org/diadata/pkg/dia/scraper/blockchain-scrapers/blockchains/ethereum/diaNFTOracleService"
+	diaNFTFloorOracleService "github.com/diadata-org/diadata/pkg/dia/scraper/blockchain-scrapers/blockchains/ethereum/diaNFTFloorOracleService"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -26,12 +27,20 @@ type FloorReturn struct {
 	Address    string
 	Floor      float64
 	FloorMA    float64
+	NumSales   int
+	LastSale   time.Time
+	Dispersion float64
+	Stale      bool
 }
 
 type Floor struct {
-	Value     float64   `json:"Floor_Price"`
-	Timestamp time.Time `json:"Time"`
-	Source    string    `json:"Source"`
+	Value      float64   `json:"Floor_Price"`
+	NumSales   int       `json:"Number_Sales"`
+	LastSale   time.Time `json:"Last_Sale"`
+	Dispersion float64   `json:"Dispersion"`
+	Stale      bool      `json:"Stale"`
+	Timestamp  time.Time `json:"Time"`
+	Source     string    `json:"Source"`
 }
 
 const (
@@ -45,6 +54,7 @@ type FloorMA struct {
 }
 
 func main() {
+	metrics.Serve()
 	key := utils.Getenv("PRIVATE_KEY", "")
 	key_password := utils.Getenv("PRIVATE_KEY_PASSWORD", "")
 	deployedContract := utils.Getenv("DEPLOYED_CONTRACT", "")
@@ -86,7 +96,7 @@ func main() {
 	}
 
 	//
-	oldFloors := make(map[string]float64)
+	oldFloors := make(map[string]FloorReturn)
 
 	/*
 	 * Setup connection to contract, deploy if necessary
@@ -102,7 +112,7 @@ func main() {
 		log.Fatalf("Failed to create authorized transactor: %v", err)
 	}
 
-	var contract *diaNFTOracleService.DIANFTOracle
+	var contract *diaNFTFloorOracleService.DIANFTFloorOracle
 	err = deployOrBindContract(deployedContract, conn, auth, &contract)
 	if err != nil {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
@@ -127,7 +137,7 @@ func main() {
 			for i, address := range addresses {
 				blockchain := blockchains[i]
 				oldFloor := oldFloors[address]
-				log.Println("old price", oldFloor)
+				log.Println("old price", oldFloor.Floor)
 				newFloor, err := periodicOracleUpdateHelper(oldFloor, deviationPermille, timeBasedUpdate, auth, contract, conn, blockchain, address)
 				oldFloors[address] = newFloor
 				if err != nil {
@@ -140,10 +150,11 @@ func main() {
 	select {}
 }
 
-// periodicOracleUpdateHelper updates a collection on either of the two conditions:
+// periodicOracleUpdateHelper updates a collection on either of the three conditions:
 // 1. The difference of the (new) floor price and @oldFloor exceeds @deviationPermille.
-// 2. @update is true.
-func periodicOracleUpdateHelper(oldFloor float64, deviationPermille int, update bool, auth *bind.TransactOpts, contract *diaNFTOracleService.DIANFTOracle, conn *ethclient.Client, blockchain string, address string) (float64, error) {
+// 2. The floor price became stale or is no longer stale.
+// 3. @update is true.
+func periodicOracleUpdateHelper(oldFloor FloorReturn, deviationPermille int, update bool, auth *bind.TransactOpts, contract *diaNFTFloorOracleService.DIANFTFloorOracle, conn *ethclient.Client, blockchain string, address string) (FloorReturn, error) {
 	var data FloorReturn
 	data.Blockchain = blockchain
 	data.Address = address
@@ -155,6 +166,10 @@ func periodicOracleUpdateHelper(oldFloor float64, deviationPermille int, update
 		return oldFloor, err
 	}
 	data.Floor = floor.Value
+	data.NumSales = floor.NumSales
+	data.LastSale = floor.LastSale
+	data.Dispersion = floor.Dispersion
+	data.Stale = floor.Stale
 
 	// Get MA of floor price
 	floorMA, err := getFloorMA(blockchain, address)
@@ -167,30 +182,35 @@ func periodicOracleUpdateHelper(oldFloor float64, deviationPermille int, update
 	// Check for deviation in floor price.
 	newFloor := floor.Value
 
-	if math.Abs(newFloor-oldFloor) > oldFloor*float64(deviationPermille)/1000 || update {
+	if math.Abs(newFloor-oldFloor.Floor) > oldFloor.Floor*float64(deviationPermille)/1000 || data.Stale != oldFloor.Stale || update {
 		log.Println("Entering deviation based update zone")
 		err = updateNFTData(data, auth, contract, conn)
 		if err != nil {
 			log.Fatalf("Failed to update DIA Oracle: %v", err)
 			return oldFloor, err
 		}
-		return newFloor, nil
+		return data, nil
 	}
 
-	return newFloor, nil
+	return data, nil
 }
 
-func updateNFTData(data FloorReturn, auth *bind.TransactOpts, contract *diaNFTOracleService.DIANFTOracle, conn *ethclient.Client) error {
-	timestamp := uint64(time.Now().Unix())
-
+func updateNFTData(data FloorReturn, auth *bind.TransactOpts, contract *diaNFTFloorOracleService.DIANFTFloorOracle, conn *ethclient.Client) error {
 	// Update floor
 	symbol := data.Blockchain + "-" + data.Address
-	var values []uint64
-	values = append(values, uint64(data.Floor*100000000))
-	values = append(values, uint64(data.FloorMA*100000000))
-	values = append(values, []uint64{0, 0, 0}...)
+	values := diaNFTFloorOracleService.DIANFTFloorOracleFloorData{
+		Floor:      uint64(data.Floor * 100000000),
+		FloorMA:    uint64(data.FloorMA * 100000000),
+		Dispersion: uint64(data.Dispersion * 100000000),
+		NumSales:   uint32(data.NumSales),
+		Stale:      data.Stale,
+		Timestamp:  uint64(time.Now().Unix()),
+	}
+	if !data.LastSale.IsZero() {
+		values.LastSaleTimestamp = uint64(data.LastSale.Unix())
+	}
 
-	err := updateOracle(conn, contract, auth, symbol, values, timestamp)
+	err := updateOracle(conn, contract, auth, symbol, values)
 	if err != nil {
 		log.Fatalf("Failed to update Oracle: %v", err)
 		return err
@@ -201,11 +221,10 @@ func updateNFTData(data FloorReturn, auth *bind.TransactOpts, contract *diaNFTOr
 
 func updateOracle(
 	client *ethclient.Client,
-	contract *diaNFTOracleService.DIANFTOracle,
+	contract *diaNFTFloorOracleService.DIANFTFloorOracle,
 	auth *bind.TransactOpts,
 	key string,
-	values []uint64,
-	timestamp uint64) error {
+	values diaNFTFloorOracleService.DIANFTFloorOracleFloorData) error {
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
@@ -224,7 +243,8 @@ func updateOracle(
 		Signer:   auth.Signer,
 		GasLimit: 1000725,
 		GasPrice: gasPrice,
-	}, key, values[0], values[1], values[2], values[3], values[4], timestamp)
+	}, key, values)
+	metrics.ObserveOracleUpdate(key, err)
 	if err != nil {
 		return err
 	}
@@ -236,7 +256,7 @@ func updateOracle(
 }
 
 func getFloor(blockchain, address string) (Floor, error) {
-	response, err := http.Get(diaAPIBaseURL + "/NFTFloor/" + blockchain + "/" + address)
+	response, err := http.Get(diaAPIBaseURL + "/NFTFloorStats/" + blockchain + "/" + address)
 	if err != nil {
 		return Floor{}, err
 	}
@@ -283,10 +303,10 @@ func getFloorMA(blockchain, address string) (FloorMA, error) {
 	return resp, err
 }
 
-func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth *bind.TransactOpts, contract **diaNFTOracleService.DIANFTOracle) error {
+func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth *bind.TransactOpts, contract **diaNFTFloorOracleService.DIANFTFloorOracle) error {
 	var err error
 	if deployedContract != "" {
-		*contract, err = diaNFTOracleService.NewDIANFTOracle(common.HexToAddress(deployedContract), conn)
+		*contract, err = diaNFTFloorOracleService.NewDIANFTFloorOracle(common.HexToAddress(deployedContract), conn)
 		if err != nil {
 			return err
 		}
@@ -294,7 +314,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 		// deploy contract
 		var addr common.Address
 		var tx *types.Transaction
-		addr, tx, *contract, err = diaNFTOracleService.DeployDIANFTOracle(auth, conn)
+		addr, tx, *contract, err = diaNFTFloorOracleService.DeployDIANFTFloorOracle(auth, conn)
 		if err != nil {
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
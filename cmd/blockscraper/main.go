@@ -9,6 +9,7 @@ import (
 	blockscrapers "github.com/diadata-org/diadata/pkg/dia/scraper/blockchain-scrapers/block-scrapers"
 
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/jackc/pgconn"
 
@@ -16,6 +17,7 @@ import (
 )
 
 func main() {
+	metrics.Serve()
 
 	wg := sync.WaitGroup{}
 
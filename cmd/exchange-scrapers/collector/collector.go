@@ -3,15 +3,19 @@ package main
 import (
 	"flag"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/scraperHealth"
 	scrapers "github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers"
 	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/diadata-org/diadata/pkg/utils/probes"
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/segmentio/kafka-go"
 	"github.com/sirupsen/logrus"
@@ -45,6 +49,9 @@ var (
 	mode = flag.String("mode", "current", "either storeTrades, current, historical or estimation.")
 
 	pairsfile = flag.Bool("pairsfile", false, "read pairs from json file in config folder.")
+
+	// Number of resubscriptions of a silent pair before it is considered failed.
+	maxResubscriptions, _ = strconv.Atoi(utils.Getenv("WATCHDOG_MAX_RESUBSCRIPTIONS", "3"))
 )
 
 func init() {
@@ -127,17 +134,25 @@ func main() {
 
 	wg := sync.WaitGroup{}
 
+	watchdogDelay := time.Duration(scrapers.Exchanges[*exchange].WatchdogDelay) * time.Second
+	monitor := scraperHealth.NewPairMonitor(*exchange, watchdogDelay, maxResubscriptions, time.Now())
+	pairScrapers := make(map[string]scrapers.PairScraper)
+
 	if scrapers.Exchanges[*exchange].Centralized {
 
 		// Scrape pairs for CEX scrapers.
 		for _, configPair := range pairsExchange {
 			log.Println("Adding pair:", configPair.Symbol, configPair.ForeignName, "on exchange", *exchange)
-			_, err := es.ScrapePair(dia.ExchangePair{
+			pair := dia.ExchangePair{
 				Symbol:      configPair.Symbol,
-				ForeignName: configPair.ForeignName})
+				ForeignName: configPair.ForeignName,
+			}
+			ps, err := es.ScrapePair(pair)
 			if err != nil {
 				log.Println(err)
 			} else {
+				pairScrapers[pair.ForeignName] = ps
+				monitor.Subscribed(pair, time.Now())
 				wg.Add(1)
 			}
 			defer wg.Wait()
@@ -150,28 +165,30 @@ func main() {
 		defer wg.Wait()
 
 	}
-	go handleTrades(es.Channel(), &wg, w, wTest, ds, *exchange, *mode)
+	go handleTrades(es, pairScrapers, monitor, &wg, w, wTest, ds, *exchange, *mode)
+
+	probes.StartWithStatus(
+		func() bool { return true },
+		monitor.Ready,
+		func() interface{} { return monitor.Health() },
+	)
 }
 
-func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, wTest *kafka.Writer, ds *models.DB, exchange string, mode string) {
-	lastTradeTime := time.Now()
-	watchdogDelay := scrapers.Exchanges[exchange].WatchdogDelay
-	t := time.NewTicker(time.Duration(watchdogDelay) * time.Second)
+func handleTrades(es scrapers.APIScraper, pairScrapers map[string]scrapers.PairScraper, monitor *scraperHealth.PairMonitor, wg *sync.WaitGroup, w *kafka.Writer, wTest *kafka.Writer, ds *models.DB, exchange string, mode string) {
+	c := es.Channel()
+	t := time.NewTicker(monitor.WatchdogDelay)
 	for {
 		select {
 		case <-t.C:
-			duration := time.Since(lastTradeTime)
-			if duration > time.Duration(watchdogDelay)*time.Second {
-				log.Error(duration)
-				panic("frozen? ")
-			}
+			checkPairs(pairScrapers, monitor, exchange)
 		case t, ok := <-c:
 			if !ok {
 				wg.Done()
 				log.Error("handleTrades")
 				return
 			}
-			lastTradeTime = time.Now()
+			monitor.Trade(t.Pair, time.Now())
+			metrics.TradesReceived.WithLabelValues(t.Source).Inc()
 			// Trades are sent to the tradesblockservice through a kafka channel - either
 			// through trades topic or historical trades topic.
 			if mode == "current" || mode == "historical" || mode == "estimation" {
@@ -212,6 +229,37 @@ func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, wTest
 	}
 }
 
+// checkPairs resubscribes stale pairs and restarts the collector if the whole exchange stays silent.
+// Only pairs of scrapers which unsubscribe on Close are resubscribed, as subscribing to a pair twice
+// duplicates its trades. Stale pairs of other scrapers are left to the restart of the collector.
+func checkPairs(pairScrapers map[string]scrapers.PairScraper, monitor *scraperHealth.PairMonitor, exchange string) {
+	resubscribe, restart := monitor.Check(time.Now())
+	if restart {
+		log.Errorf("no trades on %s since %v", exchange, monitor.Health().LastTrade)
+		panic("frozen? ")
+	}
+	for _, pair := range resubscribe {
+		resubscriber, ok := pairScrapers[pair.ForeignName].(scrapers.Resubscriber)
+		if !ok {
+			log.Warnf("no trades for %s on %s within watchdog delay.", pair.ForeignName, exchange)
+			continue
+		}
+		log.Warnf("no trades for %s on %s within watchdog delay. Resubscribe...", pair.ForeignName, exchange)
+		metrics.ScraperResubscriptions.WithLabelValues(exchange).Inc()
+		ps, err := resubscriber.Resubscribe()
+		if err != nil {
+			log.Errorf("resubscribe %s: %v", pair.ForeignName, err)
+			continue
+		}
+		pairScrapers[pair.ForeignName] = ps
+	}
+
+	health := monitor.Health()
+	metrics.ScraperPairs.WithLabelValues(exchange, scraperHealth.PairLive).Set(float64(health.Live))
+	metrics.ScraperPairs.WithLabelValues(exchange, scraperHealth.PairStale).Set(float64(health.Stale))
+	metrics.ScraperPairs.WithLabelValues(exchange, scraperHealth.PairFailed).Set(float64(health.Failed))
+}
+
 func writeTradeToKafka(w *kafka.Writer, t *dia.Trade) error {
 	// Write trade to Kafka.
 	err := kafkaHelper.WriteMessage(w, t)
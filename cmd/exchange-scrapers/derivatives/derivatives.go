@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	writers "github.com/diadata-org/diadata/internal/pkg/scraper-writers"
+	scrapers "github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+// Scrapes futures, perpetuals and options of an exchange and stores trades, funding rates,
+// open interest, mark prices and option order books in influx. Bitflyer, FTX and Huobi only
+// provide trades. Coinflex is not supported, as its match notices are scaled per asset.
+func main() {
+	exchange := flag.String("exchange", "Deribit", "derivatives exchange: Bitflyer, Bitmex, Deribit, FTX or Huobi")
+	markets := flag.String("markets", "BTC-PERPETUAL,ETH-PERPETUAL", "comma separated list of instruments")
+	kind := flag.String("kind", "future", "kind of Deribit instruments: future or option")
+	underlyings := flag.String("underlyings", "", "comma separated list of Deribit currencies whose instruments of the given kind are all scraped instead of markets")
+	flag.Parse()
+
+	metrics.Serve()
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore: ", err)
+	}
+	writer := writers.NewInfluxWriter(ds)
+	instruments := strings.Split(*markets, ",")
+	if *underlyings != "" {
+		instruments = deribitInstruments(strings.Split(*underlyings, ","), *kind)
+	}
+
+	var scraper scrapers.FuturesScraper
+	switch *exchange {
+	case "Bitflyer":
+		scraper = scrapers.NewBitflyerFuturesScraper(instruments, writer)
+	case "Bitmex":
+		scraper = scrapers.NewBitmexFuturesScraper(instruments, writer)
+	case "Deribit":
+		if *kind == "option" {
+			scraper = scrapers.NewDeribitOptionsScraper(instruments, "", "", writer)
+		} else {
+			scraper = scrapers.NewDeribitFuturesScraper(instruments, "", "", writer)
+		}
+	case "FTX":
+		scraper = scrapers.NewFTXFuturesScraper(instruments, writer)
+	case "Huobi":
+		scraper = scrapers.NewHuobiFuturesScraper(instruments, writer)
+	default:
+		log.Fatalf("no derivatives scraper for exchange %s", *exchange)
+	}
+	log.Infof("scrape %s derivatives %v", *exchange, instruments)
+	scraper.ScrapeMarkets()
+}
+
+// deribitInstruments returns all Deribit instruments of @kind on @currencies. Instruments listed
+// later are scraped after a restart.
+func deribitInstruments(currencies []string, kind string) (instruments []string) {
+	marketKind := scrapers.DeribitFuture
+	if kind == "option" {
+		marketKind = scrapers.DeribitOption
+	}
+	for _, currency := range currencies {
+		markets, err := scrapers.DeribitMarkets(currency, marketKind)
+		if err != nil {
+			log.Fatalf("get deribit instruments of %s: %v", currency, err)
+		}
+		instruments = append(instruments, markets...)
+	}
+	return
+}
@@ -0,0 +1,8 @@
+module github.com/diadata-org/diadata/exchange-scrapers/derivatives
+
+go 1.14
+
+require (
+	github.com/diadata-org/diadata v1.4.23
+	github.com/sirupsen/logrus v1.8.1
+)
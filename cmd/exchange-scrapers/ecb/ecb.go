@@ -5,6 +5,7 @@ import (
 
 	scrapers "github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/sirupsen/logrus"
 )
@@ -93,6 +94,7 @@ func handleTrades(c chan *dia.Trade, ds models.Datastore, rdb models.RelDB) {
 
 // main manages all PairScrapers and handles incoming trade information
 func main() {
+	metrics.Serve()
 	wg := sync.WaitGroup{}
 	ds, err := models.NewDataStore()
 	if err != nil {
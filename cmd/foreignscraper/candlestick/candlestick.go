@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/diadata-org/diadata/pkg/utils"
 	ws "github.com/gorilla/websocket"
@@ -45,6 +46,7 @@ func getCandleStickMessageIdent(message candlestickMessage) string {
 }
 
 func main() {
+	metrics.Serve()
 
 	wg := sync.WaitGroup{}
 	ds, err := models.NewDataStore()
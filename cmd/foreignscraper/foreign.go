@@ -7,12 +7,14 @@ import (
 	"github.com/diadata-org/diadata/pkg/dia"
 	scrapers "github.com/diadata-org/diadata/pkg/dia/scraper/foreign-scrapers"
 
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	metrics.Serve()
 
 	wg := sync.WaitGroup{}
 
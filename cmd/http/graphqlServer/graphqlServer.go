@@ -10,6 +10,7 @@ import (
 	"github.com/diadata-org/diadata/pkg/utils"
 
 	"github.com/diadata-org/diadata/pkg/graphql/resolver"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -18,6 +19,7 @@ import (
 
 // nolint: gas
 func main() {
+	metrics.Serve()
 
 	ds, err := getSchema("./schema/quotation.graphql")
 	if err != nil {
@@ -9,8 +9,11 @@ import (
 	//jwt "github.com/blockstatecom/gin-jwt"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/http/restServer/apiKeys"
 	"github.com/diadata-org/diadata/pkg/http/restServer/diaApi"
+	"github.com/diadata-org/diadata/pkg/http/restServer/diaApiV2"
 	"github.com/diadata-org/diadata/pkg/http/restServer/kafkaApi"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/gin-contrib/cache"
 	"github.com/gin-contrib/cache/persistence"
@@ -84,6 +87,7 @@ func helloHandler(c *gin.Context) {
 }
 
 func main() {
+	metrics.Serve()
 
 	r := gin.New()
 	r.Use(gin.Logger())
@@ -107,6 +111,8 @@ func main() {
 		},
 		IdentityHandler: func(c *gin.Context) interface{} {
 			claims := jwt.ExtractClaims(c)
+			// The subject is the owner of resources such as exports created with the token.
+			c.Set(diaApi.ContextJWTSubject, claims["id"])
 			return &User{
 				UserName: claims["id"].(string),
 			}
@@ -196,18 +202,49 @@ func main() {
 		RelDB:     *relStore,
 	}
 
+	// Requests with an api key in the X-API-KEY header are authenticated by the key,
+	// all other requests on write endpoints by jwt.
+	apiKeyAuth, err := apiKeys.NewAuth(relStore)
+	if err != nil {
+		log.Fatal("api key authentication: ", err)
+	}
+
 	diaAuth := r.Group("/v1")
-	diaAuth.Use(authMiddleware.MiddlewareFunc())
 	{
-		diaAuth.POST("/supply", diaApiEnv.PostSupply)
-		diaAuth.POST("/quotation", diaApiEnv.SetQuotation)
+		diaAuth.POST("/supply", apiKeyAuth.Require(apiKeys.ScopeWriteSupply, authMiddleware.MiddlewareFunc()), diaApiEnv.PostSupply)
+		diaAuth.POST("/quotation", apiKeyAuth.Require(apiKeys.ScopeWriteQuotation, authMiddleware.MiddlewareFunc()), diaApiEnv.SetQuotation)
+	}
+
+	apiKeyGroup := r.Group("/v1/apikeys")
+	apiKeyGroup.Use(apiKeyAuth.Require(apiKeys.ScopeAdmin, authMiddleware.MiddlewareFunc()))
+	{
+		apiKeyGroup.POST("", apiKeyAuth.CreateAPIKey)
+		apiKeyGroup.POST("/:prefix/rotate", apiKeyAuth.RotateAPIKey)
+		apiKeyGroup.DELETE("/:prefix", apiKeyAuth.RevokeAPIKey)
+		apiKeyGroup.GET("/:prefix/usage", apiKeyAuth.GetAPIKeyUsage)
+	}
+
+	exports := r.Group("/v1/exports")
+	exports.Use(apiKeyAuth.Require(apiKeys.ScopeExport, authMiddleware.MiddlewareFunc()))
+	{
+		exports.POST("", diaApiEnv.PostExport)
+		exports.GET("/:id", diaApiEnv.GetExport)
+		exports.GET("/:id/files/:name", diaApiEnv.GetExportFile)
+	}
+
+	readAuth := apiKeyAuth.Optional(apiKeys.ScopeRead)
+	if utils.Getenv("REQUIRE_API_KEY", "false") == "true" {
+		readAuth = apiKeyAuth.Require(apiKeys.ScopeRead, nil)
 	}
 
 	diaGroup := r.Group("/v1")
+	diaGroup.Use(readAuth)
 	{
 		// Trades and prices endpoints.
 		diaGroup.GET("/quotation/:symbol", cache.CachePageAtomic(memoryStore, cachingTime20Secs, diaApiEnv.GetQuotation))
 		diaGroup.GET("/assetQuotation/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTime20Secs, diaApiEnv.GetAssetQuotation))
+		diaGroup.POST("/assetQuotations", diaApiEnv.PostAssetQuotations)
+		diaGroup.GET("/historicalQuotation/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetHistoricalQuotation))
 		diaGroup.GET("/lastTrades/:symbol", diaApiEnv.GetLastTrades)
 		diaGroup.GET("/lastTradesAsset/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetLastTradesAsset))
 
@@ -249,28 +286,42 @@ func main() {
 		diaGroup.GET("/tokenexchanges/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetAssetExchanges))
 
 		diaGroup.GET("/exchanges", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetExchanges))
+		diaGroup.GET("/exchangeHealth/:exchange", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetExchangeHealth))
 		diaGroup.GET("/NFT/exchanges", cache.CachePageAtomic(memoryStore, cachingTime1Sec, diaApiEnv.GetNFTExchanges))
 
 		diaGroup.GET("/blockchains", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetAllBlockchains))
 
+		// Endpoints for derivatives
+		diaGroup.GET("/derivatives/:exchange/:instrument/trades", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetFuturesTrades))
+		diaGroup.GET("/derivatives/:exchange/:instrument/fundingRates", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetFundingRates))
+		diaGroup.GET("/derivatives/:exchange/:instrument/openInterest", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetOpenInterest))
+		diaGroup.GET("/derivatives/:exchange/:instrument/prices", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetDerivativePrices))
+		diaGroup.GET("/options/:exchange/:underlying/orderbooks", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetOptionOrderbooks))
+		diaGroup.GET("/cviIndex", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCVIIndex))
+
 		// Endpoints for interestrates
-		// diaGroup.GET("/interestrates", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetRates))
-		// diaGroup.GET("/interestrate/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetInterestRate))
-		// diaGroup.GET("/interestrate/:symbol/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetInterestRate))
-		// diaGroup.GET("/compoundedRate/:symbol/:dpy", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedRate))
-		// diaGroup.GET("/compoundedRate/:symbol/:dpy/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedRate))
-		// diaGroup.GET("/compoundedAvg/:symbol/:days/:dpy", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvg))
-		// diaGroup.GET("/compoundedAvg/:symbol/:days/:dpy/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvg))
-		// diaGroup.GET("/compoundedAvgDIA/:symbol/:days/:dpy", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvgDIA))
-		// diaGroup.GET("/compoundedAvgDIA/:symbol/:days/:dpy/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvgDIA))
+		diaGroup.GET("/interestrates", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetRates))
+		diaGroup.GET("/interestrate/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetInterestRate))
+		diaGroup.GET("/interestrate/:symbol/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetInterestRate))
+		diaGroup.GET("/compoundedRate/:symbol/:dpy", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedRate))
+		diaGroup.GET("/compoundedRate/:symbol/:dpy/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedRate))
+		diaGroup.GET("/compoundedAvg/:symbol/:days/:dpy", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvg))
+		diaGroup.GET("/compoundedAvg/:symbol/:days/:dpy/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvg))
+		diaGroup.GET("/compoundedAvgDIA/:symbol/:days/:dpy", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvgDIA))
+		diaGroup.GET("/compoundedAvgDIA/:symbol/:days/:dpy/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvgDIA))
+		diaGroup.GET("/rateHolidays/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetRateHolidays))
 
 		// Endpoints for fiat currencies
 		diaGroup.GET("/fiatQuotations", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetFiatQuotations))
+		diaGroup.GET("/fiatCrossRate/:base/:quote", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetFiatCrossRate))
+		diaGroup.GET("/fiatCrossRate/:base/:quote/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetFiatCrossRate))
 
-		// // Endpoints for stocks
-		// dia.GET("/stockSymbols", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetStockSymbols))
-		// dia.GET("/stockQuotation/:source/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetStockQuotation))
-		// dia.GET("/stockQuotation/:source/:symbol/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetStockQuotation))
+		// Endpoints for stocks and currency pairs
+		diaGroup.GET("/stockSymbols", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetStockSymbols))
+		diaGroup.GET("/stockQuotation/:source/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetStockQuotation))
+		diaGroup.GET("/stockQuotation/:source/:symbol/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetStockQuotation))
+		diaGroup.GET("/stockPrice/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetStockPrice))
+		diaGroup.GET("/stockPrice/:symbol/:time", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetStockPrice))
 
 		// Endpoints for foreign sources
 		diaGroup.GET("/foreignQuotation/:source/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetForeignQuotation))
@@ -282,6 +333,9 @@ func main() {
 
 		// Index
 		diaGroup.GET("/benchmarkedIndexValue/:symbol", diaApiEnv.GetBenchmarkedIndexValue)
+		diaGroup.GET("/index/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetIndex))
+		diaGroup.GET("/indexRebalances/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetIndexRebalances))
+		diaGroup.GET("/indexProposal/:symbol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetIndexProposal))
 
 		// External supply reports
 		diaGroup.GET("/diaTotalSupply", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetDiaTotalSupply))
@@ -295,6 +349,8 @@ func main() {
 		diaGroup.GET("/NFTTrades/:blockchain/:address/:id", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetNFTTrades))
 		diaGroup.GET("/NFTTradesCollection/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetNFTTradesCollection))
 		diaGroup.GET("/NFTFloor/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetNFTFloor))
+		diaGroup.GET("/NFTListings/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetNFTListings))
+		diaGroup.GET("/NFTFloorStats/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetNFTFloorStats))
 		diaGroup.GET("/NFTFloorMA/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetNFTFloorMA))
 		diaGroup.GET("/NFTDownday/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetNFTDownday))
 		diaGroup.GET("/NFTVolatility/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeLong, diaApiEnv.GetNFTFloorVola))
@@ -306,9 +362,17 @@ func main() {
 		// Endpoints for Synthassets
 
 		diaGroup.GET("/synthasset/:blockchain/:protocol", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetSyntheticAsset))
+		diaGroup.GET("/synthAssetValuation/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetSynthAssetValuation))
+		diaGroup.GET("/proofOfReserve/:blockchain/:address", cache.CachePageAtomic(memoryStore, cachingTimeShort, diaApiEnv.GetProofOfReserve))
 
 	}
 
+	diaV2Group := r.Group("/v2")
+	diaV2Group.Use(readAuth)
+	diaApiV2.NewEnv(store, relStore).RegisterRoutes(diaV2Group, func(handler gin.HandlerFunc) gin.HandlerFunc {
+		return cache.CachePageAtomic(memoryStore, cachingTime20Secs, handler)
+	})
+
 	r.Use(static.Serve("/v1/chart", static.LocalFile("/charts", true)))
 
 	AddEndpoints(r)
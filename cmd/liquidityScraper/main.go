@@ -4,6 +4,7 @@ import (
 	"flag"
 
 	liquidityscraper "github.com/diadata-org/diadata/pkg/dia/scraper/liquidity-scrapers"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 
 	"github.com/sirupsen/logrus"
@@ -21,6 +22,7 @@ func init() {
 }
 
 func main() {
+	metrics.Serve()
 
 	log.Println("Liquidity Scraper: Start scraping liquidity")
 
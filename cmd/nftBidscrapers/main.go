@@ -11,10 +11,12 @@ import (
 	"github.com/jackc/pgconn"
 
 	nftbidscrapers "github.com/diadata-org/diadata/pkg/dia/nft/nftBid-scrapers"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	metrics.Serve()
 
 	wg := sync.WaitGroup{}
 
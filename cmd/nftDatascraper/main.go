@@ -12,10 +12,12 @@ import (
 	models "github.com/diadata-org/diadata/pkg/model"
 
 	nftdatascrapers "github.com/diadata-org/diadata/pkg/dia/nft/nftData-scrapers"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	metrics.Serve()
 
 	wg := sync.WaitGroup{}
 
@@ -11,10 +11,12 @@ import (
 	"github.com/jackc/pgconn"
 
 	nftofferscrapers "github.com/diadata-org/diadata/pkg/dia/nft/nftOffer-scrapers"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	metrics.Serve()
 
 	wg := sync.WaitGroup{}
 
@@ -34,6 +36,12 @@ func main() {
 	case "CryptoKitties":
 		log.Println("NFT Offers Scraper: Start scraping bids from CryptoKitties")
 		scraper = nftofferscrapers.NewCryptokittiesScraper(rdb)
+	case dia.OpenseaSeaport:
+		log.Println("NFT Offers Scraper: Start scraping listings and offers from Seaport")
+		scraper = nftofferscrapers.NewSeaportScraper(rdb)
+	case dia.LooksRare:
+		log.Println("NFT Offers Scraper: Start scraping listings and offers from LooksRare")
+		scraper = nftofferscrapers.NewLooksRareScraper(rdb)
 	default:
 		for {
 			time.Sleep(24 * time.Hour)
@@ -54,7 +62,15 @@ func handleOffers(offerChannel chan dia.NFTOffer, wg *sync.WaitGroup, rdb *model
 			log.Error("error")
 			return
 		}
-		err := rdb.SetNFTOffer(offer)
+		var err error
+		if offer.Cancelled || offer.Filled {
+			err = rdb.UpdateNFTOfferState(offer)
+			if err != nil {
+				log.Errorf("update state of offer with tx hash %s: %v", offer.TxHash, err)
+			}
+			continue
+		}
+		err = rdb.SetNFTOffer(offer)
 		if err != nil {
 			var pgErr *pgconn.PgError
 			if errors.As(err, &pgErr) {
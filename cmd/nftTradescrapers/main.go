@@ -9,6 +9,7 @@ import (
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
 	nfttradescrapers "github.com/diadata-org/diadata/pkg/dia/nft/nftTrade-scrapers"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/jackc/pgconn"
 	"github.com/segmentio/kafka-go"
@@ -17,6 +18,7 @@ import (
 )
 
 func main() {
+	metrics.Serve()
 
 	var (
 		w            *kafka.Writer
@@ -5,7 +5,12 @@ import (
 	"github.com/diadata-org/diadata/internal/pkg/ratescrapers"
 	"github.com/diadata-org/diadata/internal/pkg/static-scrapers"
 	"sync"
+	"time"
 
+	ratederivatives "github.com/diadata-org/diadata/internal/pkg/rateDerivatives"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	log "github.com/sirupsen/logrus"
 )
@@ -27,8 +32,56 @@ func handleInterestRate(c chan *models.InterestRate, wg *sync.WaitGroup, ds mode
 	}
 }
 
+// seedRateCalendar writes the holidays of the calendar of @rateType into postgres for all years
+// since the first publication of the rate up to next year. Years which already have holidays in the
+// database are left untouched, so that manually maintained exceptions are not overwritten.
+func seedRateCalendar(ds *models.DB, relDB *models.RelDB, rateType string) error {
+	symbol := rateType
+	if rateType == "SAFR-AVGS" {
+		// The averages SOFR30, SOFR90 and SOFR180 share the calendar of SOFR.
+		symbol = "SOFR30"
+	}
+	calendar, err := ratederivatives.RateCalendar(symbol)
+	if err != nil {
+		return err
+	}
+	if calendar == dia.CalendarCrypto {
+		return nil
+	}
+	stored, err := relDB.GetRateHolidays(calendar)
+	if err != nil {
+		return err
+	}
+	storedYears := make(map[int]bool)
+	for _, holiday := range stored {
+		storedYears[holiday.Date.Year()] = true
+	}
+
+	firstYear := time.Now().Year()
+	if firstDate, err := ds.GetFirstDate(symbol); err == nil {
+		firstYear = firstDate.Year()
+	}
+	for year := firstYear; year <= time.Now().Year()+1; year++ {
+		if storedYears[year] {
+			continue
+		}
+		holidays, err := ratederivatives.GenerateHolidays(calendar, year)
+		if err != nil {
+			return err
+		}
+		for _, holiday := range holidays {
+			if err = relDB.SetRateHoliday(holiday); err != nil {
+				return err
+			}
+		}
+		log.Infof("wrote %d holidays of calendar %s for %d", len(holidays), calendar, year)
+	}
+	return nil
+}
+
 // main manages all Scraper and handles incoming trade information
 func main() {
+	metrics.Serve()
 
 	// Parse the option for the type of interest rate. The available values
 	// for the flags can be found in the Update() method in RateScraper.go.
@@ -54,6 +107,14 @@ func main() {
 			log.Errorf("Error writing rate %s: %v", *rateType, err)
 		}
 
+		// Make sure the business day calendar of the rate is available for compounding
+		relDB, err := models.NewRelDataStore()
+		if err != nil {
+			log.Errorln("NewRelDataStore:", err)
+		} else if err = seedRateCalendar(ds, relDB, *rateType); err != nil {
+			log.Errorf("Error writing calendar of rate %s: %v", *rateType, err)
+		}
+
 		// Spawn the corresponding rate scraper
 		sRate := ratescrapers.SpawnRateScraper(ds, *rateType)
 		defer func() {
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	alertservice "github.com/diadata-org/diadata/internal/pkg/alertService"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	alertRulesFilename = "alert_rules"
+	// Rules, webhooks and silences are re-read from the config file after this time.
+	configReloadInterval = time.Minute
+)
+
+func main() {
+	metrics.Serve()
+	testing := flag.Bool("testing", false, "set true for testing environment")
+	flag.Parse()
+
+	config, err := alertservice.GetConfig(alertRulesFilename)
+	if err != nil {
+		log.Fatal("get alert config: ", err)
+	}
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Fatal("relational datastore error: ", err)
+	}
+	engine, err := alertservice.NewAlertEngine(ds, relDB, alertservice.NewWebhookNotifier(), config)
+	if err != nil {
+		log.Fatal("new alert engine: ", err)
+	}
+	log.Infof("loaded %d alert rules and %d webhooks", len(config.Rules), len(config.Webhooks))
+
+	go func() {
+		for range time.Tick(configReloadInterval) {
+			config, err := alertservice.GetConfig(alertRulesFilename)
+			if err != nil {
+				log.Error("reload alert config: ", err)
+				continue
+			}
+			engine.SetConfig(config)
+		}
+	}()
+
+	filtersBlockTopic := kafkaHelper.TopicFiltersBlock
+	if *testing {
+		filtersBlockTopic = kafkaHelper.TopicFiltersBlockTest
+	}
+	r := kafkaHelper.NewReaderNextMessage(filtersBlockTopic)
+	defer func() {
+		err := r.Close()
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for {
+		m, err := r.ReadMessage(context.Background())
+		if err != nil {
+			log.Error("read filters block: ", err)
+			continue
+		}
+		var fb dia.FiltersBlock
+		err = fb.UnmarshalBinary(m.Value)
+		if err != nil {
+			log.Error("unmarshal filters block: ", err)
+			continue
+		}
+		engine.ProcessFiltersBlock(&fb)
+	}
+}
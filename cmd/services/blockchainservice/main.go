@@ -5,6 +5,7 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	log "github.com/sirupsen/logrus"
 )
@@ -33,6 +34,7 @@ type nftExchangesConfig struct {
 }
 
 func main() {
+	metrics.Serve()
 
 	rdb, err := models.NewRelDataStore()
 	if err != nil {
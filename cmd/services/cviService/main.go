@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	cvihelper "github.com/diadata-org/diadata/pkg/dia/helpers/cviHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Computes the crypto volatility index of each underlying from the latest option order book
+// snapshots of the options scraper and stores it in influx.
+func main() {
+	metrics.Serve()
+	exchange := utils.Getenv("EXCHANGE", "Deribit")
+	underlyings := strings.Split(utils.Getenv("UNDERLYINGS", "BTC,ETH"), ",")
+	riskFreeRate, err := strconv.ParseFloat(utils.Getenv("RISK_FREE_RATE", "0"), 64)
+	if err != nil {
+		log.Fatal("parse risk free rate: ", err)
+	}
+	frequencySeconds, err := strconv.Atoi(utils.Getenv("FREQUENCY_SECONDS", "60"))
+	if err != nil {
+		log.Fatal("parse frequency: ", err)
+	}
+	// Snapshots older than the window are considered stale.
+	windowSeconds, err := strconv.Atoi(utils.Getenv("SNAPSHOT_WINDOW_SECONDS", "600"))
+	if err != nil {
+		log.Fatal("parse snapshot window: ", err)
+	}
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+
+	ticker := time.NewTicker(time.Duration(frequencySeconds) * time.Second)
+	for ; true; <-ticker.C {
+		now := time.Now()
+		for _, underlying := range underlyings {
+			snapshots, err := ds.GetOptionOrderbookSnapshots(exchange, underlying, now.Add(-time.Duration(windowSeconds)*time.Second), now)
+			if err != nil {
+				log.Errorf("get option order books of %s: %v", underlying, err)
+				continue
+			}
+			if len(snapshots) == 0 {
+				log.Warnf("no option order books of %s on %s", underlying, exchange)
+				continue
+			}
+			cvi, err := cvihelper.Compute(snapshots, now, riskFreeRate)
+			if err != nil {
+				log.Errorf("compute CVI of %s: %v", underlying, err)
+				continue
+			}
+			log.Infof("CVI of %s: %v", underlying, cvi.Value)
+			if err = ds.SaveCVI(cvi); err != nil {
+				log.Errorf("save CVI of %s: %v", underlying, err)
+			}
+		}
+		if err = ds.Flush(); err != nil {
+			log.Error("flush CVI: ", err)
+		}
+	}
+}
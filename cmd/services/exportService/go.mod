@@ -0,0 +1,8 @@
+module github.com/diadata-org/diadata/services/exportService
+
+go 1.14
+
+require (
+	github.com/diadata-org/diadata v1.4.1-rc-292
+	github.com/sirupsen/logrus v1.8.1
+)
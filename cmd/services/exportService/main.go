@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/export"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	timeFormat = "2006-01-02T15:04:05"
+	// Time between two polls for pending export jobs in worker mode.
+	pollInterval = 10 * time.Second
+)
+
+func main() {
+	metrics.Serve()
+	worker := flag.Bool("worker", false, "process export jobs requested through the API instead of a single export")
+	dataset := flag.String("dataset", export.DatasetTrades, "dataset to export: trades, filters or quotations")
+	blockchain := flag.String("blockchain", dia.ETHEREUM, "blockchain of the asset")
+	address := flag.String("address", "", "address of the asset")
+	exchange := flag.String("exchange", "", "restrict trades and filters to this exchange")
+	filter := flag.String("filter", dia.FilterKing, "filter of the filters dataset")
+	format := flag.String("format", export.FormatParquet, "output format: parquet or csv")
+	startTime := flag.String("starttime", "", "start of the time range in the format 2006-01-02T15:04:05 (UTC)")
+	endTime := flag.String("endtime", time.Now().UTC().Format(timeFormat), "end of the time range in the format 2006-01-02T15:04:05 (UTC)")
+	rowsPerFile := flag.Int("rowsPerFile", export.DefaultRowsPerFile, "maximal number of rows per file")
+	outDir := flag.String("outdir", utils.Getenv("EXPORT_DIR", "/exports"), "output directory")
+	flag.Parse()
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Fatal("relational datastore error: ", err)
+	}
+	exporter := export.NewExporter(ds)
+
+	if *worker {
+		runWorker(exporter, relDB, *outDir, *rowsPerFile)
+		return
+	}
+
+	starttime, err := time.Parse(timeFormat, *startTime)
+	if err != nil {
+		log.Fatal("parse starttime: ", err)
+	}
+	endtime, err := time.Parse(timeFormat, *endTime)
+	if err != nil {
+		log.Fatal("parse endtime: ", err)
+	}
+	asset, err := relDB.GetAsset(*address, *blockchain)
+	if err != nil {
+		log.Fatalf("get asset %s on %s: %v", *address, *blockchain, err)
+	}
+
+	request := export.Request{
+		Dataset:     *dataset,
+		Asset:       asset,
+		Exchange:    *exchange,
+		Filter:      *filter,
+		Format:      strings.ToLower(*format),
+		Starttime:   starttime,
+		Endtime:     endtime,
+		RowsPerFile: *rowsPerFile,
+	}
+	manifest, err := exporter.Run(request, *outDir)
+	if err != nil {
+		log.Fatal("export: ", err)
+	}
+	log.Infof("exported %d rows into %d files in %s", manifest.Rows, len(manifest.Files), *outDir)
+}
+
+// runWorker polls for pending export jobs and writes each into a subdirectory of @outDir named by the job's ID.
+func runWorker(exporter *export.Exporter, relDB *models.RelDB, outDir string, rowsPerFile int) {
+	for {
+		job, ok, err := relDB.ClaimExportJob()
+		if err != nil {
+			log.Error("claim export job: ", err)
+		}
+		if !ok {
+			time.Sleep(pollInterval)
+			continue
+		}
+
+		log.Infof("start export %s: %s of %s on %s", job.ID, job.Dataset, job.Address, job.Blockchain)
+		status, errorMessage := dia.ExportStatusDone, ""
+		asset, err := relDB.GetAsset(job.Address, job.Blockchain)
+		if err == nil {
+			request := export.Request{
+				Dataset:     job.Dataset,
+				Asset:       asset,
+				Exchange:    job.Exchange,
+				Filter:      job.Filter,
+				Format:      job.Format,
+				Starttime:   job.Starttime,
+				Endtime:     job.Endtime,
+				RowsPerFile: rowsPerFile,
+			}
+			_, err = exporter.Run(request, filepath.Join(outDir, job.ID))
+		}
+		if err != nil {
+			log.Errorf("export %s: %v", job.ID, err)
+			status, errorMessage = dia.ExportStatusFailed, err.Error()
+		}
+		if err = relDB.FinishExportJob(job.ID, status, errorMessage); err != nil {
+			log.Errorf("finish export %s: %v", job.ID, err)
+		}
+	}
+}
@@ -3,13 +3,16 @@ package main
 import (
 	"context"
 	"flag"
+	"strconv"
 	"sync"
 	"time"
 
 	filters "github.com/diadata-org/diadata/internal/pkg/filtersBlockService"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/segmentio/kafka-go"
 	log "github.com/sirupsen/logrus"
 )
@@ -43,6 +46,7 @@ func init() {
 }
 
 func main() {
+	metrics.Serve()
 
 	if *replayInflux {
 		s, err := models.NewInfluxDataStore()
@@ -59,6 +63,9 @@ func main() {
 		channel := make(chan *dia.FiltersBlock)
 
 		f := filters.NewFiltersBlockService(loadFilterPointsFromPreviousBlock(), s, channel)
+		if !*historical {
+			setExchangeHealthScorer(f)
+		}
 
 		w := kafkaHelper.NewSyncWriterWithCompression(filtersBlockTopic)
 
@@ -212,3 +219,29 @@ func createTradeBlockFromInflux(d models.Datastore, f *filters.FiltersBlockServi
 		}
 	}
 }
+
+// setExchangeHealthScorer enables health scoring of all exchanges in the exchange table. Trades of exchanges
+// with a score below EXCHANGE_HEALTH_MIN_SCORE are excluded from the filters across all exchanges,
+// and with EXCHANGE_HEALTH_WEIGHTED=true their volumes are weighted by the score.
+func setExchangeHealthScorer(f *filters.FiltersBlockService) {
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Error("relational datastore error: ", err)
+		return
+	}
+	exchanges, err := relDB.GetAllExchanges()
+	if err != nil {
+		log.Error("get exchanges for health scores: ", err)
+		return
+	}
+	minScore, err := strconv.ParseFloat(utils.Getenv("EXCHANGE_HEALTH_MIN_SCORE", "0"), 64)
+	if err != nil {
+		log.Error("parse EXCHANGE_HEALTH_MIN_SCORE: ", err)
+	}
+	policy := filters.ExchangeHealthPolicy{
+		MinScore: minScore,
+		Weighted: utils.Getenv("EXCHANGE_HEALTH_WEIGHTED", "false") == "true",
+	}
+	f.SetExchangeHealthScorer(filters.NewExchangeHealthScorer(exchanges, policy))
+	log.Infof("exchange health scoring enabled for %d exchanges with policy %+v", len(exchanges), policy)
+}
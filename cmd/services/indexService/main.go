@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+
+	indexengine "github.com/diadata-org/diadata/internal/pkg/indexEngine"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Index definitions are re-read from postgres after this time.
+	definitionsReloadInterval = time.Minute
+	// Selection rules are evaluated on daily data, so that rebalancings are proposed once a day.
+	proposalInterval = 24 * time.Hour
+)
+
+func main() {
+	testing := flag.Bool("testing", false, "set true for testing environment")
+	backtest := flag.Bool("backtest", false, "backtest the selection rules of an index instead of computing indices")
+	index := flag.String("index", "", "symbol of the index to backtest")
+	startDate := flag.String("startDate", "", "first day of the backtest in the format 2006-01-02")
+	endDate := flag.String("endDate", time.Now().Format("2006-01-02"), "last day of the backtest in the format 2006-01-02")
+	flag.Parse()
+	filter := utils.Getenv("INDEX_FILTER", dia.FilterKing)
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Fatal("relational datastore error: ", err)
+	}
+
+	if *backtest {
+		starttime, err := time.Parse("2006-01-02", *startDate)
+		if err != nil {
+			log.Fatal("parse start date: ", err)
+		}
+		endtime, err := time.Parse("2006-01-02", *endDate)
+		if err != nil {
+			log.Fatal("parse end date: ", err)
+		}
+		runBacktest(ds, relDB, *index, starttime, endtime)
+		return
+	}
+
+	metrics.Serve()
+
+	engine := indexengine.NewEngine(ds, relDB, filter)
+	if err := engine.LoadIndices(); err != nil {
+		log.Fatal("load index definitions: ", err)
+	}
+
+	go func() {
+		for range time.Tick(definitionsReloadInterval) {
+			if err := engine.LoadIndices(); err != nil {
+				log.Error("reload index definitions: ", err)
+			}
+		}
+	}()
+
+	go func() {
+		proposeRebalances(ds, relDB, engine)
+		for range time.Tick(proposalInterval) {
+			proposeRebalances(ds, relDB, engine)
+		}
+	}()
+
+	filtersBlockTopic := kafkaHelper.TopicFiltersBlock
+	if *testing {
+		filtersBlockTopic = kafkaHelper.TopicFiltersBlockTest
+	}
+	r := kafkaHelper.NewReaderNextMessage(filtersBlockTopic)
+	defer func() {
+		err := r.Close()
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for {
+		m, err := r.ReadMessage(context.Background())
+		if err != nil {
+			log.Error("read filters block: ", err)
+			continue
+		}
+		var fb dia.FiltersBlock
+		err = fb.UnmarshalBinary(m.Value)
+		if err != nil {
+			log.Error("unmarshal filters block: ", err)
+			continue
+		}
+		engine.ProcessFiltersBlock(&fb)
+	}
+}
+
+// proposeRebalances evaluates the selection rules of all indices which have them and caches the proposed
+// constituents along with their justification.
+func proposeRebalances(ds models.Datastore, relDB models.RelDatastore, engine *indexengine.Engine) {
+	definitions, err := relDB.GetIndexDefinitions()
+	if err != nil {
+		log.Error("get index definitions: ", err)
+		return
+	}
+	allRules, err := relDB.GetIndexSelectionRules()
+	if err != nil {
+		log.Error("get index selection rules: ", err)
+		return
+	}
+	for _, rules := range allRules {
+		definition, ok := findDefinition(definitions, rules.Symbol)
+		if !ok {
+			log.Warnf("selection rules of unknown or inactive index %s", rules.Symbol)
+			continue
+		}
+		proposal, err := indexengine.ProposeIndexRebalance(ds, relDB, definition, rules, time.Now())
+		if err != nil {
+			log.Errorf("propose rebalancing of index %s: %v", rules.Symbol, err)
+			continue
+		}
+		for _, justification := range proposal.Justification {
+			log.Infof("index %s: %s", rules.Symbol, justification)
+		}
+		err = ds.SetIndexProposalCache(&proposal)
+		if err != nil {
+			log.Errorf("set rebalance proposal of index %s: %v", rules.Symbol, err)
+		}
+		engine.SetProposal(proposal)
+	}
+}
+
+// runBacktest backtests the selection rules of the index @symbol and writes the result to stdout.
+func runBacktest(ds models.Datastore, relDB models.RelDatastore, symbol string, starttime time.Time, endtime time.Time) {
+	definitions, err := relDB.GetIndexDefinitions()
+	if err != nil {
+		log.Fatal("get index definitions: ", err)
+	}
+	definition, ok := findDefinition(definitions, symbol)
+	if !ok {
+		log.Fatalf("unknown or inactive index %s", symbol)
+	}
+	allRules, err := relDB.GetIndexSelectionRules()
+	if err != nil {
+		log.Fatal("get index selection rules: ", err)
+	}
+	var rules *dia.IndexSelectionRules
+	for i := range allRules {
+		if allRules[i].Symbol == symbol {
+			rules = &allRules[i]
+		}
+	}
+	if rules == nil {
+		log.Fatalf("index %s has no selection rules", symbol)
+	}
+
+	result, err := indexengine.RunBacktest(ds, relDB, definition, *rules, starttime, endtime.AddDate(0, 0, 1).Add(-time.Second))
+	if err != nil {
+		log.Fatal("backtest: ", err)
+	}
+	for _, period := range result.Periods {
+		log.Infof("%s: %d constituents, turnover %.2f, value %v", period.Rebalance.Time.Format("2006-01-02"), len(period.Rebalance.Constituents), period.Turnover, period.Rebalance.IndexValue)
+		for _, justification := range period.Proposal.Justification {
+			log.Info("  ", justification)
+		}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatal("encode backtest: ", err)
+	}
+}
+
+func findDefinition(definitions []dia.IndexDefinition, symbol string) (dia.IndexDefinition, bool) {
+	for _, definition := range definitions {
+		if definition.Symbol == symbol {
+			return definition, true
+		}
+	}
+	return dia.IndexDefinition{}, false
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
 	nftsource "github.com/diadata-org/diadata/pkg/dia/nft/nftService"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/sirupsen/logrus"
 )
@@ -47,6 +48,7 @@ func NewNFTClassCollector(nftSource string, secret string) nftsource.NFTClassSou
 }
 
 func main() {
+	metrics.Serve()
 
 	relDB, err := models.NewRelDataStore()
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/sirupsen/logrus"
 	"github.com/tkanos/gonfig"
@@ -35,6 +36,7 @@ func init() {
 }
 
 func main() {
+	metrics.Serve()
 
 	relDB, err := models.NewRelDataStore()
 	if err != nil {
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	supplyservice "github.com/diadata-org/diadata/internal/pkg/supplyService"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Compares the reserves of bridged assets locked on their source chain with the supply minted on
+// destination chains and stores the collateralisation in influx.
+func main() {
+	metrics.Serve()
+	configFilename := utils.Getenv("RESERVES_CONFIG", "reserves")
+	frequencySeconds, err := strconv.Atoi(utils.Getenv("FREQUENCY_SECONDS", "3600"))
+	if err != nil {
+		log.Fatal("parse frequency: ", err)
+	}
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Fatal("relational datastore error: ", err)
+	}
+	engine, err := supplyservice.NewSupplyEngine(relDB)
+	if err != nil {
+		log.Fatal("make supply engine: ", err)
+	}
+
+	ticker := time.NewTicker(time.Duration(frequencySeconds) * time.Second)
+	for ; true; <-ticker.C {
+		// The config is read on every run, so that assets can be added without restart.
+		assets, err := supplyservice.GetReserveAssetsFromConfig(configFilename)
+		if err != nil {
+			log.Error("get reserve assets: ", err)
+			continue
+		}
+		for _, asset := range assets {
+			por, err := engine.GetProofOfReserve(asset)
+			if err != nil {
+				log.Errorf("get proof of reserve of %s: %v", asset.Symbol, err)
+				continue
+			}
+			if por.CollateralRatio < 1 {
+				log.Warnf("%s is undercollateralized: reserves %v, minted supply %v", asset.Symbol, por.Reserves, por.Supply)
+			} else {
+				log.Infof("collateral ratio of %s: %v", asset.Symbol, por.CollateralRatio)
+			}
+			metrics.ReserveCollateralRatio.WithLabelValues(asset.Blockchain, asset.Symbol).Set(por.CollateralRatio)
+			if err = ds.SaveProofOfReserve(por); err != nil {
+				log.Errorf("save proof of reserve of %s: %v", asset.Symbol, err)
+			}
+		}
+		if err = ds.Flush(); err != nil {
+			log.Error("flush proofs of reserve: ", err)
+		}
+	}
+}
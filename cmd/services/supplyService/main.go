@@ -1,10 +1,12 @@
 package main
 
 import (
+	"flag"
 	"time"
 
 	supplyservice "github.com/diadata-org/diadata/internal/pkg/supplyService"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	log "github.com/sirupsen/logrus"
 )
@@ -12,9 +14,18 @@ import (
 const (
 	tokensListFilename    = "tokens_list"
 	lockedWalletsFilename = "wallets"
+	supplyRulesFilename   = "supply_rules"
 )
 
 func main() {
+	metrics.Serve()
+	backfill := flag.Bool("backfill", false, "reconstruct historical supplies of an asset instead of setting the current supplies")
+	blockchain := flag.String("blockchain", dia.ETHEREUM, "blockchain of the asset to backfill")
+	address := flag.String("address", "", "address of the asset to backfill")
+	startDate := flag.String("startDate", "", "first day to backfill in the format 2006-01-02")
+	endDate := flag.String("endDate", time.Now().AddDate(0, 0, -1).Format("2006-01-02"), "last day to backfill in the format 2006-01-02")
+	startBlock := flag.Uint64("startBlock", 0, "deployment block of the asset if not given in the supply rules")
+	flag.Parse()
 
 	ds, err := models.NewDataStore()
 	if err != nil {
@@ -26,6 +37,19 @@ func main() {
 		log.Fatal("relational datastore error: ", err)
 	}
 
+	if *backfill {
+		starttime, err := time.Parse("2006-01-02", *startDate)
+		if err != nil {
+			log.Fatal("parse start date: ", err)
+		}
+		endtime, err := time.Parse("2006-01-02", *endDate)
+		if err != nil {
+			log.Fatal("parse end date: ", err)
+		}
+		backfillSupplies(ds, relDB, *blockchain, *address, *startBlock, starttime, endtime.AddDate(0, 0, 1).Add(-time.Second))
+		return
+	}
+
 	suppliesCG, err := supplyservice.GetETHSuppliesFromCG()
 	if err != nil {
 		log.Error("get supplies from coingecko: ", err)
@@ -47,6 +71,9 @@ func main() {
 		}
 	}
 
+	// Compute supplies on-chain for all assets with supply rules. These take precedence over Coingecko.
+	setOnChainSupplies(ds, relDB)
+
 	// Save old "circulating" supply as total supply (i.e. #DIA without the burnt tokens)
 	err = ds.SetDiaTotalSupply(float64(173296236.5011769))
 	if err != nil {
@@ -98,3 +125,84 @@ func main() {
 		log.Info("set supply: " + supplyBTC.Asset.Name + " - " + supplyBTC.Asset.Symbol)
 	}
 }
+
+// setOnChainSupplies computes and stores the supplies of all assets from the supply rules config
+// and the legacy locked wallets config.
+func setOnChainSupplies(ds *models.DB, relDB *models.RelDB) {
+	supplyAssets, err := supplyservice.GetSupplyAssetsFromConfig(supplyRulesFilename)
+	if err != nil {
+		log.Error("get supply rules: ", err)
+	}
+	lockedWallets, err := supplyservice.GetLockedWalletsFromConfig(lockedWalletsFilename)
+	if err != nil {
+		log.Error("get locked wallets: ", err)
+	}
+	supplyAssets = supplyservice.MergeLockedWallets(supplyAssets, lockedWallets)
+
+	engine, err := supplyservice.NewSupplyEngine(relDB)
+	if err != nil {
+		log.Error("make supply engine: ", err)
+		return
+	}
+	for _, supplyAsset := range supplyAssets {
+		supply, err := engine.GetSupply(supplyAsset)
+		if err != nil {
+			log.Errorf("get supply for %s on %s: %v", supplyAsset.Address, supplyAsset.Blockchain, err)
+			continue
+		}
+		asset, err := relDB.GetAsset(supply.Asset.Address, supply.Asset.Blockchain)
+		if err == nil {
+			supply.Asset = asset
+		}
+		err = ds.SetSupply(&supply)
+		if err != nil {
+			log.Errorf("error setting supply for %s: %v\n", supply.Asset.Symbol, err)
+		} else {
+			log.Infof("set supply: %s - %s with %d exclusions", supply.Asset.Name, supply.Asset.Symbol, len(supply.Exclusions))
+		}
+	}
+}
+
+// backfillSupplies reconstructs daily supplies of the asset with @address on @blockchain from
+// @starttime to @endtime and stores them in influx.
+func backfillSupplies(ds *models.DB, relDB *models.RelDB, blockchain string, address string, startBlock uint64, starttime time.Time, endtime time.Time) {
+	supplyAssets, err := supplyservice.GetSupplyAssetsFromConfig(supplyRulesFilename)
+	if err != nil {
+		log.Error("get supply rules: ", err)
+	}
+	lockedWallets, err := supplyservice.GetLockedWalletsFromConfig(lockedWalletsFilename)
+	if err != nil {
+		log.Error("get locked wallets: ", err)
+	}
+	supplyAssets = supplyservice.MergeLockedWallets(supplyAssets, lockedWallets)
+
+	supplyAsset, err := supplyservice.GetSupplyAsset(supplyAssets, address, blockchain)
+	if err != nil {
+		log.Warnf("%v. Reconstruct total supply only.", err)
+		supplyAsset = supplyservice.SupplyAsset{Blockchain: blockchain, Address: address}
+	}
+	if startBlock > 0 {
+		supplyAsset.StartBlock = startBlock
+	}
+
+	engine, err := supplyservice.NewSupplyEngine(relDB)
+	if err != nil {
+		log.Fatal("make supply engine: ", err)
+	}
+	supplies, err := engine.ReconstructSupplies(supplyAsset, starttime, endtime)
+	if err != nil {
+		log.Fatal("reconstruct supplies: ", err)
+	}
+
+	asset, err := relDB.GetAsset(supplyAsset.Address, supplyAsset.Blockchain)
+	for i := range supplies {
+		if err == nil {
+			supplies[i].Asset = asset
+		}
+		errSave := ds.SaveSupplyInflux(&supplies[i])
+		if errSave != nil {
+			log.Errorf("save supply at %v: %v", supplies[i].Time, errSave)
+		}
+	}
+	log.Infof("backfilled %d supplies of %s on %s", len(supplies), address, blockchain)
+}
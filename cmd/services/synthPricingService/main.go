@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	synthhelper "github.com/diadata-org/diadata/pkg/dia/helpers/synthHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Computes the fair value of the synthetic assets stored by the synth scraper from the price of their
+// underlying and their collateral ratio. The fair value is published as quotation with source
+// dia.SynthFairValueSource and compared to the market price from trades in order to detect depegs.
+func main() {
+	metrics.Serve()
+	// Comma separated list of blockchain:protocol as stored by the synth scraper.
+	protocols := strings.Split(utils.Getenv("SYNTH_PROTOCOLS", "Ethereum:Aave-V2,Polygon:Aave-V3,Avalanche:Aave-V3,Avalanche:Aave-V2"), ",")
+	frequencySeconds, err := strconv.Atoi(utils.Getenv("FREQUENCY_SECONDS", "300"))
+	if err != nil {
+		log.Fatal("parse frequency: ", err)
+	}
+	depegThreshold, err := strconv.ParseFloat(utils.Getenv("DEPEG_THRESHOLD", "0.02"), 64)
+	if err != nil {
+		log.Fatal("parse depeg threshold: ", err)
+	}
+	// Supplies older than the window are considered stale.
+	supplyWindowSeconds, err := strconv.Atoi(utils.Getenv("SUPPLY_WINDOW_SECONDS", "86400"))
+	if err != nil {
+		log.Fatal("parse supply window: ", err)
+	}
+	// Market quotations older than the maximal age are not compared to the fair value.
+	marketMaxAgeSeconds, err := strconv.Atoi(utils.Getenv("MARKET_QUOTATION_MAX_AGE_SECONDS", "3600"))
+	if err != nil {
+		log.Fatal("parse market quotation age: ", err)
+	}
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+
+	ticker := time.NewTicker(time.Duration(frequencySeconds) * time.Second)
+	for ; true; <-ticker.C {
+		now := time.Now()
+		for _, blockchainProtocol := range protocols {
+			parts := strings.Split(blockchainProtocol, ":")
+			if len(parts) != 2 {
+				log.Errorf("invalid synth protocol %s, expected blockchain:protocol", blockchainProtocol)
+				continue
+			}
+			blockchain, protocol := parts[0], parts[1]
+			addresses, err := ds.GetSynthAssets(blockchain, protocol)
+			if err != nil {
+				log.Errorf("get synthetic assets of %s on %s: %v", protocol, blockchain, err)
+				continue
+			}
+			for _, address := range addresses {
+				supplies, err := ds.GetSynthSupplyInflux(blockchain, protocol, address, 1, now.Add(-time.Duration(supplyWindowSeconds)*time.Second), now)
+				if err != nil || len(supplies) == 0 {
+					log.Warnf("no recent supply of %s on %s", address, blockchain)
+					continue
+				}
+				supply := supplies[0]
+				underlyingQuotation, err := ds.GetAssetQuotationLatest(supply.AssetUnderlying)
+				if err != nil || underlyingQuotation.Price == 0 {
+					log.Warnf("no quotation of underlying %s of %s", supply.AssetUnderlying.Symbol, supply.Asset.Symbol)
+					continue
+				}
+				var marketPrice float64
+				marketQuotation, err := ds.GetAssetQuotationLatest(supply.Asset)
+				if err == nil && now.Sub(marketQuotation.Time) <= time.Duration(marketMaxAgeSeconds)*time.Second {
+					marketPrice = marketQuotation.Price
+				}
+
+				valuation, err := synthhelper.Valuate(supply, underlyingQuotation.Price, marketPrice, depegThreshold, now)
+				if err != nil {
+					log.Errorf("valuate %s on %s: %v", supply.Asset.Symbol, blockchain, err)
+					continue
+				}
+				if valuation.Depeg {
+					log.Warnf("%s on %s depegged: market price %v deviates by %.4f from fair value %v", supply.Asset.Symbol, blockchain, valuation.MarketPrice, valuation.Deviation, valuation.FairValue)
+				} else {
+					log.Infof("fair value of %s on %s: %v", supply.Asset.Symbol, blockchain, valuation.FairValue)
+				}
+				// Without market price there is no deviation, so the gauge is removed rather than set to 0.
+				if marketPrice > 0 {
+					metrics.SynthPriceDeviation.WithLabelValues(blockchain, supply.Asset.Symbol).Set(valuation.Deviation)
+				} else {
+					metrics.SynthPriceDeviation.DeleteLabelValues(blockchain, supply.Asset.Symbol)
+				}
+				if err = ds.SaveSynthAssetValuation(valuation); err != nil {
+					log.Errorf("save valuation of %s on %s: %v", supply.Asset.Symbol, blockchain, err)
+				}
+			}
+		}
+		if err = ds.Flush(); err != nil {
+			log.Error("flush synth valuations: ", err)
+		}
+		if err = ds.ExecuteRedisPipe(); err != nil {
+			log.Error("execute redis pipe: ", err)
+		}
+	}
+}
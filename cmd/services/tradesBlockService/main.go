@@ -8,6 +8,7 @@ import (
 	"github.com/diadata-org/diadata/internal/pkg/tradesBlockService"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/segmentio/kafka-go"
 	log "github.com/sirupsen/logrus"
@@ -52,6 +53,7 @@ var (
 )
 
 func main() {
+	metrics.Serve()
 	if *historical {
 		log.Info("run tradesblock service in historical mode")
 	}
@@ -6,11 +6,13 @@ import (
 	"github.com/diadata-org/diadata/internal/pkg/tradesEstimationService"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	metrics.Serve()
 
 	kafkaReader := kafkaHelper.NewReaderNextMessage(kafkaHelper.TopicTradesEstimation)
 	defer func() {
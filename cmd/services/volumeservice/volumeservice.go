@@ -3,6 +3,7 @@ package main
 import (
 	"time"
 
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/jasonlvhit/gocron"
 	"github.com/prometheus/common/log"
@@ -19,6 +20,7 @@ const (
 )
 
 func main() {
+	metrics.Serve()
 
 	datastore, err = models.NewDataStore()
 	if err != nil {
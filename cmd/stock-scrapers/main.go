@@ -6,12 +6,14 @@ import (
 	"time"
 
 	stockscrapers "github.com/diadata-org/diadata/internal/pkg/stock-scrapers"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	metrics.Serve()
 
 	wg := sync.WaitGroup{}
 
@@ -28,6 +30,9 @@ func main() {
 	case "Finage":
 		log.Println("Stock Quote Scraper: Start scraping trades from Finage")
 		scraper = stockscrapers.NewFinageScraper(ds)
+	case "TwelveData":
+		log.Println("Stock Quote Scraper: Start scraping quotes from TwelveData")
+		scraper = stockscrapers.NewTwelveDataScraper(ds)
 	default:
 		for {
 			time.Sleep(24 * time.Hour)
@@ -11,12 +11,14 @@ import (
 	"github.com/diadata-org/diadata/pkg/utils"
 
 	synthscrapers "github.com/diadata-org/diadata/pkg/dia/scraper/synthetic-scrapers"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	log "github.com/sirupsen/logrus"
 )
 
 var watchdogDelay = 3600
 
 func main() {
+	metrics.Serve()
 
 	wg := sync.WaitGroup{}
 
@@ -3,9 +3,12 @@ package main
 import (
 	"fmt"
 	"strings"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
 )
 
 func main() {
+	metrics.Serve()
 
 	if strings.ContainsAny("d'", "!@#$%^&*()'\"|{}[];:><?/`~,") {
 		fmt.Println("kk")
@@ -180,7 +180,9 @@ CREATE TABLE nftbid (
 
 CREATE TABLE nftoffer (
     offer_id UUID DEFAULT gen_random_uuid(),
+    -- nft_id is null for offers on an entire collection.
     nft_id UUID REFERENCES nft(nft_id),
+    nftclass_id UUID REFERENCES nftclass(nftclass_id),
     start_value text,
     end_value text,
     duration numeric,
@@ -194,10 +196,20 @@ CREATE TABLE nftoffer (
     offer_time timestamp,
     tx_hash text,
     marketplace text,
+    order_hash text,
+    -- nonce of the order on marketplaces which cancel orders by nonce such as LooksRare.
+    order_nonce text,
+    expiry_time timestamp,
+    cancelled boolean default false,
+    filled boolean default false,
+    -- time of the cancellation or fill.
+    closed_time timestamp,
     UNIQUE(offer_id),
     UNIQUE(nft_id, from_address, offer_time)
 );
 
+CREATE INDEX nftoffer_order_hash ON nftoffer (marketplace, order_hash);
+
 CREATE TABLE IF NOT EXISTS scrapers (
     name character varying(255) NOT NULL,
 	conf json,
@@ -277,3 +289,118 @@ CREATE TABLE nftexchange (
 );
 
 
+CREATE TABLE apikey (
+    apikey_id UUID DEFAULT gen_random_uuid(),
+    -- The public part of a key which is used for lookup. The secret part is stored as HMAC-SHA256 hash.
+    key_prefix text NOT NULL,
+    key_hash text NOT NULL,
+    owner text NOT NULL,
+    scopes text[] NOT NULL,
+    -- Maximal number of requests per calendar month. 0 means unlimited.
+    monthly_quota bigint default 0,
+    rate_limit numeric default 10,
+    rate_burst integer default 20,
+    is_active boolean default true,
+    creation_time timestamp default now(),
+    expiry_time timestamp,
+    -- Key which was replaced by this key on rotation.
+    rotated_from UUID REFERENCES apikey(apikey_id),
+    UNIQUE(apikey_id),
+    UNIQUE(key_prefix)
+);
+
+CREATE TABLE apikeyusage (
+    apikey_id UUID REFERENCES apikey(apikey_id),
+    endpoint text NOT NULL,
+    day date NOT NULL,
+    requests bigint default 0,
+    UNIQUE(apikey_id,endpoint,day)
+);
+
+CREATE TABLE exportjob (
+    export_id UUID DEFAULT gen_random_uuid(),
+    owner text NOT NULL,
+    dataset text NOT NULL,
+    blockchain text NOT NULL,
+    address text NOT NULL,
+    exchange text default '',
+    filter text default '',
+    format text NOT NULL,
+    starttime timestamp NOT NULL,
+    endtime timestamp NOT NULL,
+    -- One of pending, running, done, failed.
+    status text NOT NULL default 'pending',
+    error text default '',
+    creation_time timestamp default now(),
+    finish_time timestamp,
+    UNIQUE(export_id)
+);
+
+-- Holidays of the business day calendars of interest rates such as US for SOFR.
+CREATE TABLE rateholiday (
+    calendar text NOT NULL,
+    day date NOT NULL,
+    name text default '',
+    UNIQUE(calendar,day)
+);
+
+-- Indices computed by the index engine. weighting is one of marketcap and equal, rebalancing one of
+-- daily, weekly, monthly and quarterly. max_weight=0 means uncapped.
+CREATE TABLE indexdefinition (
+    symbol text NOT NULL,
+    name text default '',
+    weighting text NOT NULL default 'marketcap',
+    max_weight numeric default 0,
+    rebalancing text NOT NULL default 'monthly',
+    base_value numeric default 1000,
+    active boolean default true,
+    UNIQUE(symbol)
+);
+
+CREATE TABLE indexconstituent (
+    index_symbol text REFERENCES indexdefinition(symbol),
+    asset_id UUID REFERENCES asset(asset_id),
+    UNIQUE(index_symbol,asset_id)
+);
+
+-- Audit trail of index compositions. The divisor is adjusted at each rebalancing so that index_value is preserved.
+CREATE TABLE indexrebalance (
+    rebalance_id UUID DEFAULT gen_random_uuid(),
+    index_symbol text NOT NULL,
+    time timestamp NOT NULL,
+    reason text NOT NULL,
+    index_value numeric NOT NULL,
+    divisor numeric NOT NULL,
+    previous_divisor numeric default 0,
+    UNIQUE(rebalance_id)
+);
+
+CREATE TABLE indexweight (
+    rebalance_id UUID REFERENCES indexrebalance(rebalance_id),
+    asset_id UUID REFERENCES asset(asset_id),
+    weight numeric NOT NULL,
+    units numeric NOT NULL,
+    price numeric NOT NULL,
+    supply numeric default 0,
+    UNIQUE(rebalance_id,asset_id)
+);
+
+-- Rules by which the index service proposes constituents from the candidates of an index.
+-- volatility_threshold is an annualized volatility, min_avg_volume and min_market_cap are in USD.
+CREATE TABLE indexselectionrule (
+    index_symbol text REFERENCES indexdefinition(symbol),
+    volatility_threshold numeric NOT NULL,
+    volatility_window integer default 30,
+    lookback_days integer default 90,
+    max_days_above integer default 0,
+    min_avg_volume numeric default 0,
+    min_market_cap numeric default 0,
+    max_constituents integer default 0,
+    UNIQUE(index_symbol)
+);
+
+CREATE TABLE indexcandidate (
+    index_symbol text REFERENCES indexdefinition(symbol),
+    asset_id UUID REFERENCES asset(asset_id),
+    UNIQUE(index_symbol,asset_id)
+);
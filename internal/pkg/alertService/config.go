@@ -0,0 +1,231 @@
+package alertservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Types of alert rules.
+const (
+	// Fires if the price of an asset changed by more than Threshold percent within Window seconds.
+	RulePriceChange = "priceChange"
+	// Fires if the last quotation of an asset is older than MaxAge seconds.
+	RuleStaleQuotation = "staleQuotation"
+	// Fires if the value of Filter for an asset deviates by more than Threshold percent from the previous filters block.
+	RuleFilterDeviation = "filterDeviation"
+	// Fires if no filters block contained a value of Filter for an asset during the last MaxAge seconds.
+	RuleStaleFilter = "staleFilter"
+	// Fires if the last trade of an asset on Exchange is older than MaxAge seconds.
+	RuleStaleExchange = "staleExchange"
+	// Fires if the last update of Key in the oracle contract is older than MaxAge seconds.
+	RuleStaleOracle = "staleOracle"
+	// Fires if the value of Key in the oracle contract deviates by more than Threshold percent from the asset's quotation.
+	RuleOracleDeviation = "oracleDeviation"
+	// Fires if the last quotation of Stock is older than MaxAge seconds while its market is open. The age is
+	// counted from the start of the trading session at the earliest.
+	RuleStaleStock = "staleStock"
+)
+
+// Severities of alert rules.
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+const (
+	defaultRepeatInterval = 3600
+	defaultOracleDecimals = 8
+)
+
+// Rule describes a condition on DIA data. Rule names must be unique, as alerts are deduplicated by rule name.
+type Rule struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Severity string    `json:"severity"`
+	Asset    dia.Asset `json:"asset"`
+	Exchange string    `json:"exchange"`
+	Filter   string    `json:"filter"`
+	// Threshold of deviation rules in percent.
+	Threshold float64 `json:"threshold"`
+	// Time window of priceChange rules in seconds.
+	Window int64 `json:"window"`
+	// Maximal age of data in stale rules in seconds.
+	MaxAge int64  `json:"maxAge"`
+	Oracle Oracle `json:"oracle"`
+	// Symbol of a stock or currency pair such as EUR/USD in staleStock rules.
+	Stock string `json:"stock"`
+	// Source of stock quotations in staleStock rules. All sources are considered if empty.
+	Source string `json:"source"`
+}
+
+// Oracle is a key in a DIA oracle contract.
+type Oracle struct {
+	Blockchain string `json:"blockchain"`
+	Address    string `json:"address"`
+	Key        string `json:"key"`
+	Decimals   int    `json:"decimals"`
+}
+
+// Webhook receives notifications as JSON in POST requests. If Secret is set, requests are signed
+// with HMAC-SHA256 of the body in the X-DIA-Signature header. A webhook with Severities only receives
+// notifications of these severities.
+type Webhook struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	Severities []string `json:"severities"`
+}
+
+// Silence suppresses notifications of all rules whose name matches the pattern Rule until Until.
+// Patterns use the syntax of path.Match, for instance "stale-*". A silence without Until never ends.
+type Silence struct {
+	Rule    string    `json:"rule"`
+	Until   time.Time `json:"until"`
+	Comment string    `json:"comment"`
+}
+
+// Config contains rules, webhooks and silences of the alert service. Notifications of alerts which
+// keep firing are repeated every RepeatInterval seconds.
+type Config struct {
+	Rules          []Rule    `json:"rules"`
+	Webhooks       []Webhook `json:"webhooks"`
+	Silences       []Silence `json:"silences"`
+	RepeatInterval int64     `json:"repeatInterval"`
+}
+
+// GetConfig returns the alert config from the config file @filename.
+func GetConfig(filename string) (config Config, err error) {
+	var jsonFile *os.File
+
+	executionMode := os.Getenv("EXEC_MODE")
+	if executionMode == "production" {
+		jsonFile, err = os.Open(fmt.Sprintf("/config/alertService/%s.json", filename))
+	} else {
+		jsonFile, err = os.Open(fmt.Sprintf("../../../config/alertService/%s.json", filename))
+	}
+	if err != nil {
+		return
+	}
+	defer func() {
+		cerr := jsonFile.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	byteData, err := ioutil.ReadAll(jsonFile)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(byteData, &config)
+	if err != nil {
+		return
+	}
+	err = config.validate()
+	return
+}
+
+func (config *Config) validate() error {
+	if config.RepeatInterval <= 0 {
+		config.RepeatInterval = defaultRepeatInterval
+	}
+	names := make(map[string]bool)
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+		if rule.Name == "" {
+			return errors.New("alert rule without name")
+		}
+		if names[rule.Name] {
+			return errors.New("duplicate alert rule " + rule.Name)
+		}
+		names[rule.Name] = true
+
+		switch rule.Severity {
+		case SeverityInfo, SeverityWarning, SeverityCritical:
+		case "":
+			rule.Severity = SeverityWarning
+		default:
+			return fmt.Errorf("unknown severity %s of alert rule %s", rule.Severity, rule.Name)
+		}
+
+		needsAsset, needsThreshold, needsMaxAge := true, false, false
+		switch rule.Type {
+		case RulePriceChange:
+			needsThreshold = true
+			if rule.Window <= 0 {
+				return errors.New("missing window in alert rule " + rule.Name)
+			}
+		case RuleFilterDeviation:
+			needsThreshold = true
+		case RuleStaleQuotation, RuleStaleFilter:
+			needsMaxAge = true
+		case RuleStaleExchange:
+			needsMaxAge = true
+			if rule.Exchange == "" {
+				return errors.New("missing exchange in alert rule " + rule.Name)
+			}
+		case RuleStaleOracle, RuleOracleDeviation:
+			if rule.Oracle.Blockchain == "" || rule.Oracle.Address == "" || rule.Oracle.Key == "" {
+				return errors.New("missing oracle in alert rule " + rule.Name)
+			}
+			if rule.Oracle.Decimals == 0 {
+				rule.Oracle.Decimals = defaultOracleDecimals
+			}
+			needsAsset = rule.Type == RuleOracleDeviation
+			needsThreshold = rule.Type == RuleOracleDeviation
+			needsMaxAge = rule.Type == RuleStaleOracle
+		case RuleStaleStock:
+			if rule.Stock == "" {
+				return errors.New("missing stock in alert rule " + rule.Name)
+			}
+			needsAsset, needsMaxAge = false, true
+		default:
+			return fmt.Errorf("unknown type %s of alert rule %s", rule.Type, rule.Name)
+		}
+		if (rule.Type == RuleFilterDeviation || rule.Type == RuleStaleFilter) && rule.Filter == "" {
+			rule.Filter = dia.FilterKing
+		}
+		if needsAsset && (rule.Asset.Blockchain == "" || rule.Asset.Address == "") {
+			return errors.New("missing asset in alert rule " + rule.Name)
+		}
+		if needsThreshold && rule.Threshold <= 0 {
+			return errors.New("missing threshold in alert rule " + rule.Name)
+		}
+		if needsMaxAge && rule.MaxAge <= 0 {
+			return errors.New("missing maxAge in alert rule " + rule.Name)
+		}
+	}
+
+	for _, webhook := range config.Webhooks {
+		if webhook.URL == "" {
+			return errors.New("webhook without url")
+		}
+	}
+	for _, silence := range config.Silences {
+		if _, err := path.Match(silence.Rule, ""); err != nil {
+			return fmt.Errorf("invalid silence pattern %s: %v", silence.Rule, err)
+		}
+	}
+	return nil
+}
+
+// isSilenced returns true if a silence of @config covers the rule @name at time @t.
+func (config *Config) isSilenced(name string, t time.Time) bool {
+	for _, silence := range config.Silences {
+		if !silence.Until.IsZero() && t.After(silence.Until) {
+			continue
+		}
+		if ok, _ := path.Match(silence.Rule, name); ok {
+			return true
+		}
+	}
+	return false
+}
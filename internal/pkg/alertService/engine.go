@@ -0,0 +1,421 @@
+package alertservice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	markethours "github.com/diadata-org/diadata/internal/pkg/marketHours"
+	stockscrapers "github.com/diadata-org/diadata/internal/pkg/stock-scrapers"
+	"github.com/diadata-org/diadata/pkg/dia"
+	diaOracleV2 "github.com/diadata-org/diadata/pkg/dia/scraper/blockchain-scrapers/blockchains/ethereum/diaOracleServiceV2"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// Status of notifications.
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Number of notifications which can wait for delivery. Further notifications are dropped.
+const deliveryQueueSize = 1000
+
+// Notification is sent to webhooks when an alert starts firing, while it keeps firing and when it is resolved.
+type Notification struct {
+	Rule     string    `json:"rule"`
+	Type     string    `json:"type"`
+	Severity string    `json:"severity"`
+	Status   string    `json:"status"`
+	Message  string    `json:"message"`
+	Value    float64   `json:"value"`
+	Asset    dia.Asset `json:"asset"`
+	Exchange string    `json:"exchange,omitempty"`
+	StartsAt time.Time `json:"startsAt"`
+	Time     time.Time `json:"time"`
+}
+
+// evaluation is the result of evaluating a rule.
+type evaluation struct {
+	firing  bool
+	value   float64
+	message string
+}
+
+// alertState is the deduplication state of a rule.
+type alertState struct {
+	firing       bool
+	startsAt     time.Time
+	lastNotified time.Time
+	// notified is true if the firing alert was sent to webhooks, so that its resolution is sent as well.
+	notified bool
+}
+
+type filterKey struct {
+	filter     string
+	blockchain string
+	address    string
+}
+
+// delivery is a notification waiting to be sent to a webhook.
+type delivery struct {
+	webhook      Webhook
+	notification Notification
+}
+
+// AlertEngine evaluates alert rules on each filters block and notifies webhooks of alerts.
+// Notifications are delivered by a separate worker, so that slow webhooks do not delay the evaluation.
+type AlertEngine struct {
+	datastore  models.Datastore
+	notifier   Notifier
+	deliveries chan delivery
+
+	mu     sync.Mutex
+	config Config
+	states map[string]*alertState
+
+	// Filter points of the previous filters block.
+	filterPoints map[filterKey]dia.FilterPoint
+	started      time.Time
+
+	rpcURLs map[string]string
+	clients map[string]*ethclient.Client
+}
+
+// NewAlertEngine returns an alert engine with @config. Oracle rules use the rpc urls from the chainconfig table.
+func NewAlertEngine(datastore models.Datastore, relDB *models.RelDB, notifier Notifier, config Config) (*AlertEngine, error) {
+	engine := &AlertEngine{
+		datastore:    datastore,
+		notifier:     notifier,
+		config:       config,
+		states:       make(map[string]*alertState),
+		filterPoints: make(map[filterKey]dia.FilterPoint),
+		started:      time.Now(),
+		rpcURLs:      make(map[string]string),
+		clients:      make(map[string]*ethclient.Client),
+	}
+	if notifier != nil {
+		engine.deliveries = make(chan delivery, deliveryQueueSize)
+		go engine.deliver()
+	}
+	if relDB == nil {
+		return engine, nil
+	}
+
+	chainconfigs, err := relDB.GetAllChainConfig()
+	if err != nil {
+		return nil, err
+	}
+	rpcURLs := make(map[string]string)
+	for _, chainconfig := range chainconfigs {
+		rpcURLs[chainconfig.ChainID] = chainconfig.RestURL
+	}
+	blockchains, err := relDB.GetAllBlockchains(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockchain := range blockchains {
+		if rpcURL, ok := rpcURLs[blockchain.ChainID]; ok && blockchain.ChainID != "" {
+			engine.rpcURLs[blockchain.Name] = rpcURL
+		}
+	}
+	return engine, nil
+}
+
+// SetConfig replaces the config of the engine. The state of rules which are kept is preserved.
+func (engine *AlertEngine) SetConfig(config Config) {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	engine.config = config
+	names := make(map[string]bool)
+	for _, rule := range config.Rules {
+		names[rule.Name] = true
+	}
+	for name := range engine.states {
+		if !names[name] {
+			delete(engine.states, name)
+		}
+	}
+}
+
+// ProcessFiltersBlock evaluates all rules after the filters block @block and sends the resulting notifications.
+// Blocks must be processed one after another.
+func (engine *AlertEngine) ProcessFiltersBlock(block *dia.FiltersBlock) {
+	engine.mu.Lock()
+	config := engine.config
+	engine.mu.Unlock()
+
+	now := time.Now()
+	blockPoints := make(map[filterKey]dia.FilterPoint)
+	for _, fp := range block.FiltersBlockData.FilterPoints {
+		blockPoints[filterKey{filter: fp.Name, blockchain: fp.Asset.Blockchain, address: fp.Asset.Address}] = fp
+	}
+
+	for _, rule := range config.Rules {
+		result, err := engine.evaluate(rule, blockPoints, now)
+		if err != nil {
+			log.Errorf("evaluate alert rule %s: %v", rule.Name, err)
+			continue
+		}
+		if notification := engine.update(rule, result, now); notification != nil {
+			engine.send(config, *notification)
+		}
+	}
+
+	for key, fp := range blockPoints {
+		engine.filterPoints[key] = fp
+	}
+}
+
+// update updates the state of @rule with @result and returns a notification if one is due.
+// Alerts are notified when they start firing, every RepeatInterval seconds while they keep firing
+// and once when they are resolved. Silenced alerts are tracked, but not notified.
+func (engine *AlertEngine) update(rule Rule, result evaluation, now time.Time) *Notification {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+
+	state, ok := engine.states[rule.Name]
+	if !ok {
+		state = &alertState{}
+		engine.states[rule.Name] = state
+	}
+	notification := &Notification{
+		Rule:     rule.Name,
+		Type:     rule.Type,
+		Severity: rule.Severity,
+		Message:  result.message,
+		Value:    result.value,
+		Asset:    rule.Asset,
+		Exchange: rule.Exchange,
+		Time:     now,
+	}
+	silenced := engine.config.isSilenced(rule.Name, now)
+
+	if !result.firing {
+		if !state.firing {
+			return nil
+		}
+		notification.Status = StatusResolved
+		notification.StartsAt = state.startsAt
+		wasNotified := state.notified
+		*state = alertState{}
+		if !wasNotified {
+			return nil
+		}
+		return notification
+	}
+
+	if !state.firing {
+		state.firing = true
+		state.startsAt = now
+	}
+	notification.Status = StatusFiring
+	notification.StartsAt = state.startsAt
+	repeatInterval := time.Duration(engine.config.RepeatInterval) * time.Second
+	if silenced || (state.notified && now.Sub(state.lastNotified) < repeatInterval) {
+		return nil
+	}
+	state.notified = true
+	state.lastNotified = now
+	return notification
+}
+
+func (engine *AlertEngine) send(config Config, notification Notification) {
+	log.Infof("alert %s %s: %s", notification.Rule, notification.Status, notification.Message)
+	if engine.notifier == nil {
+		return
+	}
+	for _, webhook := range config.Webhooks {
+		if !webhookAccepts(webhook, notification.Severity) {
+			continue
+		}
+		select {
+		case engine.deliveries <- delivery{webhook: webhook, notification: notification}:
+		default:
+			log.Errorf("delivery queue full, drop notification of webhook %s of alert %s", webhook.Name, notification.Rule)
+		}
+	}
+}
+
+// deliver sends queued notifications to their webhooks.
+func (engine *AlertEngine) deliver() {
+	for d := range engine.deliveries {
+		if err := engine.notifier.Notify(d.webhook, d.notification); err != nil {
+			log.Errorf("notify webhook %s of alert %s: %v", d.webhook.Name, d.notification.Rule, err)
+		}
+	}
+}
+
+func webhookAccepts(webhook Webhook, severity string) bool {
+	if len(webhook.Severities) == 0 {
+		return true
+	}
+	for _, s := range webhook.Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate evaluates @rule at time @now. @blockPoints contains the filter points of the current filters block.
+func (engine *AlertEngine) evaluate(rule Rule, blockPoints map[filterKey]dia.FilterPoint, now time.Time) (result evaluation, err error) {
+	switch rule.Type {
+
+	case RulePriceChange:
+		var current, past *models.AssetQuotation
+		current, err = engine.datastore.GetAssetQuotationCache(rule.Asset)
+		if err != nil {
+			return
+		}
+		past, err = engine.datastore.GetAssetQuotation(rule.Asset, now.Add(-time.Duration(rule.Window)*time.Second))
+		if err != nil {
+			return
+		}
+		if past.Price == 0 {
+			err = errors.New("past price is zero")
+			return
+		}
+		result.value = 100 * (current.Price - past.Price) / past.Price
+		result.firing = math.Abs(result.value) > rule.Threshold
+		result.message = fmt.Sprintf("price of %s changed by %.2f%% from %v to %v within %ds", assetName(rule.Asset), result.value, past.Price, current.Price, rule.Window)
+
+	case RuleStaleQuotation:
+		var quotation *models.AssetQuotation
+		quotation, err = engine.datastore.GetAssetQuotationCache(rule.Asset)
+		if err != nil {
+			return
+		}
+		result = staleEvaluation(now.Sub(quotation.Time), rule.MaxAge, "last quotation of "+assetName(rule.Asset))
+
+	case RuleFilterDeviation:
+		key := filterKey{filter: rule.Filter, blockchain: rule.Asset.Blockchain, address: rule.Asset.Address}
+		current, ok := blockPoints[key]
+		previous, okPrevious := engine.filterPoints[key]
+		if !ok || !okPrevious || previous.Value == 0 {
+			// Nothing to compare. Staleness of filters is covered by staleFilter rules.
+			return
+		}
+		result.value = 100 * (current.Value - previous.Value) / previous.Value
+		result.firing = math.Abs(result.value) > rule.Threshold
+		result.message = fmt.Sprintf("%s of %s deviates by %.2f%% from the previous block: %v -> %v", rule.Filter, assetName(rule.Asset), result.value, previous.Value, current.Value)
+
+	case RuleStaleFilter:
+		key := filterKey{filter: rule.Filter, blockchain: rule.Asset.Blockchain, address: rule.Asset.Address}
+		// Filters blocks repeat the points of assets without trades, so the age is given by the time of the point.
+		lastTime := engine.started
+		if fp, ok := blockPoints[key]; ok {
+			lastTime = fp.Time
+		} else if fp, ok := engine.filterPoints[key]; ok {
+			lastTime = fp.Time
+		}
+		result = staleEvaluation(now.Sub(lastTime), rule.MaxAge, rule.Filter+" of "+assetName(rule.Asset))
+
+	case RuleStaleExchange:
+		var trades []dia.Trade
+		trades, err = engine.datastore.GetLastTrades(rule.Asset, rule.Exchange, 1, false)
+		if len(trades) == 0 {
+			// GetLastTrades looks back 30 days and fails if there is no trade.
+			result.firing = true
+			result.message = fmt.Sprintf("no recent trade of %s on %s: %v", assetName(rule.Asset), rule.Exchange, err)
+			err = nil
+			return
+		}
+		err = nil
+		result = staleEvaluation(now.Sub(trades[0].Time), rule.MaxAge, "last trade of "+assetName(rule.Asset)+" on "+rule.Exchange)
+
+	case RuleStaleOracle, RuleOracleDeviation:
+		var value float64
+		var timestamp time.Time
+		value, timestamp, err = engine.getOracleValue(rule.Oracle)
+		if err != nil {
+			return
+		}
+		if rule.Type == RuleStaleOracle {
+			result = staleEvaluation(now.Sub(timestamp), rule.MaxAge, "last update of "+rule.Oracle.Key+" in oracle "+rule.Oracle.Address)
+			return
+		}
+		var quotation *models.AssetQuotation
+		quotation, err = engine.datastore.GetAssetQuotationCache(rule.Asset)
+		if err != nil {
+			return
+		}
+		if quotation.Price == 0 {
+			err = errors.New("price is zero")
+			return
+		}
+		result.value = 100 * (value - quotation.Price) / quotation.Price
+		result.firing = math.Abs(result.value) > rule.Threshold
+		result.message = fmt.Sprintf("%s in oracle %s deviates by %.2f%% from the price: %v vs %v", rule.Oracle.Key, rule.Oracle.Address, result.value, value, quotation.Price)
+
+	case RuleStaleStock:
+		sources := stockscrapers.Sources
+		if rule.Source != "" {
+			sources = []string{rule.Source}
+		}
+		var last time.Time
+		for _, source := range sources {
+			quotation, err := engine.datastore.GetStockQuotationLatest(source, rule.Stock, now)
+			if err == nil && quotation.Time.After(last) {
+				last = quotation.Time
+			}
+		}
+		age, open := markethours.MarketOf(rule.Stock).StaleAge(last, now)
+		if !open {
+			result.message = "market of " + rule.Stock + " is closed"
+			return
+		}
+		result = staleEvaluation(age, rule.MaxAge, "last quotation of "+rule.Stock)
+	}
+	return
+}
+
+func staleEvaluation(age time.Duration, maxAge int64, subject string) evaluation {
+	return evaluation{
+		firing:  age > time.Duration(maxAge)*time.Second,
+		value:   age.Seconds(),
+		message: fmt.Sprintf("%s is %ds old, maximal age is %ds", subject, int64(age.Seconds()), maxAge),
+	}
+}
+
+// getOracleValue returns the value and the timestamp of the last update of @oracle's key.
+func (engine *AlertEngine) getOracleValue(oracle Oracle) (value float64, timestamp time.Time, err error) {
+	client, ok := engine.clients[oracle.Blockchain]
+	if !ok {
+		rpcURL, ok := engine.rpcURLs[oracle.Blockchain]
+		if !ok {
+			err = fmt.Errorf("no chain config for blockchain %s", oracle.Blockchain)
+			return
+		}
+		client, err = ethclient.Dial(rpcURL)
+		if err != nil {
+			return
+		}
+		engine.clients[oracle.Blockchain] = client
+	}
+	contract, err := diaOracleV2.NewDIAOracleV2Caller(common.HexToAddress(oracle.Address), client)
+	if err != nil {
+		return
+	}
+	rawValue, rawTimestamp, err := contract.GetValue(&bind.CallOpts{}, oracle.Key)
+	if err != nil {
+		return
+	}
+	value, _ = new(big.Float).Quo(new(big.Float).SetInt(rawValue), big.NewFloat(math.Pow10(oracle.Decimals))).Float64()
+	timestamp = time.Unix(rawTimestamp.Int64(), 0)
+	return
+}
+
+func assetName(asset dia.Asset) string {
+	if asset.Symbol != "" {
+		return asset.Symbol
+	}
+	return asset.Blockchain + ":" + asset.Address
+}
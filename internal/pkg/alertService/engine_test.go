@@ -0,0 +1,177 @@
+package alertservice
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []Notification
+	// Deliveries block until release is closed, if it is set.
+	release chan struct{}
+}
+
+func (rn *recordingNotifier) Notify(webhook Webhook, notification Notification) error {
+	if rn.release != nil {
+		<-rn.release
+	}
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.notifications = append(rn.notifications, notification)
+	return nil
+}
+
+// wait waits up to a second until @n notifications were delivered and returns their number.
+func (rn *recordingNotifier) wait(n int) int {
+	deadline := time.Now().Add(time.Second)
+	for {
+		rn.mu.Lock()
+		delivered := len(rn.notifications)
+		rn.mu.Unlock()
+		if delivered >= n || time.Now().After(deadline) {
+			return delivered
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestUpdateDeduplication(t *testing.T) {
+	rule := Rule{Name: "btc-stale", Type: RuleStaleQuotation, Severity: SeverityCritical, MaxAge: 60}
+	engine, _ := NewAlertEngine(nil, nil, nil, Config{Rules: []Rule{rule}, RepeatInterval: 3600})
+	now := time.Now()
+
+	steps := []struct {
+		offset time.Duration
+		firing bool
+		status string
+	}{
+		{0, false, ""},
+		{time.Minute, true, StatusFiring},
+		{2 * time.Minute, true, ""},
+		{30 * time.Minute, true, ""},
+		{62 * time.Minute, true, StatusFiring},
+		{63 * time.Minute, false, StatusResolved},
+		{64 * time.Minute, false, ""},
+	}
+	for i, step := range steps {
+		notification := engine.update(rule, evaluation{firing: step.firing}, now.Add(step.offset))
+		status := ""
+		if notification != nil {
+			status = notification.Status
+		}
+		if status != step.status {
+			t.Errorf("step %d: got notification %q, want %q", i, status, step.status)
+		}
+	}
+}
+
+func TestUpdateSilence(t *testing.T) {
+	rule := Rule{Name: "eth-price", Type: RulePriceChange, Severity: SeverityWarning}
+	now := time.Now()
+	config := Config{
+		Rules:          []Rule{rule},
+		Silences:       []Silence{{Rule: "eth-*", Until: now.Add(time.Hour)}},
+		RepeatInterval: 3600,
+	}
+	engine, _ := NewAlertEngine(nil, nil, nil, config)
+
+	if n := engine.update(rule, evaluation{firing: true}, now); n != nil {
+		t.Error("silenced alert was notified")
+	}
+	// The silence ended while the alert keeps firing.
+	if n := engine.update(rule, evaluation{firing: true}, now.Add(2*time.Hour)); n == nil || n.Status != StatusFiring {
+		t.Error("alert was not notified after the silence ended")
+	} else if !n.StartsAt.Equal(now) {
+		t.Errorf("got start %v, want %v", n.StartsAt, now)
+	}
+
+	// Alerts which were never notified are resolved silently.
+	engine.SetConfig(Config{Rules: []Rule{rule}, Silences: []Silence{{Rule: "*"}}, RepeatInterval: 3600})
+	engine.states = make(map[string]*alertState)
+	engine.update(rule, evaluation{firing: true}, now)
+	if n := engine.update(rule, evaluation{firing: false}, now.Add(time.Minute)); n != nil {
+		t.Error("resolution of a silenced alert was notified")
+	}
+}
+
+func TestSendSeverities(t *testing.T) {
+	notifier := &recordingNotifier{}
+	engine, _ := NewAlertEngine(nil, nil, notifier, Config{})
+	config := Config{Webhooks: []Webhook{
+		{Name: "all", URL: "http://all"},
+		{Name: "critical", URL: "http://critical", Severities: []string{SeverityCritical}},
+	}}
+	engine.send(config, Notification{Rule: "a", Severity: SeverityWarning})
+	engine.send(config, Notification{Rule: "b", Severity: SeverityCritical})
+	if delivered := notifier.wait(3); delivered != 3 {
+		t.Errorf("got %d notifications, want 3", delivered)
+	}
+}
+
+func TestSendDoesNotBlock(t *testing.T) {
+	notifier := &recordingNotifier{release: make(chan struct{})}
+	engine, _ := NewAlertEngine(nil, nil, notifier, Config{})
+	config := Config{Webhooks: []Webhook{{Name: "slow", URL: "http://slow"}}}
+
+	sent := make(chan struct{})
+	go func() {
+		// More notifications than fit into the queue are dropped instead of blocking.
+		for i := 0; i < deliveryQueueSize+10; i++ {
+			engine.send(config, Notification{Rule: "a", Severity: SeverityWarning})
+		}
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("send blocked on a slow webhook")
+	}
+
+	close(notifier.release)
+	// One notification may have been taken by the worker before the queue was full.
+	if delivered := notifier.wait(deliveryQueueSize); delivered < deliveryQueueSize || delivered > deliveryQueueSize+1 {
+		t.Errorf("got %d notifications, want %d", delivered, deliveryQueueSize)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	config := Config{Rules: []Rule{
+		{Name: "a", Type: RuleStaleExchange, MaxAge: 600, Exchange: "Binance"},
+	}}
+	config.Rules[0].Asset.Blockchain = "Ethereum"
+	config.Rules[0].Asset.Address = "0x0000000000000000000000000000000000000000"
+	if err := config.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if config.Rules[0].Severity != SeverityWarning || config.RepeatInterval != defaultRepeatInterval {
+		t.Error("defaults were not set")
+	}
+	config.Rules = append(config.Rules, config.Rules[0])
+	if err := config.validate(); err == nil {
+		t.Error("duplicate rule names were accepted")
+	}
+	config.Rules = []Rule{{Name: "b", Type: RulePriceChange, Threshold: 5}}
+	if err := config.validate(); err == nil {
+		t.Error("priceChange rule without window was accepted")
+	}
+	config.Rules = []Rule{{Name: "c", Type: RuleStaleStock, MaxAge: 300}}
+	if err := config.validate(); err == nil {
+		t.Error("staleStock rule without stock was accepted")
+	}
+	config.Rules[0].Stock = "AAPL"
+	if err := config.validate(); err != nil {
+		t.Errorf("staleStock rule without asset was rejected: %v", err)
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	config, err := GetConfig("alert_rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Rules) == 0 {
+		t.Error("no rules in config file")
+	}
+}
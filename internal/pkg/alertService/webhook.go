@@ -0,0 +1,78 @@
+package alertservice
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	webhookTimeout    = 10 * time.Second
+	webhookRetries    = 3
+	webhookRetryDelay = 2 * time.Second
+	signatureHeader   = "X-DIA-Signature"
+)
+
+// Notifier delivers notifications to webhooks.
+type Notifier interface {
+	Notify(webhook Webhook, notification Notification) error
+}
+
+// WebhookNotifier posts notifications as JSON and retries failed deliveries.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{client: &http.Client{Timeout: webhookTimeout}}
+}
+
+// Notify posts @notification to @webhook. Deliveries are retried on network errors and non-2xx responses.
+func (notifier *WebhookNotifier) Notify(webhook Webhook, notification Notification) (err error) {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return
+	}
+	for i := 0; i < webhookRetries; i++ {
+		if i > 0 {
+			time.Sleep(webhookRetryDelay * time.Duration(i))
+		}
+		if err = notifier.post(webhook, body); err == nil {
+			return
+		}
+	}
+	return
+}
+
+func (notifier *WebhookNotifier) post(webhook Webhook, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		req.Header.Set(signatureHeader, Sign(webhook.Secret, body))
+	}
+	resp, err := notifier.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of @body with @secret. Receivers of webhooks can verify
+// notifications by comparing it with the X-DIA-Signature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvWriter writes gzipped CSV with a header line.
+type csvWriter struct {
+	columns []Column
+	gz      *gzip.Writer
+	csv     *csv.Writer
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	gz := gzip.NewWriter(w)
+	writer := &csvWriter{
+		columns: columns,
+		gz:      gz,
+		csv:     csv.NewWriter(gz),
+		record:  make([]string, len(columns)),
+	}
+	for i, column := range columns {
+		writer.record[i] = column.Name
+	}
+	return writer, writer.csv.Write(writer.record)
+}
+
+func (writer *csvWriter) WriteRow(row []interface{}) error {
+	if err := checkRow(writer.columns, row); err != nil {
+		return err
+	}
+	for i, value := range row {
+		switch v := value.(type) {
+		case time.Time:
+			writer.record[i] = v.UTC().Format(time.RFC3339Nano)
+		case float64:
+			writer.record[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		case string:
+			writer.record[i] = v
+		case bool:
+			writer.record[i] = strconv.FormatBool(v)
+		}
+	}
+	return writer.csv.Write(writer.record)
+}
+
+func (writer *csvWriter) Close() error {
+	writer.csv.Flush()
+	if err := writer.csv.Error(); err != nil {
+		return err
+	}
+	return writer.gz.Close()
+}
@@ -0,0 +1,372 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// Datasets which can be exported.
+const (
+	DatasetTrades     = "trades"
+	DatasetFilters    = "filters"
+	DatasetQuotations = "quotations"
+)
+
+const (
+	// Data is read from influx in windows of this size, so that queries do not time out.
+	DefaultQueryWindow = time.Hour
+	// Files are split after this number of rows.
+	DefaultRowsPerFile = 1000000
+	ManifestFilename   = "manifest.json"
+)
+
+var (
+	// Names of the filters computed by the filtersBlockService, such as MAIR120.
+	filterNamePattern   = regexp.MustCompile(`^(MA|MAIR|MEDIR|EMA|VOL|VWAP)[0-9]+$`)
+	exchangeNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+	tradeColumns = []Column{
+		{Name: "time", Type: ColumnTimestamp},
+		{Name: "exchange", Type: ColumnString},
+		{Name: "pair", Type: ColumnString},
+		{Name: "symbol", Type: ColumnString},
+		{Name: "price", Type: ColumnFloat},
+		{Name: "volume", Type: ColumnFloat},
+		{Name: "estimated_usd_price", Type: ColumnFloat},
+		{Name: "foreign_trade_id", Type: ColumnString},
+		{Name: "verified", Type: ColumnBool},
+		{Name: "quotetoken_blockchain", Type: ColumnString},
+		{Name: "quotetoken_address", Type: ColumnString},
+		{Name: "basetoken_blockchain", Type: ColumnString},
+		{Name: "basetoken_address", Type: ColumnString},
+	}
+	filterColumns = []Column{
+		{Name: "time", Type: ColumnTimestamp},
+		{Name: "filter", Type: ColumnString},
+		{Name: "exchange", Type: ColumnString},
+		{Name: "symbol", Type: ColumnString},
+		{Name: "blockchain", Type: ColumnString},
+		{Name: "address", Type: ColumnString},
+		{Name: "value", Type: ColumnFloat},
+	}
+	quotationColumns = []Column{
+		{Name: "time", Type: ColumnTimestamp},
+		{Name: "symbol", Type: ColumnString},
+		{Name: "blockchain", Type: ColumnString},
+		{Name: "address", Type: ColumnString},
+		{Name: "price", Type: ColumnFloat},
+	}
+)
+
+// Request describes an export of @Dataset for @Asset in the time range (Starttime, Endtime].
+// Exchange restricts trades and filters to an exchange, Filter is the filter of the filters dataset.
+type Request struct {
+	Dataset     string
+	Asset       dia.Asset
+	Exchange    string
+	Filter      string
+	Format      string
+	Starttime   time.Time
+	Endtime     time.Time
+	RowsPerFile int
+	QueryWindow time.Duration
+}
+
+// Manifest describes the files of an export. It is written to manifest.json in the export directory.
+type Manifest struct {
+	Dataset    string         `json:"dataset"`
+	Format     string         `json:"format"`
+	Blockchain string         `json:"blockchain"`
+	Address    string         `json:"address"`
+	Exchange   string         `json:"exchange,omitempty"`
+	Filter     string         `json:"filter,omitempty"`
+	Starttime  time.Time      `json:"starttime"`
+	Endtime    time.Time      `json:"endtime"`
+	Columns    []Column       `json:"columns"`
+	Files      []ManifestFile `json:"files"`
+	Rows       int64          `json:"rows"`
+	Created    time.Time      `json:"created"`
+}
+
+// ManifestFile describes a single file of an export. Starttime and Endtime are the times of its first and last row.
+type ManifestFile struct {
+	Name      string    `json:"name"`
+	Rows      int64     `json:"rows"`
+	Bytes     int64     `json:"bytes"`
+	Starttime time.Time `json:"starttime"`
+	Endtime   time.Time `json:"endtime"`
+}
+
+// Exporter streams data from influx into files.
+type Exporter struct {
+	datastore models.Datastore
+}
+
+func NewExporter(datastore models.Datastore) *Exporter {
+	return &Exporter{datastore: datastore}
+}
+
+// Columns returns the columns of @dataset.
+func Columns(dataset string) ([]Column, error) {
+	switch dataset {
+	case DatasetTrades:
+		return tradeColumns, nil
+	case DatasetFilters:
+		return filterColumns, nil
+	case DatasetQuotations:
+		return quotationColumns, nil
+	}
+	return nil, errors.New("unknown dataset " + dataset)
+}
+
+// Validate returns an error if @request cannot be exported.
+func (request *Request) Validate() error {
+	if _, err := Columns(request.Dataset); err != nil {
+		return err
+	}
+	if request.Format != FormatParquet && request.Format != FormatCSV {
+		return errors.New("format must be parquet or csv")
+	}
+	if request.Dataset == DatasetFilters && request.Filter == "" {
+		return errors.New("filters export needs a filter")
+	}
+	// Exchange and filter are part of influx queries.
+	if request.Filter != "" && !filterNamePattern.MatchString(request.Filter) {
+		return errors.New("unknown filter " + request.Filter)
+	}
+	if request.Exchange != "" && !exchangeNamePattern.MatchString(request.Exchange) {
+		return errors.New("invalid exchange " + request.Exchange)
+	}
+	if !request.Endtime.After(request.Starttime) {
+		return errors.New("endtime must be after starttime")
+	}
+	if request.Asset.Address == "" || request.Asset.Blockchain == "" {
+		return errors.New("asset needs address and blockchain")
+	}
+	return nil
+}
+
+// Run exports the data of @request into @dir and writes the manifest. Data is queried window by window,
+// so that memory usage is bounded by the query window and the number of rows per file.
+func (exporter *Exporter) Run(request Request, dir string) (manifest Manifest, err error) {
+	if err = request.Validate(); err != nil {
+		return
+	}
+	if request.RowsPerFile <= 0 {
+		request.RowsPerFile = DefaultRowsPerFile
+	}
+	if request.QueryWindow <= 0 {
+		request.QueryWindow = DefaultQueryWindow
+	}
+	columns, _ := Columns(request.Dataset)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	manifest = Manifest{
+		Dataset:    request.Dataset,
+		Format:     request.Format,
+		Blockchain: request.Asset.Blockchain,
+		Address:    request.Asset.Address,
+		Exchange:   request.Exchange,
+		Filter:     request.Filter,
+		Starttime:  request.Starttime,
+		Endtime:    request.Endtime,
+		Columns:    columns,
+		Files:      []ManifestFile{},
+		Created:    time.Now(),
+	}
+	files := &fileSequence{request: request, dir: dir, columns: columns}
+
+	for windowStart := request.Starttime; windowStart.Before(request.Endtime); windowStart = windowStart.Add(request.QueryWindow) {
+		windowEnd := windowStart.Add(request.QueryWindow)
+		if windowEnd.After(request.Endtime) {
+			windowEnd = request.Endtime
+		}
+		var rows [][]interface{}
+		rows, err = exporter.fetchRows(request, windowStart, windowEnd)
+		if err != nil {
+			files.abort()
+			return
+		}
+		for _, row := range rows {
+			if err = files.write(row); err != nil {
+				files.abort()
+				return
+			}
+		}
+		log.Infof("exported %s of %s from %v to %v: %d rows", request.Dataset, request.Asset.Address, windowStart, windowEnd, len(rows))
+	}
+	if err = files.close(); err != nil {
+		return
+	}
+
+	manifest.Files = append(manifest.Files, files.done...)
+	for _, f := range manifest.Files {
+		manifest.Rows += f.Rows
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, ManifestFilename), content, 0644)
+	return
+}
+
+// fetchRows returns the rows of @request's dataset in the time range (@starttime, @endtime] in ascending order.
+func (exporter *Exporter) fetchRows(request Request, starttime time.Time, endtime time.Time) (rows [][]interface{}, err error) {
+	switch request.Dataset {
+	case DatasetTrades:
+		var trades []dia.Trade
+		trades, err = exporter.datastore.GetTradesInRange(request.Asset, request.Exchange, starttime, endtime)
+		for _, t := range trades {
+			rows = append(rows, []interface{}{
+				t.Time,
+				t.Source,
+				t.Pair,
+				t.Symbol,
+				t.Price,
+				t.Volume,
+				t.EstimatedUSDPrice,
+				t.ForeignTradeID,
+				t.VerifiedPair,
+				t.QuoteToken.Blockchain,
+				t.QuoteToken.Address,
+				t.BaseToken.Blockchain,
+				t.BaseToken.Address,
+			})
+		}
+	case DatasetFilters:
+		var filterPoints []dia.FilterPoint
+		filterPoints, err = exporter.datastore.GetFilterPointsInRange(request.Filter, request.Asset, request.Exchange, starttime, endtime)
+		for _, fp := range filterPoints {
+			rows = append(rows, []interface{}{
+				fp.Time,
+				fp.Name,
+				request.Exchange,
+				fp.Asset.Symbol,
+				fp.Asset.Blockchain,
+				fp.Asset.Address,
+				fp.Value,
+			})
+		}
+	case DatasetQuotations:
+		var quotations []models.AssetQuotation
+		quotations, err = exporter.datastore.GetAssetQuotations(request.Asset, starttime, endtime)
+		// Quotations are returned in descending order.
+		sort.Slice(quotations, func(i, j int) bool { return quotations[i].Time.Before(quotations[j].Time) })
+		for _, q := range quotations {
+			rows = append(rows, []interface{}{
+				q.Time,
+				q.Asset.Symbol,
+				q.Asset.Blockchain,
+				q.Asset.Address,
+				q.Price,
+			})
+		}
+	}
+	return
+}
+
+// fileSequence writes rows into consecutive files with at most RowsPerFile rows each.
+type fileSequence struct {
+	request Request
+	dir     string
+	columns []Column
+	done    []ManifestFile
+
+	file    *os.File
+	writer  RowWriter
+	current ManifestFile
+}
+
+func (fs *fileSequence) write(row []interface{}) error {
+	if fs.writer != nil && fs.current.Rows >= int64(fs.request.RowsPerFile) {
+		if err := fs.close(); err != nil {
+			return err
+		}
+	}
+	if fs.writer == nil {
+		if err := fs.open(); err != nil {
+			return err
+		}
+	}
+	if err := fs.writer.WriteRow(row); err != nil {
+		return err
+	}
+	timestamp := row[0].(time.Time)
+	if fs.current.Rows == 0 {
+		fs.current.Starttime = timestamp
+	}
+	fs.current.Endtime = timestamp
+	fs.current.Rows++
+	return nil
+}
+
+func (fs *fileSequence) open() error {
+	name := fmt.Sprintf("%s_%s_%s_%05d%s",
+		fs.request.Dataset,
+		strings.ToLower(fs.request.Asset.Blockchain),
+		fs.request.Asset.Address,
+		len(fs.done),
+		FileExtension(fs.request.Format),
+	)
+	file, err := os.Create(filepath.Join(fs.dir, name))
+	if err != nil {
+		return err
+	}
+	writer, err := NewRowWriter(fs.request.Format, file, fs.columns)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	fs.file = file
+	fs.writer = writer
+	fs.current = ManifestFile{Name: name}
+	return nil
+}
+
+// close finishes the current file if there is one.
+func (fs *fileSequence) close() error {
+	if fs.writer == nil {
+		return nil
+	}
+	err := fs.writer.Close()
+	if err != nil {
+		fs.file.Close()
+		return err
+	}
+	info, err := fs.file.Stat()
+	if err != nil {
+		fs.file.Close()
+		return err
+	}
+	fs.current.Bytes = info.Size()
+	if err = fs.file.Close(); err != nil {
+		return err
+	}
+	fs.done = append(fs.done, fs.current)
+	fs.writer = nil
+	fs.file = nil
+	return nil
+}
+
+// abort closes the current file without finishing it.
+func (fs *fileSequence) abort() {
+	if fs.file != nil {
+		fs.file.Close()
+		os.Remove(fs.file.Name())
+	}
+}
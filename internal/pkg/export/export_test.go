@@ -0,0 +1,45 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestRequestValidate(t *testing.T) {
+	start := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	valid := Request{
+		Dataset:   DatasetFilters,
+		Asset:     dia.Asset{Blockchain: dia.ETHEREUM, Address: "0x0000000000000000000000000000000000000000"},
+		Exchange:  "UniswapV3-polygon",
+		Filter:    "MAIR120",
+		Format:    FormatParquet,
+		Starttime: start,
+		Endtime:   start.Add(time.Hour),
+	}
+
+	cases := []struct {
+		name   string
+		modify func(r *Request)
+		valid  bool
+	}{
+		{"valid", func(r *Request) {}, true},
+		{"no exchange", func(r *Request) { r.Exchange = "" }, true},
+		{"unknown dataset", func(r *Request) { r.Dataset = "blocks" }, false},
+		{"unknown format", func(r *Request) { r.Format = "xlsx" }, false},
+		{"missing filter", func(r *Request) { r.Filter = "" }, false},
+		{"unknown filter", func(r *Request) { r.Filter = "MAIR" }, false},
+		{"filter injection", func(r *Request) { r.Filter = "MA120' OR '1'='1" }, false},
+		{"exchange injection", func(r *Request) { r.Exchange = "Binance' OR exchange=~/.*/" }, false},
+		{"empty time range", func(r *Request) { r.Endtime = r.Starttime }, false},
+		{"missing address", func(r *Request) { r.Asset.Address = "" }, false},
+	}
+	for _, c := range cases {
+		request := valid
+		c.modify(&request)
+		if err := request.Validate(); (err == nil) != c.valid {
+			t.Errorf("%s: got error %v, want valid %t", c.name, err, c.valid)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetWriter writes a parquet file with a flat schema of required columns.
+type parquetWriter struct {
+	pw      *writer.CSVWriter
+	columns []Column
+}
+
+func newParquetWriter(w io.Writer, columns []Column) (*parquetWriter, error) {
+	var metadata []string
+	for _, column := range columns {
+		metadata = append(metadata, fmt.Sprintf("name=%s, %s, repetitiontype=REQUIRED", column.Name, parquetType(column.Type)))
+	}
+	pw, err := writer.NewCSVWriterFromWriter(metadata, w, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetWriter{pw: pw, columns: columns}, nil
+}
+
+func (writer *parquetWriter) WriteRow(row []interface{}) error {
+	if err := checkRow(writer.columns, row); err != nil {
+		return err
+	}
+	values := make([]interface{}, len(row))
+	for i, value := range row {
+		if t, ok := value.(time.Time); ok {
+			values[i] = t.UnixNano() / 1e3
+			continue
+		}
+		values[i] = value
+	}
+	return writer.pw.Write(values)
+}
+
+// Close writes the buffered rows and the footer.
+func (writer *parquetWriter) Close() error {
+	return writer.pw.WriteStop()
+}
+
+// parquetType returns the schema metadata of a column of @columnType.
+func parquetType(columnType string) string {
+	switch columnType {
+	case ColumnTimestamp:
+		return "type=INT64, convertedtype=TIMESTAMP_MICROS"
+	case ColumnFloat:
+		return "type=DOUBLE"
+	case ColumnBool:
+		return "type=BOOLEAN"
+	}
+	return "type=BYTE_ARRAY, convertedtype=UTF8"
+}
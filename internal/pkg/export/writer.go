@@ -0,0 +1,80 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Output formats of exports.
+const (
+	FormatParquet = "parquet"
+	FormatCSV     = "csv"
+)
+
+// Column types. They map to the physical types INT64 (timestamp in microseconds), DOUBLE, BYTE_ARRAY (UTF8)
+// and BOOLEAN in parquet files.
+const (
+	ColumnTimestamp = "timestamp"
+	ColumnFloat     = "float"
+	ColumnString    = "string"
+	ColumnBool      = "bool"
+)
+
+// Column describes a column of an exported dataset.
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// RowWriter writes rows of a fixed schema to a file. Values of a row must have the Go types
+// time.Time, float64, string and bool corresponding to the column types.
+type RowWriter interface {
+	WriteRow(row []interface{}) error
+	// Close flushes all buffered rows. It does not close the underlying writer.
+	Close() error
+}
+
+// NewRowWriter returns a writer for @format which writes rows with @columns to @w.
+func NewRowWriter(format string, w io.Writer, columns []Column) (RowWriter, error) {
+	switch format {
+	case FormatParquet:
+		return newParquetWriter(w, columns)
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	}
+	return nil, errors.New("unknown export format " + format)
+}
+
+// FileExtension returns the extension of files in @format.
+func FileExtension(format string) string {
+	if format == FormatCSV {
+		return ".csv.gz"
+	}
+	return "." + format
+}
+
+// checkRow returns an error if the values of @row do not match @columns.
+func checkRow(columns []Column, row []interface{}) error {
+	if len(row) != len(columns) {
+		return fmt.Errorf("row has %d values for %d columns", len(row), len(columns))
+	}
+	for i, column := range columns {
+		var ok bool
+		switch column.Type {
+		case ColumnTimestamp:
+			_, ok = row[i].(time.Time)
+		case ColumnFloat:
+			_, ok = row[i].(float64)
+		case ColumnString:
+			_, ok = row[i].(string)
+		case ColumnBool:
+			_, ok = row[i].(bool)
+		}
+		if !ok {
+			return fmt.Errorf("value %v of column %s is not of type %s", row[i], column.Name, column.Type)
+		}
+	}
+	return nil
+}
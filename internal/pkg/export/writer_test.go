@@ -0,0 +1,124 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+var testColumns = []Column{
+	{Name: "time", Type: ColumnTimestamp},
+	{Name: "price", Type: ColumnFloat},
+	{Name: "symbol", Type: ColumnString},
+	{Name: "verified", Type: ColumnBool},
+}
+
+func testRows() [][]interface{} {
+	var rows [][]interface{}
+	start := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		rows = append(rows, []interface{}{start.Add(time.Duration(i) * time.Second), float64(i) / 2, "BTC", i%3 == 0})
+	}
+	return rows
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewRowWriter(FormatCSV, &buf, testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range testRows() {
+		if err = writer.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(gz).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 11 {
+		t.Fatalf("got %d records, want 11", len(records))
+	}
+	if records[0][0] != "time" || records[0][3] != "verified" {
+		t.Errorf("unexpected header %v", records[0])
+	}
+	if got := records[2]; got[0] != "2021-05-01T00:00:01Z" || got[1] != "0.5" || got[2] != "BTC" || got[3] != "false" {
+		t.Errorf("unexpected record %v", got)
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewRowWriter(FormatParquet, &buf, testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := testRows()
+	for _, row := range rows {
+		if err = writer.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := buffer.NewBufferFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.ReadStop()
+	if got := pr.GetNumRows(); got != int64(len(rows)) {
+		t.Fatalf("got %d rows, want %d", got, len(rows))
+	}
+
+	for i, column := range testColumns {
+		values, _, _, err := pr.ReadColumnByPath("parquet_go_root\x01"+column.Name, int64(len(rows)))
+		if err != nil {
+			t.Fatalf("read column %s: %v", column.Name, err)
+		}
+		if len(values) != len(rows) {
+			t.Fatalf("column %s: got %d values, want %d", column.Name, len(values), len(rows))
+		}
+		for j, value := range values {
+			want := rows[j][i]
+			if ts, ok := want.(time.Time); ok {
+				want = ts.UnixNano() / 1e3
+			}
+			if value != want {
+				t.Errorf("column %s, row %d: got %v, want %v", column.Name, j, value, want)
+			}
+		}
+	}
+}
+
+func TestWriteRowTypeMismatch(t *testing.T) {
+	writer, err := NewRowWriter(FormatCSV, new(bytes.Buffer), testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = writer.WriteRow([]interface{}{time.Now(), "1.0", "BTC", true}); err == nil {
+		t.Error("expected error for string in float column")
+	}
+	if err = writer.WriteRow([]interface{}{time.Now()}); err == nil {
+		t.Error("expected error for missing values")
+	}
+}
@@ -52,6 +52,13 @@ func removeOutliersScaled(samples []float64, scale float64) ([]float64, []int) {
 	return samples[lowerIndex:upperIndex], indexBounds
 }
 
+// iqrBounds returns the bounds outside of which samples are removed by removeOutliersScaled.
+func iqrBounds(samples []float64, scale float64) (lower float64, upper float64) {
+	Q1, Q3 := computeQuartiles(append([]float64{}, samples...))
+	IQR := Q3 - Q1
+	return Q1 - scale*IQR, Q3 + scale*IQR
+}
+
 // computeMean returns the weighted mean of @samples with @weights.
 // Special case of non-weighted mean is obtained by setting weights to constant 1-slice.
 func computeMean(samples []float64, weights []float64) (mean float64, err error) {
@@ -23,6 +23,11 @@ type FilterMAIR struct {
 	value       float64
 	filterName  string
 	modified    bool
+	// Trades of the current block which are checked against the outlier bounds, and the
+	// number of checked and rejected trades by exchange in the last block.
+	blockTrades []dia.Trade
+	checked     map[string]int64
+	rejected    map[string]int64
 }
 
 //NewFilterMAIR returns a FilterMAIR
@@ -53,6 +58,18 @@ func (filter *FilterMAIR) compute(trade dia.Trade) {
 	}
 	filter.fill(trade)
 	filter.lastTrade = trade
+	filter.blockTrades = append(filter.blockTrades, trade)
+}
+
+// observe checks @trade against the outlier bounds of the current block without adding it to the samples.
+func (filter *FilterMAIR) observe(trade dia.Trade) {
+	filter.blockTrades = append(filter.blockTrades, trade)
+}
+
+// outliers returns the number of trades by exchange which were checked against the outlier bounds
+// in the last block, and the number of those outside of the bounds.
+func (filter *FilterMAIR) outliers() (checked map[string]int64, rejected map[string]int64) {
+	return filter.checked, filter.rejected
 }
 
 // fill fills up the 120 seconds slots with trades.
@@ -93,12 +110,25 @@ func (filter *FilterMAIR) FinalCompute(t time.Time) float64 {
 }
 
 func (filter *FilterMAIR) finalCompute(t time.Time) float64 {
+	blockTrades := filter.blockTrades
+	filter.blockTrades = nil
+	filter.checked = make(map[string]int64)
+	filter.rejected = make(map[string]int64)
 	if filter.lastTrade == (dia.Trade{}) {
 		return 0.0
 	}
 	// Add the last trade again to compensate for the delay since measurement to EOB
 	// adopted behaviour from FilterMA
 	filter.processDataPoint(filter.lastTrade)
+	if len(filter.prices) > 1 {
+		lower, upper := iqrBounds(filter.prices, 1.5)
+		for _, trade := range blockTrades {
+			filter.checked[trade.Source]++
+			if trade.EstimatedUSDPrice < lower || trade.EstimatedUSDPrice > upper {
+				filter.rejected[trade.Source]++
+			}
+		}
+	}
 	cleanPrices, bounds := removeOutliers(filter.prices)
 	mean, err := computeMean(cleanPrices, filter.volumes[bounds[0]:bounds[1]])
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/cnf/structhash"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	log "github.com/sirupsen/logrus"
 )
@@ -51,6 +52,7 @@ type FiltersBlockService struct {
 	calculationValues    []int
 	previousBlockFilters []dia.FilterPoint
 	datastore            models.Datastore
+	healthScorer         *ExchangeHealthScorer
 }
 
 // NewFiltersBlockService returns a new FiltersBlockService and
@@ -75,6 +77,13 @@ func NewFiltersBlockService(previousBlockFilters []dia.FilterPoint, datastore mo
 	return s
 }
 
+// SetExchangeHealthScorer enables health scoring of exchanges. Scores are stored with each block
+// and applied to the filters across all exchanges in the next block according to the scorer's policy.
+// It must be called before the first trades block is processed.
+func (s *FiltersBlockService) SetExchangeHealthScorer(scorer *ExchangeHealthScorer) {
+	s.healthScorer = scorer
+}
+
 // mainLoop runs processTradesBlock until FiltersBlockService @s is shut down.
 func (s *FiltersBlockService) mainLoop() {
 	for {
@@ -105,6 +114,7 @@ func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 		s.computeFilters(trade, trade.Source)
 	}
 
+	metrics.ObserveSince(metrics.FilterComputeDuration.WithLabelValues("compute"), t0)
 	log.Info("time spent for create and compute filters: ", time.Since(t0))
 	log.Info("filter begin time: ", tb.TradesBlockData.BeginTime)
 	resultFilters := []dia.FilterPoint{}
@@ -120,8 +130,13 @@ func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 			}
 		}
 	}
+	metrics.ObserveSince(metrics.FilterComputeDuration.WithLabelValues("finalCompute"), t0)
 	log.Info("time spent for final compute: ", time.Since(t0))
 
+	if s.healthScorer != nil {
+		s.scoreExchanges(tb)
+	}
+
 	resultFilters = addMissingPoints(s.previousBlockFilters, resultFilters)
 
 	s.previousBlockFilters = resultFilters
@@ -157,6 +172,7 @@ func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 			}
 		}
 	}
+	metrics.ObserveSince(metrics.FilterComputeDuration.WithLabelValues("save"), t0)
 	log.Info("time spent for save filters: ", time.Since(t0))
 
 	err = s.datastore.ExecuteRedisPipe()
@@ -176,6 +192,37 @@ func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 
 }
 
+// scoreExchanges scores the exchanges with the trades in @tb and the outliers rejected by the
+// MAIR filters across all exchanges, and stores the scores.
+func (s *FiltersBlockService) scoreExchanges(tb *dia.TradesBlock) {
+	checked := make(map[string]int64)
+	rejected := make(map[string]int64)
+	for fa, filters := range s.filters {
+		if fa.Source != "" {
+			continue
+		}
+		for _, f := range filters {
+			mair, ok := f.(*FilterMAIR)
+			if !ok || mair.filterName != dia.FilterKing {
+				continue
+			}
+			mairChecked, mairRejected := mair.outliers()
+			for exchange, count := range mairChecked {
+				checked[exchange] += count
+			}
+			for exchange, count := range mairRejected {
+				rejected[exchange] += count
+			}
+		}
+	}
+	for _, health := range s.healthScorer.ScoreBlock(tb, checked, rejected) {
+		err := s.datastore.SetExchangeHealth(health)
+		if err != nil {
+			log.Error("set exchange health: ", err)
+		}
+	}
+}
+
 func (s *FiltersBlockService) createFilters(asset dia.Asset, exchange string, BeginTime time.Time) {
 	fa := filtersAsset{
 		Identifier: getIdentifier(asset),
@@ -198,8 +245,34 @@ func (s *FiltersBlockService) computeFilters(t dia.Trade, exchange string) {
 		Identifier: getIdentifier(t.QuoteToken),
 		Source:     exchange,
 	}
+	if exchange != "" || s.healthScorer == nil {
+		for _, f := range s.filters[fa] {
+			f.compute(t)
+		}
+		return
+	}
+
+	// Trades of unhealthy exchanges are excluded from or down-weighted in the filters across all exchanges.
+	weight := s.healthScorer.Weight(t.Source)
+	weightedTrade := t
+	weightedTrade.Volume *= weight
 	for _, f := range s.filters[fa] {
-		f.compute(t)
+		switch filter := f.(type) {
+		case *FilterVOL:
+			// Volumes are neither weighted nor excluded.
+			filter.compute(t)
+		case *FilterMAIR:
+			if weight == 0 {
+				// Excluded trades are still checked for outliers, so that their exchange can recover.
+				filter.observe(t)
+				continue
+			}
+			filter.compute(weightedTrade)
+		default:
+			if weight > 0 {
+				f.compute(weightedTrade)
+			}
+		}
 	}
 }
 
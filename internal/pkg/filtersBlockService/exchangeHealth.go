@@ -0,0 +1,228 @@
+package filters
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Weights of the component scores in the health score of an exchange.
+const (
+	weightTradeFrequency = 0.3
+	weightOutliers       = 0.25
+	weightDeviation      = 0.25
+	weightVolume         = 0.2
+)
+
+const (
+	// Relative deviation from the cross-exchange median at which the deviation score reaches 0.
+	maxPriceDeviation = 0.05
+	// Z-scores of the USD volume below volumeZScoreLow are normal, the volume score reaches 0 at volumeZScoreHigh.
+	volumeZScoreLow  = 3
+	volumeZScoreHigh = 10
+	// Number of blocks before volume anomalies are scored, and smoothing factor of the volume's moving average.
+	volumeWarmupBlocks = 10
+	volumeAlpha        = 0.05
+)
+
+// ExchangeHealthPolicy determines how health scores are used in the filters across all exchanges.
+// Trades of exchanges with a score below MinScore are excluded. If Weighted is true, the volumes of trades
+// are multiplied by the score of their exchange in the volume weighted filters.
+type ExchangeHealthPolicy struct {
+	MinScore float64
+	Weighted bool
+}
+
+// ExchangeHealthScorer computes health scores of exchanges from trades blocks.
+type ExchangeHealthScorer struct {
+	mu             sync.RWMutex
+	watchdogDelays map[string]int
+	lastTradeTimes map[string]time.Time
+	volumes        map[string]*movingStats
+	scores         map[string]dia.ExchangeHealth
+	policy         ExchangeHealthPolicy
+}
+
+// movingStats is an exponentially weighted moving average and variance.
+type movingStats struct {
+	mean     float64
+	variance float64
+	n        int
+}
+
+func (ms *movingStats) add(x float64) {
+	if ms.n == 0 {
+		ms.mean = x
+	} else {
+		diff := x - ms.mean
+		incr := volumeAlpha * diff
+		ms.mean += incr
+		ms.variance = (1 - volumeAlpha) * (ms.variance + diff*incr)
+	}
+	ms.n++
+}
+
+// NewExchangeHealthScorer returns a scorer for the exchanges in @exchanges. Trade frequency is
+// only scored for exchanges with a positive WatchdogDelay. These are scored from the start, so
+// that exchanges which never trade lose their trade frequency score as well.
+func NewExchangeHealthScorer(exchanges []dia.Exchange, policy ExchangeHealthPolicy) *ExchangeHealthScorer {
+	scorer := &ExchangeHealthScorer{
+		watchdogDelays: make(map[string]int),
+		lastTradeTimes: make(map[string]time.Time),
+		volumes:        make(map[string]*movingStats),
+		scores:         make(map[string]dia.ExchangeHealth),
+		policy:         policy,
+	}
+	now := time.Now()
+	for _, exchange := range exchanges {
+		scorer.watchdogDelays[exchange.Name] = exchange.WatchdogDelay
+		if exchange.WatchdogDelay > 0 {
+			scorer.lastTradeTimes[exchange.Name] = now
+		}
+	}
+	return scorer
+}
+
+// Score returns the current health score of @exchange. Exchanges without a score are considered healthy.
+func (scorer *ExchangeHealthScorer) Score(exchange string) float64 {
+	scorer.mu.RLock()
+	defer scorer.mu.RUnlock()
+	health, ok := scorer.scores[exchange]
+	if !ok {
+		return 1
+	}
+	return health.Score
+}
+
+// Weight returns the factor for volumes of trades on @exchange in filters across all exchanges
+// according to the policy. A weight of 0 excludes the exchange.
+func (scorer *ExchangeHealthScorer) Weight(exchange string) float64 {
+	score := scorer.Score(exchange)
+	if score < scorer.policy.MinScore {
+		return 0
+	}
+	if scorer.policy.Weighted {
+		return score
+	}
+	return 1
+}
+
+// ScoreBlock updates the health scores of all exchanges with the trades in @tb and returns them.
+// @checked and @rejected are the numbers of trades by exchange which the MAIR filters across all
+// exchanges checked against their outlier bounds and rejected in the same block.
+func (scorer *ExchangeHealthScorer) ScoreBlock(tb *dia.TradesBlock, checked map[string]int64, rejected map[string]int64) []dia.ExchangeHealth {
+	scorer.mu.Lock()
+	defer scorer.mu.Unlock()
+
+	endTime := tb.TradesBlockData.EndTime
+	trades := tb.TradesBlockData.Trades
+
+	tradesCount := make(map[string]int64)
+	volumesUSD := make(map[string]float64)
+	// Prices of each asset by exchange.
+	assetPrices := make(map[string]map[string][]float64)
+	assetVolumes := make(map[string]map[string]float64)
+
+	for _, trade := range trades {
+		exchange := trade.Source
+		tradesCount[exchange]++
+		if trade.Time.After(scorer.lastTradeTimes[exchange]) {
+			scorer.lastTradeTimes[exchange] = trade.Time
+		}
+		if trade.EstimatedUSDPrice <= 0 {
+			continue
+		}
+		volumesUSD[exchange] += math.Abs(trade.Volume) * trade.EstimatedUSDPrice
+		id := getIdentifier(trade.QuoteToken)
+		if _, ok := assetPrices[id]; !ok {
+			assetPrices[id] = make(map[string][]float64)
+			assetVolumes[id] = make(map[string]float64)
+		}
+		assetPrices[id][exchange] = append(assetPrices[id][exchange], trade.EstimatedUSDPrice)
+		assetVolumes[id][exchange] += math.Abs(trade.Volume) * trade.EstimatedUSDPrice
+	}
+
+	deviationSums := make(map[string]float64)
+	deviationWeights := make(map[string]float64)
+	for id, pricesByExchange := range assetPrices {
+		// Deviation of each exchange's median price from the median across exchanges.
+		if len(pricesByExchange) < 2 {
+			continue
+		}
+		medians := make(map[string]float64)
+		var allMedians []float64
+		for exchange, prices := range pricesByExchange {
+			medians[exchange] = computeMedian(append([]float64{}, prices...))
+			allMedians = append(allMedians, medians[exchange])
+		}
+		crossMedian := computeMedian(allMedians)
+		if crossMedian == 0 {
+			continue
+		}
+		for exchange, median := range medians {
+			weight := assetVolumes[id][exchange]
+			deviationSums[exchange] += weight * math.Abs(median-crossMedian) / crossMedian
+			deviationWeights[exchange] += weight
+		}
+	}
+
+	var healths []dia.ExchangeHealth
+	for exchange := range scorer.lastTradeTimes {
+		previous, ok := scorer.scores[exchange]
+		if !ok {
+			previous = dia.ExchangeHealth{OutlierScore: 1, DeviationScore: 1, VolumeScore: 1}
+		}
+		health := dia.ExchangeHealth{
+			Exchange:          exchange,
+			Trades:            tradesCount[exchange],
+			Time:              endTime,
+			SecondsSinceTrade: math.Max(0, endTime.Sub(scorer.lastTradeTimes[exchange]).Seconds()),
+			// Components which need trades keep their values in blocks without trades.
+			OutlierScore:   previous.OutlierScore,
+			DeviationScore: previous.DeviationScore,
+			VolumeScore:    previous.VolumeScore,
+		}
+
+		health.TradeFrequencyScore = 1
+		if delay := float64(scorer.watchdogDelays[exchange]); delay > 0 && health.SecondsSinceTrade > delay {
+			health.TradeFrequencyScore = delay / health.SecondsSinceTrade
+		}
+
+		if checked[exchange] > 0 {
+			health.OutlierShare = float64(rejected[exchange]) / float64(checked[exchange])
+			health.OutlierScore = 1 - health.OutlierShare
+		}
+		if deviationWeights[exchange] > 0 {
+			health.PriceDeviation = deviationSums[exchange] / deviationWeights[exchange]
+			health.DeviationScore = clamp(1 - health.PriceDeviation/maxPriceDeviation)
+		}
+
+		health.VolumeUSD = volumesUSD[exchange]
+		stats, ok := scorer.volumes[exchange]
+		if !ok {
+			stats = &movingStats{}
+			scorer.volumes[exchange] = stats
+		}
+		if stats.n >= volumeWarmupBlocks && stats.variance > 0 {
+			health.VolumeZScore = (health.VolumeUSD - stats.mean) / math.Sqrt(stats.variance)
+			health.VolumeScore = clamp(1 - (health.VolumeZScore-volumeZScoreLow)/(volumeZScoreHigh-volumeZScoreLow))
+		} else if stats.n >= volumeWarmupBlocks {
+			health.VolumeScore = 1
+		}
+		stats.add(health.VolumeUSD)
+
+		health.Score = weightTradeFrequency*health.TradeFrequencyScore +
+			weightOutliers*health.OutlierScore +
+			weightDeviation*health.DeviationScore +
+			weightVolume*health.VolumeScore
+		scorer.scores[exchange] = health
+		healths = append(healths, health)
+	}
+	return healths
+}
+
+func clamp(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
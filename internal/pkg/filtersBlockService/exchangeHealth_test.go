@@ -0,0 +1,119 @@
+package filters
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func healthTestBlock(endTime time.Time, prices map[string][]float64) *dia.TradesBlock {
+	asset := dia.Asset{Symbol: "ETH", Blockchain: "Ethereum", Address: "0x0000000000000000000000000000000000000000"}
+	tb := &dia.TradesBlock{}
+	tb.TradesBlockData.BeginTime = endTime.Add(-time.Duration(dia.BlockSizeSeconds) * time.Second)
+	tb.TradesBlockData.EndTime = endTime
+	var exchanges []string
+	for exchange := range prices {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	// Each trade has its own second, ordered by time and ending with the block.
+	var count int
+	for _, exchangePrices := range prices {
+		count += len(exchangePrices)
+	}
+	second := dia.BlockSizeSeconds - count
+	for i := 0; ; i++ {
+		added := false
+		for _, exchange := range exchanges {
+			if i >= len(prices[exchange]) {
+				continue
+			}
+			tb.TradesBlockData.Trades = append(tb.TradesBlockData.Trades, dia.Trade{
+				Source:            exchange,
+				QuoteToken:        asset,
+				EstimatedUSDPrice: prices[exchange][i],
+				Volume:            1,
+				Time:              tb.TradesBlockData.BeginTime.Add(time.Duration(second+1) * time.Second),
+			})
+			second++
+			added = true
+		}
+		if !added {
+			return tb
+		}
+	}
+}
+
+// scoreHealthTestBlock runs the trades in @tb through a MAIR filter and scores the block
+// with the filter's outliers.
+func scoreHealthTestBlock(scorer *ExchangeHealthScorer, tb *dia.TradesBlock) []dia.ExchangeHealth {
+	filter := NewFilterMAIR(dia.Asset{}, "", tb.TradesBlockData.BeginTime, dia.BlockSizeSeconds)
+	for _, trade := range tb.TradesBlockData.Trades {
+		if scorer.Weight(trade.Source) == 0 {
+			filter.observe(trade)
+			continue
+		}
+		filter.compute(trade)
+	}
+	filter.finalCompute(tb.TradesBlockData.EndTime)
+	checked, rejected := filter.outliers()
+	return scorer.ScoreBlock(tb, checked, rejected)
+}
+
+func TestExchangeHealthScorer(t *testing.T) {
+	exchanges := []dia.Exchange{{Name: "A", WatchdogDelay: 60}, {Name: "B", WatchdogDelay: 60}, {Name: "C", WatchdogDelay: 60}, {Name: "D", WatchdogDelay: 60}, {Name: "E", WatchdogDelay: 60}}
+	scorer := NewExchangeHealthScorer(exchanges, ExchangeHealthPolicy{MinScore: 0.8})
+	now := time.Now().Add(10 * time.Minute)
+
+	healths := scoreHealthTestBlock(scorer, healthTestBlock(now, map[string][]float64{
+		"A": {100, 100, 100, 100},
+		"B": {100, 100, 100, 100},
+		"C": {110, 111, 109, 110},
+		"D": {100, 100, 100, 250},
+	}))
+	if len(healths) != 5 {
+		t.Fatalf("got %d scores, want 5", len(healths))
+	}
+	if got := scorer.Score("E"); got > 0.8 {
+		t.Errorf("score of exchange without trades is %v", got)
+	}
+	if scorer.Score("C") >= scorer.Score("A") {
+		t.Errorf("deviating exchange C has score %v, not below A with %v", scorer.Score("C"), scorer.Score("A"))
+	}
+	for _, health := range healths {
+		if health.Exchange == "C" && health.DeviationScore != 0 {
+			t.Errorf("unexpected deviation score of C: %+v", health)
+		}
+		if health.Exchange == "C" && health.OutlierShare != 1 {
+			t.Errorf("unexpected outlier share of C: %+v", health)
+		}
+		if health.Exchange == "D" && health.OutlierShare != 0.25 {
+			t.Errorf("unexpected outlier share of D: %+v", health)
+		}
+	}
+	if scorer.Weight("A") != 1 || scorer.Weight("C") != 0 {
+		t.Errorf("got weights %v and %v, want 1 and 0", scorer.Weight("A"), scorer.Weight("C"))
+	}
+
+	// Trades of the excluded exchange C are still checked for outliers.
+	healths = scoreHealthTestBlock(scorer, healthTestBlock(now.Add(2*time.Minute), map[string][]float64{
+		"A": {100, 100, 100, 100},
+		"C": {100, 100},
+	}))
+	for _, health := range healths {
+		if health.Exchange == "C" && (health.Trades != 2 || health.OutlierShare != 0) {
+			t.Errorf("unexpected health of excluded exchange C: %+v", health)
+		}
+	}
+
+	// A stops trading for 10 minutes.
+	scoreHealthTestBlock(scorer, healthTestBlock(now.Add(12*time.Minute), map[string][]float64{"B": {100}}))
+	if got := scorer.Score("A"); got > 0.8 {
+		t.Errorf("score of silent exchange is %v", got)
+	}
+	if scorer.Score("unknown") != 1 {
+		t.Error("unknown exchanges must be considered healthy")
+	}
+}
@@ -0,0 +1,216 @@
+package indexengine
+
+import (
+	"sync"
+
+	"github.com/cnf/structhash"
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// indexState is the current composition of an index.
+type indexState struct {
+	definition dia.IndexDefinition
+	rebalance  *dia.IndexRebalance
+	// Volatility ratios of the constituents from the latest rebalance proposal.
+	volatility map[assetKey]dia.VolatilityRatio
+}
+
+// Engine computes the values of all indices defined in postgres on each filters block.
+type Engine struct {
+	datastore models.Datastore
+	relDB     models.RelDatastore
+	filter    string
+
+	mu      sync.Mutex
+	indices map[string]*indexState
+	// Latest filter points of @filter.
+	prices map[assetKey]dia.FilterPoint
+}
+
+// NewEngine returns an engine computing indices from the filter points of the filter @filter.
+func NewEngine(datastore models.Datastore, relDB models.RelDatastore, filter string) *Engine {
+	return &Engine{
+		datastore: datastore,
+		relDB:     relDB,
+		filter:    filter,
+		indices:   make(map[string]*indexState),
+		prices:    make(map[assetKey]dia.FilterPoint),
+	}
+}
+
+// LoadIndices reads the index definitions from postgres. The composition of indices which are loaded for the
+// first time is restored from their latest rebalancing. Invalid definitions are skipped.
+func (e *Engine) LoadIndices() error {
+	definitions, err := e.relDB.GetIndexDefinitions()
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	indices := make(map[string]*indexState)
+	for _, definition := range definitions {
+		if err := ValidateDefinition(definition); err != nil {
+			log.Error("index definition: ", err)
+			continue
+		}
+		state, ok := e.indices[definition.Symbol]
+		if !ok {
+			state = &indexState{}
+			rebalance, err := e.relDB.GetLatestIndexRebalance(definition.Symbol)
+			if err == nil {
+				state.rebalance = &rebalance
+			} else {
+				log.Infof("index %s has not been rebalanced yet", definition.Symbol)
+			}
+		}
+		state.definition = definition
+		indices[definition.Symbol] = state
+	}
+	e.indices = indices
+	return nil
+}
+
+// ProcessFiltersBlock updates the prices with the filter points of @fb and computes all indices.
+func (e *Engine) ProcessFiltersBlock(fb *dia.FiltersBlock) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, point := range fb.FiltersBlockData.FilterPoints {
+		if point.Name != e.filter {
+			continue
+		}
+		e.prices[keyOf(point.Asset)] = point
+	}
+	for symbol, state := range e.indices {
+		block, err := e.computeIndex(state, fb)
+		if err != nil {
+			log.Errorf("compute index %s: %v", symbol, err)
+			continue
+		}
+		err = e.datastore.SetIndexValue(symbol, block.IndexBlockData.IndexValue, block.IndexBlockData.Time)
+		if err != nil {
+			log.Errorf("set value of index %s: %v", symbol, err)
+		}
+		err = e.datastore.SetIndexBlockCache(symbol, block)
+		if err != nil {
+			log.Errorf("set index block of %s: %v", symbol, err)
+		}
+	}
+}
+
+// computeIndex returns the index block of @state at the end of @fb, rebalancing the index if the
+// constituents changed or the rebalancing schedule is due.
+func (e *Engine) computeIndex(state *indexState, fb *dia.FiltersBlock) (*dia.IndexBlock, error) {
+	t := fb.FiltersBlockData.EndTime
+	prices := make(map[assetKey]float64)
+	for key, point := range e.prices {
+		prices[key] = point.Value
+	}
+
+	if state.rebalance == nil {
+		rebalance, err := Rebalance(state.definition, prices, e.supplies(state.definition), state.definition.BaseValue, 0, t, ReasonInitial)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.storeRebalance(state, rebalance); err != nil {
+			return nil, err
+		}
+	}
+
+	value, weights, err := Value(*state.rebalance, prices)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := ""
+	if ConstituentsChanged(state.definition, *state.rebalance) {
+		reason = ReasonConstituents
+	} else if due, err := RebalanceDue(state.definition.Rebalancing, state.rebalance.Time, t); err != nil {
+		return nil, err
+	} else if due {
+		reason = ReasonScheduled
+	}
+	if reason != "" {
+		rebalance, err := Rebalance(state.definition, prices, e.supplies(state.definition), value, state.rebalance.Divisor, t, reason)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.storeRebalance(state, rebalance); err != nil {
+			return nil, err
+		}
+		value, weights, err = Value(rebalance, prices)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	block := &dia.IndexBlock{
+		IndexBlockData: dia.IndexBlockData{
+			FiltersBlockHash:    fb.BlockHash,
+			IndexElementsNumber: len(state.rebalance.Constituents),
+			Time:                t,
+			IndexValue:          value,
+		},
+	}
+	for i, constituent := range state.rebalance.Constituents {
+		block.IndexBlockData.IndexElements = append(block.IndexBlockData.IndexElements, dia.IndexElement{
+			Name:            constituent.Asset.Name,
+			Symbol:          constituent.Asset.Symbol,
+			Percentage:      weights[i],
+			FilteredPoint:   e.prices[keyOf(constituent.Asset)],
+			Supply:          dia.Supply{Asset: constituent.Asset, CirculatingSupply: constituent.Supply},
+			VolatilityRatio: state.volatility[keyOf(constituent.Asset)],
+		})
+	}
+	hash, err := structhash.Hash(block.IndexBlockData, 1)
+	if err != nil {
+		return nil, err
+	}
+	block.BlockHash = hash
+	return block, nil
+}
+
+// SetProposal attaches the volatility ratios of the candidates evaluated by @proposal to the elements of the
+// blocks of its index.
+func (e *Engine) SetProposal(proposal dia.IndexRebalanceProposal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	state, ok := e.indices[proposal.Symbol]
+	if !ok {
+		return
+	}
+	state.volatility = make(map[assetKey]dia.VolatilityRatio)
+	for _, evaluation := range proposal.Evaluations {
+		state.volatility[keyOf(evaluation.Asset)] = evaluation.VolatilityRatio
+	}
+}
+
+// storeRebalance stores @rebalance for auditability and makes it the composition of @state.
+func (e *Engine) storeRebalance(state *indexState, rebalance dia.IndexRebalance) error {
+	if err := e.relDB.SetIndexRebalance(rebalance); err != nil {
+		return err
+	}
+	log.Infof("rebalanced index %s (%s): value %v, divisor %v -> %v", rebalance.Symbol, rebalance.Reason, rebalance.IndexValue, rebalance.PreviousDivisor, rebalance.Divisor)
+	state.rebalance = &rebalance
+	return nil
+}
+
+// supplies returns the latest circulating supplies of the constituents of @definition.
+func (e *Engine) supplies(definition dia.IndexDefinition) map[assetKey]float64 {
+	supplies := make(map[assetKey]float64)
+	if definition.Weighting != dia.IndexWeightingMarketCap {
+		return supplies
+	}
+	for _, asset := range definition.Constituents {
+		supply, err := e.datastore.GetSupplyCache(asset)
+		if err != nil {
+			log.Warnf("get supply of %s on %s: %v", asset.Address, asset.Blockchain, err)
+			continue
+		}
+		supplies[keyOf(asset)] = supply.CirculatingSupply
+	}
+	return supplies
+}
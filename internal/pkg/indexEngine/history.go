@@ -0,0 +1,139 @@
+package indexengine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// Supplies are not updated daily, so that they are read from this many days before the start of a history.
+const supplyLookbackDays = 30
+
+// LoadHistory returns the daily prices, volumes and market caps of @asset in [@starttime, @endtime]. Prices are
+// read from asset quotations, volumes from the aggregated volumes of all pairs and exchanges, and market caps
+// are computed from circulating supplies.
+func LoadHistory(datastore models.Datastore, relDB models.RelDatastore, asset dia.Asset, starttime time.Time, endtime time.Time) (CandidateHistory, error) {
+	quotations, err := datastore.GetAssetQuotations(asset, starttime, endtime)
+	if err != nil {
+		return CandidateHistory{Asset: asset}, err
+	}
+	volumes, err := relDB.GetAggregatedVolumes(asset, starttime, endtime)
+	if err != nil {
+		log.Warnf("get aggregated volumes of %s on %s: %v", asset.Address, asset.Blockchain, err)
+	}
+	supplies, err := datastore.GetSupplyInflux(asset, starttime.AddDate(0, 0, -supplyLookbackDays), endtime)
+	if err != nil {
+		log.Warnf("get supplies of %s on %s: %v", asset.Address, asset.Blockchain, err)
+	}
+	return dailyHistory(asset, quotations, volumes, supplies), nil
+}
+
+// LoadHistories returns the histories of all @assets. Assets without prices get an empty history, so that
+// their evaluation reports the missing data.
+func LoadHistories(datastore models.Datastore, relDB models.RelDatastore, assets []dia.Asset, starttime time.Time, endtime time.Time) []CandidateHistory {
+	var histories []CandidateHistory
+	for _, asset := range assets {
+		history, err := LoadHistory(datastore, relDB, asset, starttime, endtime)
+		if err != nil {
+			log.Warnf("load history of %s on %s: %v", asset.Address, asset.Blockchain, err)
+		}
+		histories = append(histories, history)
+	}
+	return histories
+}
+
+// dailyHistory aggregates @quotations, @volumes and @supplies of @asset to daily points. The price of a day is
+// its last quotation. The volume is the sum over all pairs and exchanges of a computation scaled to one day,
+// averaged over the computations of the day. The market cap uses the last circulating supply before the end
+// of the day.
+func dailyHistory(asset dia.Asset, quotations []models.AssetQuotation, volumes []dia.AggregatedVolume, supplies []dia.Supply) CandidateHistory {
+	history := CandidateHistory{Asset: asset}
+
+	closes := make(map[time.Time]models.AssetQuotation)
+	for _, quotation := range quotations {
+		day := startOfDay(quotation.Time)
+		if last, ok := closes[day]; !ok || quotation.Time.After(last.Time) {
+			closes[day] = quotation
+		}
+	}
+
+	computations := make(map[time.Time]float64)
+	for _, volume := range volumes {
+		if volume.TimeRangeSeconds <= 0 {
+			continue
+		}
+		computations[volume.Timestamp] += volume.Volume * float64(24*time.Hour/time.Second) / float64(volume.TimeRangeSeconds)
+	}
+	dailyVolumes := make(map[time.Time][]float64)
+	for timestamp, volume := range computations {
+		day := startOfDay(timestamp)
+		dailyVolumes[day] = append(dailyVolumes[day], volume)
+	}
+
+	sort.Slice(supplies, func(i, j int) bool { return supplies[i].Time.Before(supplies[j].Time) })
+
+	for day, quotation := range closes {
+		point := DailyPoint{Time: day, Price: quotation.Price}
+		if len(dailyVolumes[day]) > 0 {
+			for _, volume := range dailyVolumes[day] {
+				point.Volume += volume
+			}
+			point.Volume /= float64(len(dailyVolumes[day]))
+		}
+		end := day.AddDate(0, 0, 1)
+		i := sort.Search(len(supplies), func(i int) bool { return !supplies[i].Time.Before(end) })
+		if i > 0 {
+			point.MarketCap = quotation.Price * supplies[i-1].CirculatingSupply
+		}
+		history.Points = append(history.Points, point)
+	}
+	sort.Slice(history.Points, func(i, j int) bool { return history.Points[i].Time.Before(history.Points[j].Time) })
+	return history
+}
+
+// candidatesOf returns @candidates along with the constituents of @definition which are no candidates, so that
+// current constituents are always evaluated.
+func candidatesOf(definition dia.IndexDefinition, candidates []dia.Asset) []dia.Asset {
+	isCandidate := make(map[assetKey]bool)
+	for _, asset := range candidates {
+		isCandidate[keyOf(asset)] = true
+	}
+	for _, asset := range definition.Constituents {
+		if !isCandidate[keyOf(asset)] {
+			candidates = append(candidates, asset)
+		}
+	}
+	return candidates
+}
+
+// ProposeIndexRebalance evaluates @rules on the candidates of the index given by @definition with the data of
+// the days before @t and returns the proposed constituents.
+func ProposeIndexRebalance(datastore models.Datastore, relDB models.RelDatastore, definition dia.IndexDefinition, rules dia.IndexSelectionRules, t time.Time) (dia.IndexRebalanceProposal, error) {
+	if err := ValidateRules(rules); err != nil {
+		return dia.IndexRebalanceProposal{}, err
+	}
+	candidates, err := relDB.GetIndexCandidates(definition.Symbol)
+	if err != nil {
+		return dia.IndexRebalanceProposal{}, err
+	}
+	day := startOfDay(t)
+	starttime := day.AddDate(0, 0, -(rules.LookbackDays + rules.VolatilityWindow + 1))
+	histories := LoadHistories(datastore, relDB, candidatesOf(definition, candidates), starttime, day)
+	return ProposeRebalance(definition.Symbol, definition.Constituents, EvaluateCandidates(rules, histories, day), day), nil
+}
+
+// RunBacktest backtests @rules on the candidates of the index given by @definition from @starttime to @endtime.
+func RunBacktest(datastore models.Datastore, relDB models.RelDatastore, definition dia.IndexDefinition, rules dia.IndexSelectionRules, starttime time.Time, endtime time.Time) (BacktestResult, error) {
+	candidates, err := relDB.GetIndexCandidates(definition.Symbol)
+	if err != nil {
+		return BacktestResult{}, err
+	}
+	historyStart := startOfDay(starttime).AddDate(0, 0, -(rules.LookbackDays + rules.VolatilityWindow + 1))
+	histories := LoadHistories(datastore, relDB, candidatesOf(definition, candidates), historyStart, endtime)
+	// The backtest starts without constituents, so that the initial composition is selected by the rules.
+	definition.Constituents = nil
+	return Backtest(definition, rules, histories, starttime, endtime)
+}
@@ -0,0 +1,266 @@
+// Package indexengine computes indices defined in postgres from the filter points of each filters block and
+// the circulating supplies of their constituents. Rebalancings are recorded along with constituent weights
+// and divisors, so that every index value can be traced back to its composition.
+package indexengine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Rebalancing schedules. Rebalancings take place at the first filters block of a period in UTC.
+// Weeks start on Monday, quarters in January, April, July and October.
+const (
+	RebalanceDaily     = "daily"
+	RebalanceWeekly    = "weekly"
+	RebalanceMonthly   = "monthly"
+	RebalanceQuarterly = "quarterly"
+)
+
+// Reasons of rebalancings.
+const (
+	ReasonInitial      = "initial"
+	ReasonScheduled    = "scheduled"
+	ReasonConstituents = "constituents"
+)
+
+var (
+	// ErrMissingPrice is returned if a constituent has no price.
+	ErrMissingPrice = errors.New("missing price")
+	// ErrMissingSupply is returned if a constituent of a market cap weighted index has no supply.
+	ErrMissingSupply = errors.New("missing supply")
+)
+
+// assetKey identifies an asset regardless of the symbol and name given by a source.
+type assetKey struct {
+	blockchain string
+	address    string
+}
+
+func keyOf(asset dia.Asset) assetKey {
+	return assetKey{blockchain: asset.Blockchain, address: asset.Address}
+}
+
+// ValidateDefinition returns an error if @definition cannot be computed.
+func ValidateDefinition(definition dia.IndexDefinition) error {
+	if definition.Symbol == "" {
+		return errors.New("missing index symbol")
+	}
+	if len(definition.Constituents) == 0 {
+		return errors.New("index " + definition.Symbol + " has no constituents")
+	}
+	if definition.Weighting != dia.IndexWeightingMarketCap && definition.Weighting != dia.IndexWeightingEqual {
+		return fmt.Errorf("unknown weighting %s of index %s", definition.Weighting, definition.Symbol)
+	}
+	if _, err := PeriodStart(definition.Rebalancing, time.Now()); err != nil {
+		return err
+	}
+	if definition.BaseValue <= 0 {
+		return errors.New("base value of index " + definition.Symbol + " must be positive")
+	}
+	if definition.MaxWeight < 0 || definition.MaxWeight > 1 {
+		return errors.New("max weight of index " + definition.Symbol + " must be in [0,1]")
+	}
+	if definition.MaxWeight > 0 && definition.MaxWeight*float64(len(definition.Constituents)) < 1 {
+		return fmt.Errorf("max weight %v of index %s is too small for %d constituents", definition.MaxWeight, definition.Symbol, len(definition.Constituents))
+	}
+	return nil
+}
+
+// PeriodStart returns the start of the rebalancing period of @schedule which contains @t.
+func PeriodStart(schedule string, t time.Time) (time.Time, error) {
+	t = t.UTC()
+	day := startOfDay(t)
+	switch schedule {
+	case RebalanceDaily:
+		return day, nil
+	case RebalanceWeekly:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), nil
+	case RebalanceMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	case RebalanceQuarterly:
+		return time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, errors.New("unknown rebalancing schedule " + schedule)
+}
+
+// startOfDay returns the start of the day of @t in UTC.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// RebalanceDue returns true if the last rebalancing at @last took place before the current period at @now.
+func RebalanceDue(schedule string, last time.Time, now time.Time) (bool, error) {
+	start, err := PeriodStart(schedule, now)
+	if err != nil {
+		return false, err
+	}
+	return last.Before(start), nil
+}
+
+// Weights returns the target weights of constituents with market caps @marketCaps under @weighting.
+// Weights exceeding @maxWeight are capped and the excess is distributed proportionally over the others.
+func Weights(weighting string, marketCaps []float64, maxWeight float64) ([]float64, error) {
+	weights := make([]float64, len(marketCaps))
+	if len(marketCaps) == 0 {
+		return weights, nil
+	}
+	switch weighting {
+	case dia.IndexWeightingEqual:
+		for i := range weights {
+			weights[i] = 1 / float64(len(weights))
+		}
+	case dia.IndexWeightingMarketCap:
+		var total float64
+		for _, marketCap := range marketCaps {
+			if marketCap <= 0 {
+				return nil, ErrMissingSupply
+			}
+			total += marketCap
+		}
+		for i, marketCap := range marketCaps {
+			weights[i] = marketCap / total
+		}
+	default:
+		return nil, errors.New("unknown weighting " + weighting)
+	}
+	if maxWeight <= 0 {
+		return weights, nil
+	}
+	if maxWeight*float64(len(weights)) < 1 {
+		return nil, fmt.Errorf("max weight %v is too small for %d constituents", maxWeight, len(weights))
+	}
+
+	// Each pass caps at least one more constituent, so that at most len(weights) passes are needed.
+	capped := make([]bool, len(weights))
+	for pass := 0; pass < len(weights); pass++ {
+		var excess, uncapped float64
+		for i, weight := range weights {
+			if !capped[i] && weight > maxWeight {
+				excess += weight - maxWeight
+				weights[i] = maxWeight
+				capped[i] = true
+			}
+		}
+		if excess == 0 {
+			break
+		}
+		for i, weight := range weights {
+			if !capped[i] {
+				uncapped += weight
+			}
+		}
+		if uncapped == 0 {
+			break
+		}
+		for i := range weights {
+			if !capped[i] {
+				weights[i] += excess * weights[i] / uncapped
+			}
+		}
+	}
+	return weights, nil
+}
+
+// Rebalance returns the rebalancing of the index given by @definition at @t. @indexValue is the value of the
+// index before the rebalancing, which is preserved by adjusting the divisor. For market cap weighting, the
+// units of uncapped constituents are their circulating supplies.
+func Rebalance(definition dia.IndexDefinition, prices map[assetKey]float64, supplies map[assetKey]float64, indexValue float64, previousDivisor float64, t time.Time, reason string) (dia.IndexRebalance, error) {
+	if indexValue <= 0 {
+		return dia.IndexRebalance{}, errors.New("index value must be positive")
+	}
+	marketCaps := make([]float64, len(definition.Constituents))
+	var totalMarketCap float64
+	for i, asset := range definition.Constituents {
+		price := prices[keyOf(asset)]
+		if price <= 0 {
+			return dia.IndexRebalance{}, fmt.Errorf("%w of %s on %s", ErrMissingPrice, asset.Address, asset.Blockchain)
+		}
+		marketCaps[i] = price * supplies[keyOf(asset)]
+		totalMarketCap += marketCaps[i]
+	}
+	weights, err := Weights(definition.Weighting, marketCaps, definition.MaxWeight)
+	if err != nil {
+		return dia.IndexRebalance{}, err
+	}
+
+	// The notional of the index portfolio determines the magnitude of the divisor. Without supplies of all
+	// constituents, the divisor is kept.
+	notional := totalMarketCap
+	for _, marketCap := range marketCaps {
+		if marketCap <= 0 {
+			notional = indexValue * previousDivisor
+			break
+		}
+	}
+	if notional <= 0 {
+		notional = indexValue
+	}
+
+	rebalance := dia.IndexRebalance{
+		Symbol:          definition.Symbol,
+		Time:            t,
+		Reason:          reason,
+		IndexValue:      indexValue,
+		Divisor:         notional / indexValue,
+		PreviousDivisor: previousDivisor,
+	}
+	for i, asset := range definition.Constituents {
+		price := prices[keyOf(asset)]
+		rebalance.Constituents = append(rebalance.Constituents, dia.IndexConstituent{
+			Asset:  asset,
+			Weight: weights[i],
+			Units:  weights[i] * notional / price,
+			Price:  price,
+			Supply: supplies[keyOf(asset)],
+		})
+	}
+	return rebalance, nil
+}
+
+// Value returns the value of the index with composition @rebalance at @prices and the current weights of
+// its constituents.
+func Value(rebalance dia.IndexRebalance, prices map[assetKey]float64) (value float64, weights []float64, err error) {
+	if rebalance.Divisor <= 0 {
+		return 0, nil, errors.New("divisor must be positive")
+	}
+	var total float64
+	for _, constituent := range rebalance.Constituents {
+		price := prices[keyOf(constituent.Asset)]
+		if price <= 0 {
+			return 0, nil, fmt.Errorf("%w of %s on %s", ErrMissingPrice, constituent.Asset.Address, constituent.Asset.Blockchain)
+		}
+		weights = append(weights, constituent.Units*price)
+		total += constituent.Units * price
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+	value = total / rebalance.Divisor
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, nil, errors.New("invalid index value")
+	}
+	return value, weights, nil
+}
+
+// ConstituentsChanged returns true if the constituents of @definition differ from those of @rebalance.
+func ConstituentsChanged(definition dia.IndexDefinition, rebalance dia.IndexRebalance) bool {
+	if len(definition.Constituents) != len(rebalance.Constituents) {
+		return true
+	}
+	current := make(map[assetKey]bool)
+	for _, constituent := range rebalance.Constituents {
+		current[keyOf(constituent.Asset)] = true
+	}
+	for _, asset := range definition.Constituents {
+		if !current[keyOf(asset)] {
+			return true
+		}
+	}
+	return false
+}
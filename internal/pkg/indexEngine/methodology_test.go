@@ -0,0 +1,149 @@
+package indexengine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+var (
+	btc = dia.Asset{Symbol: "BTC", Blockchain: "Bitcoin", Address: "0x0000000000000000000000000000000000000000"}
+	eth = dia.Asset{Symbol: "ETH", Blockchain: "Ethereum", Address: "0x0000000000000000000000000000000000000000"}
+	dot = dia.Asset{Symbol: "DOT", Blockchain: "Polkadot", Address: "0x0000000000000000000000000000000000000000"}
+)
+
+func TestWeights(t *testing.T) {
+	tests := []struct {
+		name       string
+		weighting  string
+		marketCaps []float64
+		maxWeight  float64
+		want       []float64
+	}{
+		{"equal", dia.IndexWeightingEqual, []float64{0, 0, 0, 0}, 0, []float64{0.25, 0.25, 0.25, 0.25}},
+		{"market cap", dia.IndexWeightingMarketCap, []float64{60, 30, 10}, 0, []float64{0.6, 0.3, 0.1}},
+		{"capped", dia.IndexWeightingMarketCap, []float64{60, 30, 10}, 0.5, []float64{0.5, 0.375, 0.125}},
+		// Redistributing the excess of the first constituent pushes the second one over the cap.
+		{"capped twice", dia.IndexWeightingMarketCap, []float64{70, 20, 5, 5}, 0.3, []float64{0.3, 0.3, 0.2, 0.2}},
+	}
+	for _, test := range tests {
+		weights, err := Weights(test.weighting, test.marketCaps, test.maxWeight)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		for i := range weights {
+			if math.Abs(weights[i]-test.want[i]) > 1e-9 {
+				t.Errorf("%s: got %v, want %v", test.name, weights, test.want)
+				break
+			}
+		}
+	}
+
+	if _, err := Weights(dia.IndexWeightingMarketCap, []float64{1, 0}, 0); err != ErrMissingSupply {
+		t.Errorf("got %v, want ErrMissingSupply", err)
+	}
+	if _, err := Weights(dia.IndexWeightingEqual, []float64{1, 1, 1}, 0.2); err == nil {
+		t.Error("infeasible cap was accepted")
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2022, 8, 17, 13, 30, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		RebalanceDaily:     time.Date(2022, 8, 17, 0, 0, 0, 0, time.UTC),
+		RebalanceWeekly:    time.Date(2022, 8, 15, 0, 0, 0, 0, time.UTC),
+		RebalanceMonthly:   time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+		RebalanceQuarterly: time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for schedule, want := range tests {
+		start, err := PeriodStart(schedule, now)
+		if err != nil || !start.Equal(want) {
+			t.Errorf("%s: got %v %v, want %v", schedule, start, err, want)
+		}
+	}
+	if _, err := PeriodStart("yearly", now); err == nil {
+		t.Error("unknown schedule was accepted")
+	}
+
+	due, _ := RebalanceDue(RebalanceMonthly, time.Date(2022, 7, 31, 23, 59, 0, 0, time.UTC), now)
+	if !due {
+		t.Error("rebalancing of the last month is not due")
+	}
+	due, _ = RebalanceDue(RebalanceMonthly, time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC), now)
+	if due {
+		t.Error("rebalancing of the current month is due")
+	}
+}
+
+func TestRebalanceKeepsValue(t *testing.T) {
+	t0 := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	definition := dia.IndexDefinition{
+		Symbol:       "TEST",
+		Weighting:    dia.IndexWeightingMarketCap,
+		MaxWeight:    0.5,
+		Rebalancing:  RebalanceMonthly,
+		BaseValue:    1000,
+		Constituents: []dia.Asset{btc, eth, dot},
+	}
+	if err := ValidateDefinition(definition); err != nil {
+		t.Fatal(err)
+	}
+	prices := map[assetKey]float64{keyOf(btc): 20000, keyOf(eth): 1500, keyOf(dot): 8}
+	supplies := map[assetKey]float64{keyOf(btc): 19e6, keyOf(eth): 120e6, keyOf(dot): 1.1e9}
+
+	initial, err := Rebalance(definition, prices, supplies, definition.BaseValue, 0, t0, ReasonInitial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, weights, err := Value(initial, prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(value-1000) > 1e-6 {
+		t.Errorf("initial value: got %v, want 1000", value)
+	}
+	if math.Abs(weights[0]-0.5) > 1e-9 {
+		t.Errorf("capped weight: got %v, want 0.5", weights[0])
+	}
+
+	// BTC doubles and DOT is replaced. The value after the rebalancing equals the value before.
+	prices[keyOf(btc)] = 40000
+	value, _, err = Value(initial, prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(value-1500) > 1e-6 {
+		t.Errorf("value: got %v, want 1500", value)
+	}
+	definition.Constituents = []dia.Asset{btc, eth}
+	if !ConstituentsChanged(definition, initial) {
+		t.Fatal("changed constituents are not detected")
+	}
+	definition.MaxWeight = 0.9
+	rebalance, err := Rebalance(definition, prices, supplies, value, initial.Divisor, t0.AddDate(0, 0, 1), ReasonConstituents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebalance.PreviousDivisor != initial.Divisor || rebalance.Divisor == initial.Divisor {
+		t.Errorf("divisor: got %v -> %v", rebalance.PreviousDivisor, rebalance.Divisor)
+	}
+	rebalanced, _, err := Value(rebalance, prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(rebalanced-value) > 1e-6 {
+		t.Errorf("value after rebalancing: got %v, want %v", rebalanced, value)
+	}
+	if ConstituentsChanged(definition, rebalance) {
+		t.Error("unchanged constituents are detected as changed")
+	}
+
+	delete(prices, keyOf(eth))
+	if _, _, err := Value(rebalance, prices); err == nil {
+		t.Error("missing price was accepted")
+	}
+}
@@ -0,0 +1,380 @@
+package indexengine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Volatilities of daily returns are annualized with 365 days, as crypto assets are traded every day.
+const daysPerYear = 365
+
+// DailyPoint holds the closing price, the trading volume in USD and the market cap of an asset on a day.
+type DailyPoint struct {
+	// Time is the start of the day in UTC.
+	Time      time.Time
+	Price     float64
+	Volume    float64
+	MarketCap float64
+}
+
+// CandidateHistory holds the daily points of a candidate constituent in ascending order of time.
+type CandidateHistory struct {
+	Asset  dia.Asset
+	Points []DailyPoint
+}
+
+// before returns the points of @history before @t.
+func (history CandidateHistory) before(t time.Time) []DailyPoint {
+	i := sort.Search(len(history.Points), func(i int) bool { return !history.Points[i].Time.Before(t) })
+	return history.Points[:i]
+}
+
+// ValidateRules returns an error if @rules cannot be evaluated.
+func ValidateRules(rules dia.IndexSelectionRules) error {
+	if rules.VolatilityThreshold <= 0 {
+		return errors.New("volatility threshold of index " + rules.Symbol + " must be positive")
+	}
+	if rules.VolatilityWindow < 2 {
+		return errors.New("volatility window of index " + rules.Symbol + " must be at least 2 days")
+	}
+	if rules.LookbackDays < 1 {
+		return errors.New("lookback of index " + rules.Symbol + " must be at least 1 day")
+	}
+	if rules.MaxDaysAbove < 0 || rules.MaxConstituents < 0 {
+		return errors.New("limits of index " + rules.Symbol + " must not be negative")
+	}
+	return nil
+}
+
+// RollingVolatility returns the annualized volatilities of the daily log returns of @prices over each window of
+// @window returns. The i-th volatility is the one of the window ending at prices[i+window].
+func RollingVolatility(prices []float64, window int) []float64 {
+	if window < 2 || len(prices) <= window {
+		return nil
+	}
+	returns := make([]float64, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		returns[i-1] = math.Log(prices[i] / prices[i-1])
+	}
+	var volatilities []float64
+	for end := window; end <= len(returns); end++ {
+		volatilities = append(volatilities, stdDev(returns[end-window:end])*math.Sqrt(daysPerYear))
+	}
+	return volatilities
+}
+
+// stdDev returns the sample standard deviation of @values.
+func stdDev(values []float64) float64 {
+	var mean float64
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	var variance float64
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)-1))
+}
+
+// EvaluateCandidates evaluates @rules on the candidates given by @histories with the data before @t. Eligible
+// candidates are ranked by market cap, and at most rules.MaxConstituents of them are selected.
+func EvaluateCandidates(rules dia.IndexSelectionRules, histories []CandidateHistory, t time.Time) []dia.IndexCandidateEvaluation {
+	evaluations := make([]dia.IndexCandidateEvaluation, len(histories))
+	var eligible []int
+	for i, history := range histories {
+		evaluations[i] = evaluateCandidate(rules, history, t)
+		if len(evaluations[i].Reasons) == 0 {
+			eligible = append(eligible, i)
+		}
+	}
+	sort.SliceStable(eligible, func(a, b int) bool {
+		return evaluations[eligible[a]].MarketCap > evaluations[eligible[b]].MarketCap
+	})
+	for rank, i := range eligible {
+		evaluations[i].Rank = rank + 1
+		if rules.MaxConstituents > 0 && rank >= rules.MaxConstituents {
+			evaluations[i].Reasons = append(evaluations[i].Reasons, fmt.Sprintf("rank %d by market cap exceeds %d constituents", rank+1, rules.MaxConstituents))
+			continue
+		}
+		evaluations[i].Selected = true
+	}
+	return evaluations
+}
+
+// evaluateCandidate returns the volatility ratio, average daily volume and market cap of @history in the
+// lookback period before @t, along with the rules the candidate fails.
+func evaluateCandidate(rules dia.IndexSelectionRules, history CandidateHistory, t time.Time) dia.IndexCandidateEvaluation {
+	evaluation := dia.IndexCandidateEvaluation{Asset: history.Asset}
+	lookbackStart := t.AddDate(0, 0, -rules.LookbackDays)
+	volatilityStart := lookbackStart.AddDate(0, 0, -rules.VolatilityWindow)
+
+	var (
+		prices   []float64
+		lookback []DailyPoint
+	)
+	for _, point := range history.before(t) {
+		if !point.Time.Before(volatilityStart) && point.Price > 0 {
+			prices = append(prices, point.Price)
+		}
+		if !point.Time.Before(lookbackStart) {
+			lookback = append(lookback, point)
+		}
+	}
+
+	// Only volatilities of windows ending in the lookback period are counted.
+	volatilities := RollingVolatility(prices, rules.VolatilityWindow)
+	if len(volatilities) > rules.LookbackDays {
+		volatilities = volatilities[len(volatilities)-rules.LookbackDays:]
+	}
+	ratio := dia.VolatilityRatio{
+		Symbol:    history.Asset.Symbol,
+		Threehold: rules.VolatilityThreshold,
+		Time:      t,
+	}
+	for _, volatility := range volatilities {
+		if volatility > rules.VolatilityThreshold {
+			ratio.DaysAbove++
+		} else {
+			ratio.DaysBelow++
+		}
+	}
+	ratio.Selected = len(volatilities) > 0 && ratio.DaysAbove <= rules.MaxDaysAbove
+	evaluation.VolatilityRatio = ratio
+	if len(volatilities) > 0 {
+		evaluation.Volatility = volatilities[len(volatilities)-1]
+	}
+
+	if len(lookback) > 0 {
+		for _, point := range lookback {
+			evaluation.AvgDailyVolume += point.Volume
+		}
+		evaluation.AvgDailyVolume /= float64(len(lookback))
+		evaluation.MarketCap = lookback[len(lookback)-1].MarketCap
+	}
+
+	switch {
+	case len(volatilities) == 0:
+		evaluation.Reasons = append(evaluation.Reasons, fmt.Sprintf("insufficient price history for a volatility over %d days", rules.VolatilityWindow))
+	case ratio.DaysAbove > rules.MaxDaysAbove:
+		evaluation.Reasons = append(evaluation.Reasons, fmt.Sprintf("volatility above %.0f%% on %d of %d days, at most %d allowed", rules.VolatilityThreshold*100, ratio.DaysAbove, ratio.DaysAbove+ratio.DaysBelow, rules.MaxDaysAbove))
+	}
+	if evaluation.AvgDailyVolume < rules.MinAvgVolume {
+		evaluation.Reasons = append(evaluation.Reasons, fmt.Sprintf("average daily volume %.0f USD below %.0f USD", evaluation.AvgDailyVolume, rules.MinAvgVolume))
+	}
+	switch {
+	case evaluation.MarketCap <= 0:
+		evaluation.Reasons = append(evaluation.Reasons, "no market cap")
+	case evaluation.MarketCap < rules.MinMarketCap:
+		evaluation.Reasons = append(evaluation.Reasons, fmt.Sprintf("market cap %.0f USD below %.0f USD", evaluation.MarketCap, rules.MinMarketCap))
+	}
+	return evaluation
+}
+
+// ProposeRebalance returns the composition of the index @symbol with the selected candidates of @evaluations
+// and the justification of each change to the @current constituents.
+func ProposeRebalance(symbol string, current []dia.Asset, evaluations []dia.IndexCandidateEvaluation, t time.Time) dia.IndexRebalanceProposal {
+	proposal := dia.IndexRebalanceProposal{
+		Symbol:      symbol,
+		Time:        t,
+		Evaluations: evaluations,
+	}
+	isCurrent := make(map[assetKey]bool)
+	for _, asset := range current {
+		isCurrent[keyOf(asset)] = true
+	}
+
+	var selected []dia.IndexCandidateEvaluation
+	evaluated := make(map[assetKey]bool)
+	for _, evaluation := range evaluations {
+		key := keyOf(evaluation.Asset)
+		evaluated[key] = true
+		switch {
+		case evaluation.Selected:
+			selected = append(selected, evaluation)
+			if !isCurrent[key] {
+				proposal.Added = append(proposal.Added, evaluation.Asset)
+				proposal.Justification = append(proposal.Justification, fmt.Sprintf(
+					"add %s: rank %d with market cap %.0f USD, average daily volume %.0f USD, volatility above %.0f%% on %d of %d days",
+					evaluation.Asset.Symbol,
+					evaluation.Rank,
+					evaluation.MarketCap,
+					evaluation.AvgDailyVolume,
+					evaluation.VolatilityRatio.Threehold*100,
+					evaluation.VolatilityRatio.DaysAbove,
+					evaluation.VolatilityRatio.DaysAbove+evaluation.VolatilityRatio.DaysBelow,
+				))
+			}
+		case isCurrent[key]:
+			proposal.Removed = append(proposal.Removed, evaluation.Asset)
+			proposal.Justification = append(proposal.Justification, "remove "+evaluation.Asset.Symbol+": "+strings.Join(evaluation.Reasons, ", "))
+		}
+	}
+	for _, asset := range current {
+		if !evaluated[keyOf(asset)] {
+			proposal.Removed = append(proposal.Removed, asset)
+			proposal.Justification = append(proposal.Justification, "remove "+asset.Symbol+": not a candidate")
+		}
+	}
+	if len(proposal.Added) == 0 && len(proposal.Removed) == 0 {
+		proposal.Justification = append(proposal.Justification, fmt.Sprintf("keep all %d constituents", len(current)))
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].Rank < selected[j].Rank })
+	for _, evaluation := range selected {
+		proposal.Constituents = append(proposal.Constituents, evaluation.Asset)
+	}
+	return proposal
+}
+
+// BacktestPeriod is a rebalancing in a backtest of selection rules.
+type BacktestPeriod struct {
+	Proposal  dia.IndexRebalanceProposal
+	Rebalance dia.IndexRebalance
+	// Turnover is half the sum of the absolute changes of constituent weights. It is 0 at the initial rebalancing.
+	Turnover float64
+}
+
+// IndexValue is the value of an index at the end of a day.
+type IndexValue struct {
+	Time  time.Time
+	Value float64
+}
+
+// BacktestResult holds the rebalancings and daily values of an index in a backtest.
+type BacktestResult struct {
+	Symbol  string
+	Periods []BacktestPeriod
+	Values  []IndexValue
+}
+
+// Backtest simulates the index given by @definition with constituents selected by @rules from @histories at
+// each rebalancing from @starttime to @endtime. Selections only use data before the day of a rebalancing, and
+// index values are computed from the latest daily closing prices, which may precede @starttime. If no candidate
+// is eligible, the constituents are kept.
+func Backtest(definition dia.IndexDefinition, rules dia.IndexSelectionRules, histories []CandidateHistory, starttime time.Time, endtime time.Time) (BacktestResult, error) {
+	result := BacktestResult{Symbol: definition.Symbol}
+	if err := ValidateRules(rules); err != nil {
+		return result, err
+	}
+	var (
+		// Latest prices and supplies up to the current day.
+		prices    = make(map[assetKey]float64)
+		supplies  = make(map[assetKey]float64)
+		rebalance *dia.IndexRebalance
+		current   []dia.Asset
+	)
+	start := startOfDay(starttime)
+	points := make(map[assetKey]map[time.Time]DailyPoint)
+	for _, history := range histories {
+		key := keyOf(history.Asset)
+		// Points before the first day seed the latest prices and supplies, so that candidates
+		// without a point on the first day can be weighted.
+		for _, point := range history.before(start) {
+			updateLatest(key, point, prices, supplies)
+		}
+		days := make(map[time.Time]DailyPoint)
+		for _, point := range history.Points {
+			days[startOfDay(point.Time)] = point
+		}
+		points[key] = days
+	}
+
+	for day := start; !day.After(endtime); day = day.AddDate(0, 0, 1) {
+		for key, days := range points {
+			if point, ok := days[day]; ok {
+				updateLatest(key, point, prices, supplies)
+			}
+		}
+
+		due := rebalance == nil
+		if !due {
+			var err error
+			due, err = RebalanceDue(definition.Rebalancing, rebalance.Time, day)
+			if err != nil {
+				return result, err
+			}
+		}
+		if due {
+			proposal := ProposeRebalance(definition.Symbol, current, EvaluateCandidates(rules, histories, day), day)
+			constituents := proposal.Constituents
+			if len(constituents) == 0 {
+				constituents = current
+				proposal.Justification = append(proposal.Justification, "no eligible candidates, constituents kept")
+			}
+			if len(constituents) > 0 {
+				period, err := backtestRebalance(definition, constituents, rebalance, prices, supplies, day)
+				if err != nil {
+					return result, fmt.Errorf("rebalance at %v: %w", day, err)
+				}
+				period.Proposal = proposal
+				result.Periods = append(result.Periods, period)
+				rebalance = &period.Rebalance
+				current = constituents
+			}
+		}
+
+		if rebalance != nil {
+			value, _, err := Value(*rebalance, prices)
+			if err != nil {
+				return result, fmt.Errorf("value at %v: %w", day, err)
+			}
+			result.Values = append(result.Values, IndexValue{Time: day, Value: value})
+		}
+	}
+	return result, nil
+}
+
+// updateLatest sets the latest price and supply of @key in @prices and @supplies to the ones of @point,
+// if they are given.
+func updateLatest(key assetKey, point DailyPoint, prices map[assetKey]float64, supplies map[assetKey]float64) {
+	if point.Price <= 0 {
+		return
+	}
+	prices[key] = point.Price
+	if point.MarketCap > 0 {
+		supplies[key] = point.MarketCap / point.Price
+	}
+}
+
+// backtestRebalance rebalances the index given by @definition with @constituents, starting from the composition
+// @previous which is nil at the initial rebalancing.
+func backtestRebalance(definition dia.IndexDefinition, constituents []dia.Asset, previous *dia.IndexRebalance, prices map[assetKey]float64, supplies map[assetKey]float64, t time.Time) (BacktestPeriod, error) {
+	definition.Constituents = constituents
+	if previous == nil {
+		rebalance, err := Rebalance(definition, prices, supplies, definition.BaseValue, 0, t, ReasonInitial)
+		return BacktestPeriod{Rebalance: rebalance}, err
+	}
+
+	value, weights, err := Value(*previous, prices)
+	if err != nil {
+		return BacktestPeriod{}, err
+	}
+	reason := ReasonScheduled
+	if ConstituentsChanged(definition, *previous) {
+		reason = ReasonConstituents
+	}
+	rebalance, err := Rebalance(definition, prices, supplies, value, previous.Divisor, t, reason)
+	if err != nil {
+		return BacktestPeriod{}, err
+	}
+
+	changes := make(map[assetKey]float64)
+	for i, constituent := range previous.Constituents {
+		changes[keyOf(constituent.Asset)] -= weights[i]
+	}
+	for _, constituent := range rebalance.Constituents {
+		changes[keyOf(constituent.Asset)] += constituent.Weight
+	}
+	var turnover float64
+	for _, change := range changes {
+		turnover += math.Abs(change)
+	}
+	return BacktestPeriod{Rebalance: rebalance, Turnover: turnover / 2}, nil
+}
@@ -0,0 +1,254 @@
+package indexengine
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+var (
+	link = dia.Asset{Symbol: "LINK", Blockchain: "Ethereum", Address: "0x514910771AF9Ca656af840dff83E8264EcF986CA"}
+	uni  = dia.Asset{Symbol: "UNI", Blockchain: "Ethereum", Address: "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984"}
+)
+
+// makeHistory returns @days daily points of @asset from @start with prices given by @price.
+func makeHistory(asset dia.Asset, start time.Time, days int, price func(day int) float64, volume float64, supply float64) CandidateHistory {
+	history := CandidateHistory{Asset: asset}
+	for i := 0; i < days; i++ {
+		history.Points = append(history.Points, DailyPoint{
+			Time:      start.AddDate(0, 0, i),
+			Price:     price(i),
+			Volume:    volume,
+			MarketCap: price(i) * supply,
+		})
+	}
+	return history
+}
+
+func calm(base float64) func(int) float64 {
+	return func(day int) float64 { return base * math.Pow(1.001, float64(day)) }
+}
+
+func volatile(base float64) func(int) float64 {
+	return func(day int) float64 {
+		if day%2 == 0 {
+			return base
+		}
+		return base * 1.2
+	}
+}
+
+func TestRollingVolatility(t *testing.T) {
+	if volatilities := RollingVolatility([]float64{1, 2}, 2); volatilities != nil {
+		t.Errorf("got %v for insufficient prices", volatilities)
+	}
+	prices := []float64{100, 110, 100, 110, 100}
+	volatilities := RollingVolatility(prices, 2)
+	if len(volatilities) != 3 {
+		t.Fatalf("got %d volatilities, want 3", len(volatilities))
+	}
+	// Returns of +-log(1.1) have a sample standard deviation of sqrt(2)*log(1.1).
+	want := math.Sqrt(2) * math.Log(1.1) * math.Sqrt(daysPerYear)
+	for _, volatility := range volatilities {
+		if math.Abs(volatility-want) > 1e-9 {
+			t.Errorf("got %v, want %v", volatility, want)
+		}
+	}
+}
+
+func TestEvaluateCandidates(t *testing.T) {
+	start := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 60)
+	rules := dia.IndexSelectionRules{
+		Symbol:              "TEST",
+		VolatilityThreshold: 0.5,
+		VolatilityWindow:    10,
+		LookbackDays:        20,
+		MinAvgVolume:        1e6,
+		MaxConstituents:     1,
+	}
+	if err := ValidateRules(rules); err != nil {
+		t.Fatal(err)
+	}
+	histories := []CandidateHistory{
+		makeHistory(btc, start, 60, calm(20000), 1e9, 19e6),
+		makeHistory(eth, start, 60, calm(1500), 1e9, 120e6),
+		makeHistory(dot, start, 60, volatile(8), 1e8, 1.1e9),
+		makeHistory(link, start, 60, calm(7), 1e3, 500e6),
+		// No data in the lookback period.
+		makeHistory(uni, start, 10, calm(5), 1e8, 700e6),
+	}
+
+	evaluations := EvaluateCandidates(rules, histories, now)
+	if !evaluations[0].Selected || evaluations[0].Rank != 1 {
+		t.Errorf("BTC: got selected %v rank %d, want rank 1", evaluations[0].Selected, evaluations[0].Rank)
+	}
+	if evaluations[0].VolatilityRatio.DaysAbove != 0 || evaluations[0].VolatilityRatio.DaysBelow != 20 || !evaluations[0].VolatilityRatio.Selected {
+		t.Errorf("BTC: got volatility ratio %+v", evaluations[0].VolatilityRatio)
+	}
+	if evaluations[1].Selected || evaluations[1].Rank != 2 || !strings.Contains(evaluations[1].Reasons[0], "exceeds 1 constituents") {
+		t.Errorf("ETH: got selected %v rank %d reasons %v", evaluations[1].Selected, evaluations[1].Rank, evaluations[1].Reasons)
+	}
+	if evaluations[2].Selected || evaluations[2].VolatilityRatio.Selected || evaluations[2].VolatilityRatio.DaysAbove != 20 {
+		t.Errorf("DOT: got %+v", evaluations[2])
+	}
+	if evaluations[3].Selected || len(evaluations[3].Reasons) != 1 || !strings.Contains(evaluations[3].Reasons[0], "average daily volume") {
+		t.Errorf("LINK: got reasons %v", evaluations[3].Reasons)
+	}
+	if evaluations[4].Selected || !strings.Contains(evaluations[4].Reasons[0], "insufficient price history") {
+		t.Errorf("UNI: got reasons %v", evaluations[4].Reasons)
+	}
+
+	// Data from @now on must not be used.
+	histories[0].Points[59].Price = 1
+	if reevaluated := EvaluateCandidates(rules, histories, now.AddDate(0, 0, -1)); reevaluated[0].Volatility != evaluations[0].Volatility {
+		t.Errorf("future price changed the volatility from %v to %v", evaluations[0].Volatility, reevaluated[0].Volatility)
+	}
+
+	proposal := ProposeRebalance("TEST", []dia.Asset{eth, dot}, evaluations, now)
+	if len(proposal.Constituents) != 1 || proposal.Constituents[0] != btc {
+		t.Errorf("constituents: got %v, want BTC", proposal.Constituents)
+	}
+	if len(proposal.Added) != 1 || len(proposal.Removed) != 2 || len(proposal.Justification) != 3 {
+		t.Fatalf("got added %v removed %v justification %v", proposal.Added, proposal.Removed, proposal.Justification)
+	}
+	if !strings.HasPrefix(proposal.Justification[2], "remove DOT: volatility above 50% on 20 of 20 days") {
+		t.Errorf("justification: got %s", proposal.Justification[2])
+	}
+}
+
+func TestBacktest(t *testing.T) {
+	historyStart := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	starttime := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	endtime := time.Date(2022, 8, 15, 0, 0, 0, 0, time.UTC)
+	days := int(endtime.Sub(historyStart).Hours()/24) + 1
+
+	// DOT becomes volatile in July and is removed at the rebalancing in August.
+	dotPrice := func(day int) float64 {
+		if historyStart.AddDate(0, 0, day).Before(time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)) {
+			return calm(8)(day)
+		}
+		return volatile(8)(day)
+	}
+	histories := []CandidateHistory{
+		makeHistory(btc, historyStart, days, calm(20000), 1e9, 19e6),
+		makeHistory(eth, historyStart, days, calm(1500), 1e9, 120e6),
+		makeHistory(dot, historyStart, days, dotPrice, 1e9, 1.1e9),
+	}
+	definition := dia.IndexDefinition{Symbol: "TEST", Weighting: dia.IndexWeightingEqual, Rebalancing: RebalanceMonthly, BaseValue: 100}
+	rules := dia.IndexSelectionRules{Symbol: "TEST", VolatilityThreshold: 0.5, VolatilityWindow: 10, LookbackDays: 20, MaxDaysAbove: 5}
+
+	result, err := Backtest(definition, rules, histories, starttime, endtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Periods) != 3 {
+		t.Fatalf("got %d rebalancings, want 3", len(result.Periods))
+	}
+	if len(result.Values) != 76 || result.Values[0].Value != 100 {
+		t.Errorf("got %d values starting at %v, want 76 starting at 100", len(result.Values), result.Values[0].Value)
+	}
+	if n := len(result.Periods[1].Rebalance.Constituents); n != 3 {
+		t.Errorf("July: got %d constituents, want 3", n)
+	}
+	august := result.Periods[2]
+	if len(august.Rebalance.Constituents) != 2 || len(august.Proposal.Removed) != 1 || august.Proposal.Removed[0] != dot {
+		t.Errorf("August: got constituents %v, removed %v", august.Rebalance.Constituents, august.Proposal.Removed)
+	}
+	// BTC and ETH gain equally, so that the turnover is the weight DOT had drifted to since July.
+	augustDay := int(august.Rebalance.Time.Sub(historyStart).Hours() / 24)
+	prices := make(map[assetKey]float64)
+	for _, history := range histories {
+		prices[keyOf(history.Asset)] = history.Points[augustDay].Price
+	}
+	_, weights, err := Value(result.Periods[1].Rebalance, prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dotWeight float64
+	for i, constituent := range result.Periods[1].Rebalance.Constituents {
+		if constituent.Asset == dot {
+			dotWeight = weights[i]
+		}
+	}
+	if august.Rebalance.Reason != ReasonConstituents || math.Abs(august.Turnover-dotWeight) > 1e-9 {
+		t.Errorf("August: got reason %s turnover %v, want constituents %v", august.Rebalance.Reason, august.Turnover, dotWeight)
+	}
+	// The value on the day of the rebalancing is the one preserved by the divisor.
+	for _, value := range result.Values {
+		if value.Time.Equal(august.Rebalance.Time) && math.Abs(value.Value-august.Rebalance.IndexValue) > 1e-9 {
+			t.Errorf("value on rebalancing day: got %v, want %v", value.Value, august.Rebalance.IndexValue)
+		}
+	}
+}
+
+func TestBacktestSeedsLatestPoints(t *testing.T) {
+	historyStart := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	starttime := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	endtime := time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)
+	days := int(endtime.Sub(historyStart).Hours()/24) + 1
+
+	// ETH has no quotation on the first day of the backtest.
+	ethHistory := makeHistory(eth, historyStart, days, calm(1500), 1e9, 120e6)
+	firstDay := int(starttime.Sub(historyStart).Hours() / 24)
+	lastBefore := ethHistory.Points[firstDay-1]
+	ethHistory.Points = append(ethHistory.Points[:firstDay:firstDay], ethHistory.Points[firstDay+1:]...)
+	histories := []CandidateHistory{
+		makeHistory(btc, historyStart, days, calm(20000), 1e9, 19e6),
+		ethHistory,
+	}
+	definition := dia.IndexDefinition{Symbol: "TEST", Weighting: dia.IndexWeightingEqual, Rebalancing: RebalanceMonthly, BaseValue: 100}
+	rules := dia.IndexSelectionRules{Symbol: "TEST", VolatilityThreshold: 0.5, VolatilityWindow: 10, LookbackDays: 20, MaxDaysAbove: 5}
+
+	result, err := Backtest(definition, rules, histories, starttime, endtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Periods) != 1 || len(result.Periods[0].Rebalance.Constituents) != 2 {
+		t.Fatalf("got rebalancings %v, want one with 2 constituents", result.Periods)
+	}
+	for _, constituent := range result.Periods[0].Rebalance.Constituents {
+		if constituent.Asset == eth && constituent.Price != lastBefore.Price {
+			t.Errorf("ETH: got price %v, want the last price before the backtest %v", constituent.Price, lastBefore.Price)
+		}
+	}
+	if len(result.Values) != 10 {
+		t.Errorf("got %d values, want 10", len(result.Values))
+	}
+}
+
+func TestDailyHistory(t *testing.T) {
+	day := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	quotations := []models.AssetQuotation{
+		{Asset: eth, Price: 1600, Time: day.Add(23 * time.Hour)},
+		{Asset: eth, Price: 1500, Time: day.Add(time.Hour)},
+		{Asset: eth, Price: 1700, Time: day.Add(25 * time.Hour)},
+	}
+	pair := dia.Pair{QuoteToken: eth, BaseToken: dia.Asset{Symbol: "USDC"}}
+	volumes := []dia.AggregatedVolume{
+		{Pair: pair, Exchange: "Uniswap", Volume: 100, TimeRangeSeconds: 43200, Timestamp: day.Add(12 * time.Hour)},
+		{Pair: pair, Exchange: "Binance", Volume: 300, TimeRangeSeconds: 43200, Timestamp: day.Add(12 * time.Hour)},
+		{Pair: pair, Exchange: "Binance", Volume: 400, TimeRangeSeconds: 86400, Timestamp: day.Add(18 * time.Hour)},
+	}
+	supplies := []dia.Supply{
+		{Asset: eth, CirculatingSupply: 121e6, Time: day.Add(26 * time.Hour)},
+		{Asset: eth, CirculatingSupply: 120e6, Time: day.AddDate(0, 0, -3)},
+	}
+
+	history := dailyHistory(eth, quotations, volumes, supplies)
+	if len(history.Points) != 2 {
+		t.Fatalf("got %d points, want 2", len(history.Points))
+	}
+	first := history.Points[0]
+	// The computation at 12:00 covers half a day with 400 USD, the one at 18:00 a full day with 400 USD.
+	if !first.Time.Equal(day) || first.Price != 1600 || first.Volume != 600 || first.MarketCap != 1600*120e6 {
+		t.Errorf("first day: got %+v", first)
+	}
+	if second := history.Points[1]; second.MarketCap != 1700*121e6 || second.Volume != 0 {
+		t.Errorf("second day: got %+v", second)
+	}
+}
@@ -0,0 +1,131 @@
+// Package markethours determines the trading hours of traditional markets, so that the absence of
+// quotations outside of trading hours is not mistaken for an outage.
+package markethours
+
+import (
+	"strings"
+	"time"
+
+	ratederivatives "github.com/diadata-org/diadata/internal/pkg/rateDerivatives"
+	"github.com/diadata-org/diadata/pkg/dia"
+	log "github.com/sirupsen/logrus"
+)
+
+// Markets with trading hours.
+const (
+	// MarketNYSE trades on the business days of the New York Stock Exchange from 9:30 to 16:00 New York time.
+	// Early closes are not taken into account.
+	MarketNYSE = "NYSE"
+	// MarketFX trades continuously from Sunday 17:00 to Friday 17:00 New York time.
+	MarketFX = "FX"
+)
+
+// firstCalendarYear is the first year for which holidays are generated.
+const firstCalendarYear = 2000
+
+var (
+	newYork = loadNewYork()
+	nyse    = newNYSE()
+	fx      = &Market{Name: MarketFX, open: 17 * time.Hour, close: 17 * time.Hour}
+)
+
+// Market determines whether a market is open at a given time.
+type Market struct {
+	Name string
+	// open and close are the offsets of the trading session from midnight in New York. For MarketFX,
+	// they refer to the opening on Sunday and the close on Friday.
+	open     time.Duration
+	close    time.Duration
+	calendar *dia.Calendar
+}
+
+func newNYSE() *Market {
+	var holidays []dia.Holiday
+	for year := firstCalendarYear; year <= time.Now().Year()+5; year++ {
+		yearHolidays, err := ratederivatives.GenerateHolidays(dia.CalendarNYSE, year)
+		if err != nil {
+			log.Error("generate NYSE holidays: ", err)
+			continue
+		}
+		holidays = append(holidays, yearHolidays...)
+	}
+	return &Market{
+		Name:     MarketNYSE,
+		open:     9*time.Hour + 30*time.Minute,
+		close:    16 * time.Hour,
+		calendar: dia.NewCalendar(dia.CalendarNYSE, holidays),
+	}
+}
+
+func loadNewYork() *time.Location {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		log.Error("load time zone of New York, falling back to EST: ", err)
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return location
+}
+
+// MarketOf returns the market on which @symbol is traded. Currency pairs such as EUR/USD are traded on
+// MarketFX, all other symbols are considered US stocks.
+func MarketOf(symbol string) *Market {
+	if strings.Contains(symbol, "/") {
+		return fx
+	}
+	return nyse
+}
+
+// IsOpen returns true if the market is open at @t.
+func (m *Market) IsOpen(t time.Time) bool {
+	local := t.In(newYork)
+	offset := local.Sub(midnight(local))
+	if m.Name == MarketFX {
+		switch local.Weekday() {
+		case time.Saturday:
+			return false
+		case time.Sunday:
+			return offset >= m.open
+		case time.Friday:
+			return offset < m.close
+		}
+		return true
+	}
+	return m.calendar.IsBusinessDay(local) && offset >= m.open && offset < m.close
+}
+
+// SessionStart returns the opening time of the last trading session which started at or before @t.
+func (m *Market) SessionStart(t time.Time) time.Time {
+	local := t.In(newYork)
+	if m.Name == MarketFX {
+		sunday := time.Date(local.Year(), local.Month(), local.Day()-int(local.Weekday()), 0, 0, 0, 0, newYork).Add(m.open)
+		if sunday.After(t) {
+			sunday = time.Date(sunday.Year(), sunday.Month(), sunday.Day()-7, 0, 0, 0, 0, newYork).Add(m.open)
+		}
+		return sunday
+	}
+	date := midnight(local)
+	for {
+		if m.calendar.IsBusinessDay(date) {
+			if start := date.Add(m.open); !start.After(t) {
+				return start
+			}
+		}
+		date = time.Date(date.Year(), date.Month(), date.Day()-1, 0, 0, 0, 0, newYork)
+	}
+}
+
+// StaleAge returns the age at @now of the last quotation at @last, counted from the start of the current
+// trading session at the earliest. If the market is closed at @now, open is false and quotations are not stale.
+func (m *Market) StaleAge(last time.Time, now time.Time) (age time.Duration, open bool) {
+	if !m.IsOpen(now) {
+		return 0, false
+	}
+	if start := m.SessionStart(now); last.Before(start) {
+		last = start
+	}
+	return now.Sub(last), true
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
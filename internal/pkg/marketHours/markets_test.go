@@ -0,0 +1,71 @@
+package markethours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsOpen(t *testing.T) {
+	tables := []struct {
+		symbol string
+		time   string
+		open   bool
+	}{
+		// Wednesday, New York is 4 hours behind UTC in summer.
+		{"AAPL", "2022-06-08T13:29:00Z", false},
+		{"AAPL", "2022-06-08T13:30:00Z", true},
+		{"AAPL", "2022-06-08T19:59:00Z", true},
+		{"AAPL", "2022-06-08T20:00:00Z", false},
+		// Juneteenth observed on Monday.
+		{"AAPL", "2022-06-20T15:00:00Z", false},
+		{"AAPL", "2022-06-18T15:00:00Z", false},
+		// Winter time, New York is 5 hours behind UTC.
+		{"AAPL", "2022-01-05T14:29:00Z", false},
+		{"AAPL", "2022-01-05T14:30:00Z", true},
+		{"EUR/USD", "2022-06-10T20:59:00Z", true},
+		{"EUR/USD", "2022-06-10T21:00:00Z", false},
+		{"EUR/USD", "2022-06-11T12:00:00Z", false},
+		{"EUR/USD", "2022-06-12T20:59:00Z", false},
+		{"EUR/USD", "2022-06-12T21:00:00Z", true},
+		{"EUR/USD", "2022-06-20T15:00:00Z", true},
+	}
+	for _, table := range tables {
+		tm, _ := time.Parse(time.RFC3339, table.time)
+		if open := MarketOf(table.symbol).IsOpen(tm); open != table.open {
+			t.Errorf("%s at %s: open is %v, want %v", table.symbol, table.time, open, table.open)
+		}
+	}
+}
+
+func TestSessionStart(t *testing.T) {
+	tables := []struct {
+		symbol string
+		time   string
+		start  string
+	}{
+		// Tuesday after Juneteenth, before the open the last session started on Friday.
+		{"AAPL", "2022-06-21T12:00:00Z", "2022-06-17T13:30:00Z"},
+		{"AAPL", "2022-06-21T14:00:00Z", "2022-06-21T13:30:00Z"},
+		{"EUR/USD", "2022-06-15T12:00:00Z", "2022-06-12T21:00:00Z"},
+		{"EUR/USD", "2022-06-12T20:00:00Z", "2022-06-05T21:00:00Z"},
+	}
+	for _, table := range tables {
+		tm, _ := time.Parse(time.RFC3339, table.time)
+		if start := MarketOf(table.symbol).SessionStart(tm).UTC().Format(time.RFC3339); start != table.start {
+			t.Errorf("%s at %s: session start is %s, want %s", table.symbol, table.time, start, table.start)
+		}
+	}
+}
+
+func TestStaleAge(t *testing.T) {
+	monday, _ := time.Parse(time.RFC3339, "2022-06-13T13:35:00Z")
+	friday, _ := time.Parse(time.RFC3339, "2022-06-10T19:59:00Z")
+	// The quotation of Friday is only counted from the open on Monday.
+	age, open := MarketOf("AAPL").StaleAge(friday, monday)
+	if !open || age != 5*time.Minute {
+		t.Errorf("age is %v, open %v, want 5m0s, true", age, open)
+	}
+	if _, open := MarketOf("AAPL").StaleAge(friday, monday.AddDate(0, 0, -1)); open {
+		t.Error("market is open on Sunday")
+	}
+}
@@ -0,0 +1,219 @@
+package ratederivatives
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// rateCalendars maps rates to the calendars of their publication days.
+var rateCalendars = map[string]string{
+	"SOFR":    dia.CalendarUS,
+	"SOFR30":  dia.CalendarUS,
+	"SOFR90":  dia.CalendarUS,
+	"SOFR180": dia.CalendarUS,
+	"SAFR":    dia.CalendarUS,
+	"ESTER":   dia.CalendarTARGET2,
+	"SONIA":   dia.CalendarUK,
+}
+
+// defiRateProtocols are the prefixes of the symbols of on-chain rates, such as COMPOUND-USDC-SUPPLY.
+var defiRateProtocols = map[string]bool{
+	"AAVEV2":     true,
+	"AAVEV3":     true,
+	"COMPOUND":   true,
+	"LIDO":       true,
+	"ROCKETPOOL": true,
+}
+
+// ukSpecialHolidays are the bank holidays in England and Wales which are not given by the regular rules.
+// Moved regular holidays are listed in ukMovedHolidays.
+var ukSpecialHolidays = map[string]string{
+	"2020-05-08": "VE Day",
+	"2022-06-02": "Spring bank holiday",
+	"2022-06-03": "Platinum Jubilee",
+	"2022-09-19": "State Funeral of Queen Elizabeth II",
+	"2023-05-08": "Coronation of King Charles III",
+}
+
+// ukMovedHolidays are regular bank holidays which were moved to one of the special holidays.
+var ukMovedHolidays = map[string]bool{
+	"2020-05-04": true,
+	"2022-05-30": true,
+}
+
+// RateCalendar returns the name of the calendar of the rate @symbol.
+func RateCalendar(symbol string) (string, error) {
+	if calendar, ok := rateCalendars[symbol]; ok {
+		return calendar, nil
+	}
+	if defiRateProtocols[strings.Split(symbol, "-")[0]] {
+		return dia.CalendarCrypto, nil
+	}
+	return "", errors.New("no calendar for rate " + symbol)
+}
+
+// GenerateHolidays returns the holidays of @calendar in @year as given by its regular rules. Holidays on
+// weekends are omitted. Calendars stored in the database can be edited for exceptions such as early closes.
+func GenerateHolidays(calendar string, year int) ([]dia.Holiday, error) {
+	easter := easterSunday(year)
+	var holidays []dia.Holiday
+	add := func(date time.Time, name string) {
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			holidays = append(holidays, dia.Holiday{Calendar: calendar, Date: date, Name: name})
+		}
+	}
+
+	switch calendar {
+	case dia.CalendarUS:
+		// Holidays on Sundays are observed on Monday, on Saturdays on Friday except for New Year's Day.
+		observed := func(date time.Time) time.Time {
+			switch date.Weekday() {
+			case time.Sunday:
+				return date.AddDate(0, 0, 1)
+			case time.Saturday:
+				return date.AddDate(0, 0, -1)
+			}
+			return date
+		}
+		if newYear := day(year, time.January, 1); newYear.Weekday() != time.Saturday {
+			add(observed(newYear), "New Year's Day")
+		}
+		add(nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day")
+		add(nthWeekday(year, time.February, time.Monday, 3), "Presidents Day")
+		add(easter.AddDate(0, 0, -2), "Good Friday")
+		add(lastWeekday(year, time.May, time.Monday), "Memorial Day")
+		if year >= 2022 {
+			add(observed(day(year, time.June, 19)), "Juneteenth")
+		}
+		add(observed(day(year, time.July, 4)), "Independence Day")
+		add(nthWeekday(year, time.September, time.Monday, 1), "Labor Day")
+		add(nthWeekday(year, time.October, time.Monday, 2), "Columbus Day")
+		add(observed(day(year, time.November, 11)), "Veterans Day")
+		add(nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day")
+		add(observed(day(year, time.December, 25)), "Christmas Day")
+
+	case dia.CalendarNYSE:
+		// Holidays on Sundays are observed on Monday, on Saturdays on Friday. New Year's Day on a Saturday is not observed.
+		observed := func(date time.Time) time.Time {
+			switch date.Weekday() {
+			case time.Sunday:
+				return date.AddDate(0, 0, 1)
+			case time.Saturday:
+				return date.AddDate(0, 0, -1)
+			}
+			return date
+		}
+		if newYear := day(year, time.January, 1); newYear.Weekday() != time.Saturday {
+			add(observed(newYear), "New Year's Day")
+		}
+		add(nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day")
+		add(nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday")
+		add(easter.AddDate(0, 0, -2), "Good Friday")
+		add(lastWeekday(year, time.May, time.Monday), "Memorial Day")
+		if year >= 2022 {
+			add(observed(day(year, time.June, 19)), "Juneteenth")
+		}
+		add(observed(day(year, time.July, 4)), "Independence Day")
+		add(nthWeekday(year, time.September, time.Monday, 1), "Labor Day")
+		add(nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day")
+		add(observed(day(year, time.December, 25)), "Christmas Day")
+
+	case dia.CalendarTARGET2:
+		add(day(year, time.January, 1), "New Year's Day")
+		add(easter.AddDate(0, 0, -2), "Good Friday")
+		add(easter.AddDate(0, 0, 1), "Easter Monday")
+		add(day(year, time.May, 1), "Labour Day")
+		add(day(year, time.December, 25), "Christmas Day")
+		add(day(year, time.December, 26), "Boxing Day")
+
+	case dia.CalendarUK:
+		// Holidays on weekends are substituted by the following weekdays.
+		newYear := day(year, time.January, 1)
+		for newYear.Weekday() == time.Saturday || newYear.Weekday() == time.Sunday {
+			newYear = newYear.AddDate(0, 0, 1)
+		}
+		add(newYear, "New Year's Day")
+		add(easter.AddDate(0, 0, -2), "Good Friday")
+		add(easter.AddDate(0, 0, 1), "Easter Monday")
+		add(nthWeekday(year, time.May, time.Monday, 1), "Early May bank holiday")
+		add(lastWeekday(year, time.May, time.Monday), "Spring bank holiday")
+		add(lastWeekday(year, time.August, time.Monday), "Summer bank holiday")
+		christmas, boxingDay := day(year, time.December, 25), day(year, time.December, 26)
+		switch christmas.Weekday() {
+		case time.Friday:
+			boxingDay = day(year, time.December, 28)
+		case time.Saturday:
+			christmas, boxingDay = day(year, time.December, 27), day(year, time.December, 28)
+		case time.Sunday:
+			christmas = day(year, time.December, 27)
+		}
+		add(christmas, "Christmas Day")
+		add(boxingDay, "Boxing Day")
+
+		var regular []dia.Holiday
+		for _, holiday := range holidays {
+			if !ukMovedHolidays[dayKey(holiday.Date)] {
+				regular = append(regular, holiday)
+			}
+		}
+		holidays = regular
+		for date, name := range ukSpecialHolidays {
+			special, _ := time.Parse("2006-01-02", date)
+			if special.Year() == year {
+				add(special, name)
+			}
+		}
+
+	case dia.CalendarCrypto:
+		return nil, nil
+
+	default:
+		return nil, errors.New("unknown calendar " + calendar)
+	}
+
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+	return holidays, nil
+}
+
+// easterSunday returns the date of Easter Sunday in the Gregorian calendar.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := (19*a + b - b/4 - (b-(b+8)/25+1)/3 + 15) % 30
+	e := (32 + 2*(b%4) + 2*(c/4) - d - c%4) % 7
+	f := d + e - 7*((a+11*d+22*e)/451) + 114
+	return day(year, time.Month(f/31), f%31+1)
+}
+
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	date := day(year, month, 1)
+	for date.Weekday() != weekday {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date.AddDate(0, 0, 7*(n-1))
+}
+
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	date := day(year, month+1, 1).AddDate(0, 0, -1)
+	for date.Weekday() != weekday {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func truncateDay(date time.Time) time.Time {
+	return day(date.Year(), date.Month(), date.Day())
+}
+
+func dayKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
@@ -0,0 +1,85 @@
+package ratederivatives
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestGenerateHolidays(t *testing.T) {
+	tables := []struct {
+		calendar string
+		year     int
+		holidays []string
+	}{
+		{dia.CalendarUS, 2020, []string{"2020-01-01", "2020-01-20", "2020-02-17", "2020-04-10", "2020-05-25", "2020-07-03", "2020-09-07", "2020-10-12", "2020-11-11", "2020-11-26", "2020-12-25"}},
+		{dia.CalendarUS, 2022, []string{"2022-01-17", "2022-02-21", "2022-04-15", "2022-05-30", "2022-06-20", "2022-07-04", "2022-09-05", "2022-10-10", "2022-11-11", "2022-11-24", "2022-12-26"}},
+		{dia.CalendarNYSE, 2022, []string{"2022-01-17", "2022-02-21", "2022-04-15", "2022-05-30", "2022-06-20", "2022-07-04", "2022-09-05", "2022-11-24", "2022-12-26"}},
+		{dia.CalendarTARGET2, 2021, []string{"2021-01-01", "2021-04-02", "2021-04-05"}},
+		{dia.CalendarUK, 2020, []string{"2020-01-01", "2020-04-10", "2020-04-13", "2020-05-08", "2020-05-25", "2020-08-31", "2020-12-25", "2020-12-28"}},
+		{dia.CalendarUK, 2021, []string{"2021-01-01", "2021-04-02", "2021-04-05", "2021-05-03", "2021-05-31", "2021-08-30", "2021-12-27", "2021-12-28"}},
+		{dia.CalendarUK, 2022, []string{"2022-01-03", "2022-04-15", "2022-04-18", "2022-05-02", "2022-06-02", "2022-06-03", "2022-08-29", "2022-09-19", "2022-12-26", "2022-12-27"}},
+	}
+	for _, table := range tables {
+		holidays, err := GenerateHolidays(table.calendar, table.year)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dates []string
+		for _, holiday := range holidays {
+			dates = append(dates, dayKey(holiday.Date))
+		}
+		if len(dates) != len(table.holidays) {
+			t.Errorf("%s %d: expected %v, got %v", table.calendar, table.year, table.holidays, dates)
+			continue
+		}
+		for i := range dates {
+			if dates[i] != table.holidays[i] {
+				t.Errorf("%s %d: expected %v, got %v", table.calendar, table.year, table.holidays, dates)
+				break
+			}
+		}
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	holidays, _ := GenerateHolidays(dia.CalendarUS, 2020)
+	calendar := dia.NewCalendar(dia.CalendarUS, holidays)
+	// Thursday before Independence Day, observed on Friday 2020-07-03.
+	thursday := day(2020, time.July, 2)
+	tables := []struct {
+		date time.Time
+		n    int
+		want string
+	}{
+		{thursday, 1, "2020-07-06"},
+		{thursday, 0, "2020-07-02"},
+		{day(2020, time.July, 4), 0, "2020-07-06"},
+		{day(2020, time.July, 4), -1, "2020-07-02"},
+		{day(2020, time.July, 7), -2, "2020-07-02"},
+	}
+	for _, table := range tables {
+		if got := dayKey(calendar.AddBusinessDays(table.date, table.n)); got != table.want {
+			t.Errorf("%s + %d business days: expected %s, got %s", dayKey(table.date), table.n, table.want, got)
+		}
+	}
+}
+
+func TestCryptoCalendar(t *testing.T) {
+	name, err := RateCalendar("COMPOUND-USDC-SUPPLY")
+	if err != nil || name != dia.CalendarCrypto {
+		t.Fatalf("expected calendar %s, got %s, %v", dia.CalendarCrypto, name, err)
+	}
+	if _, err = RateCalendar("UNKNOWN-USDC-SUPPLY"); err == nil {
+		t.Error("expected error for unknown rate")
+	}
+	calendar := dia.NewCalendar(name, nil)
+	saturday := day(2022, time.June, 4)
+	if !calendar.IsBusinessDay(saturday) {
+		t.Errorf("%s should be a business day of %s", dayKey(saturday), name)
+	}
+	if got := dayKey(calendar.AddBusinessDays(saturday, 1)); got != "2022-06-05" {
+		t.Errorf("expected 2022-06-05, got %s", got)
+	}
+}
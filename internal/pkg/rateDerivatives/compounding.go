@@ -0,0 +1,105 @@
+package ratederivatives
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// ErrMissingRate is returned if no rate is published for a business day of the calendar.
+var ErrMissingRate = errors.New("no rate published")
+
+// CompoundInArrears returns the compounded index and the annualized compounded rate in percent of the daily
+// @rates in percent over the interest period [@dateInit, @dateFinal). @rates maps dates in the format
+// 2006-01-02 to the rate published for the respective business day of @calendar.
+func CompoundInArrears(rates map[string]float64, calendar *dia.Calendar, dateInit, dateFinal time.Time, convention dia.CompoundingConvention) (index float64, rate float64, err error) {
+	if convention.DaysPerYear <= 0 {
+		return 0, 0, errors.New("days per year must be a positive integer")
+	}
+	if convention.Lookback < 0 {
+		return 0, 0, errors.New("lookback must not be negative")
+	}
+	dateInit, dateFinal = truncateDay(dateInit), truncateDay(dateFinal)
+	if !dateInit.Before(dateFinal) {
+		return 0, 0, errors.New("the final date must be after the initial date")
+	}
+
+	// The period whose calendar days weight the rates.
+	periodInit, periodFinal := dateInit, dateFinal
+	if convention.ObservationShift && convention.Lookback > 0 {
+		periodInit = calendar.AddBusinessDays(dateInit, -convention.Lookback)
+		periodFinal = calendar.AddBusinessDays(dateFinal, -convention.Lookback)
+	}
+	// Rates accrue from the last business day before the start of the period if it is not a business day.
+	start := periodInit
+	if !calendar.IsBusinessDay(start) {
+		start = calendar.AddBusinessDays(start, -1)
+	}
+
+	index = 1
+	for date := start; date.Before(periodFinal); {
+		next := calendar.AddBusinessDays(date, 1)
+		from, to := date, next
+		if from.Before(periodInit) {
+			from = periodInit
+		}
+		if to.After(periodFinal) {
+			to = periodFinal
+		}
+		days := math.Round(to.Sub(from).Hours() / 24)
+
+		observation := date
+		if !convention.ObservationShift {
+			observation = calendar.AddBusinessDays(date, -convention.Lookback)
+		}
+		value, ok := rates[dayKey(observation)]
+		if !ok {
+			return 0, 0, fmt.Errorf("%w on %s", ErrMissingRate, dayKey(observation))
+		}
+		index *= 1 + value/100*days/float64(convention.DaysPerYear)
+		date = next
+	}
+
+	periodDays := math.Round(periodFinal.Sub(periodInit).Hours() / 24)
+	rate = 100 * (index - 1) * float64(convention.DaysPerYear) / periodDays
+	return round(index, convention.Rounding), round(rate, convention.Rounding), nil
+}
+
+// CompoundDaily returns the compounded index and the annualized compounded rate in percent of @rates over
+// [@dateInit, @dateFinal) compounding on every calendar day. Non-business days are assigned the rate of the
+// preceding business day.
+func CompoundDaily(rates map[string]float64, calendar *dia.Calendar, dateInit, dateFinal time.Time, daysPerYear int, rounding int) (index float64, rate float64, err error) {
+	if daysPerYear <= 0 {
+		return 0, 0, errors.New("days per year must be a positive integer")
+	}
+	dateInit, dateFinal = truncateDay(dateInit), truncateDay(dateFinal)
+	if !dateInit.Before(dateFinal) {
+		return 0, 0, errors.New("the final date must be after the initial date")
+	}
+	index = 1
+	var days float64
+	for date := dateInit; date.Before(dateFinal); date = date.AddDate(0, 0, 1) {
+		observation := date
+		if !calendar.IsBusinessDay(date) {
+			observation = calendar.AddBusinessDays(date, -1)
+		}
+		value, ok := rates[dayKey(observation)]
+		if !ok {
+			return 0, 0, fmt.Errorf("%w on %s", ErrMissingRate, dayKey(observation))
+		}
+		index *= 1 + value/100/float64(daysPerYear)
+		days++
+	}
+	rate = 100 * (index - 1) * float64(daysPerYear) / days
+	return round(index, rounding), round(rate, rounding), nil
+}
+
+func round(value float64, decimals int) float64 {
+	if decimals == 0 {
+		return value
+	}
+	return math.Round(value*math.Pow(10, float64(decimals))) / math.Pow(10, float64(decimals))
+}
@@ -0,0 +1,139 @@
+package ratederivatives
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// sofrRates are the first published SOFR rates.
+var sofrRates = map[string]float64{
+	"2018-04-02": 1.80,
+	"2018-04-03": 1.83,
+	"2018-04-04": 1.74,
+	"2018-04-05": 1.75,
+	"2018-04-06": 1.75,
+	"2018-04-09": 1.75,
+}
+
+func TestCompoundInArrears(t *testing.T) {
+	calendar := dia.NewCalendar(dia.CalendarUS, nil)
+	convention := dia.CompoundingConvention{DaysPerYear: 360, Rounding: 8}
+	base := day(2018, time.April, 2)
+
+	// Published values of the SOFR Index with base date 2018-04-02.
+	tables := []struct {
+		date  time.Time
+		index float64
+	}{
+		{day(2018, time.April, 3), 1.00005000},
+		{day(2018, time.April, 4), 1.00010084},
+		{day(2018, time.April, 5), 1.00014917},
+		{day(2018, time.April, 6), 1.00019779},
+		{day(2018, time.April, 9), 1.00034365},
+		{day(2018, time.April, 10), 1.00039228},
+	}
+	for _, table := range tables {
+		index, _, err := CompoundInArrears(sofrRates, calendar, base, table.date, convention)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index != table.index {
+			t.Errorf("SOFR Index on %s: expected %v, got %v", dayKey(table.date), table.index, index)
+		}
+	}
+
+	// The rate of Friday accrues over the weekend.
+	index, rate, err := CompoundInArrears(sofrRates, calendar, base, day(2018, time.April, 9), dia.CompoundingConvention{DaysPerYear: 360})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := (1 + 1.80/36000) * (1 + 1.83/36000) * (1 + 1.74/36000) * (1 + 1.75/36000) * (1 + 3*1.75/36000)
+	if math.Abs(index-expected) > 1e-12 {
+		t.Errorf("index over weekend: expected %v, got %v", expected, index)
+	}
+	if math.Abs(rate-100*(expected-1)*360/7) > 1e-9 {
+		t.Errorf("rate over weekend: expected %v, got %v", 100*(expected-1)*360/7, rate)
+	}
+}
+
+func TestCompoundInArrearsESTER(t *testing.T) {
+	// The first published ESTER for 2019-10-01, the base date of the compounded ESTER index of the ECB,
+	// and the published index of 2019-10-02 scaled from base value 100 to 1.
+	rates := map[string]float64{"2019-10-01": -0.549}
+	calendar := dia.NewCalendar(dia.CalendarTARGET2, nil)
+	index, _, err := CompoundInArrears(rates, calendar, day(2019, time.October, 1), day(2019, time.October, 2), dia.CompoundingConvention{DaysPerYear: 360, Rounding: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index != 0.99998475 {
+		t.Errorf("compounded ESTER index on 2019-10-02: expected 0.99998475, got %v", index)
+	}
+}
+
+func TestCompoundInArrearsSONIA(t *testing.T) {
+	// SONIA accrues on an ACT/365 basis over the bank holidays of England and Wales, such as the
+	// early May bank holiday on 2018-05-07.
+	holidays, err := GenerateHolidays(dia.CalendarUK, 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calendar := dia.NewCalendar(dia.CalendarUK, holidays)
+	rates := map[string]float64{"2018-05-03": 0.4, "2018-05-04": 0.5}
+	index, rate, err := CompoundInArrears(rates, calendar, day(2018, time.May, 3), day(2018, time.May, 8), dia.CompoundingConvention{DaysPerYear: 365})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := (1 + 0.4/36500) * (1 + 4*0.5/36500)
+	if math.Abs(index-expected) > 1e-12 {
+		t.Errorf("index over bank holiday: expected %v, got %v", expected, index)
+	}
+	if math.Abs(rate-100*(expected-1)*365/5) > 1e-9 {
+		t.Errorf("rate over bank holiday: expected %v, got %v", 100*(expected-1)*365/5, rate)
+	}
+}
+
+func TestCompoundInArrearsLookback(t *testing.T) {
+	calendar := dia.NewCalendar(dia.CalendarUS, nil)
+	// Interest period from Monday to Tuesday of the following week with a lookback of 2 business days.
+	dateInit, dateFinal := day(2018, time.April, 4), day(2018, time.April, 10)
+
+	// Without shift, the rates observed 2 business days earlier are weighted with the days of the interest period.
+	index, _, err := CompoundInArrears(sofrRates, calendar, dateInit, dateFinal, dia.CompoundingConvention{DaysPerYear: 360, Lookback: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := (1 + 1.80/36000) * (1 + 1.83/36000) * (1 + 3*1.74/36000) * (1 + 1.75/36000)
+	if math.Abs(index-expected) > 1e-12 {
+		t.Errorf("lookback without shift: expected %v, got %v", expected, index)
+	}
+
+	// With shift, the rates are weighted with the days of the observation period from 04-02 to 04-06.
+	index, _, err = CompoundInArrears(sofrRates, calendar, dateInit, dateFinal, dia.CompoundingConvention{DaysPerYear: 360, Lookback: 2, ObservationShift: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = (1 + 1.80/36000) * (1 + 1.83/36000) * (1 + 1.74/36000) * (1 + 1.75/36000)
+	if math.Abs(index-expected) > 1e-12 {
+		t.Errorf("lookback with shift: expected %v, got %v", expected, index)
+	}
+
+	// Missing rates are reported instead of being treated as holidays.
+	if _, _, err = CompoundInArrears(sofrRates, calendar, day(2018, time.March, 29), dateFinal, dia.CompoundingConvention{DaysPerYear: 360}); err == nil {
+		t.Error("expected error for missing rate")
+	}
+}
+
+func TestCompoundDaily(t *testing.T) {
+	calendar := dia.NewCalendar(dia.CalendarUS, nil)
+	index, _, err := CompoundDaily(sofrRates, calendar, day(2018, time.April, 6), day(2018, time.April, 10), 360, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := math.Pow(1+1.75/36000, 4)
+	if math.Abs(index-expected) > 1e-12 {
+		t.Errorf("expected %v, got %v", expected, index)
+	}
+}
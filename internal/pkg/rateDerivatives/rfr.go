@@ -27,7 +27,7 @@ func RateFactor(date time.Time, holidays []time.Time) (int, error) {
 // @holidays is a slice of strings where each entry corresponds to a special holiday (i.e. not a
 // 			saturday or sunday) in the respective period.
 // @daysPerYear determines the total number of days per business year.
-// @rounding is a float of type 1e-n which rounds the result to n digits. If @rounding == 0 no rounding
+// @rounding is the number of decimals of the result. If @rounding == 0 no rounding
 func CompoundedRate(rates []float64, dateInit, dateFinal time.Time, holidays []time.Time, daysPerYear int, rounding int) (float64, error) {
 
 	// Check feasibility and consistency of input data
@@ -44,7 +44,12 @@ func CompoundedRate(rates []float64, dateInit, dateFinal time.Time, holidays []t
 		return float64(0), errors.New("days per year must be a positive integer")
 	}
 	NumBusinessDays, _ := utils.CountDays(dateInit, dateFinal, true)
-	NumBusinessDays -= len(holidays)
+	// Only holidays on weekdays in the period reduce the number of business days.
+	for _, holiday := range holidays {
+		if utils.CheckWeekDay(holiday) && !utils.AfterDay(dateInit, holiday) && utils.AfterDay(dateFinal, holiday) {
+			NumBusinessDays--
+		}
+	}
 	if NumBusinessDays == 0 {
 		log.Info("No business days in period of interest.")
 		return float64(0), errors.New("no business days in period of interest")
@@ -85,7 +90,7 @@ func CompoundedRate(rates []float64, dateInit, dateFinal time.Time, holidays []t
 // @rates is a slice with daily rates for all calendar days in the respective period.
 // @dateInit, @dateFinal determine the period of the loan.
 // @daysPerYear determines the total number of days per business year.
-// @rounding is a float of type 1e-n which rounds the result to n digits. If @rounding == 0 no rounding
+// @rounding is the number of decimals of the result. If @rounding == 0 no rounding
 func CompoundedRateSimple(rates []float64, dateInit, dateFinal time.Time, daysPerYear int, rounding int) (float64, error) {
 
 	// Check feasibility and consistency of input data
@@ -34,14 +34,17 @@ func TestRateFactor(t *testing.T) {
 }
 
 func TestCompoundedRate(t *testing.T) {
-	tol := 10e-8
-	rates1 := []float64{0, 0, 0}
-	rates2 := []float64{1.1, 1.2, 1.5}
-	date1, _ := time.Parse("2006-01-02 15:04:05", "2020-04-01 14:22:55")
-	date2, _ := time.Parse("2006-01-02", "2020-04-02")
-	date3, _ := time.Parse("2006-01-02", "2020-04-03")
-	date4, _ := time.Parse("2006-01-02", "2020-04-06")
-	daysPerYear := 365
+	// Published SOFR rates of the first week and the published SOFR Index with base date 2018-04-02.
+	sofr := []float64{1.80, 1.83, 1.74, 1.75, 1.75, 1.75}
+	date1, _ := time.Parse("2006-01-02 15:04:05", "2018-04-02 14:22:55")
+	date2, _ := time.Parse("2006-01-02", "2018-04-03")
+	date3, _ := time.Parse("2006-01-02", "2018-04-04")
+	date4, _ := time.Parse("2006-01-02", "2018-04-06")
+	date5, _ := time.Parse("2006-01-02", "2018-04-09")
+	date6, _ := time.Parse("2006-01-02", "2018-04-10")
+	sunday, _ := time.Parse("2006-01-02", "2018-04-08")
+	memorialDay, _ := time.Parse("2006-01-02", "2018-05-28")
+	daysPerYear := 360
 
 	tables := []struct {
 		rates       []float64
@@ -51,21 +54,24 @@ func TestCompoundedRate(t *testing.T) {
 		daysPerYear int
 		rounding    int
 		cumRate     float64
-		err         error
 	}{
-		{rates1[:2], date1, date3, []time.Time{}, daysPerYear, 0, 0, nil},
-		{rates2[:2], date1, date3, []time.Time{}, daysPerYear, 0, 1.151808219, nil},
-		{rates2, date1, date4, []time.Time{}, daysPerYear, 0, 1.366403438, nil},
-		{rates2[:2], date1, date4, []time.Time{date2}, daysPerYear, 0, 1.164339726, nil},
-		{rates2[0:1], date1, date2, []time.Time{}, daysPerYear, 0, rates2[0], nil},
+		{sofr[:1], date1, date2, []time.Time{}, daysPerYear, 8, 1.00005000},
+		{sofr[:2], date1, date3, []time.Time{}, daysPerYear, 8, 1.00010084},
+		{sofr[:4], date1, date4, []time.Time{}, daysPerYear, 8, 1.00019779},
+		// The rate of Friday accrues over the weekend.
+		{sofr[:5], date1, date5, []time.Time{}, daysPerYear, 8, 1.00034365},
+		// Holidays outside the period or on weekends do not change the number of business days.
+		{sofr, date1, date6, []time.Time{sunday, memorialDay}, daysPerYear, 8, 1.00039228},
+		// The rate of the business day before a holiday accrues over the holiday.
+		{sofr[:1], date1, date3, []time.Time{date2}, daysPerYear, 0, 1 + 2*1.80/36000},
 	}
 	for _, table := range tables {
 		value, err := CompoundedRate(table.rates, table.dateInit, table.dateFinal, table.holidays, table.daysPerYear, table.rounding)
-		if math.Abs(value-table.cumRate) > tol {
-			t.Errorf("Rate factor is %v but should be %v.", value, table.cumRate)
-		}
 		if err != nil {
-			t.Errorf("Error should be %v but is %v", table.err, err)
+			t.Errorf("Error should be nil but is %v", err)
+		}
+		if math.Abs(value-table.cumRate) > 1e-12 {
+			t.Errorf("Compounded rate is %v but should be %v.", value, table.cumRate)
 		}
 	}
 }
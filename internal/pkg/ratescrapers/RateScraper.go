@@ -6,6 +6,7 @@ import (
 	"time"
 
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/ethclient"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -29,6 +30,9 @@ type RateScraper struct {
 	ticker           *time.Ticker
 	datastore        models.Datastore
 	chanInterestRate chan *models.InterestRate
+	// connection and last update times for on-chain rates
+	ethClient  *ethclient.Client
+	lastUpdate map[string]time.Time
 }
 
 // SpawnRateScraper returns a new RateScraper initialized with default values.
@@ -41,6 +45,7 @@ func SpawnRateScraper(datastore models.Datastore, rateType string) *RateScraper
 		ticker:           time.NewTicker(refreshDelay),
 		datastore:        datastore,
 		chanInterestRate: make(chan *models.InterestRate),
+		lastUpdate:       make(map[string]time.Time),
 	}
 
 	log.Info("Rate scraper is built and triggered")
@@ -100,6 +105,15 @@ func (s *RateScraper) Channel() chan *models.InterestRate {
 
 // Update calls the appropriate function corresponding to the rate type.
 func (s *RateScraper) Update(rateType string) error {
+	switch rateType {
+	case "AAVEV2", "AAVEV3", "COMPOUND", "LIDO", "ROCKETPOOL":
+		// On-chain rates are read from an Ethereum node and updated less frequently.
+		if time.Since(s.lastUpdate[rateType]) < defiRefreshDelay {
+			return nil
+		}
+		s.lastUpdate[rateType] = time.Now()
+	}
+
 	switch rateType {
 	case "ESTER":
 		return s.UpdateESTER()
@@ -112,6 +126,16 @@ func (s *RateScraper) Update(rateType string) error {
 		return s.UpdateSAFRAvgs()
 	case "SONIA":
 		return s.UpdateSonia()
+	case "AAVEV2":
+		return s.UpdateAave(2)
+	case "AAVEV3":
+		return s.UpdateAave(3)
+	case "COMPOUND":
+		return s.UpdateCompound()
+	case "LIDO":
+		return s.UpdateStaking(lidoToken)
+	case "ROCKETPOOL":
+		return s.UpdateStaking(rocketPoolToken)
 	}
 	return errors.New("Error: " + rateType + " does not exist in database")
 }
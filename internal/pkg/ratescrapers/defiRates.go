@@ -0,0 +1,87 @@
+package ratescrapers
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// On-chain rates are stored as annual rates in percent with daily compounding over 365 days, such that
+// the compounding endpoints with 365 days per year reproduce the yield of the protocol. Rates are stored
+// once per calendar day, later updates on the same day overwrite the earlier ones.
+const (
+	// defiRefreshDelay is the minimal time between two updates of the on-chain rates of a protocol.
+	defiRefreshDelay = time.Hour
+	defiDaysPerYear  = 365
+	// ethBlocksPerDay is the number of Ethereum blocks per day with a block time of 12 seconds.
+	ethBlocksPerDay = 7200
+	secondsPerYear  = 365 * 24 * 60 * 60
+)
+
+// Sides of on-chain lending rates.
+const (
+	rateSideSupply  = "SUPPLY"
+	rateSideBorrow  = "BORROW"
+	rateSideStaking = "STAKING"
+)
+
+// defiRateSymbol returns the symbol of the rate of @asset in @protocol, such as COMPOUND-USDC-SUPPLY.
+// The protocol prefixes are registered with the crypto calendar in package ratederivatives.
+func defiRateSymbol(protocol, asset, side string) string {
+	return protocol + "-" + strings.ToUpper(asset) + "-" + side
+}
+
+// newDeFiRate returns the rate @value of @symbol published by @source at @publicationTime.
+func newDeFiRate(symbol string, value float64, source string, publicationTime time.Time) *models.InterestRate {
+	publicationTime = publicationTime.UTC()
+	return &models.InterestRate{
+		Symbol:          symbol,
+		Value:           value,
+		PublicationTime: publicationTime,
+		EffectiveDate:   time.Date(publicationTime.Year(), publicationTime.Month(), publicationTime.Day(), 0, 0, 0, 0, time.UTC),
+		Source:          source,
+	}
+}
+
+// aaveRate converts an Aave rate in ray, i.e. an annual rate accruing per second scaled by 1e27,
+// into an annual rate in percent with daily compounding.
+func aaveRate(ray *big.Int) float64 {
+	apr, _ := new(big.Float).Quo(new(big.Float).SetInt(ray), big.NewFloat(1e27)).Float64()
+	perSecond := apr / secondsPerYear
+	dailyFactor := math.Pow(1+perSecond, secondsPerYear/defiDaysPerYear)
+	return 100 * defiDaysPerYear * (dailyFactor - 1)
+}
+
+// compoundRate converts a Compound rate per block scaled by 1e18 into an annual rate in percent with
+// daily compounding, as in the APY published by Compound.
+func compoundRate(ratePerBlock *big.Int, blocksPerDay float64) float64 {
+	rate, _ := new(big.Float).Quo(new(big.Float).SetInt(ratePerBlock), big.NewFloat(1e18)).Float64()
+	return 100 * defiDaysPerYear * rate * blocksPerDay
+}
+
+// stakingRate returns the annual rate in percent with daily compounding of a liquid staking token whose
+// exchange rate to the staked asset grew from @rateInit to @rateFinal in @days days.
+func stakingRate(rateInit, rateFinal *big.Int, days float64) float64 {
+	growth, _ := new(big.Float).Quo(new(big.Float).SetInt(rateFinal), new(big.Float).SetInt(rateInit)).Float64()
+	return 100 * defiDaysPerYear * (math.Pow(growth, 1/days) - 1)
+}
+
+// getEthClient returns the connection to the Ethereum node given by the environment variable ETHEREUM_URI_REST.
+// Staking rates are computed from historic states, so the node must serve archive data.
+func (s *RateScraper) getEthClient() (*ethclient.Client, error) {
+	if s.ethClient != nil {
+		return s.ethClient, nil
+	}
+	client, err := ethclient.Dial(utils.Getenv(strings.ToUpper(dia.ETHEREUM)+"_URI_REST", ""))
+	if err != nil {
+		return nil, err
+	}
+	s.ethClient = client
+	return client, nil
+}
@@ -0,0 +1,43 @@
+package ratescrapers
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDeFiRateConversions(t *testing.T) {
+	tol := 1e-9
+
+	// 5% per year accruing per second is close to 5% with daily compounding.
+	ray, _ := new(big.Int).SetString("50000000000000000000000000", 10)
+	expected := 100 * 365 * (math.Pow(1+0.05/secondsPerYear, secondsPerYear/365) - 1)
+	if rate := aaveRate(ray); math.Abs(rate-expected) > tol || math.Abs(rate-5.00034) > 1e-4 {
+		t.Errorf("aave rate: expected %v, got %v", expected, rate)
+	}
+
+	// A rate per block of 1e-9 at 7200 blocks per day.
+	if rate := compoundRate(big.NewInt(1000000000), ethBlocksPerDay); math.Abs(rate-100*365*7200*1e-9) > tol {
+		t.Errorf("compound rate: expected %v, got %v", 100*365*7200*1e-9, rate)
+	}
+
+	// An exchange rate growing by 0.1% in 7 days.
+	rateInit, rateFinal := big.NewInt(1000000), big.NewInt(1001000)
+	expected = 100 * 365 * (math.Pow(1.001, 1.0/7) - 1)
+	if rate := stakingRate(rateInit, rateFinal, 7); math.Abs(rate-expected) > tol {
+		t.Errorf("staking rate: expected %v, got %v", expected, rate)
+	}
+}
+
+func TestNewDeFiRate(t *testing.T) {
+	publicationTime := time.Date(2022, time.June, 7, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+	ir := newDeFiRate(defiRateSymbol("COMPOUND", "usdc", rateSideSupply), 2.5, "Compound", publicationTime)
+	if ir.Symbol != "COMPOUND-USDC-SUPPLY" {
+		t.Errorf("expected symbol COMPOUND-USDC-SUPPLY, got %s", ir.Symbol)
+	}
+	// Rates are stored under the UTC day of publication.
+	if !ir.EffectiveDate.Equal(time.Date(2022, time.June, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected effective date 2022-06-08, got %v", ir.EffectiveDate)
+	}
+}
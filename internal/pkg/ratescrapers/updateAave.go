@@ -0,0 +1,110 @@
+package ratescrapers
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/config/synthContracts/aavepool2"
+	"github.com/diadata-org/diadata/config/synthContracts/aavepool3"
+	ceth "github.com/diadata-org/diadata/config/synthContracts/cETH"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	aaveV2PoolAddress = "0x7d2768dE32b0b80b7a3454c06BdAc94A69DDc7A9"
+	aaveV3PoolAddress = "0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2"
+)
+
+// aaveReserve holds the current rates of a reserve of an Aave lending pool in ray.
+type aaveReserve struct {
+	asset      common.Address
+	supplyRate *big.Int
+	borrowRate *big.Int
+}
+
+// UpdateAave sends the supply and variable borrow rates of all reserves of the Aave lending pool of
+// @version on Ethereum. The pool address can be set by the environment variable AAVE_V<version>_POOL.
+func (s *RateScraper) UpdateAave(version int) error {
+	log.Printf("AaveV%d Update\n", version)
+	client, err := s.getEthClient()
+	if err != nil {
+		return err
+	}
+
+	var reserves []aaveReserve
+	switch version {
+	case 2:
+		reserves, err = aaveV2Reserves(client, common.HexToAddress(utils.Getenv("AAVE_V2_POOL", aaveV2PoolAddress)))
+	case 3:
+		reserves, err = aaveV3Reserves(client, common.HexToAddress(utils.Getenv("AAVE_V3_POOL", aaveV3PoolAddress)))
+	}
+	if err != nil {
+		return err
+	}
+
+	protocol := "AAVEV" + strconv.Itoa(version)
+	source := "Aave V" + strconv.Itoa(version)
+	now := time.Now()
+	for _, reserve := range reserves {
+		token, err := ceth.NewERC20Caller(reserve.asset, client)
+		if err != nil {
+			log.Error("new erc20 caller: ", err)
+			continue
+		}
+		// Tokens such as MKR return the symbol as bytes32 and are skipped.
+		symbol, err := token.Symbol(&bind.CallOpts{})
+		if err != nil {
+			log.Warnf("get symbol of %s: %v", reserve.asset.Hex(), err)
+			continue
+		}
+		s.chanInterestRate <- newDeFiRate(defiRateSymbol(protocol, symbol, rateSideSupply), aaveRate(reserve.supplyRate), source, now)
+		s.chanInterestRate <- newDeFiRate(defiRateSymbol(protocol, symbol, rateSideBorrow), aaveRate(reserve.borrowRate), source, now)
+	}
+	log.Info("Update complete")
+	return nil
+}
+
+func aaveV2Reserves(client *ethclient.Client, pool common.Address) (reserves []aaveReserve, err error) {
+	caller, err := aavepool2.NewAavepool2Caller(pool, client)
+	if err != nil {
+		return
+	}
+	assets, err := caller.GetReservesList(&bind.CallOpts{})
+	if err != nil {
+		return
+	}
+	for _, asset := range assets {
+		data, err := caller.GetReserveData(&bind.CallOpts{}, asset)
+		if err != nil {
+			log.Errorf("GetReserveData of %s: %v", asset.Hex(), err)
+			continue
+		}
+		reserves = append(reserves, aaveReserve{asset: asset, supplyRate: data.CurrentLiquidityRate, borrowRate: data.CurrentVariableBorrowRate})
+	}
+	return reserves, nil
+}
+
+func aaveV3Reserves(client *ethclient.Client, pool common.Address) (reserves []aaveReserve, err error) {
+	caller, err := aavepool3.NewAavepool3Caller(pool, client)
+	if err != nil {
+		return
+	}
+	assets, err := caller.GetReservesList(&bind.CallOpts{})
+	if err != nil {
+		return
+	}
+	for _, asset := range assets {
+		data, err := caller.GetReserveData(&bind.CallOpts{}, asset)
+		if err != nil {
+			log.Errorf("GetReserveData of %s: %v", asset.Hex(), err)
+			continue
+		}
+		reserves = append(reserves, aaveReserve{asset: asset, supplyRate: data.CurrentLiquidityRate, borrowRate: data.CurrentVariableBorrowRate})
+	}
+	return reserves, nil
+}
@@ -0,0 +1,51 @@
+package ratescrapers
+
+import (
+	"time"
+
+	ceth "github.com/diadata-org/diadata/config/synthContracts/cETH"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// compoundMarkets maps the underlying assets of Compound markets on Ethereum to their cToken addresses.
+var compoundMarkets = map[string]string{
+	"ETH":  "0x4Ddc2D193948926D02f9B1fE9e1daa0718270ED5",
+	"DAI":  "0x5d3a536E4D6DbD6114cc1Ead35777bAB948E3643",
+	"USDC": "0x39AA39c021dfc3eC372d6Fd4D6e1de1b36C1FdC5",
+	"USDT": "0xf650C3d88D12dB855b8bf7D11Be6C55A4e07dCC9",
+	"WBTC": "0xccF4429DB6322D5C611ee964527D42E5d685DD6a",
+}
+
+// UpdateCompound sends the supply and borrow rates of the Compound markets in compoundMarkets.
+func (s *RateScraper) UpdateCompound() error {
+	log.Printf("Compound Update\n")
+	client, err := s.getEthClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for asset, address := range compoundMarkets {
+		cToken, err := ceth.NewERC20Caller(common.HexToAddress(address), client)
+		if err != nil {
+			log.Error("new cToken caller: ", err)
+			continue
+		}
+		supplyRate, err := cToken.SupplyRatePerBlock(&bind.CallOpts{})
+		if err != nil {
+			log.Errorf("SupplyRatePerBlock of c%s: %v", asset, err)
+			continue
+		}
+		borrowRate, err := cToken.BorrowRatePerBlock(&bind.CallOpts{})
+		if err != nil {
+			log.Errorf("BorrowRatePerBlock of c%s: %v", asset, err)
+			continue
+		}
+		s.chanInterestRate <- newDeFiRate(defiRateSymbol("COMPOUND", asset, rateSideSupply), compoundRate(supplyRate, ethBlocksPerDay), "Compound", now)
+		s.chanInterestRate <- newDeFiRate(defiRateSymbol("COMPOUND", asset, rateSideBorrow), compoundRate(borrowRate, ethBlocksPerDay), "Compound", now)
+	}
+	log.Info("Update complete")
+	return nil
+}
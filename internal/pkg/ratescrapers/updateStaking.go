@@ -0,0 +1,79 @@
+package ratescrapers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// stakingWindowDays is the number of days over which the growth of the exchange rate of
+// liquid staking tokens is observed.
+const stakingWindowDays = 7
+
+// stakingToken is a liquid staking token whose exchange rate to the staked asset is returned by method.
+type stakingToken struct {
+	protocol string
+	source   string
+	symbol   string
+	address  string
+	method   string
+}
+
+var (
+	lidoToken       = stakingToken{protocol: "LIDO", source: "Lido", symbol: "STETH", address: "0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0", method: "stEthPerToken()"}
+	rocketPoolToken = stakingToken{protocol: "ROCKETPOOL", source: "Rocket Pool", symbol: "RETH", address: "0xae78736Cd615f374D3085123A210448E74Fc6393", method: "getExchangeRate()"}
+)
+
+// UpdateStaking sends the staking yield of @token, derived from the growth of its exchange rate
+// over the last stakingWindowDays days.
+func (s *RateScraper) UpdateStaking(token stakingToken) error {
+	log.Printf("%s Update\n", token.source)
+	client, err := s.getEthClient()
+	if err != nil {
+		return err
+	}
+
+	header, err := client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	pastHeader, err := client.HeaderByNumber(context.Background(), new(big.Int).Sub(header.Number, big.NewInt(stakingWindowDays*ethBlocksPerDay)))
+	if err != nil {
+		return err
+	}
+
+	rateFinal, err := exchangeRateAt(client, token, header.Number)
+	if err != nil {
+		return err
+	}
+	rateInit, err := exchangeRateAt(client, token, pastHeader.Number)
+	if err != nil {
+		return err
+	}
+	if rateInit.Sign() == 0 {
+		return errors.New("zero exchange rate of " + token.symbol)
+	}
+
+	days := float64(header.Time-pastHeader.Time) / (24 * 60 * 60)
+	publicationTime := time.Unix(int64(header.Time), 0)
+	s.chanInterestRate <- newDeFiRate(defiRateSymbol(token.protocol, token.symbol, rateSideStaking), stakingRate(rateInit, rateFinal, days), token.source, publicationTime)
+	log.Info("Update complete")
+	return nil
+}
+
+// exchangeRateAt returns the exchange rate of @token at @block.
+func exchangeRateAt(client *ethclient.Client, token stakingToken, block *big.Int) (*big.Int, error) {
+	contract := common.HexToAddress(token.address)
+	result, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &contract, Data: crypto.Keccak256([]byte(token.method))[:4]}, block)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(result), nil
+}
@@ -0,0 +1,360 @@
+package writers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// bitflyerExecutionsPrefix is the prefix of the Bitflyer channels with the executions of an instrument.
+const bitflyerExecutionsPrefix = "lightning_executions_"
+
+type bitmexMessage struct {
+	Table string          `json:"table"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type bitmexTrade struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Symbol     string    `json:"symbol"`
+	Side       string    `json:"side"`
+	Size       float64   `json:"size"`
+	Price      float64   `json:"price"`
+	TrdMatchID string    `json:"trdMatchID"`
+}
+
+// bitmexInstrument is an entry of the instrument table. Updates only contain the changed fields.
+type bitmexInstrument struct {
+	Timestamp             time.Time `json:"timestamp"`
+	Symbol                string    `json:"symbol"`
+	MarkPrice             *float64  `json:"markPrice"`
+	IndicativeSettlePrice *float64  `json:"indicativeSettlePrice"`
+	OpenInterest          *float64  `json:"openInterest"`
+}
+
+type bitmexFunding struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Symbol      string    `json:"symbol"`
+	FundingRate float64   `json:"fundingRate"`
+}
+
+// ParseBitmexLine parses messages of the trade, instrument and funding tables of the Bitmex websocket.
+func ParseBitmexLine(line string) (records DerivativeRecords, err error) {
+	var message bitmexMessage
+	if err = json.Unmarshal([]byte(line), &message); err != nil || len(message.Data) == 0 {
+		return
+	}
+	switch message.Table {
+	case "trade":
+		var trades []bitmexTrade
+		if err = json.Unmarshal(message.Data, &trades); err != nil {
+			return
+		}
+		for _, t := range trades {
+			volume := t.Size
+			if t.Side == "Sell" {
+				volume = -volume
+			}
+			records.Trades = append(records.Trades, dia.FuturesTrade{
+				Exchange:       "Bitmex",
+				Instrument:     t.Symbol,
+				Underlying:     bitmexUnderlying(t.Symbol),
+				Kind:           bitmexKind(t.Symbol),
+				Price:          t.Price,
+				Volume:         volume,
+				ForeignTradeID: t.TrdMatchID,
+				Time:           t.Timestamp,
+			})
+		}
+	case "instrument":
+		var instruments []bitmexInstrument
+		if err = json.Unmarshal(message.Data, &instruments); err != nil {
+			return
+		}
+		for _, i := range instruments {
+			if i.MarkPrice != nil {
+				price := dia.DerivativePrice{Exchange: "Bitmex", Instrument: i.Symbol, MarkPrice: *i.MarkPrice, Time: i.Timestamp}
+				if i.IndicativeSettlePrice != nil {
+					price.IndexPrice = *i.IndicativeSettlePrice
+				}
+				records.Prices = append(records.Prices, price)
+			}
+			if i.OpenInterest != nil {
+				records.OpenInterests = append(records.OpenInterests, dia.OpenInterest{Exchange: "Bitmex", Instrument: i.Symbol, OpenInterest: *i.OpenInterest, Time: i.Timestamp})
+			}
+		}
+	case "funding":
+		var fundings []bitmexFunding
+		if err = json.Unmarshal(message.Data, &fundings); err != nil {
+			return
+		}
+		for _, f := range fundings {
+			records.FundingRates = append(records.FundingRates, dia.FundingRate{Exchange: "Bitmex", Instrument: f.Symbol, Rate: f.FundingRate, Time: f.Timestamp})
+		}
+	}
+	return
+}
+
+// bitmexUnderlying returns the underlying of symbols such as XBTUSD or ETHZ20.
+func bitmexUnderlying(symbol string) string {
+	if len(symbol) < 3 {
+		return symbol
+	}
+	if symbol[:3] == "XBT" {
+		return "BTC"
+	}
+	return symbol[:3]
+}
+
+// bitmexKind distinguishes futures with expiry codes such as XBTZ20 from perpetual swaps such as XBTUSD.
+func bitmexKind(symbol string) string {
+	if len(symbol) > 2 && strings.ContainsAny(symbol[len(symbol)-2:], "0123456789") {
+		return dia.DerivativeFuture
+	}
+	return dia.DerivativePerpetual
+}
+
+type deribitSubscriptionMessage struct {
+	Method string `json:"method"`
+	Params struct {
+		Channel string          `json:"channel"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"params"`
+}
+
+type deribitTrade struct {
+	TradeID        string  `json:"trade_id"`
+	Timestamp      int64   `json:"timestamp"`
+	Price          float64 `json:"price"`
+	InstrumentName string  `json:"instrument_name"`
+	Direction      string  `json:"direction"`
+	Amount         float64 `json:"amount"`
+}
+
+type deribitTicker struct {
+	Timestamp       int64    `json:"timestamp"`
+	InstrumentName  string   `json:"instrument_name"`
+	MarkPrice       float64  `json:"mark_price"`
+	IndexPrice      float64  `json:"index_price"`
+	OpenInterest    float64  `json:"open_interest"`
+	Funding8h       *float64 `json:"funding_8h"`
+	BestBidPrice    float64  `json:"best_bid_price"`
+	BestBidAmount   float64  `json:"best_bid_amount"`
+	BestAskPrice    float64  `json:"best_ask_price"`
+	BestAskAmount   float64  `json:"best_ask_amount"`
+	UnderlyingPrice float64  `json:"underlying_price"`
+}
+
+// ParseDeribitLine parses notifications of the public trades and ticker channels of the Deribit websocket.
+// Tickers of options are stored as order book snapshots, tickers of futures as prices, open interest and funding.
+func ParseDeribitLine(line string) (records DerivativeRecords, err error) {
+	var message deribitSubscriptionMessage
+	if err = json.Unmarshal([]byte(line), &message); err != nil || message.Method != "subscription" {
+		return
+	}
+	switch strings.SplitN(message.Params.Channel, ".", 2)[0] {
+	case "trades":
+		var trades []deribitTrade
+		if err = json.Unmarshal(message.Params.Data, &trades); err != nil {
+			return
+		}
+		for _, t := range trades {
+			volume := t.Amount
+			if t.Direction == "sell" {
+				volume = -volume
+			}
+			records.Trades = append(records.Trades, dia.FuturesTrade{
+				Exchange:       "Deribit",
+				Instrument:     t.InstrumentName,
+				Underlying:     strings.SplitN(t.InstrumentName, "-", 2)[0],
+				Kind:           deribitKind(t.InstrumentName),
+				Price:          t.Price,
+				Volume:         volume,
+				ForeignTradeID: t.TradeID,
+				Time:           time.Unix(0, t.Timestamp*int64(time.Millisecond)).UTC(),
+			})
+		}
+	case "ticker":
+		var t deribitTicker
+		if err = json.Unmarshal(message.Params.Data, &t); err != nil {
+			return
+		}
+		timestamp := time.Unix(0, t.Timestamp*int64(time.Millisecond)).UTC()
+		if deribitKind(t.InstrumentName) == dia.DerivativeOption {
+			underlying, expiry, strike, optionType, errParse := dia.ParseOptionInstrument(t.InstrumentName)
+			if errParse != nil {
+				return records, errParse
+			}
+			records.Options = append(records.Options, dia.OptionOrderbookSnapshot{
+				Exchange:        "Deribit",
+				Instrument:      t.InstrumentName,
+				Underlying:      underlying,
+				OptionType:      optionType,
+				Strike:          strike,
+				Expiry:          expiry,
+				BidPrice:        t.BestBidPrice,
+				BidSize:         t.BestBidAmount,
+				AskPrice:        t.BestAskPrice,
+				AskSize:         t.BestAskAmount,
+				UnderlyingPrice: t.UnderlyingPrice,
+				Time:            timestamp,
+			})
+			return
+		}
+		records.Prices = append(records.Prices, dia.DerivativePrice{Exchange: "Deribit", Instrument: t.InstrumentName, MarkPrice: t.MarkPrice, IndexPrice: t.IndexPrice, Time: timestamp})
+		records.OpenInterests = append(records.OpenInterests, dia.OpenInterest{Exchange: "Deribit", Instrument: t.InstrumentName, OpenInterest: t.OpenInterest, Time: timestamp})
+		if t.Funding8h != nil {
+			records.FundingRates = append(records.FundingRates, dia.FundingRate{Exchange: "Deribit", Instrument: t.InstrumentName, Rate: *t.Funding8h, Time: timestamp})
+		}
+	}
+	return
+}
+
+// deribitKind returns the kind of Deribit instruments such as BTC-PERPETUAL, BTC-25DEC20 and BTC-25DEC20-20000-C.
+func deribitKind(instrument string) string {
+	switch {
+	case strings.HasSuffix(instrument, "-PERPETUAL"):
+		return dia.DerivativePerpetual
+	case strings.Count(instrument, "-") == 3:
+		return dia.DerivativeOption
+	default:
+		return dia.DerivativeFuture
+	}
+}
+
+type huobiTradeMessage struct {
+	Channel string `json:"ch"`
+	Tick    struct {
+		Data []struct {
+			ID        int64   `json:"id"`
+			Timestamp int64   `json:"ts"`
+			Price     float64 `json:"price"`
+			Amount    float64 `json:"amount"`
+			Direction string  `json:"direction"`
+		} `json:"data"`
+	} `json:"tick"`
+}
+
+// ParseHuobiLine parses messages of the trade detail channel of the Huobi futures websocket, such as
+// market.BTC_CQ.trade.detail. Amounts are numbers of contracts.
+func ParseHuobiLine(line string) (records DerivativeRecords, err error) {
+	var message huobiTradeMessage
+	if err = json.Unmarshal([]byte(line), &message); err != nil || !strings.HasSuffix(message.Channel, ".trade.detail") {
+		return
+	}
+	instrument := strings.TrimSuffix(strings.TrimPrefix(message.Channel, "market."), ".trade.detail")
+	for _, t := range message.Tick.Data {
+		volume := t.Amount
+		if t.Direction == "sell" {
+			volume = -volume
+		}
+		records.Trades = append(records.Trades, dia.FuturesTrade{
+			Exchange:       "Huobi",
+			Instrument:     instrument,
+			Underlying:     strings.SplitN(instrument, "_", 2)[0],
+			Kind:           dia.DerivativeFuture,
+			Price:          t.Price,
+			Volume:         volume,
+			ForeignTradeID: strconv.FormatInt(t.ID, 10),
+			Time:           time.Unix(0, t.Timestamp*int64(time.Millisecond)).UTC(),
+		})
+	}
+	return
+}
+
+type ftxTradeMessage struct {
+	Channel string `json:"channel"`
+	Market  string `json:"market"`
+	Type    string `json:"type"`
+	Data    []struct {
+		ID    int64     `json:"id"`
+		Price float64   `json:"price"`
+		Size  float64   `json:"size"`
+		Side  string    `json:"side"`
+		Time  time.Time `json:"time"`
+	} `json:"data"`
+}
+
+// ParseFTXLine parses updates of the trades channel of the FTX websocket for perpetuals such as
+// BTC-PERP and futures such as BTC-1225.
+func ParseFTXLine(line string) (records DerivativeRecords, err error) {
+	var message ftxTradeMessage
+	if err = json.Unmarshal([]byte(line), &message); err != nil || message.Channel != "trades" || message.Type != "update" {
+		return
+	}
+	kind := dia.DerivativeFuture
+	if strings.HasSuffix(message.Market, "-PERP") {
+		kind = dia.DerivativePerpetual
+	}
+	for _, t := range message.Data {
+		volume := t.Size
+		if t.Side == "sell" {
+			volume = -volume
+		}
+		records.Trades = append(records.Trades, dia.FuturesTrade{
+			Exchange:       "FTX",
+			Instrument:     message.Market,
+			Underlying:     strings.SplitN(message.Market, "-", 2)[0],
+			Kind:           kind,
+			Price:          t.Price,
+			Volume:         volume,
+			ForeignTradeID: strconv.FormatInt(t.ID, 10),
+			Time:           t.Time.UTC(),
+		})
+	}
+	return
+}
+
+type bitflyerChannelMessage struct {
+	Method string `json:"method"`
+	Params struct {
+		Channel string `json:"channel"`
+		Message []struct {
+			ID       int64     `json:"id"`
+			Side     string    `json:"side"`
+			Price    float64   `json:"price"`
+			Size     float64   `json:"size"`
+			ExecDate time.Time `json:"exec_date"`
+		} `json:"message"`
+	} `json:"params"`
+}
+
+// ParseBitflyerLine parses messages of the executions channels of the Bitflyer Lightning websocket for
+// futures such as BTCJPY25DEC2020 and the perpetual FX_BTC_JPY.
+func ParseBitflyerLine(line string) (records DerivativeRecords, err error) {
+	var message bitflyerChannelMessage
+	if err = json.Unmarshal([]byte(line), &message); err != nil || message.Method != "channelMessage" ||
+		!strings.HasPrefix(message.Params.Channel, bitflyerExecutionsPrefix) {
+		return
+	}
+	instrument := strings.TrimPrefix(message.Params.Channel, bitflyerExecutionsPrefix)
+	kind := dia.DerivativeFuture
+	underlying := instrument
+	if strings.HasPrefix(instrument, "FX_") {
+		kind = dia.DerivativePerpetual
+		underlying = strings.TrimPrefix(instrument, "FX_")
+	}
+	if len(underlying) > 3 {
+		underlying = underlying[:3]
+	}
+	for _, t := range message.Params.Message {
+		volume := t.Size
+		if t.Side == "SELL" {
+			volume = -volume
+		}
+		records.Trades = append(records.Trades, dia.FuturesTrade{
+			Exchange:       "Bitflyer",
+			Instrument:     instrument,
+			Underlying:     underlying,
+			Kind:           kind,
+			Price:          t.Price,
+			Volume:         volume,
+			ForeignTradeID: strconv.FormatInt(t.ID, 10),
+			Time:           t.ExecDate.UTC(),
+		})
+	}
+	return
+}
@@ -0,0 +1,116 @@
+package writers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestParseBitmexLine(t *testing.T) {
+	records, err := ParseBitmexLine(`{"table":"trade","action":"insert","data":[{"timestamp":"2020-11-02T10:00:00.000Z","symbol":"XBTZ20","side":"Sell","size":100,"price":13500.5,"trdMatchID":"abc"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(records.Trades))
+	}
+	trade := records.Trades[0]
+	if trade.Underlying != "BTC" || trade.Kind != dia.DerivativeFuture || trade.Volume != -100 || trade.Price != 13500.5 {
+		t.Errorf("unexpected trade %+v", trade)
+	}
+
+	// Instrument updates only contain changed fields.
+	records, err = ParseBitmexLine(`{"table":"instrument","action":"update","data":[{"symbol":"XBTUSD","openInterest":500,"timestamp":"2020-11-02T10:00:00.000Z"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Prices) != 0 || len(records.OpenInterests) != 1 || records.OpenInterests[0].OpenInterest != 500 {
+		t.Errorf("unexpected records %+v", records)
+	}
+
+	records, err = ParseBitmexLine(`{"success":true,"subscribe":"trade:XBTUSD"}`)
+	if err != nil || len(records.Trades) != 0 {
+		t.Errorf("subscription confirmation parsed as %+v, %v", records, err)
+	}
+}
+
+func TestParseDeribitLine(t *testing.T) {
+	records, err := ParseDeribitLine(`{"jsonrpc":"2.0","method":"subscription","params":{"channel":"ticker.BTC-25DEC20-20000-C.100ms","data":{"timestamp":1604311200000,"instrument_name":"BTC-25DEC20-20000-C","best_bid_price":0.0105,"best_bid_amount":2,"best_ask_price":0.012,"best_ask_amount":5,"underlying_price":13600}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Options) != 1 {
+		t.Fatalf("expected 1 option snapshot, got %d", len(records.Options))
+	}
+	option := records.Options[0]
+	expiry := time.Date(2020, 12, 25, 8, 0, 0, 0, time.UTC)
+	if option.OptionType != dia.OptionCall || option.Strike != 20000 || !option.Expiry.Equal(expiry) || option.UnderlyingPrice != 13600 {
+		t.Errorf("unexpected option snapshot %+v", option)
+	}
+
+	records, err = ParseDeribitLine(`{"jsonrpc":"2.0","method":"subscription","params":{"channel":"ticker.BTC-PERPETUAL.100ms","data":{"timestamp":1604311200000,"instrument_name":"BTC-PERPETUAL","mark_price":13601,"index_price":13600,"open_interest":1000,"funding_8h":0.0001}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Prices) != 1 || len(records.OpenInterests) != 1 || len(records.FundingRates) != 1 || records.FundingRates[0].Rate != 0.0001 {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
+
+func TestParseHuobiLine(t *testing.T) {
+	records, err := ParseHuobiLine(`{"ch":"market.BTC_CQ.trade.detail","ts":1604311200010,"tick":{"id":131602265,"ts":1604311200000,"data":[{"amount":2,"ts":1604311200000,"id":1316022650000,"price":13573.3,"direction":"sell"}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(records.Trades))
+	}
+	trade := records.Trades[0]
+	if trade.Instrument != "BTC_CQ" || trade.Underlying != "BTC" || trade.Volume != -2 || trade.ForeignTradeID != "1316022650000" || !trade.Time.Equal(time.Unix(1604311200, 0)) {
+		t.Errorf("unexpected trade %+v", trade)
+	}
+
+	records, err = ParseHuobiLine(`{"id":"id1","status":"ok","subbed":"market.BTC_CQ.trade.detail","ts":1604311200000}`)
+	if err != nil || len(records.Trades) != 0 {
+		t.Errorf("subscription confirmation parsed as %+v, %v", records, err)
+	}
+}
+
+func TestParseFTXLine(t *testing.T) {
+	records, err := ParseFTXLine(`{"channel":"trades","market":"BTC-PERP","type":"update","data":[{"id":213961,"price":13560.5,"size":0.25,"side":"buy","liquidation":false,"time":"2020-11-02T10:00:00.123456+00:00"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(records.Trades))
+	}
+	trade := records.Trades[0]
+	if trade.Kind != dia.DerivativePerpetual || trade.Underlying != "BTC" || trade.Volume != 0.25 || trade.Price != 13560.5 {
+		t.Errorf("unexpected trade %+v", trade)
+	}
+
+	records, err = ParseFTXLine(`{"channel":"trades","market":"BTC-1225","type":"update","data":[{"id":213962,"price":13700,"size":1,"side":"sell","liquidation":false,"time":"2020-11-02T10:00:01+00:00"}]}`)
+	if err != nil || len(records.Trades) != 1 || records.Trades[0].Kind != dia.DerivativeFuture || records.Trades[0].Volume != -1 {
+		t.Errorf("unexpected records %+v, %v", records, err)
+	}
+}
+
+func TestParseBitflyerLine(t *testing.T) {
+	records, err := ParseBitflyerLine(`{"jsonrpc":"2.0","method":"channelMessage","params":{"channel":"lightning_executions_BTCJPY25DEC2020","message":[{"id":2000,"side":"SELL","price":1420000,"size":0.01,"exec_date":"2020-11-02T10:00:00.1234567Z","buy_child_order_acceptance_id":"a","sell_child_order_acceptance_id":"b"}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(records.Trades))
+	}
+	trade := records.Trades[0]
+	if trade.Instrument != "BTCJPY25DEC2020" || trade.Underlying != "BTC" || trade.Kind != dia.DerivativeFuture || trade.Volume != -0.01 {
+		t.Errorf("unexpected trade %+v", trade)
+	}
+
+	records, err = ParseBitflyerLine(`{"jsonrpc":"2.0","method":"channelMessage","params":{"channel":"lightning_executions_FX_BTC_JPY","message":[{"id":2001,"side":"BUY","price":1410000,"size":0.5,"exec_date":"2020-11-02T10:00:01Z"}]}}`)
+	if err != nil || len(records.Trades) != 1 || records.Trades[0].Kind != dia.DerivativePerpetual || records.Trades[0].Underlying != "BTC" {
+		t.Errorf("unexpected records %+v, %v", records, err)
+	}
+}
@@ -0,0 +1,107 @@
+package writers
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+const influxWriterFlushInterval = 10 * time.Second
+
+// DerivativeRecords are the normalised records parsed from a raw line of a futures or options scraper.
+type DerivativeRecords struct {
+	Trades        []dia.FuturesTrade
+	FundingRates  []dia.FundingRate
+	OpenInterests []dia.OpenInterest
+	Prices        []dia.DerivativePrice
+	Options       []dia.OptionOrderbookSnapshot
+}
+
+// DerivativeParser parses a raw websocket message of an exchange into normalised records.
+type DerivativeParser func(line string) (DerivativeRecords, error)
+
+// derivativeParsers maps exchange names as passed to GetWriteFileName to the parsers of their messages.
+// The match notices of Coinflex contain amounts scaled per asset and are not parsed.
+var derivativeParsers = map[string]DerivativeParser{
+	"Bitflyer": ParseBitflyerLine,
+	"Bitmex":   ParseBitmexLine,
+	"Deribit":  ParseDeribitLine,
+	"ftx":      ParseFTXLine,
+	"huobi":    ParseHuobiLine,
+}
+
+// InfluxWriter - implementation of the Writer interface which parses the raw lines of the
+// futures and options scrapers and stores the normalised records in influx.
+type InfluxWriter struct {
+	datastore models.Datastore
+	mu        sync.Mutex
+}
+
+// NewInfluxWriter returns a writer saving into @datastore. Batches are flushed periodically.
+func NewInfluxWriter(datastore models.Datastore) *InfluxWriter {
+	w := &InfluxWriter{datastore: datastore}
+	go func() {
+		for range time.Tick(influxWriterFlushInterval) {
+			w.mu.Lock()
+			err := w.datastore.Flush()
+			w.mu.Unlock()
+			if err != nil {
+				log.Error("flush derivatives batch: ", err)
+			}
+		}
+	}()
+	return w
+}
+
+// GetWriteFileName - returns the key of the form exchange:market which Write uses to select the parser.
+func (w *InfluxWriter) GetWriteFileName(exchange string, market string) string {
+	return exchange + ":" + market
+}
+
+// Write - parses the line with the parser of the exchange in @filename and saves all records.
+// Messages without derivative data, such as subscription confirmations, are ignored.
+func (w *InfluxWriter) Write(line string, filename string) (int, error) {
+	exchange := strings.SplitN(filename, ":", 2)[0]
+	parse, ok := derivativeParsers[exchange]
+	if !ok {
+		return 0, errors.New("no derivatives parser for exchange " + exchange)
+	}
+	records, err := parse(line)
+	if err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, trade := range records.Trades {
+		if err = w.datastore.SaveFuturesTrade(trade); err != nil {
+			return 0, err
+		}
+	}
+	for _, rate := range records.FundingRates {
+		if err = w.datastore.SaveFundingRate(rate); err != nil {
+			return 0, err
+		}
+	}
+	for _, openInterest := range records.OpenInterests {
+		if err = w.datastore.SaveOpenInterest(openInterest); err != nil {
+			return 0, err
+		}
+	}
+	for _, price := range records.Prices {
+		if err = w.datastore.SaveDerivativePrice(price); err != nil {
+			return 0, err
+		}
+	}
+	for _, snapshot := range records.Options {
+		if err = w.datastore.SaveOptionOrderbookSnapshot(snapshot); err != nil {
+			return 0, err
+		}
+	}
+	return len(line), nil
+}
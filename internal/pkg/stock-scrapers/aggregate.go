@@ -0,0 +1,58 @@
+package stockscrapers
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+// Sources are the sources of stock and forex quotations.
+var Sources = []string{"Finage", "TwelveData"}
+
+// ErrNoQuotations is returned if none of the quotations to be aggregated has a price.
+var ErrNoQuotations = errors.New("no quotations with price")
+
+// MedianQuotation returns the median of the mid prices of @quotations, which are the latest quotations of
+// a symbol from different sources. The result has source dia.Diadata and the time of the latest quotation.
+func MedianQuotation(quotations []models.StockQuotation) (models.StockQuotation, error) {
+	var prices []float64
+	var median models.StockQuotation
+	for _, quotation := range quotations {
+		price := midPrice(quotation)
+		if price <= 0 {
+			continue
+		}
+		prices = append(prices, price)
+		if quotation.Time.After(median.Time) {
+			median.Time = quotation.Time
+		}
+		if median.Symbol == "" || median.ISIN == "" {
+			median.Symbol, median.Name, median.ISIN = quotation.Symbol, quotation.Name, quotation.ISIN
+		}
+	}
+	if len(prices) == 0 {
+		return models.StockQuotation{}, ErrNoQuotations
+	}
+	sort.Float64s(prices)
+	price := prices[len(prices)/2]
+	if len(prices)%2 == 0 {
+		price = (prices[len(prices)/2-1] + prices[len(prices)/2]) / 2
+	}
+	median.PriceAsk, median.PriceBid = price, price
+	median.Source = dia.Diadata
+	return median, nil
+}
+
+// midPrice returns the mean of ask and bid, or the one of them which is given.
+func midPrice(quotation models.StockQuotation) float64 {
+	switch {
+	case quotation.PriceAsk > 0 && quotation.PriceBid > 0:
+		return (quotation.PriceAsk + quotation.PriceBid) / 2
+	case quotation.PriceAsk > 0:
+		return quotation.PriceAsk
+	default:
+		return quotation.PriceBid
+	}
+}
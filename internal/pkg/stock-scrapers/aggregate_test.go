@@ -0,0 +1,55 @@
+package stockscrapers
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+func TestMedianQuotation(t *testing.T) {
+	now := time.Now()
+	tables := []struct {
+		quotations []models.StockQuotation
+		price      float64
+		err        error
+	}{
+		{nil, 0, ErrNoQuotations},
+		{[]models.StockQuotation{{PriceAsk: 101, PriceBid: 99}}, 100, nil},
+		// Quotations without bid are taken at the ask price, zero quotations are ignored.
+		{[]models.StockQuotation{{PriceAsk: 101, PriceBid: 99}, {PriceAsk: 104}, {}}, 102, nil},
+		{[]models.StockQuotation{{PriceAsk: 100, PriceBid: 100}, {PriceAsk: 150, PriceBid: 150}, {PriceAsk: 102, PriceBid: 102}}, 102, nil},
+	}
+	for i, table := range tables {
+		for j := range table.quotations {
+			table.quotations[j].Time = now.Add(-time.Duration(j) * time.Minute)
+		}
+		median, err := MedianQuotation(table.quotations)
+		if err != table.err {
+			t.Errorf("case %d: error %v, want %v", i, err, table.err)
+			continue
+		}
+		if median.PriceAsk != table.price || median.PriceBid != table.price {
+			t.Errorf("case %d: median is %v, want %v", i, median.PriceAsk, table.price)
+		}
+		if err == nil && !median.Time.Equal(now) {
+			t.Errorf("case %d: time is %v, want the latest quotation", i, median.Time)
+		}
+	}
+}
+
+func TestUnmarshalTwelveDataQuotes(t *testing.T) {
+	single := []byte(`{"symbol":"AAPL","name":"Apple Inc","close":"148.71","timestamp":1654718400}`)
+	quotes, err := unmarshalTwelveDataQuotes(single, []string{"AAPL"})
+	if err != nil || len(quotes) != 1 || quotes[0].Close != "148.71" {
+		t.Errorf("single quote: %v, %v", quotes, err)
+	}
+	multiple := []byte(`{"AAPL":{"symbol":"AAPL","close":"148.71","timestamp":1654718400},"EUR/USD":{"code":400,"message":"not found","status":"error"}}`)
+	quotes, err = unmarshalTwelveDataQuotes(multiple, []string{"AAPL", "EUR/USD"})
+	if err != nil || len(quotes) != 1 || quotes[0].Symbol != "AAPL" {
+		t.Errorf("multiple quotes: %v, %v", quotes, err)
+	}
+	if _, err = unmarshalTwelveDataQuotes([]byte(`{"code":401,"message":"invalid api key","status":"error"}`), []string{"AAPL"}); err == nil {
+		t.Error("error response accepted")
+	}
+}
@@ -17,7 +17,7 @@ import (
 const (
 	msciWorldIndexTop10 = "AAPL,MSFT,AMZN,FB,GOOGL,GOOG,TSLA,NVDA,JPM,JNJ"
 	subscribeMessage    = "{\"action\": \"subscribe\", \"symbols\":\"" + msciWorldIndexTop10 + "\"}"
-	apiKey              = "api_finage"
+	finageAPIKey        = "api_finage"
 )
 
 type FinageScraper struct {
@@ -39,7 +39,7 @@ func NewFinageScraper(db *models.DB) *FinageScraper {
 	}
 	s := &FinageScraper{
 		stockScraper:               stockScraper,
-		apiWsURL:                   getAPIKeyFromSecrets(),
+		apiWsURL:                   getAPIKeyFromSecrets(finageAPIKey),
 		timeResolutionMilliseconds: 1000,
 	}
 	fmt.Println("scraper built. Start main loop.")
@@ -183,8 +183,8 @@ func getCompanyDetails(symbol string) (name string, isin string) {
 	return "", ""
 }
 
-// getAPIKeyFromSecrets returns a github api key
-func getAPIKeyFromSecrets() string {
+// getAPIKeyFromSecrets returns the api key stored in the secret @apiKey.
+func getAPIKeyFromSecrets(apiKey string) string {
 	var lines []string
 	executionMode := os.Getenv("EXEC_MODE")
 	var file *os.File
@@ -0,0 +1,202 @@
+package stockscrapers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	markethours "github.com/diadata-org/diadata/internal/pkg/marketHours"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	twelveDataAPIKey  = "api_twelvedata"
+	twelveDataBaseURL = "https://api.twelvedata.com/quote"
+	// Default symbols of the TwelveData scraper: the stocks of the Finage scraper and major currency pairs.
+	twelveDataSymbols = msciWorldIndexTop10 + ",EUR/USD,GBP/USD,USD/JPY,USD/CHF,USD/CAD,AUD/USD"
+)
+
+// twelveDataQuote is a quote of the TwelveData API. Prices are given as strings.
+type twelveDataQuote struct {
+	Symbol    string `json:"symbol"`
+	Name      string `json:"name"`
+	Close     string `json:"close"`
+	Timestamp int64  `json:"timestamp"`
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+}
+
+// TwelveDataScraper polls the latest quotes of stocks and currency pairs from the TwelveData REST API.
+// Symbols are only polled while their market is open.
+type TwelveDataScraper struct {
+	stockScraper StockScraper
+	apiKey       string
+	symbols      []string
+	pollPeriod   time.Duration
+}
+
+// NewTwelveDataScraper returns a scraper polling the symbols given by the env var TWELVEDATA_SYMBOLS.
+func NewTwelveDataScraper(db *models.DB) *TwelveDataScraper {
+	stockScraper := StockScraper{
+		shutdown:     make(chan nothing),
+		shutdownDone: make(chan nothing),
+		errorLock:    new(sync.RWMutex),
+		error:        nil,
+		datastore:    db,
+		chanStock:    make(chan models.StockQuotation),
+		source:       "TwelveData",
+	}
+	pollSeconds, err := strconv.Atoi(utils.Getenv("TWELVEDATA_POLL_SECONDS", "60"))
+	if err != nil {
+		log.Fatal("parse poll period: ", err)
+	}
+	s := &TwelveDataScraper{
+		stockScraper: stockScraper,
+		apiKey:       getAPIKeyFromSecrets(twelveDataAPIKey),
+		symbols:      strings.Split(utils.Getenv("TWELVEDATA_SYMBOLS", twelveDataSymbols), ","),
+		pollPeriod:   time.Duration(pollSeconds) * time.Second,
+	}
+	go s.mainLoop()
+	return s
+}
+
+// mainLoop runs in a goroutine until the scraper is closed.
+func (scraper *TwelveDataScraper) mainLoop() {
+	defer close(scraper.GetStockQuotationChannel())
+
+	ticker := time.NewTicker(scraper.pollPeriod)
+	defer ticker.Stop()
+	for {
+		if err := scraper.FetchQuotes(); err != nil {
+			log.Error("fetch quotes from TwelveData: ", err)
+		}
+		select {
+		case <-scraper.stockScraper.shutdown:
+			scraper.cleanup(nil)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FetchQuotes fetches the quotes of all symbols whose market is open and feeds them into the channel.
+func (scraper *TwelveDataScraper) FetchQuotes() error {
+	now := time.Now()
+	var symbols []string
+	for _, symbol := range scraper.symbols {
+		if markethours.MarketOf(symbol).IsOpen(now) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	data, _, err := utils.GetRequest(twelveDataBaseURL + "?symbol=" + url.QueryEscape(strings.Join(symbols, ",")) + "&apikey=" + scraper.apiKey)
+	if err != nil {
+		return err
+	}
+	quotes, err := unmarshalTwelveDataQuotes(data, symbols)
+	if err != nil {
+		return err
+	}
+	for _, quote := range quotes {
+		quotation, err := scraper.toStockQuotation(quote)
+		if err != nil {
+			log.Errorf("parse TwelveData quote of %s: %v", quote.Symbol, err)
+			continue
+		}
+		scraper.GetStockQuotationChannel() <- quotation
+	}
+	return nil
+}
+
+// unmarshalTwelveDataQuotes returns the quotes in @data. The API returns a single quote if one symbol
+// is requested and a map from symbols to quotes otherwise.
+func unmarshalTwelveDataQuotes(data []byte, symbols []string) ([]twelveDataQuote, error) {
+	if len(symbols) == 1 {
+		var quote twelveDataQuote
+		if err := json.Unmarshal(data, &quote); err != nil {
+			return nil, err
+		}
+		if quote.Status == "error" {
+			return nil, errors.New(quote.Message)
+		}
+		return []twelveDataQuote{quote}, nil
+	}
+	var quoteMap map[string]twelveDataQuote
+	if err := json.Unmarshal(data, &quoteMap); err != nil {
+		return nil, err
+	}
+	var quotes []twelveDataQuote
+	for _, symbol := range symbols {
+		quote, ok := quoteMap[symbol]
+		if !ok {
+			continue
+		}
+		if quote.Status == "error" {
+			log.Errorf("TwelveData quote of %s: %s", symbol, quote.Message)
+			continue
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes, nil
+}
+
+func (scraper *TwelveDataScraper) toStockQuotation(quote twelveDataQuote) (models.StockQuotation, error) {
+	price, err := strconv.ParseFloat(quote.Close, 64)
+	if err != nil {
+		return models.StockQuotation{}, err
+	}
+	if price <= 0 {
+		return models.StockQuotation{}, fmt.Errorf("invalid price %v", price)
+	}
+	name, isin := getCompanyDetails(quote.Symbol)
+	if name == "" {
+		name = quote.Name
+	}
+	// Quotes only contain the last price, which is used for both sides.
+	return models.StockQuotation{
+		Symbol:   quote.Symbol,
+		Name:     name,
+		PriceAsk: price,
+		PriceBid: price,
+		Source:   scraper.stockScraper.source,
+		Time:     time.Unix(quote.Timestamp, 0),
+		ISIN:     isin,
+	}, nil
+}
+
+// GetStockQuotationChannel returns the scrapers data channel.
+func (scraper *TwelveDataScraper) GetStockQuotationChannel() chan models.StockQuotation {
+	return scraper.stockScraper.chanStock
+}
+
+// cleanup must only be called from mainLoop.
+func (scraper *TwelveDataScraper) cleanup(err error) {
+	scraper.stockScraper.errorLock.Lock()
+	defer scraper.stockScraper.errorLock.Unlock()
+	if err != nil {
+		scraper.stockScraper.error = err
+	}
+	scraper.stockScraper.closed = true
+	close(scraper.stockScraper.shutdownDone)
+}
+
+// Close stops polling.
+func (scraper *TwelveDataScraper) Close() error {
+	if scraper.stockScraper.closed {
+		return errors.New("scraper already closed")
+	}
+	close(scraper.stockScraper.shutdown)
+	<-scraper.stockScraper.shutdownDone
+	scraper.stockScraper.errorLock.RLock()
+	defer scraper.stockScraper.errorLock.RUnlock()
+	return scraper.stockScraper.error
+}
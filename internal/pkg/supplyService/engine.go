@@ -0,0 +1,376 @@
+package supplyservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// Types of supply rules. Balances of addresses with rule type burn are subtracted from total supply,
+// all others from circulating supply only.
+const (
+	// RuleLockedWallet is a wallet whose tokens are locked, such as a treasury or team wallet.
+	RuleLockedWallet = "wallet"
+	// RuleVesting is a vesting contract which releases tokens over time.
+	RuleVesting = "vesting"
+	// RuleBurn is an address whose tokens can never be moved again.
+	RuleBurn = "burn"
+	// RuleBridge is a bridge escrow which backs the supply of the token on other chains.
+	// Its balance is subtracted from total supply in order to avoid double counting when
+	// aggregating the supply over all deployments of the token.
+	RuleBridge = "bridge"
+)
+
+// SupplyRule excludes the balance of the token held by Address on Blockchain.
+type SupplyRule struct {
+	Type       string `json:"type"`
+	Blockchain string `json:"blockchain"`
+	Address    string `json:"address"`
+}
+
+// SupplyDeployment is a deployment of the token on a blockchain.
+type SupplyDeployment struct {
+	Blockchain string `json:"blockchain"`
+	Address    string `json:"address"`
+	// Block in which the token was deployed. Historical supplies are reconstructed from here on.
+	StartBlock uint64 `json:"startBlock,omitempty"`
+}
+
+// SupplyAsset describes how the supply of an asset is computed. Asset is the canonical deployment
+// of the token. The supply of bridged versions given in Deployments is added to its total supply.
+type SupplyAsset struct {
+	Blockchain  string             `json:"blockchain"`
+	Address     string             `json:"address"`
+	StartBlock  uint64             `json:"startBlock,omitempty"`
+	Deployments []SupplyDeployment `json:"deployments"`
+	Rules       []SupplyRule       `json:"rules"`
+}
+
+// SupplyEngine computes total and circulating supply of ERC20 tokens on all EVM chains
+// for which a chain config is available.
+type SupplyEngine struct {
+	relDB   *models.RelDB
+	clients map[string]*ethclient.Client
+}
+
+// NewSupplyEngine returns a supply engine which connects to the blockchains given by the
+// chain_id column of the blockchain table using the rpc urls from the chainconfig table.
+func NewSupplyEngine(relDB *models.RelDB) (*SupplyEngine, error) {
+	engine := &SupplyEngine{
+		relDB:   relDB,
+		clients: make(map[string]*ethclient.Client),
+	}
+
+	chainconfigs, err := relDB.GetAllChainConfig()
+	if err != nil {
+		return nil, err
+	}
+	rpcURLs := make(map[string]string)
+	for _, chainconfig := range chainconfigs {
+		rpcURLs[chainconfig.ChainID] = chainconfig.RestURL
+	}
+
+	blockchains, err := relDB.GetAllBlockchains(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockchain := range blockchains {
+		rpcURL, ok := rpcURLs[blockchain.ChainID]
+		if blockchain.ChainID == "" || !ok {
+			continue
+		}
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			log.Errorf("dial rpc for %s: %v", blockchain.Name, err)
+			continue
+		}
+		engine.clients[blockchain.Name] = client
+	}
+	return engine, nil
+}
+
+// SetClient sets the client used for @blockchain, overriding the one from chain config.
+func (engine *SupplyEngine) SetClient(blockchain string, client *ethclient.Client) {
+	engine.clients[blockchain] = client
+}
+
+func (engine *SupplyEngine) client(blockchain string) (*ethclient.Client, error) {
+	client, ok := engine.clients[blockchain]
+	if !ok {
+		return nil, fmt.Errorf("no chain config for blockchain %s", blockchain)
+	}
+	return client, nil
+}
+
+// GetSupply returns total and circulating supply of @asset at the latest block of each chain.
+func (engine *SupplyEngine) GetSupply(asset SupplyAsset) (dia.Supply, error) {
+	return engine.GetSupplyAtBlocks(asset, nil)
+}
+
+// GetSupplyAtBlocks returns total and circulating supply of @asset, where the state of each chain is
+// taken at the block given in @blocks. Chains which are not in @blocks are evaluated at the latest block.
+func (engine *SupplyEngine) GetSupplyAtBlocks(asset SupplyAsset, blocks map[string]*big.Int) (supply dia.Supply, err error) {
+	client, err := engine.client(asset.Blockchain)
+	if err != nil {
+		return
+	}
+	token, err := NewERC20(common.HexToAddress(asset.Address), client)
+	if err != nil {
+		return
+	}
+	callOpts := &bind.CallOpts{BlockNumber: blocks[asset.Blockchain]}
+	symbol, err := token.Symbol(callOpts)
+	if err != nil {
+		return
+	}
+	name, err := token.Name(callOpts)
+	if err != nil {
+		return
+	}
+	decimals, err := token.Decimals(callOpts)
+	if err != nil {
+		return
+	}
+
+	var totalSupplies []float64
+	for _, deployment := range asset.allDeployments() {
+		var totalSupply float64
+		totalSupply, err = engine.totalSupply(deployment, blocks[deployment.Blockchain])
+		if err != nil {
+			return
+		}
+		totalSupplies = append(totalSupplies, totalSupply)
+	}
+
+	var exclusions []dia.SupplyExclusion
+	for _, rule := range asset.Rules {
+		var balance float64
+		balance, err = engine.ruleBalance(asset, rule, blocks[rule.Blockchain])
+		if err != nil {
+			log.Errorf("get balance of %s on %s: %v", rule.Address, rule.Blockchain, err)
+			return
+		}
+		exclusions = append(exclusions, dia.SupplyExclusion{
+			Blockchain: rule.Blockchain,
+			Address:    common.HexToAddress(rule.Address).Hex(),
+			Type:       rule.Type,
+			Balance:    balance,
+		})
+	}
+
+	supply.Supply, supply.CirculatingSupply = aggregateSupply(totalSupplies, exclusions)
+	supply.Exclusions = exclusions
+	supply.Asset = dia.Asset{
+		Symbol:     symbol,
+		Name:       name,
+		Decimals:   decimals,
+		Address:    common.HexToAddress(asset.Address).Hex(),
+		Blockchain: asset.Blockchain,
+	}
+	supply.Source = dia.Diadata
+	supply.Time, err = engine.blockTime(asset.Blockchain, blocks[asset.Blockchain])
+	return
+}
+
+// allDeployments returns the canonical deployment of @asset followed by its bridged deployments.
+func (asset SupplyAsset) allDeployments() []SupplyDeployment {
+	return append([]SupplyDeployment{{Blockchain: asset.Blockchain, Address: asset.Address, StartBlock: asset.StartBlock}}, asset.Deployments...)
+}
+
+// aggregateSupply returns total and circulating supply given the total supplies of all
+// deployments of a token and the balances excluded by supply rules.
+func aggregateSupply(totalSupplies []float64, exclusions []dia.SupplyExclusion) (totalSupply float64, circulatingSupply float64) {
+	for _, s := range totalSupplies {
+		totalSupply += s
+	}
+	circulatingSupply = totalSupply
+	for _, exclusion := range exclusions {
+		switch exclusion.Type {
+		case RuleBurn, RuleBridge:
+			totalSupply -= exclusion.Balance
+			circulatingSupply -= exclusion.Balance
+		default:
+			circulatingSupply -= exclusion.Balance
+		}
+	}
+	return
+}
+
+// totalSupply returns the total supply of @deployment normalized by its decimals.
+func (engine *SupplyEngine) totalSupply(deployment SupplyDeployment, blockNumber *big.Int) (float64, error) {
+	client, err := engine.client(deployment.Blockchain)
+	if err != nil {
+		return 0, err
+	}
+	token, err := NewERC20(common.HexToAddress(deployment.Address), client)
+	if err != nil {
+		return 0, err
+	}
+	callOpts := &bind.CallOpts{BlockNumber: blockNumber}
+	decimals, err := token.Decimals(callOpts)
+	if err != nil {
+		return 0, err
+	}
+	totalSupply, err := token.TotalSupply(callOpts)
+	if err != nil {
+		return 0, err
+	}
+	return normalizeAmount(totalSupply, decimals), nil
+}
+
+// ruleBalance returns the balance of the token deployed on @rule.Blockchain held by @rule.Address.
+func (engine *SupplyEngine) ruleBalance(asset SupplyAsset, rule SupplyRule, blockNumber *big.Int) (float64, error) {
+	tokenAddress := asset.Address
+	if rule.Blockchain != asset.Blockchain {
+		tokenAddress = ""
+		for _, deployment := range asset.Deployments {
+			if deployment.Blockchain == rule.Blockchain {
+				tokenAddress = deployment.Address
+			}
+		}
+		if tokenAddress == "" {
+			return 0, fmt.Errorf("asset %s has no deployment on %s", asset.Address, rule.Blockchain)
+		}
+	}
+
+	return engine.tokenBalance(rule.Blockchain, tokenAddress, rule.Address, blockNumber)
+}
+
+// tokenBalance returns the balance of the token @tokenAddress on @blockchain held by @holder normalized by its decimals.
+func (engine *SupplyEngine) tokenBalance(blockchain string, tokenAddress string, holder string, blockNumber *big.Int) (float64, error) {
+	client, err := engine.client(blockchain)
+	if err != nil {
+		return 0, err
+	}
+	token, err := NewERC20(common.HexToAddress(tokenAddress), client)
+	if err != nil {
+		return 0, err
+	}
+	callOpts := &bind.CallOpts{BlockNumber: blockNumber}
+	decimals, err := token.Decimals(callOpts)
+	if err != nil {
+		return 0, err
+	}
+	balance, err := token.BalanceOf(callOpts, common.HexToAddress(holder))
+	if err != nil {
+		return 0, err
+	}
+	return normalizeAmount(balance, decimals), nil
+}
+
+func (engine *SupplyEngine) blockTime(blockchain string, blockNumber *big.Int) (time.Time, error) {
+	client, err := engine.client(blockchain)
+	if err != nil {
+		return time.Time{}, err
+	}
+	header, err := client.HeaderByNumber(context.Background(), blockNumber)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(header.Time), 0), nil
+}
+
+func normalizeAmount(amount *big.Int, decimals uint8) float64 {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), big.NewFloat(math.Pow10(int(decimals)))).Float64()
+	return value
+}
+
+// GetSupplyAssetsFromConfig returns the supply configuration of all assets in the config file @filename.
+func GetSupplyAssetsFromConfig(filename string) (assets []SupplyAsset, err error) {
+	byteData, err := readConfig(filename)
+	if err != nil {
+		return
+	}
+
+	type supplyAssetList struct {
+		Assets []SupplyAsset `json:"assets"`
+	}
+	var allAssets supplyAssetList
+	err = json.Unmarshal(byteData, &allAssets)
+	if err != nil {
+		return
+	}
+
+	for _, asset := range allAssets.Assets {
+		for _, rule := range asset.Rules {
+			switch rule.Type {
+			case RuleLockedWallet, RuleVesting, RuleBurn, RuleBridge:
+			default:
+				err = fmt.Errorf("unknown supply rule type %s for asset %s", rule.Type, asset.Address)
+				return
+			}
+			if rule.Blockchain == "" {
+				err = errors.New("missing blockchain in supply rule for asset " + asset.Address)
+				return
+			}
+		}
+	}
+	return allAssets.Assets, nil
+}
+
+// readConfig returns the content of the json file @filename in the token supply config directory.
+func readConfig(filename string) (byteData []byte, err error) {
+	var jsonFile *os.File
+
+	executionMode := os.Getenv("EXEC_MODE")
+	if executionMode == "production" {
+		jsonFile, err = os.Open(fmt.Sprintf("/config/token_supply/%s.json", filename))
+	} else {
+		jsonFile, err = os.Open(fmt.Sprintf("../../../config/token_supply/%s.json", filename))
+	}
+	if err != nil {
+		return
+	}
+	defer func() {
+		cerr := jsonFile.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	return ioutil.ReadAll(jsonFile)
+}
+
+// MergeLockedWallets adds the locked wallets from the legacy wallets config, which only covers
+// Ethereum tokens, as rules of type RuleLockedWallet to @assets.
+func MergeLockedWallets(assets []SupplyAsset, lockedWallets map[string][]string) []SupplyAsset {
+	index := make(map[string]int)
+	for i, asset := range assets {
+		if asset.Blockchain == dia.ETHEREUM {
+			index[strings.ToLower(asset.Address)] = i
+		}
+	}
+	for address, wallets := range lockedWallets {
+		i, ok := index[strings.ToLower(address)]
+		if !ok {
+			assets = append(assets, SupplyAsset{Blockchain: dia.ETHEREUM, Address: address})
+			i = len(assets) - 1
+			index[strings.ToLower(address)] = i
+		}
+		existing := make(map[string]bool)
+		for _, rule := range assets[i].Rules {
+			existing[strings.ToLower(rule.Address)] = true
+		}
+		for _, wallet := range wallets {
+			if existing[strings.ToLower(wallet)] {
+				continue
+			}
+			assets[i].Rules = append(assets[i].Rules, SupplyRule{Type: RuleLockedWallet, Blockchain: dia.ETHEREUM, Address: wallet})
+		}
+	}
+	return assets
+}
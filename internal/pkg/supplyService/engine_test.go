@@ -0,0 +1,53 @@
+package supplyservice
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestAggregateSupply(t *testing.T) {
+	tables := []struct {
+		totalSupplies     []float64
+		exclusions        []dia.SupplyExclusion
+		totalSupply       float64
+		circulatingSupply float64
+	}{
+		{[]float64{100}, nil, 100, 100},
+		{[]float64{100}, []dia.SupplyExclusion{{Type: RuleLockedWallet, Balance: 10}, {Type: RuleVesting, Balance: 20}}, 100, 70},
+		{[]float64{100}, []dia.SupplyExclusion{{Type: RuleBurn, Balance: 5}, {Type: RuleLockedWallet, Balance: 10}}, 95, 85},
+		// 30 tokens are bridged to a second chain and held in the bridge escrow on the first one.
+		{[]float64{100, 30}, []dia.SupplyExclusion{{Type: RuleBridge, Balance: 30}, {Type: RuleLockedWallet, Balance: 10}}, 100, 90},
+	}
+	for _, table := range tables {
+		totalSupply, circulatingSupply := aggregateSupply(table.totalSupplies, table.exclusions)
+		if totalSupply != table.totalSupply {
+			t.Errorf("Total supply is %v but should be %v.", totalSupply, table.totalSupply)
+		}
+		if circulatingSupply != table.circulatingSupply {
+			t.Errorf("Circulating supply is %v but should be %v.", circulatingSupply, table.circulatingSupply)
+		}
+	}
+}
+
+func TestMergeLockedWallets(t *testing.T) {
+	assets := []SupplyAsset{{
+		Blockchain: dia.ETHEREUM,
+		Address:    "0xAbC",
+		Rules:      []SupplyRule{{Type: RuleBurn, Blockchain: dia.ETHEREUM, Address: "0x01"}},
+	}}
+	lockedWallets := map[string][]string{
+		"0xabc": {"0x01", "0x02"},
+		"0xdef": {"0x03"},
+	}
+	assets = MergeLockedWallets(assets, lockedWallets)
+	if len(assets) != 2 {
+		t.Fatalf("Number of assets is %v but should be 2.", len(assets))
+	}
+	if len(assets[0].Rules) != 2 || assets[0].Rules[1].Type != RuleLockedWallet {
+		t.Errorf("Rules of first asset are %v.", assets[0].Rules)
+	}
+	if len(assets[1].Rules) != 1 || assets[1].Rules[0].Address != "0x03" {
+		t.Errorf("Rules of second asset are %v.", assets[1].Rules)
+	}
+}
@@ -0,0 +1,288 @@
+package supplyservice
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Maximal number of blocks filtered for Transfer events in one request.
+	transferBatchSize = uint64(5000)
+)
+
+var zeroAddress = common.HexToAddress("0x0000000000000000000000000000000000000000")
+
+// deploymentState is the state of a deployment reconstructed from Transfer events.
+type deploymentState struct {
+	deployment  SupplyDeployment
+	decimals    uint8
+	totalSupply *big.Int
+	// Balances of the addresses of supply rules on the deployment's chain.
+	balances map[common.Address]*big.Int
+}
+
+// ReconstructSupplies returns daily total and circulating supplies of @asset in the time range
+// @starttime -- @endtime. Each supply is taken at the last block of the day on each chain.
+// Days which have not ended yet are skipped.
+// Total supply is reconstructed by replaying mint and burn Transfer events from the start block of
+// each deployment, balances of rule addresses by replaying Transfer events from and to them.
+// Hence, no archive node is needed.
+func (engine *SupplyEngine) ReconstructSupplies(asset SupplyAsset, starttime time.Time, endtime time.Time) (supplies []dia.Supply, err error) {
+	client, err := engine.client(asset.Blockchain)
+	if err != nil {
+		return
+	}
+	token, err := NewERC20(common.HexToAddress(asset.Address), client)
+	if err != nil {
+		return
+	}
+	symbol, err := token.Symbol(&bind.CallOpts{})
+	if err != nil {
+		return
+	}
+	name, err := token.Name(&bind.CallOpts{})
+	if err != nil {
+		return
+	}
+
+	var states []*deploymentState
+	for _, deployment := range asset.allDeployments() {
+		var state *deploymentState
+		state, err = engine.newDeploymentState(deployment, asset.Rules)
+		if err != nil {
+			return
+		}
+		states = append(states, state)
+	}
+
+	for _, day := range supplyDays(starttime, endtime) {
+		if day.After(time.Now()) {
+			break
+		}
+		var totalSupplies []float64
+		var exclusions []dia.SupplyExclusion
+		for _, state := range states {
+			var blockNumber uint64
+			blockNumber, err = engine.blockAtTime(state.deployment.Blockchain, day)
+			if err != nil {
+				return
+			}
+			err = engine.replayTransfers(state, blockNumber)
+			if err != nil {
+				return
+			}
+			totalSupplies = append(totalSupplies, normalizeAmount(state.totalSupply, state.decimals))
+			exclusions = append(exclusions, state.exclusions(asset.Rules)...)
+		}
+
+		supply := dia.Supply{
+			Asset: dia.Asset{
+				Symbol:     symbol,
+				Name:       name,
+				Decimals:   states[0].decimals,
+				Address:    common.HexToAddress(asset.Address).Hex(),
+				Blockchain: asset.Blockchain,
+			},
+			Source:     dia.Diadata,
+			Time:       day,
+			Exclusions: exclusions,
+		}
+		supply.Supply, supply.CirculatingSupply = aggregateSupply(totalSupplies, exclusions)
+		log.Infof("reconstructed supply of %s at %v: %v -- %v", symbol, day, supply.Supply, supply.CirculatingSupply)
+		supplies = append(supplies, supply)
+	}
+	return
+}
+
+// supplyDays returns the ends of all days between @starttime and @endtime.
+func supplyDays(starttime time.Time, endtime time.Time) (days []time.Time) {
+	day := time.Date(starttime.Year(), starttime.Month(), starttime.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1).Add(-time.Second)
+	for !day.After(endtime) {
+		days = append(days, day)
+		day = day.AddDate(0, 0, 1)
+	}
+	return
+}
+
+func (engine *SupplyEngine) newDeploymentState(deployment SupplyDeployment, rules []SupplyRule) (*deploymentState, error) {
+	client, err := engine.client(deployment.Blockchain)
+	if err != nil {
+		return nil, err
+	}
+	token, err := NewERC20(common.HexToAddress(deployment.Address), client)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := token.Decimals(&bind.CallOpts{})
+	if err != nil {
+		return nil, err
+	}
+	state := &deploymentState{
+		deployment:  deployment,
+		decimals:    decimals,
+		totalSupply: big.NewInt(0),
+		balances:    make(map[common.Address]*big.Int),
+	}
+	// The state is at the end of the block before the start block.
+	if state.deployment.StartBlock > 0 {
+		state.deployment.StartBlock--
+	}
+	for _, rule := range rules {
+		if rule.Blockchain == deployment.Blockchain {
+			state.balances[common.HexToAddress(rule.Address)] = big.NewInt(0)
+		}
+	}
+	return state, nil
+}
+
+// exclusions returns the balances of all rule addresses on the state's chain.
+func (state *deploymentState) exclusions(rules []SupplyRule) (exclusions []dia.SupplyExclusion) {
+	for _, rule := range rules {
+		if rule.Blockchain != state.deployment.Blockchain {
+			continue
+		}
+		address := common.HexToAddress(rule.Address)
+		exclusions = append(exclusions, dia.SupplyExclusion{
+			Blockchain: rule.Blockchain,
+			Address:    address.Hex(),
+			Type:       rule.Type,
+			Balance:    normalizeAmount(state.balances[address], state.decimals),
+		})
+	}
+	return
+}
+
+// replayTransfers applies all relevant Transfer events after the last replayed block up to
+// and including @endBlock to @state.
+func (engine *SupplyEngine) replayTransfers(state *deploymentState, endBlock uint64) error {
+	client, err := engine.client(state.deployment.Blockchain)
+	if err != nil {
+		return err
+	}
+	filterer, err := NewERC20Filterer(common.HexToAddress(state.deployment.Address), client)
+	if err != nil {
+		return err
+	}
+	return state.replay(filterer, endBlock)
+}
+
+// replay applies the Transfer events filtered by @filterer after the last replayed block up to
+// and including @endBlock to @state.
+func (state *deploymentState) replay(filterer *ERC20Filterer, endBlock uint64) error {
+	var ruleAddresses []common.Address
+	for address := range state.balances {
+		ruleAddresses = append(ruleAddresses, address)
+	}
+
+	for state.deployment.StartBlock < endBlock {
+		startBlock := state.deployment.StartBlock + 1
+		batchEnd := startBlock + transferBatchSize - 1
+		if batchEnd > endBlock {
+			batchEnd = endBlock
+		}
+		opts := &bind.FilterOpts{Start: startBlock, End: &batchEnd}
+
+		// Mints increase, burns decrease total supply.
+		err := filterTransfers(filterer, opts, []common.Address{zeroAddress}, nil, func(ev *ERC20Transfer) {
+			state.totalSupply.Add(state.totalSupply, ev.Value)
+		})
+		if err != nil {
+			return err
+		}
+		err = filterTransfers(filterer, opts, nil, []common.Address{zeroAddress}, func(ev *ERC20Transfer) {
+			state.totalSupply.Sub(state.totalSupply, ev.Value)
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(ruleAddresses) > 0 {
+			err = filterTransfers(filterer, opts, ruleAddresses, nil, func(ev *ERC20Transfer) {
+				state.balances[ev.From].Sub(state.balances[ev.From], ev.Value)
+			})
+			if err != nil {
+				return err
+			}
+			err = filterTransfers(filterer, opts, nil, ruleAddresses, func(ev *ERC20Transfer) {
+				state.balances[ev.To].Add(state.balances[ev.To], ev.Value)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		state.deployment.StartBlock = batchEnd
+	}
+	return nil
+}
+
+func filterTransfers(filterer *ERC20Filterer, opts *bind.FilterOpts, from []common.Address, to []common.Address, apply func(*ERC20Transfer)) error {
+	iter, err := filterer.FilterTransfer(opts, from, to)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for iter.Next() {
+		if iter.Event.Raw.Removed {
+			continue
+		}
+		apply(iter.Event)
+	}
+	return iter.Error()
+}
+
+// blockAtTime returns the last block on @blockchain with a timestamp not after @timestamp.
+func (engine *SupplyEngine) blockAtTime(blockchain string, timestamp time.Time) (uint64, error) {
+	client, err := engine.client(blockchain)
+	if err != nil {
+		return 0, err
+	}
+	header, err := client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	if int64(header.Time) <= timestamp.Unix() {
+		return header.Number.Uint64(), nil
+	}
+
+	low, high := uint64(0), header.Number.Uint64()
+	for low < high {
+		mid := (low + high + 1) / 2
+		blockTime, err := headerTime(client, mid)
+		if err != nil {
+			return 0, err
+		}
+		if blockTime <= timestamp.Unix() {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, nil
+}
+
+func headerTime(client *ethclient.Client, blockNumber uint64) (int64, error) {
+	header, err := client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return 0, err
+	}
+	return int64(header.Time), nil
+}
+
+// GetSupplyAsset returns the supply configuration of the asset with @address on @blockchain from @assets.
+func GetSupplyAsset(assets []SupplyAsset, address string, blockchain string) (SupplyAsset, error) {
+	for _, asset := range assets {
+		if strings.EqualFold(asset.Address, address) && asset.Blockchain == blockchain {
+			return asset, nil
+		}
+	}
+	return SupplyAsset{}, errors.New("no supply config for asset " + address + " on " + blockchain)
+}
@@ -0,0 +1,120 @@
+package supplyservice
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSupplyDays(t *testing.T) {
+	starttime := time.Date(2022, 3, 1, 13, 0, 0, 0, time.UTC)
+	endtime := time.Date(2022, 3, 3, 23, 59, 59, 0, time.UTC)
+	days := supplyDays(starttime, endtime)
+	if len(days) != 3 {
+		t.Fatalf("Number of days is %v but should be 3.", len(days))
+	}
+	if !days[0].Equal(time.Date(2022, 3, 1, 23, 59, 59, 0, time.UTC)) {
+		t.Errorf("First day ends at %v.", days[0])
+	}
+	if !days[2].Equal(endtime) {
+		t.Errorf("Last day ends at %v but should end at %v.", days[2], endtime)
+	}
+}
+
+// transferTokenABI and transferTokenBin belong to a contract which emits ERC20 Transfer
+// events without keeping balances:
+//
+//	contract TestToken {
+//	    event Transfer(address indexed from, address indexed to, uint256 value);
+//	    function mint(address to, uint256 value) external { emit Transfer(address(0), to, value); }
+//	    function burn(address from, uint256 value) external { emit Transfer(from, address(0), value); }
+//	    function move(address from, address to, uint256 value) external { emit Transfer(from, to, value); }
+//	}
+const (
+	transferTokenABI = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"burn\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"mint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"move\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+	transferTokenBin = "0x608060405234801561001057600080fd5b5061020f806100206000396000f3fe608060405234801561001057600080fd5b50600436106100415760003560e01c806340c10f19146100465780639dc29fac1461005b578063bb35783b1461006e575b600080fd5b610059610054366004610173565b610081565b005b610059610069366004610173565b6100c7565b61005961007c36600461019d565b610105565b6040518181526001600160a01b038316906000907fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef906020015b60405180910390a35050565b6040518181526000906001600160a01b038416907fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef906020016100bb565b816001600160a01b0316836001600160a01b03167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef8360405161014a91815260200190565b60405180910390a3505050565b80356001600160a01b038116811461016e57600080fd5b919050565b6000806040838503121561018657600080fd5b61018f83610157565b946020939093013593505050565b6000806000606084860312156101b257600080fd5b6101bb84610157565b92506101c960208501610157565b915060408401359050925092509256fea2646970667358221220f78058f6356947df797113dce300f702f3b768d0344a67aa3dc538070297fd1f64736f6c63430008150033"
+)
+
+func TestReplayTransfers(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: big.NewInt(1e18)}}, 8000000)
+	defer backend.Close()
+
+	parsed, err := abi.JSON(strings.NewReader(transferTokenABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenAddress, _, token, err := bind.DeployContract(auth, parsed, common.FromHex(transferTokenBin), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	holder := common.HexToAddress("0x01")
+	locked := common.HexToAddress("0x02")
+	// Each call is mined in its own block.
+	calls := []struct {
+		method string
+		args   []interface{}
+	}{
+		{"mint", []interface{}{holder, big.NewInt(1000)}},
+		{"move", []interface{}{holder, locked, big.NewInt(300)}},
+		{"burn", []interface{}{holder, big.NewInt(100)}},
+		{"move", []interface{}{locked, holder, big.NewInt(50)}},
+		{"mint", []interface{}{locked, big.NewInt(20)}},
+	}
+	for _, call := range calls {
+		if _, err = token.Transact(auth, call.method, call.args...); err != nil {
+			t.Fatal(err)
+		}
+		backend.Commit()
+	}
+	deployBlock := uint64(1)
+
+	filterer, err := NewERC20Filterer(tokenAddress, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &deploymentState{
+		deployment:  SupplyDeployment{Blockchain: dia.ETHEREUM, Address: tokenAddress.Hex(), StartBlock: deployBlock},
+		totalSupply: big.NewInt(0),
+		balances:    map[common.Address]*big.Int{locked: big.NewInt(0)},
+	}
+
+	// Replay up to the burn, then continue to the last block.
+	steps := []struct {
+		endBlock    uint64
+		totalSupply int64
+		locked      int64
+	}{
+		{deployBlock + 3, 900, 300},
+		{deployBlock + 5, 920, 270},
+	}
+	for _, step := range steps {
+		if err = state.replay(filterer, step.endBlock); err != nil {
+			t.Fatal(err)
+		}
+		if state.totalSupply.Int64() != step.totalSupply {
+			t.Errorf("Total supply at block %d is %v but should be %v.", step.endBlock, state.totalSupply, step.totalSupply)
+		}
+		if state.balances[locked].Int64() != step.locked {
+			t.Errorf("Locked balance at block %d is %v but should be %v.", step.endBlock, state.balances[locked], step.locked)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package supplyservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nativeTokenAddress is the address of the native token of a blockchain, such as ETH on Ethereum.
+const nativeTokenAddress = "0x0000000000000000000000000000000000000000"
+
+// ErrNoMintedSupply is returned if no supply of a bridged asset is minted on its destination chains.
+var ErrNoMintedSupply = errors.New("no minted supply")
+
+// ReserveAsset describes a bridged asset whose source token on Blockchain is locked by the Custodians,
+// such as bridge escrows or the anyTokens of Multichain, and minted on destination chains as Deployments.
+// Address is the zero address if the source token is the native token of the blockchain.
+type ReserveAsset struct {
+	Symbol      string             `json:"symbol"`
+	Blockchain  string             `json:"blockchain"`
+	Address     string             `json:"address"`
+	Custodians  []string           `json:"custodians"`
+	Deployments []SupplyDeployment `json:"deployments"`
+}
+
+// GetProofOfReserve returns the balances of @asset locked by its custodians and minted on its destination
+// chains at the latest block of each chain.
+func (engine *SupplyEngine) GetProofOfReserve(asset ReserveAsset) (por dia.ProofOfReserve, err error) {
+	var balances []dia.ReserveBalance
+	for _, custodian := range asset.Custodians {
+		var balance float64
+		balance, err = engine.lockedBalance(asset, custodian)
+		if err != nil {
+			err = fmt.Errorf("get balance of custodian %s on %s: %v", custodian, asset.Blockchain, err)
+			return
+		}
+		balances = append(balances, dia.ReserveBalance{
+			Blockchain: asset.Blockchain,
+			Address:    common.HexToAddress(custodian).Hex(),
+			Type:       dia.ReserveLocked,
+			Balance:    balance,
+		})
+	}
+	for _, deployment := range asset.Deployments {
+		var supply float64
+		supply, err = engine.totalSupply(deployment, nil)
+		if err != nil {
+			err = fmt.Errorf("get supply of %s on %s: %v", deployment.Address, deployment.Blockchain, err)
+			return
+		}
+		balances = append(balances, dia.ReserveBalance{
+			Blockchain: deployment.Blockchain,
+			Address:    common.HexToAddress(deployment.Address).Hex(),
+			Type:       dia.ReserveMinted,
+			Balance:    supply,
+		})
+	}
+
+	por.Reserves, por.Supply, por.CollateralRatio = aggregateReserves(balances)
+	if por.Supply == 0 {
+		err = ErrNoMintedSupply
+		return
+	}
+	por.Balances = balances
+	por.Asset, err = engine.reserveAsset(asset)
+	if err != nil {
+		return
+	}
+	por.Time, err = engine.blockTime(asset.Blockchain, nil)
+	return
+}
+
+// aggregateReserves returns the locked reserves, the minted supply and their ratio given by @balances.
+// The ratio is 0 if nothing is minted.
+func aggregateReserves(balances []dia.ReserveBalance) (reserves float64, supply float64, ratio float64) {
+	for _, balance := range balances {
+		switch balance.Type {
+		case dia.ReserveLocked:
+			reserves += balance.Balance
+		case dia.ReserveMinted:
+			supply += balance.Balance
+		}
+	}
+	if supply > 0 {
+		ratio = reserves / supply
+	}
+	return
+}
+
+// lockedBalance returns the balance of the source token of @asset held by @custodian.
+func (engine *SupplyEngine) lockedBalance(asset ReserveAsset, custodian string) (float64, error) {
+	if asset.Address != nativeTokenAddress {
+		return engine.tokenBalance(asset.Blockchain, asset.Address, custodian, nil)
+	}
+	client, err := engine.client(asset.Blockchain)
+	if err != nil {
+		return 0, err
+	}
+	balance, err := client.BalanceAt(context.Background(), common.HexToAddress(custodian), nil)
+	if err != nil {
+		return 0, err
+	}
+	return normalizeAmount(balance, 18), nil
+}
+
+// reserveAsset returns the source token of @asset. Symbol and name of native tokens are taken from the config.
+func (engine *SupplyEngine) reserveAsset(asset ReserveAsset) (dia.Asset, error) {
+	if asset.Address == nativeTokenAddress {
+		return dia.Asset{Symbol: asset.Symbol, Name: asset.Symbol, Decimals: 18, Address: asset.Address, Blockchain: asset.Blockchain}, nil
+	}
+	client, err := engine.client(asset.Blockchain)
+	if err != nil {
+		return dia.Asset{}, err
+	}
+	token, err := NewERC20(common.HexToAddress(asset.Address), client)
+	if err != nil {
+		return dia.Asset{}, err
+	}
+	callOpts := &bind.CallOpts{}
+	name, err := token.Name(callOpts)
+	if err != nil {
+		return dia.Asset{}, err
+	}
+	decimals, err := token.Decimals(callOpts)
+	if err != nil {
+		return dia.Asset{}, err
+	}
+	return dia.Asset{
+		Symbol:     asset.Symbol,
+		Name:       name,
+		Decimals:   decimals,
+		Address:    common.HexToAddress(asset.Address).Hex(),
+		Blockchain: asset.Blockchain,
+	}, nil
+}
+
+// GetReserveAssetsFromConfig returns the bridged assets to be monitored from the config file @filename.
+func GetReserveAssetsFromConfig(filename string) (assets []ReserveAsset, err error) {
+	byteData, err := readConfig(filename)
+	if err != nil {
+		return
+	}
+	type reserveAssetList struct {
+		Assets []ReserveAsset `json:"assets"`
+	}
+	var allAssets reserveAssetList
+	err = json.Unmarshal(byteData, &allAssets)
+	if err != nil {
+		return
+	}
+	for _, asset := range allAssets.Assets {
+		if err = validateReserveAsset(asset); err != nil {
+			return
+		}
+	}
+	return allAssets.Assets, nil
+}
+
+func validateReserveAsset(asset ReserveAsset) error {
+	switch {
+	case asset.Symbol == "":
+		return errors.New("missing symbol of reserve asset " + asset.Address)
+	case asset.Blockchain == "":
+		return errors.New("missing blockchain of reserve asset " + asset.Symbol)
+	case len(asset.Custodians) == 0:
+		return errors.New("no custodians of reserve asset " + asset.Symbol)
+	case len(asset.Deployments) == 0:
+		return errors.New("no deployments of reserve asset " + asset.Symbol)
+	}
+	for _, deployment := range asset.Deployments {
+		if strings.EqualFold(deployment.Blockchain, asset.Blockchain) && strings.EqualFold(deployment.Address, asset.Address) {
+			return errors.New("source token of reserve asset " + asset.Symbol + " is listed as deployment")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package supplyservice
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestAggregateReserves(t *testing.T) {
+	tables := []struct {
+		balances []dia.ReserveBalance
+		reserves float64
+		supply   float64
+		ratio    float64
+	}{
+		{nil, 0, 0, 0},
+		{[]dia.ReserveBalance{{Type: dia.ReserveLocked, Balance: 100}}, 100, 0, 0},
+		{[]dia.ReserveBalance{{Type: dia.ReserveLocked, Balance: 100}, {Type: dia.ReserveMinted, Balance: 80}}, 100, 80, 1.25},
+		// Two custodians back the supply minted on two chains.
+		{[]dia.ReserveBalance{
+			{Type: dia.ReserveLocked, Balance: 60},
+			{Type: dia.ReserveLocked, Balance: 30},
+			{Type: dia.ReserveMinted, Balance: 50},
+			{Type: dia.ReserveMinted, Balance: 50},
+		}, 90, 100, 0.9},
+	}
+	for _, table := range tables {
+		reserves, supply, ratio := aggregateReserves(table.balances)
+		if reserves != table.reserves || supply != table.supply || ratio != table.ratio {
+			t.Errorf("Aggregated reserves are %v, %v, %v but should be %v, %v, %v.", reserves, supply, ratio, table.reserves, table.supply, table.ratio)
+		}
+	}
+}
+
+func TestValidateReserveAsset(t *testing.T) {
+	asset := ReserveAsset{
+		Symbol:      "USDC",
+		Blockchain:  dia.ETHEREUM,
+		Address:     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Custodians:  []string{"0x40ec5B33f54e0E8A33A975908C5BA1c14e5BbbDf"},
+		Deployments: []SupplyDeployment{{Blockchain: dia.POLYGON, Address: "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"}},
+	}
+	if err := validateReserveAsset(asset); err != nil {
+		t.Errorf("Valid reserve asset rejected: %v", err)
+	}
+	asset.Deployments = append(asset.Deployments, SupplyDeployment{Blockchain: dia.ETHEREUM, Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"})
+	if err := validateReserveAsset(asset); err == nil {
+		t.Error("Reserve asset with source token as deployment accepted.")
+	}
+	asset.Deployments, asset.Custodians = asset.Deployments[:1], nil
+	if err := validateReserveAsset(asset); err == nil {
+		t.Error("Reserve asset without custodians accepted.")
+	}
+}
@@ -152,6 +152,7 @@ func GetLockedWalletsFromConfig(filename string) (allAssetsMap map[string][]stri
 
 	// make map[string][]string from allAssets. This accounts for erroneous addition of new entry
 	// for already existing asset in config file.
+	allAssetsMap = make(map[string][]string)
 	var diff []string
 	for _, asset := range allAssets.AllAssets {
 		if _, ok := allAssetsMap[asset.Address]; !ok {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/cnf/structhash"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/common"
@@ -116,7 +117,11 @@ func (s *TradesBlockService) process(t dia.Trade) {
 
 	// Price estimation can only be done for verified pairs.
 	// Trades with unverified pairs are still saved, but not sent to the filtersBlockService.
-	if t.VerifiedPair && s.checkTrade(t) {
+	passedCheck := t.VerifiedPair && s.checkTrade(t)
+	if t.VerifiedPair && !passedCheck {
+		metrics.TradesDropped.WithLabelValues(t.Source, metrics.DropReasonCheckTrade).Inc()
+	}
+	if passedCheck {
 		if t.BaseToken.Address == "840" && t.BaseToken.Blockchain == dia.FIAT {
 			// All prices are measured in US-Dollar, so just price for base token == USD
 			t.EstimatedUSDPrice = t.Price
@@ -183,6 +188,9 @@ func (s *TradesBlockService) process(t dia.Trade) {
 	if _, ok := stablecoins[t.Symbol]; ok {
 		if math.Abs(t.EstimatedUSDPrice-1) > tol {
 			log.Errorf("price for stablecoin %s diverges by %v", t.Symbol, math.Abs(t.EstimatedUSDPrice-1))
+			if verifiedTrade {
+				metrics.TradesDropped.WithLabelValues(t.Source, metrics.DropReasonStablecoinTolerance).Inc()
+			}
 			verifiedTrade = false
 		}
 	}
@@ -203,6 +211,9 @@ func (s *TradesBlockService) process(t dia.Trade) {
 
 	if s.currentBlock != nil && s.currentBlock.TradesBlockData.BeginTime.After(t.Time) {
 		log.Debugf("ignore trade should be in previous block %v", t)
+		if verifiedTrade {
+			metrics.TradesDropped.WithLabelValues(t.Source, metrics.DropReasonPreviousBlock).Inc()
+		}
 		verifiedTrade = false
 	}
 
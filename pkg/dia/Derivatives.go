@@ -0,0 +1,116 @@
+package dia
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of derivative instruments.
+const (
+	DerivativeFuture    = "future"
+	DerivativePerpetual = "perpetual"
+	DerivativeOption    = "option"
+)
+
+// Types of options.
+const (
+	OptionCall = "call"
+	OptionPut  = "put"
+)
+
+// FuturesTrade is a trade of a futures or perpetual contract.
+type FuturesTrade struct {
+	Exchange   string
+	Instrument string
+	// Underlying is the symbol of the underlying asset, such as BTC.
+	Underlying string
+	Kind       string
+	Price      float64
+	// Volume is the number of contracts, negative for sells.
+	Volume         float64
+	ForeignTradeID string
+	Time           time.Time
+}
+
+// FundingRate is the funding rate of a perpetual contract for one funding interval.
+type FundingRate struct {
+	Exchange   string
+	Instrument string
+	Rate       float64
+	Time       time.Time
+}
+
+// OpenInterest is the number of open contracts of a derivative instrument.
+type OpenInterest struct {
+	Exchange     string
+	Instrument   string
+	OpenInterest float64
+	Time         time.Time
+}
+
+// DerivativePrice contains the mark price and the index price of the underlying of a derivative instrument.
+type DerivativePrice struct {
+	Exchange   string
+	Instrument string
+	MarkPrice  float64
+	IndexPrice float64
+	Time       time.Time
+}
+
+// OptionOrderbookSnapshot is the top of the order book of an option. Prices are in units of the underlying
+// as quoted by most option exchanges. UnderlyingPrice is the price of the underlying in USD at snapshot time.
+type OptionOrderbookSnapshot struct {
+	Exchange        string
+	Instrument      string
+	Underlying      string
+	OptionType      string
+	Strike          float64
+	Expiry          time.Time
+	BidPrice        float64
+	BidSize         float64
+	AskPrice        float64
+	AskSize         float64
+	UnderlyingPrice float64
+	Time            time.Time
+}
+
+// ParseOptionInstrument parses instrument names of the form UNDERLYING-EXPIRY-STRIKE-TYPE such
+// as BTC-25DEC20-20000-C, which are used by Deribit among others. Options expire at 08:00 UTC.
+func ParseOptionInstrument(instrument string) (underlying string, expiry time.Time, strike float64, optionType string, err error) {
+	parts := strings.Split(instrument, "-")
+	if len(parts) != 4 {
+		err = errors.New("invalid option instrument " + instrument)
+		return
+	}
+	underlying = parts[0]
+	expiry, err = time.Parse("2Jan06", parts[1])
+	if err != nil {
+		return
+	}
+	expiry = expiry.Add(8 * time.Hour)
+	strike, err = strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return
+	}
+	switch parts[3] {
+	case "C":
+		optionType = OptionCall
+	case "P":
+		optionType = OptionPut
+	default:
+		err = errors.New("invalid option type in instrument " + instrument)
+	}
+	return
+}
+
+// CVI is a value of the crypto volatility index of an underlying, computed from the option order books
+// of the near-term and next-term expiries and interpolated to 30 days.
+type CVI struct {
+	Underlying     string
+	Value          float64
+	NearTermExpiry time.Time
+	NextTermExpiry time.Time
+	Time           time.Time
+}
@@ -195,6 +195,56 @@ type NFTOffer struct {
 	Timestamp     time.Time
 	TxHash        string
 	Exchange      string
+
+	// Hash of the order on order book based marketplaces such as Seaport.
+	OrderHash string
+	// Nonce of the order on marketplaces which cancel orders by nonce such as LooksRare.
+	OrderNonce *big.Int
+	// Offers can no longer be filled after Expiry.
+	Expiry    time.Time
+	Cancelled bool
+	Filled    bool
+}
+
+const (
+	// NFTOfferListing is an ask for a specific nft.
+	NFTOfferListing = "Listing"
+	// NFTOfferItem is a bid for a specific nft.
+	NFTOfferItem = "ItemOffer"
+	// NFTOfferCollection is a bid for any nft of a collection.
+	NFTOfferCollection = "CollectionOffer"
+)
+
+// NFTFloorStats is the floor price of an nft collection together with metadata on its reliability.
+type NFTFloorStats struct {
+	NFTClass    NFTClass
+	Time        time.Time
+	FloorWindow time.Duration
+	Floor       float64
+	// Number of sales in the floor window.
+	NumSales int
+	LastSale time.Time
+	// Coefficient of variation of the sale prices in the floor window.
+	Dispersion float64
+	// Stale is true if the last sale is older than the staleness threshold.
+	Stale bool
+}
+
+// NFTListingBook summarizes the active listings of an nft collection at a given time.
+type NFTListingBook struct {
+	NFTClass    NFTClass
+	Time        time.Time
+	NumListings int
+	// Lowest active ask, normalized by the decimals of the currency.
+	FloorAsk float64
+	Depth    []NFTListingDepth
+}
+
+// NFTListingDepth is the number of listings priced within a relative distance
+// from the floor ask, such as 0.1 for 10%.
+type NFTListingDepth struct {
+	Distance    float64
+	NumListings int
 }
 
 // MarshalBinary for NFTOffer
@@ -302,6 +352,28 @@ type Exchange struct {
 	WatchdogDelay int        `json:"WatchdogDelay"`
 }
 
+// ExchangeHealth is the health score of an exchange after a trades block. Score is a weighted mean of the
+// component scores, which lie in [0,1] with 1 meaning healthy:
+// TradeFrequencyScore compares the time since the last trade with the exchange's WatchdogDelay,
+// OutlierScore is one minus the share of trades outside the interquartile range of all trades of an asset,
+// DeviationScore measures the deviation of the exchange's prices from the cross-exchange median and
+// VolumeScore the deviation of the exchange's USD volume from its moving average.
+type ExchangeHealth struct {
+	Exchange            string
+	Score               float64
+	TradeFrequencyScore float64
+	OutlierScore        float64
+	DeviationScore      float64
+	VolumeScore         float64
+	Trades              int64
+	OutlierShare        float64
+	PriceDeviation      float64
+	VolumeUSD           float64
+	VolumeZScore        float64
+	SecondsSinceTrade   float64
+	Time                time.Time
+}
+
 type NFTExchange struct {
 	Name          string     `json:"Name"`
 	Centralized   bool       `json:"Centralized"`
@@ -318,6 +390,66 @@ type Supply struct {
 	CirculatingSupply float64
 	Source            string
 	Time              time.Time
+	// Exclusions lists the balances which were subtracted from total or circulating supply.
+	Exclusions []SupplyExclusion `json:"Exclusions,omitempty"`
+}
+
+// SupplyExclusion is a balance held by an address which is not counted as (circulating) supply.
+type SupplyExclusion struct {
+	Blockchain string
+	Address    string
+	// Type is the kind of rule the address was excluded by, such as a burn address or a vesting contract.
+	Type    string
+	Balance float64
+}
+
+// Types of balances in a proof of reserve.
+const (
+	// ReserveLocked is a balance of the source token locked in a bridge custodian.
+	ReserveLocked = "locked"
+	// ReserveMinted is the supply of a bridged token minted on a destination chain.
+	ReserveMinted = "minted"
+)
+
+// ProofOfReserve compares the balance of an asset locked by bridge custodians on its source chain with the
+// supply of its bridged versions minted on destination chains.
+type ProofOfReserve struct {
+	Asset Asset
+	// Reserves is the total balance locked on the source chain.
+	Reserves float64
+	// Supply is the total supply minted on destination chains.
+	Supply float64
+	// CollateralRatio is Reserves divided by Supply. Bridged tokens are fully backed if it is at least 1.
+	CollateralRatio float64
+	Balances        []ReserveBalance `json:"Balances,omitempty"`
+	Time            time.Time
+}
+
+// ReserveBalance is a locked balance or a minted supply contributing to a proof of reserve.
+type ReserveBalance struct {
+	Blockchain string
+	Address    string
+	// Type is ReserveLocked or ReserveMinted.
+	Type    string
+	Balance float64
+}
+
+// FiatRate is the price in USD of a fiat currency from which cross rates are derived.
+type FiatRate struct {
+	Symbol   string
+	PriceUSD float64
+	Source   string
+	Time     time.Time
+}
+
+// FiatCrossRate is the price of BaseCurrency in units of QuoteCurrency. It is triangulated from the USD
+// prices of both currencies given in Legs. Time is the time of the older leg.
+type FiatCrossRate struct {
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          float64
+	Time          time.Time
+	Legs          []FiatRate
 }
 
 // Asset is the data type for all assets, ranging from fiat to crypto.
@@ -341,6 +473,57 @@ type BlockChain struct {
 	ChainID string `json:"ChainID"`
 }
 
+// APIKey is a key for the REST API. Only the hash of its secret part is stored.
+type APIKey struct {
+	ID     string
+	Prefix string
+	Hash   string
+	Owner  string
+	Scopes []string
+	// Maximal number of requests per calendar month. 0 means unlimited.
+	MonthlyQuota int64
+	// Requests per second refilled into the token bucket of the key and its capacity.
+	RateLimit   float64
+	RateBurst   int
+	Active      bool
+	Created     time.Time
+	Expiry      time.Time
+	RotatedFrom string
+}
+
+// APIKeyUsage is the number of requests done with an api key on an endpoint on a given day.
+type APIKeyUsage struct {
+	Endpoint string
+	Day      time.Time
+	Requests int64
+}
+
+// ExportJob is a request for a bulk export of a dataset of an asset in the time range Starttime -- Endtime.
+// Status is one of the ExportStatus constants.
+type ExportJob struct {
+	ID         string
+	Owner      string
+	Dataset    string
+	Blockchain string
+	Address    string
+	Exchange   string
+	Filter     string
+	Format     string
+	Starttime  time.Time
+	Endtime    time.Time
+	Status     string
+	Error      string
+	Created    time.Time
+	Finished   time.Time
+}
+
+const (
+	ExportStatusPending = "pending"
+	ExportStatusRunning = "running"
+	ExportStatusDone    = "done"
+	ExportStatusFailed  = "failed"
+)
+
 type ChainConfig struct {
 	RestURL string `json:"restURL"`
 	WSURL   string `json:"wsURL"`
@@ -452,6 +635,27 @@ type SynthAssetSupply struct {
 	TotalDebt        float64
 }
 
+// SynthFairValueSource is the source of quotations of synthetic assets derived from their collateral.
+const SynthFairValueSource = "diadata.org/fairvalue"
+
+// SynthAssetValuation is the fair value of a synthetic asset derived from the price of the underlying
+// and the collateral ratio, compared to the price of the synthetic asset obtained from trades.
+type SynthAssetValuation struct {
+	Asset           Asset
+	AssetUnderlying Asset
+	Protocol        string
+	UnderlyingPrice float64
+	CollateralRatio float64
+	FairValue       float64
+	// MarketPrice is zero if there is no recent quotation from trades.
+	MarketPrice float64
+	// Deviation is the relative deviation of the market price from the fair value.
+	Deviation float64
+	// Depeg is true if the absolute deviation exceeds the threshold of the pricing service.
+	Depeg bool
+	Time  time.Time
+}
+
 type TradesBlockData struct {
 	BeginTime    time.Time
 	EndTime      time.Time
@@ -516,6 +720,49 @@ type IndexElement struct {
 	VolatilityRatio VolatilityRatio
 }
 
+// Weighting schemes of indices computed by the index engine.
+const (
+	// IndexWeightingMarketCap weights constituents by their market capitalization.
+	IndexWeightingMarketCap = "marketcap"
+	// IndexWeightingEqual assigns the same weight to all constituents.
+	IndexWeightingEqual = "equal"
+)
+
+// IndexDefinition determines the constituents and the methodology of an index.
+type IndexDefinition struct {
+	Symbol    string
+	Name      string
+	Weighting string
+	// MaxWeight caps the weight of a single constituent as a fraction. Zero means no cap.
+	MaxWeight float64
+	// Rebalancing is the schedule of rebalancings: daily, weekly, monthly or quarterly.
+	Rebalancing  string
+	BaseValue    float64
+	Constituents []Asset
+}
+
+// IndexRebalance records the composition and the divisor of an index set at a rebalancing. The value of
+// the index is the sum of units times price over its constituents, divided by the divisor.
+type IndexRebalance struct {
+	Symbol string
+	Time   time.Time
+	// Reason is initial, scheduled or constituents, the latter if the definition of the index changed.
+	Reason          string
+	IndexValue      float64
+	Divisor         float64
+	PreviousDivisor float64
+	Constituents    []IndexConstituent
+}
+
+// IndexConstituent is an asset of an index with its target weight at a rebalancing.
+type IndexConstituent struct {
+	Asset  Asset
+	Weight float64
+	Units  float64
+	Price  float64
+	Supply float64
+}
+
 type VolatilityRatio struct {
 	Symbol    string
 	Threehold float64
@@ -525,6 +772,52 @@ type VolatilityRatio struct {
 	Selected  bool
 }
 
+// IndexSelectionRules are the criteria by which constituents of an index are selected from its candidates.
+type IndexSelectionRules struct {
+	Symbol string
+	// VolatilityThreshold is the annualized volatility of daily log returns above which a day counts as volatile.
+	VolatilityThreshold float64
+	// VolatilityWindow is the number of daily returns the rolling volatility is computed from.
+	VolatilityWindow int
+	// LookbackDays is the number of days over which volatility, volume and market cap are evaluated.
+	LookbackDays int
+	// MaxDaysAbove is the maximal number of volatile days of a constituent in the lookback period.
+	MaxDaysAbove int64
+	// MinAvgVolume is the minimal average daily trading volume in USD.
+	MinAvgVolume float64
+	// MinMarketCap is the minimal market cap in USD.
+	MinMarketCap float64
+	// MaxConstituents is the maximal number of constituents. The eligible candidates with the largest market
+	// caps are selected. 0 means unlimited.
+	MaxConstituents int
+}
+
+// IndexCandidateEvaluation is the result of evaluating the selection rules on a candidate constituent.
+type IndexCandidateEvaluation struct {
+	Asset           Asset
+	VolatilityRatio VolatilityRatio
+	// Volatility is the latest rolling volatility.
+	Volatility     float64
+	AvgDailyVolume float64
+	MarketCap      float64
+	// Rank by market cap among eligible candidates, starting at 1. 0 if not eligible.
+	Rank     int
+	Selected bool
+	// Reasons why the candidate is not selected.
+	Reasons []string
+}
+
+// IndexRebalanceProposal is the composition of an index proposed by its selection rules.
+type IndexRebalanceProposal struct {
+	Symbol        string
+	Time          time.Time
+	Constituents  []Asset
+	Added         []Asset
+	Removed       []Asset
+	Evaluations   []IndexCandidateEvaluation
+	Justification []string
+}
+
 type SuppliesBlock struct {
 	BlockHash string
 	BlockData SuppliesBlockData
@@ -635,6 +928,19 @@ func (e *IndexBlock) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// MarshalBinary -
+func (e *IndexRebalanceProposal) MarshalBinary() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// UnmarshalBinary -
+func (e *IndexRebalanceProposal) UnmarshalBinary(data []byte) error {
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	return nil
+}
+
 // MarshalBinary -
 func (e *SuppliesBlock) MarshalBinary() ([]byte, error) {
 	return json.Marshal(e)
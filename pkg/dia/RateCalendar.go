@@ -0,0 +1,100 @@
+package dia
+
+import "time"
+
+// Business day calendars of rates and markets.
+const (
+	// CalendarUS are the U.S. government securities business days recommended by SIFMA, on which SOFR is published.
+	CalendarUS = "US"
+	// CalendarTARGET2 are the TARGET2 business days, on which ESTER is published.
+	CalendarTARGET2 = "TARGET2"
+	// CalendarUK are the bank holidays of England and Wales, on which SONIA is not published.
+	CalendarUK = "UK"
+	// CalendarCrypto has no holidays and no weekends, as on-chain rates accrue on every calendar day.
+	CalendarCrypto = "CRYPTO"
+	// CalendarNYSE are the trading days of the New York Stock Exchange.
+	CalendarNYSE = "NYSE"
+)
+
+// Holiday is a day without publication of the rates using the calendar.
+type Holiday struct {
+	Calendar string
+	Date     time.Time
+	Name     string
+}
+
+// Calendar determines the business days of a rate or market.
+type Calendar struct {
+	Name     string
+	holidays map[string]bool
+	weekends bool
+}
+
+// NewCalendar returns the calendar @name with weekends and @holidays as non-business days.
+// Weekends are business days of CalendarCrypto.
+func NewCalendar(name string, holidays []Holiday) *Calendar {
+	c := &Calendar{Name: name, holidays: make(map[string]bool), weekends: name != CalendarCrypto}
+	for _, holiday := range holidays {
+		c.holidays[dayKey(holiday.Date)] = true
+	}
+	return c
+}
+
+// IsBusinessDay returns true if @date is neither a weekend nor a holiday.
+func (c *Calendar) IsBusinessDay(date time.Time) bool {
+	if c.weekends && (date.Weekday() == time.Saturday || date.Weekday() == time.Sunday) {
+		return false
+	}
+	return !c.holidays[dayKey(date)]
+}
+
+// AddBusinessDays returns the business day @n business days after @date, or before @date for negative @n.
+// For @n == 0, @date is rolled forward to the next business day.
+func (c *Calendar) AddBusinessDays(date time.Time, n int) time.Time {
+	date = truncateDay(date)
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if c.IsBusinessDay(date) {
+			n--
+		}
+	}
+	for !c.IsBusinessDay(date) {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// BusinessDays returns the business days in [@dateInit, @dateFinal).
+func (c *Calendar) BusinessDays(dateInit, dateFinal time.Time) (days []time.Time) {
+	for date := truncateDay(dateInit); date.Before(truncateDay(dateFinal)); date = date.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(date) {
+			days = append(days, date)
+		}
+	}
+	return
+}
+
+// CompoundingConvention determines how daily rates are compounded in arrears over an interest period.
+type CompoundingConvention struct {
+	DaysPerYear int
+	// Lookback is the number of business days by which the observed rates precede the interest period.
+	Lookback int
+	// ObservationShift weights the rates with the calendar days of the observation period instead of the
+	// interest period. Without shift, the rate of the business day Lookback days earlier is weighted with
+	// the calendar days of the respective day in the interest period.
+	ObservationShift bool
+	// Rounding is the number of decimals of the results. If Rounding == 0, results are not rounded.
+	Rounding int
+}
+
+func truncateDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func dayKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
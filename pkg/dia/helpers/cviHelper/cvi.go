@@ -0,0 +1,232 @@
+// Package cvihelper computes the crypto volatility index (CVI) from option order books following the
+// variance swap methodology of the CBOE VIX: the variances of the near-term and the next-term expiry are
+// computed from out-of-the-money options and interpolated to a constant maturity of 30 days.
+package cvihelper
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	minutesPerYear  = 365 * 24 * 60
+	minutesPer30Day = 30 * 24 * 60
+	// MinNearTermDuration is the minimal time to expiry of the near-term options. Options close to
+	// expiry are excluded, as their prices are dominated by noise.
+	MinNearTermDuration = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrNoExpiries is returned if the order books do not contain two expiries suited for the index.
+	ErrNoExpiries = errors.New("no near-term and next-term expiries")
+	// ErrNoQuotes is returned if an expiry has no call and put with two-sided quotes at the same strike.
+	ErrNoQuotes = errors.New("no strike with quoted call and put")
+)
+
+// quote holds the mid prices in USD of call and put at a strike. A zero bid marks an option without bid.
+type quote struct {
+	strike  float64
+	callMid float64
+	callBid float64
+	putMid  float64
+	putBid  float64
+	hasCall bool
+	hasPut  bool
+}
+
+// Compute returns the CVI of the options in @snapshots at time @t. All snapshots must belong to the same
+// underlying. @riskFreeRate is the continuously compounded annual risk free rate.
+func Compute(snapshots []dia.OptionOrderbookSnapshot, t time.Time, riskFreeRate float64) (cvi dia.CVI, err error) {
+	terms := make(map[time.Time]map[float64]*quote)
+	for _, snapshot := range snapshots {
+		if !snapshot.Expiry.After(t) {
+			continue
+		}
+		if terms[snapshot.Expiry] == nil {
+			terms[snapshot.Expiry] = make(map[float64]*quote)
+		}
+		q, ok := terms[snapshot.Expiry][snapshot.Strike]
+		if !ok {
+			q = &quote{strike: snapshot.Strike}
+			terms[snapshot.Expiry][snapshot.Strike] = q
+		}
+		// Option prices are quoted in units of the underlying.
+		var mid float64
+		if snapshot.BidPrice > 0 && snapshot.AskPrice > 0 {
+			mid = (snapshot.BidPrice + snapshot.AskPrice) / 2 * snapshot.UnderlyingPrice
+		}
+		switch snapshot.OptionType {
+		case dia.OptionCall:
+			q.callMid, q.callBid, q.hasCall = mid, snapshot.BidPrice, true
+		case dia.OptionPut:
+			q.putMid, q.putBid, q.hasPut = mid, snapshot.BidPrice, true
+		}
+	}
+
+	var expiries []time.Time
+	for expiry := range terms {
+		expiries = append(expiries, expiry)
+	}
+	nearTerm, nextTerm, err := SelectTerms(expiries, t)
+	if err != nil {
+		return
+	}
+
+	minutesNear := nearTerm.Sub(t).Minutes()
+	minutesNext := nextTerm.Sub(t).Minutes()
+	t1 := minutesNear / minutesPerYear
+	t2 := minutesNext / minutesPerYear
+	variance1, err := variance(sortedQuotes(terms[nearTerm]), t1, riskFreeRate)
+	if err != nil {
+		return
+	}
+	variance2, err := variance(sortedQuotes(terms[nextTerm]), t2, riskFreeRate)
+	if err != nil {
+		return
+	}
+
+	weight1 := (minutesNext - minutesPer30Day) / (minutesNext - minutesNear)
+	weight2 := (minutesPer30Day - minutesNear) / (minutesNext - minutesNear)
+	variance30 := (t1*variance1*weight1 + t2*variance2*weight2) * minutesPerYear / minutesPer30Day
+	if variance30 < 0 {
+		err = errors.New("negative interpolated variance")
+		return
+	}
+
+	cvi = dia.CVI{
+		Underlying:     snapshots[0].Underlying,
+		Value:          100 * math.Sqrt(variance30),
+		NearTermExpiry: nearTerm,
+		NextTermExpiry: nextTerm,
+		Time:           t,
+	}
+	return
+}
+
+// SelectTerms returns the expiries whose variances are interpolated to 30 days after @t: the last expiry
+// within 30 days and at least MinNearTermDuration after @t and the first expiry after 30 days. If there
+// is no expiry within 30 days, the first two expiries after 30 days are returned and the variance is extrapolated.
+func SelectTerms(expiries []time.Time, t time.Time) (nearTerm time.Time, nextTerm time.Time, err error) {
+	var candidates []time.Time
+	for _, expiry := range expiries {
+		if expiry.Sub(t) >= MinNearTermDuration {
+			candidates = append(candidates, expiry)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+	thirtyDays := t.Add(30 * 24 * time.Hour)
+	next := sort.Search(len(candidates), func(i int) bool { return candidates[i].After(thirtyDays) })
+	switch {
+	case next > 0 && next < len(candidates):
+		return candidates[next-1], candidates[next], nil
+	case next == 0 && len(candidates) > 1:
+		return candidates[0], candidates[1], nil
+	}
+	return nearTerm, nextTerm, ErrNoExpiries
+}
+
+// variance returns the variance of an expiry with time to expiry @T in years from @quotes sorted by strike.
+func variance(quotes []quote, T float64, riskFreeRate float64) (float64, error) {
+	growth := math.Exp(riskFreeRate * T)
+
+	// The forward price is derived from the strike with the smallest difference between call and put.
+	forwardIndex := -1
+	for i, q := range quotes {
+		if q.callMid == 0 || q.putMid == 0 {
+			continue
+		}
+		if forwardIndex < 0 || math.Abs(q.callMid-q.putMid) < math.Abs(quotes[forwardIndex].callMid-quotes[forwardIndex].putMid) {
+			forwardIndex = i
+		}
+	}
+	if forwardIndex < 0 {
+		return 0, ErrNoQuotes
+	}
+	fq := quotes[forwardIndex]
+	forward := fq.strike + growth*(fq.callMid-fq.putMid)
+
+	// K0 is the first strike below the forward price.
+	k0 := 0
+	for i, q := range quotes {
+		if q.strike <= forward {
+			k0 = i
+		}
+	}
+
+	// Out-of-the-money options are included until two consecutive options without bid.
+	included := make(map[int]float64)
+	included[k0] = atTheMoneyPrice(quotes[k0])
+	zeroBids := 0
+	for i := k0 - 1; i >= 0 && zeroBids < 2; i-- {
+		if !quotes[i].hasPut || quotes[i].putBid == 0 {
+			zeroBids++
+			continue
+		}
+		zeroBids = 0
+		if quotes[i].putMid > 0 {
+			included[i] = quotes[i].putMid
+		}
+	}
+	zeroBids = 0
+	for i := k0 + 1; i < len(quotes) && zeroBids < 2; i++ {
+		if !quotes[i].hasCall || quotes[i].callBid == 0 {
+			zeroBids++
+			continue
+		}
+		zeroBids = 0
+		if quotes[i].callMid > 0 {
+			included[i] = quotes[i].callMid
+		}
+	}
+
+	var strikes []int
+	for i := range included {
+		strikes = append(strikes, i)
+	}
+	sort.Ints(strikes)
+	if len(strikes) < 2 {
+		return 0, errors.New("less than two out-of-the-money options")
+	}
+
+	var sum float64
+	for j, i := range strikes {
+		var deltaK float64
+		switch j {
+		case 0:
+			deltaK = quotes[strikes[1]].strike - quotes[i].strike
+		case len(strikes) - 1:
+			deltaK = quotes[i].strike - quotes[strikes[j-1]].strike
+		default:
+			deltaK = (quotes[strikes[j+1]].strike - quotes[strikes[j-1]].strike) / 2
+		}
+		strike := quotes[i].strike
+		sum += deltaK / (strike * strike) * growth * included[i]
+	}
+	return 2/T*sum - 1/T*math.Pow(forward/quotes[k0].strike-1, 2), nil
+}
+
+// atTheMoneyPrice returns the mean of call and put at K0, or the quoted one if only one has a mid price.
+func atTheMoneyPrice(q quote) float64 {
+	switch {
+	case q.callMid > 0 && q.putMid > 0:
+		return (q.callMid + q.putMid) / 2
+	case q.callMid > 0:
+		return q.callMid
+	default:
+		return q.putMid
+	}
+}
+
+func sortedQuotes(quotesByStrike map[float64]*quote) []quote {
+	var quotes []quote
+	for _, q := range quotesByStrike {
+		quotes = append(quotes, *q)
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].strike < quotes[j].strike })
+	return quotes
+}
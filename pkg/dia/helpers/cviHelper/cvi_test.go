@@ -0,0 +1,85 @@
+package cvihelper
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// blackScholes returns call and put prices with zero interest rate.
+func blackScholes(spot, strike, vol, T float64) (call float64, put float64) {
+	d1 := (math.Log(spot/strike) + vol*vol/2*T) / (vol * math.Sqrt(T))
+	d2 := d1 - vol*math.Sqrt(T)
+	n := func(x float64) float64 { return 0.5 * math.Erfc(-x/math.Sqrt2) }
+	call = spot*n(d1) - strike*n(d2)
+	put = call - spot + strike
+	return
+}
+
+func syntheticBook(t time.Time, expiry time.Time, spot float64, vol float64) (snapshots []dia.OptionOrderbookSnapshot) {
+	T := expiry.Sub(t).Minutes() / minutesPerYear
+	for strike := 2000.0; strike <= 150000; strike += 500 {
+		call, put := blackScholes(spot, strike, vol, T)
+		for optionType, price := range map[string]float64{dia.OptionCall: call, dia.OptionPut: put} {
+			mid := price / spot
+			snapshot := dia.OptionOrderbookSnapshot{
+				Underlying:      "BTC",
+				OptionType:      optionType,
+				Strike:          strike,
+				Expiry:          expiry,
+				AskPrice:        mid * 1.01,
+				UnderlyingPrice: spot,
+			}
+			// Far out-of-the-money options have no bid.
+			if mid > 0.0005 {
+				snapshot.BidPrice = mid * 0.99
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return
+}
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2020, 11, 2, 12, 0, 0, 0, time.UTC)
+	near := time.Date(2020, 11, 27, 8, 0, 0, 0, time.UTC)
+	next := time.Date(2020, 12, 25, 8, 0, 0, 0, time.UTC)
+	snapshots := append(syntheticBook(now, near, 30000, 0.8), syntheticBook(now, next, 30000, 0.8)...)
+	// An expiry within a week is not used.
+	snapshots = append(snapshots, syntheticBook(now, now.Add(48*time.Hour), 30000, 2)...)
+
+	cvi, err := Compute(snapshots, now, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cvi.NearTermExpiry.Equal(near) || !cvi.NextTermExpiry.Equal(next) {
+		t.Errorf("unexpected terms %v and %v", cvi.NearTermExpiry, cvi.NextTermExpiry)
+	}
+	// With a constant implied volatility of 80% the index is 80 up to discretisation errors.
+	if math.Abs(cvi.Value-80) > 2 {
+		t.Errorf("expected CVI of about 80, got %v", cvi.Value)
+	}
+}
+
+func TestSelectTerms(t *testing.T) {
+	now := time.Date(2020, 11, 2, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	expiries := []time.Time{now.Add(60 * day), now.Add(3 * day), now.Add(20 * day), now.Add(35 * day), now.Add(13 * day)}
+	near, next, err := SelectTerms(expiries, now)
+	if err != nil || !near.Equal(now.Add(20*day)) || !next.Equal(now.Add(35*day)) {
+		t.Errorf("unexpected terms %v, %v, %v", near, next, err)
+	}
+
+	near, next, err = SelectTerms(expiries[3:4], now)
+	if err != ErrNoExpiries {
+		t.Errorf("expected ErrNoExpiries, got %v, %v", near, next)
+	}
+
+	// Without expiry within 30 days the two following expiries are extrapolated.
+	near, next, err = SelectTerms([]time.Time{now.Add(60 * day), now.Add(35 * day)}, now)
+	if err != nil || !near.Equal(now.Add(35*day)) || !next.Equal(now.Add(60*day)) {
+		t.Errorf("unexpected terms %v, %v, %v", near, next, err)
+	}
+}
@@ -0,0 +1,135 @@
+// Package fiathelper triangulates exchange rates between arbitrary fiat currencies from their prices in USD,
+// which are published by the ECB and the forex scrapers.
+package fiathelper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	// USD is the currency in which fiat prices are stored and through which cross rates are triangulated.
+	USD = "USD"
+	// MaxRateAge is the maximal age of a USD price used for a cross rate. ECB rates are published on
+	// TARGET2 business days only, so the last rate before a long weekend has to be accepted.
+	MaxRateAge = 4 * 24 * time.Hour
+)
+
+// ErrStaleRate is returned if the latest USD price of a currency is older than MaxRateAge.
+var ErrStaleRate = errors.New("stale fiat rate")
+
+// ErrNoRate is returned if there is no USD price of a currency.
+var ErrNoRate = errors.New("no fiat rate")
+
+// USDRate returns the trivial rate of USD at @t.
+func USDRate(t time.Time) dia.FiatRate {
+	return dia.FiatRate{Symbol: USD, PriceUSD: 1, Source: dia.Diadata, Time: t}
+}
+
+// NormalizeCurrency returns the upper case ISO 4217 code @currency, or an error if it is not a
+// code of three letters.
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(currency)
+	if len(currency) != 3 {
+		return "", fmt.Errorf("invalid currency %s", currency)
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency %s", currency)
+		}
+	}
+	return currency, nil
+}
+
+// CheckAge returns ErrStaleRate if @rate is older than MaxRateAge at @t.
+func CheckAge(rate dia.FiatRate, t time.Time) error {
+	if t.Sub(rate.Time) > MaxRateAge {
+		return fmt.Errorf("%w: last price of %s at %v", ErrStaleRate, rate.Symbol, rate.Time)
+	}
+	return nil
+}
+
+// USDPairs returns the currency pairs of @symbol against USD in both directions, written with @separator
+// such as EUR/USD and USD/EUR.
+func USDPairs(symbol string, separator string) []string {
+	return []string{symbol + separator + USD, USD + separator + symbol}
+}
+
+// PairRate returns the USD price of @symbol from the @price of @pair, which is one of the pairs returned
+// by USDPairs. The price of USD/@symbol is inverted.
+func PairRate(symbol string, pair string, separator string, price float64, source string, t time.Time) (dia.FiatRate, error) {
+	if price <= 0 {
+		return dia.FiatRate{}, fmt.Errorf("no positive price of %s", pair)
+	}
+	switch pair {
+	case symbol + separator + USD:
+	case USD + separator + symbol:
+		price = 1 / price
+	default:
+		return dia.FiatRate{}, fmt.Errorf("%s is not a pair of %s against USD", pair, symbol)
+	}
+	return dia.FiatRate{Symbol: symbol, PriceUSD: price, Source: source, Time: t}, nil
+}
+
+// LatestRate returns the latest of @rates at or before @t, which may come from different sources.
+// It returns ErrNoRate if there is none and ErrStaleRate if it is older than MaxRateAge.
+func LatestRate(rates []dia.FiatRate, t time.Time) (dia.FiatRate, error) {
+	rate, ok := NewRateSeries(rates).At(t)
+	if rate.Time.IsZero() {
+		return dia.FiatRate{}, ErrNoRate
+	}
+	if !ok {
+		return rate, CheckAge(rate, t)
+	}
+	return rate, nil
+}
+
+// CrossRate returns the price of the currency of @base in units of the currency of @quote.
+func CrossRate(base dia.FiatRate, quote dia.FiatRate) (dia.FiatCrossRate, error) {
+	if base.PriceUSD <= 0 || quote.PriceUSD <= 0 {
+		return dia.FiatCrossRate{}, fmt.Errorf("no positive USD prices of %s and %s", base.Symbol, quote.Symbol)
+	}
+	t := base.Time
+	if quote.Time.Before(t) {
+		t = quote.Time
+	}
+	return dia.FiatCrossRate{
+		BaseCurrency:  base.Symbol,
+		QuoteCurrency: quote.Symbol,
+		Rate:          base.PriceUSD / quote.PriceUSD,
+		Time:          t,
+		Legs:          []dia.FiatRate{base, quote},
+	}, nil
+}
+
+// RateSeries holds the USD prices of a fiat currency in ascending order of time. Prices from different
+// sources may be mixed, in which case the latest of them is used.
+type RateSeries []dia.FiatRate
+
+// NewRateSeries returns the series of @rates, which may be given in any order.
+func NewRateSeries(rates []dia.FiatRate) RateSeries {
+	series := make(RateSeries, len(rates))
+	copy(series, rates)
+	sort.Slice(series, func(i, j int) bool { return series[i].Time.Before(series[j].Time) })
+	return series
+}
+
+// At returns the last price at or before @t. ok is false if there is none or if it is older than MaxRateAge.
+func (series RateSeries) At(t time.Time) (rate dia.FiatRate, ok bool) {
+	i := sort.Search(len(series), func(i int) bool { return series[i].Time.After(t) })
+	if i == 0 {
+		return dia.FiatRate{}, false
+	}
+	rate = series[i-1]
+	return rate, CheckAge(rate, t) == nil
+}
+
+// ConvertUSD returns @priceUSD in units of the currency of @rate.
+func ConvertUSD(priceUSD float64, rate dia.FiatRate) float64 {
+	return priceUSD / rate.PriceUSD
+}
@@ -0,0 +1,151 @@
+package fiathelper
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestCrossRate(t *testing.T) {
+	t0 := time.Date(2022, 6, 10, 16, 0, 0, 0, time.UTC)
+	brl := dia.FiatRate{Symbol: "BRL", PriceUSD: 0.2, Source: dia.Diadata, Time: t0}
+	jpy := dia.FiatRate{Symbol: "JPY", PriceUSD: 0.0075, Source: dia.Diadata, Time: t0.Add(-time.Hour)}
+
+	cross, err := CrossRate(brl, jpy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(cross.Rate-26.666666666666668) > 1e-9 {
+		t.Errorf("rate: got %v, want 26.67", cross.Rate)
+	}
+	if !cross.Time.Equal(jpy.Time) {
+		t.Errorf("time: got %v, want time of the older leg %v", cross.Time, jpy.Time)
+	}
+	if len(cross.Legs) != 2 || cross.Legs[0].Symbol != "BRL" || cross.Legs[1].Symbol != "JPY" {
+		t.Errorf("legs: got %v", cross.Legs)
+	}
+
+	usd, err := CrossRate(USDRate(t0), brl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usd.Rate != 5 {
+		t.Errorf("USD/BRL: got %v, want 5", usd.Rate)
+	}
+
+	if _, err := CrossRate(brl, dia.FiatRate{Symbol: "XXX"}); err == nil {
+		t.Error("cross rate with zero price was accepted")
+	}
+}
+
+func TestRateSeries(t *testing.T) {
+	friday := time.Date(2022, 6, 10, 16, 0, 0, 0, time.UTC)
+	series := NewRateSeries([]dia.FiatRate{
+		{Symbol: "EUR", PriceUSD: 1.05, Time: friday},
+		{Symbol: "EUR", PriceUSD: 1.06, Time: friday.AddDate(0, 0, -1)},
+	})
+
+	tests := []struct {
+		t     time.Time
+		price float64
+		ok    bool
+	}{
+		{friday.Add(-48 * time.Hour), 0, false},
+		{friday.Add(-time.Hour), 1.06, true},
+		{friday, 1.05, true},
+		// The rate of Friday is used over the weekend.
+		{friday.AddDate(0, 0, 3), 1.05, true},
+		{friday.AddDate(0, 0, 5), 1.05, false},
+	}
+	for _, test := range tests {
+		rate, ok := series.At(test.t)
+		if ok != test.ok || (ok && rate.PriceUSD != test.price) {
+			t.Errorf("At(%v): got %v %v, want %v %v", test.t, rate.PriceUSD, ok, test.price, test.ok)
+		}
+	}
+}
+
+func TestCheckAge(t *testing.T) {
+	now := time.Now()
+	if err := CheckAge(dia.FiatRate{Symbol: "EUR", Time: now.Add(-MaxRateAge - time.Second)}, now); !errors.Is(err, ErrStaleRate) {
+		t.Errorf("got %v, want ErrStaleRate", err)
+	}
+	if err := CheckAge(dia.FiatRate{Symbol: "EUR", Time: now.Add(-time.Hour)}, now); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestNormalizeCurrency(t *testing.T) {
+	if currency, err := NormalizeCurrency("brl"); err != nil || currency != "BRL" {
+		t.Errorf("got %s %v, want BRL", currency, err)
+	}
+	for _, currency := range []string{"", "EURO", "U$D"} {
+		if _, err := NormalizeCurrency(currency); err == nil {
+			t.Errorf("invalid currency %q was accepted", currency)
+		}
+	}
+}
+
+func TestPairRate(t *testing.T) {
+	t0 := time.Date(2022, 6, 10, 16, 0, 0, 0, time.UTC)
+	pairs := USDPairs("JPY", "/")
+	if len(pairs) != 2 || pairs[0] != "JPY/USD" || pairs[1] != "USD/JPY" {
+		t.Fatalf("pairs: got %v", pairs)
+	}
+
+	rate, err := PairRate("JPY", "USD/JPY", "/", 125, "TwelveData", t0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.PriceUSD != 0.008 || rate.Source != "TwelveData" || rate.Symbol != "JPY" || !rate.Time.Equal(t0) {
+		t.Errorf("USD/JPY: got %v, want 0.008 from TwelveData", rate)
+	}
+	rate, err = PairRate("EUR", "EUR-USD", "-", 1.05, "FinageForex", t0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.PriceUSD != 1.05 || rate.Source != "FinageForex" {
+		t.Errorf("EUR-USD: got %v, want 1.05 from FinageForex", rate)
+	}
+
+	if _, err := PairRate("EUR", "EUR/GBP", "/", 0.85, "TwelveData", t0); err == nil {
+		t.Error("pair without USD was accepted")
+	}
+	if _, err := PairRate("JPY", "USD/JPY", "/", 0, "TwelveData", t0); err == nil {
+		t.Error("zero price was accepted")
+	}
+}
+
+func TestLatestRate(t *testing.T) {
+	friday := time.Date(2022, 6, 10, 16, 0, 0, 0, time.UTC)
+	rates := []dia.FiatRate{
+		{Symbol: "EUR", PriceUSD: 1.0521, Source: "ECB", Time: friday.Add(-2 * time.Hour)},
+		{Symbol: "EUR", PriceUSD: 1.0518, Source: "FinageForex", Time: friday},
+		{Symbol: "EUR", PriceUSD: 1.0523, Source: "TwelveData", Time: friday.Add(-time.Minute)},
+	}
+
+	rate, err := LatestRate(rates, friday.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Source != "FinageForex" || rate.PriceUSD != 1.0518 {
+		t.Errorf("got %v, want the latest rate from FinageForex", rate)
+	}
+	rate, err = LatestRate(rates, friday.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Source != "ECB" {
+		t.Errorf("got %v, want the ECB rate preceding the time", rate)
+	}
+
+	if _, err := LatestRate(rates, friday.Add(-3*time.Hour)); !errors.Is(err, ErrNoRate) {
+		t.Errorf("got %v, want ErrNoRate", err)
+	}
+	if _, err := LatestRate(rates, friday.Add(MaxRateAge+time.Second)); !errors.Is(err, ErrStaleRate) {
+		t.Errorf("got %v, want ErrStaleRate", err)
+	}
+}
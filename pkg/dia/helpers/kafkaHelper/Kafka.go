@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/kafka-go/compress"
 	log "github.com/sirupsen/logrus"
@@ -187,13 +188,16 @@ func WriteMessage(w *kafka.Writer, m KafkaMessage) error {
 	key := []byte("helloKafka")
 	value, err := m.MarshalBinary()
 	if err == nil && value != nil {
+		start := time.Now()
 		err = w.WriteMessages(context.Background(),
 			kafka.Message{
 				Key:   key,
 				Value: value,
 			},
 		)
+		metrics.ObserveSince(metrics.KafkaWriteDuration.WithLabelValues(w.Topic), start)
 		if err != nil {
+			metrics.KafkaWriteErrors.WithLabelValues(w.Topic).Inc()
 			log.Errorln("WriteMessage error:", err, "sizeMessage:", float64(len(value))/(1024.0*1024.0), "MB")
 		}
 	} else {
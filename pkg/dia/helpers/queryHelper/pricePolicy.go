@@ -0,0 +1,79 @@
+package queryhelper
+
+import (
+	"errors"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Policies for the price of an asset at a point in time.
+const (
+	// PolicyLastBefore takes the last filter point at or before the requested time.
+	PolicyLastBefore = "lastBefore"
+	// PolicyNearest takes the filter point closest to the requested time, before or after.
+	PolicyNearest = "nearest"
+	// PolicyLinear interpolates linearly between the filter points around the requested time.
+	PolicyLinear = "linear"
+	// PolicyDailyClose takes the last filter point of the day containing the requested time in a given timezone.
+	PolicyDailyClose = "dailyClose"
+)
+
+var errNoFilterPoint = errors.New("no filter point around requested time")
+
+// ValidPricePolicy returns true if @policy is one of the price policies.
+func ValidPricePolicy(policy string) bool {
+	switch policy {
+	case PolicyLastBefore, PolicyNearest, PolicyLinear, PolicyDailyClose:
+		return true
+	}
+	return false
+}
+
+// DailyCloseTime returns the last instant of the day in @location which contains @timestamp.
+func DailyCloseTime(timestamp time.Time, location *time.Location) time.Time {
+	t := timestamp.In(location)
+	nextDay := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+	return nextDay.Add(-time.Nanosecond)
+}
+
+// PriceAt returns the price at @timestamp according to @policy, given the last filter point @before at or
+// before @timestamp and the first filter point @after after @timestamp. Either one can be nil.
+// It also returns the filter points the price is taken from.
+// For PolicyDailyClose, @timestamp must be the daily close time and behaves like PolicyLastBefore.
+func PriceAt(policy string, timestamp time.Time, before *dia.FilterPoint, after *dia.FilterPoint) (price float64, points []dia.FilterPoint, err error) {
+	switch policy {
+	case PolicyLastBefore, PolicyDailyClose:
+		if before == nil {
+			return 0, nil, errNoFilterPoint
+		}
+		return before.Value, []dia.FilterPoint{*before}, nil
+
+	case PolicyNearest:
+		switch {
+		case before == nil && after == nil:
+			return 0, nil, errNoFilterPoint
+		case after == nil:
+			return before.Value, []dia.FilterPoint{*before}, nil
+		case before == nil:
+			return after.Value, []dia.FilterPoint{*after}, nil
+		}
+		// Ties are resolved in favour of the earlier point.
+		if timestamp.Sub(before.Time) <= after.Time.Sub(timestamp) {
+			return before.Value, []dia.FilterPoint{*before}, nil
+		}
+		return after.Value, []dia.FilterPoint{*after}, nil
+
+	case PolicyLinear:
+		if before == nil || after == nil {
+			return 0, nil, errors.New("interpolation needs filter points before and after requested time")
+		}
+		if before.Time.Equal(timestamp) || !after.Time.After(before.Time) {
+			return before.Value, []dia.FilterPoint{*before}, nil
+		}
+		weight := float64(timestamp.Sub(before.Time)) / float64(after.Time.Sub(before.Time))
+		price = before.Value + weight*(after.Value-before.Value)
+		return price, []dia.FilterPoint{*before, *after}, nil
+	}
+	return 0, nil, errors.New("unknown price policy " + policy)
+}
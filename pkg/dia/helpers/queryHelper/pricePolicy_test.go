@@ -0,0 +1,57 @@
+package queryhelper
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestPriceAt(t *testing.T) {
+	t0 := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := &dia.FilterPoint{Value: 100, Time: t0.Add(-30 * time.Second)}
+	after := &dia.FilterPoint{Value: 200, Time: t0.Add(90 * time.Second)}
+
+	cases := []struct {
+		policy    string
+		before    *dia.FilterPoint
+		after     *dia.FilterPoint
+		price     float64
+		numPoints int
+		fails     bool
+	}{
+		{PolicyLastBefore, before, after, 100, 1, false},
+		{PolicyLastBefore, nil, after, 0, 0, true},
+		{PolicyNearest, before, after, 100, 1, false},
+		{PolicyNearest, nil, after, 200, 1, false},
+		{PolicyNearest, nil, nil, 0, 0, true},
+		{PolicyLinear, before, after, 125, 2, false},
+		{PolicyLinear, before, nil, 0, 0, true},
+		{PolicyDailyClose, before, nil, 100, 1, false},
+		{"median", before, after, 0, 0, true},
+	}
+	for _, c := range cases {
+		price, points, err := PriceAt(c.policy, t0, c.before, c.after)
+		if (err != nil) != c.fails {
+			t.Errorf("%s: unexpected error %v", c.policy, err)
+			continue
+		}
+		if math.Abs(price-c.price) > 1e-9 || len(points) != c.numPoints {
+			t.Errorf("%s: got price %v from %d points, want %v from %d", c.policy, price, len(points), c.price, c.numPoints)
+		}
+	}
+}
+
+func TestDailyCloseTime(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no timezone database: ", err)
+	}
+	// 2022-06-02 02:00 UTC is still June 1st in New York.
+	closeTime := DailyCloseTime(time.Date(2022, 6, 2, 2, 0, 0, 0, time.UTC), location)
+	want := time.Date(2022, 6, 2, 4, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+	if !closeTime.Equal(want) {
+		t.Errorf("got %v, want %v", closeTime.UTC(), want)
+	}
+}
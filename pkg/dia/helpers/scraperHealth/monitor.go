@@ -0,0 +1,162 @@
+// Package scraperHealth tracks the trades of the pairs scraped by a collector.
+package scraperHealth
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// States of scraped pairs.
+const (
+	PairLive   = "live"
+	PairStale  = "stale"
+	PairFailed = "failed"
+)
+
+// PairHealth is the liveness of a single pair as exposed on the readiness probe.
+type PairHealth struct {
+	Pair            string    `json:"pair"`
+	State           string    `json:"state"`
+	LastTrade       time.Time `json:"lastTrade"`
+	Resubscriptions int       `json:"resubscriptions"`
+}
+
+// ScraperHealth is the liveness of all pairs of the collector's exchange.
+type ScraperHealth struct {
+	Exchange  string       `json:"exchange"`
+	LastTrade time.Time    `json:"lastTrade"`
+	Live      int          `json:"live"`
+	Stale     int          `json:"stale"`
+	Failed    int          `json:"failed"`
+	Pairs     []PairHealth `json:"pairs"`
+}
+
+type pairState struct {
+	pair dia.ExchangePair
+	// lastTrade is the time of the last trade or, before the first trade, of the subscription.
+	lastTrade time.Time
+	// lastAttempt is the time of the last subscription, which restarts the watchdog delay.
+	lastAttempt     time.Time
+	resubscriptions int
+	state           string
+}
+
+// PairMonitor tracks the time of the last trade per pair. Pairs without trades within the watchdog delay are
+// stale and resubscribed. Pairs which stay silent after maxResubscriptions resubscriptions are failed and left
+// alone until they receive a trade, as they are most likely just illiquid. The collector is only restarted if
+// the whole exchange stays silent.
+// Pairs are identified by their foreign name, which is compared to the pair of trades regardless of case
+// and separators, as scrapers write the foreign name in the exchange's notation to trades.
+type PairMonitor struct {
+	mu                 sync.Mutex
+	exchange           string
+	WatchdogDelay      time.Duration
+	maxResubscriptions int
+	pairs              map[string]*pairState
+	lastTrade          time.Time
+}
+
+// NewPairMonitor returns a monitor for the pairs of @exchange which starts at time @start.
+func NewPairMonitor(exchange string, watchdogDelay time.Duration, maxResubscriptions int, start time.Time) *PairMonitor {
+	return &PairMonitor{
+		exchange:           exchange,
+		WatchdogDelay:      watchdogDelay,
+		maxResubscriptions: maxResubscriptions,
+		pairs:              make(map[string]*pairState),
+		lastTrade:          start,
+	}
+}
+
+// Subscribed registers @pair as subscribed at time @t.
+func (pm *PairMonitor) Subscribed(pair dia.ExchangePair, t time.Time) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.pairs[pairKey(pair.ForeignName)] = &pairState{pair: pair, lastTrade: t, lastAttempt: t, state: PairLive}
+}
+
+// Trade records a trade of the pair with foreign name @foreignName at time @t.
+func (pm *PairMonitor) Trade(foreignName string, t time.Time) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.lastTrade = t
+	ps, ok := pm.pairs[pairKey(foreignName)]
+	if !ok {
+		return
+	}
+	ps.lastTrade = t
+	ps.lastAttempt = t
+	ps.resubscriptions = 0
+	ps.state = PairLive
+}
+
+// Check updates the states of all pairs at time @t. It returns the pairs which should be resubscribed
+// and whether the collector should be restarted, which is the case if no pair received a trade since the
+// resubscriptions of all pairs failed.
+func (pm *PairMonitor) Check(t time.Time) (resubscribe []dia.ExchangePair, restart bool) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	for _, ps := range pm.pairs {
+		if ps.state == PairFailed || t.Sub(ps.lastAttempt) <= pm.WatchdogDelay {
+			continue
+		}
+		if ps.resubscriptions >= pm.maxResubscriptions {
+			ps.state = PairFailed
+			continue
+		}
+		ps.state = PairStale
+		ps.resubscriptions++
+		ps.lastAttempt = t
+		resubscribe = append(resubscribe, ps.pair)
+	}
+	sort.Slice(resubscribe, func(i, j int) bool { return resubscribe[i].ForeignName < resubscribe[j].ForeignName })
+
+	// Without subscribed pairs, as for DEX scrapers, there is nothing to resubscribe before restarting.
+	silence := pm.WatchdogDelay
+	if len(pm.pairs) > 0 {
+		silence *= time.Duration(pm.maxResubscriptions + 1)
+	}
+	restart = t.Sub(pm.lastTrade) > silence
+	return
+}
+
+// Health returns the current liveness of all pairs.
+func (pm *PairMonitor) Health() ScraperHealth {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	health := ScraperHealth{Exchange: pm.exchange, LastTrade: pm.lastTrade, Pairs: []PairHealth{}}
+	for _, ps := range pm.pairs {
+		switch ps.state {
+		case PairLive:
+			health.Live++
+		case PairStale:
+			health.Stale++
+		case PairFailed:
+			health.Failed++
+		}
+		health.Pairs = append(health.Pairs, PairHealth{
+			Pair:            ps.pair.ForeignName,
+			State:           ps.state,
+			LastTrade:       ps.lastTrade,
+			Resubscriptions: ps.resubscriptions,
+		})
+	}
+	sort.Slice(health.Pairs, func(i, j int) bool { return health.Pairs[i].Pair < health.Pairs[j].Pair })
+	return health
+}
+
+// Ready returns true if at least one pair is live or no pairs are subscribed.
+func (pm *PairMonitor) Ready() bool {
+	health := pm.Health()
+	return len(health.Pairs) == 0 || health.Live > 0
+}
+
+// pairKey returns the key of the pair with foreign name @foreignName.
+func pairKey(foreignName string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", "_", "", "/", "", ":", "").Replace(foreignName))
+}
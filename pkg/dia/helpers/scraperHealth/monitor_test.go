@@ -0,0 +1,88 @@
+package scraperHealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestPairMonitor(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	delay := time.Minute
+	pm := NewPairMonitor("Exchange", delay, 2, start)
+	pm.Subscribed(dia.ExchangePair{ForeignName: "BTC-USD"}, start)
+	pm.Subscribed(dia.ExchangePair{ForeignName: "XYZ-USD"}, start)
+
+	// BTC-USD trades continuously, XYZ-USD is silent.
+	var resubscriptions int
+	for i := 1; i <= 5; i++ {
+		now := start.Add(time.Duration(i) * 61 * time.Second)
+		pm.Trade("BTC-USD", now)
+		resubscribe, restart := pm.Check(now)
+		if restart {
+			t.Fatalf("restart in round %d although BTC-USD is live", i)
+		}
+		for _, pair := range resubscribe {
+			if pair.ForeignName != "XYZ-USD" {
+				t.Errorf("resubscribed live pair %s", pair.ForeignName)
+			}
+			resubscriptions++
+		}
+	}
+	if resubscriptions != 2 {
+		t.Errorf("expected 2 resubscriptions of XYZ-USD, got %d", resubscriptions)
+	}
+
+	health := pm.Health()
+	if health.Live != 1 || health.Failed != 1 || !pm.Ready() {
+		t.Errorf("unexpected health %+v", health)
+	}
+
+	// A trade revives a failed pair.
+	pm.Trade("XYZ-USD", start.Add(6*time.Minute))
+	if health = pm.Health(); health.Live != 2 {
+		t.Errorf("expected 2 live pairs, got %+v", health)
+	}
+
+	// The collector restarts if the whole exchange stays silent for all resubscriptions.
+	last := start.Add(6 * time.Minute)
+	if _, restart := pm.Check(last.Add(3 * delay)); restart {
+		t.Error("restart before all resubscriptions")
+	}
+	if _, restart := pm.Check(last.Add(3*delay + time.Second)); !restart {
+		t.Error("no restart of silent exchange")
+	}
+}
+
+func TestPairMonitorWithoutPairs(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	pm := NewPairMonitor("DEX", time.Minute, 3, start)
+	if _, restart := pm.Check(start.Add(time.Minute)); restart {
+		t.Error("restart within watchdog delay")
+	}
+	if _, restart := pm.Check(start.Add(time.Minute + time.Second)); !restart {
+		t.Error("no restart after watchdog delay")
+	}
+	if !pm.Ready() {
+		t.Error("monitor without pairs not ready")
+	}
+}
+
+func TestPairMonitorMatchesTradePairs(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	pm := NewPairMonitor("Exchange", time.Minute, 1, start)
+	pm.Subscribed(dia.ExchangePair{ForeignName: "BTC-USDT"}, start)
+	pm.Subscribed(dia.ExchangePair{ForeignName: "eth_usdt"}, start)
+
+	now := start.Add(2 * time.Minute)
+	pm.Trade("BTCUSDT", now)
+	pm.Trade("ETH-USDT", now)
+	if resubscribe, _ := pm.Check(now); len(resubscribe) != 0 {
+		t.Errorf("resubscribed pairs with trades: %v", resubscribe)
+	}
+	health := pm.Health()
+	if health.Live != 2 || health.Pairs[0].Pair != "BTC-USDT" || health.Pairs[1].Pair != "eth_usdt" {
+		t.Errorf("unexpected health %+v", health)
+	}
+}
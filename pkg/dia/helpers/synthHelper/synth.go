@@ -0,0 +1,59 @@
+// Package synthhelper values synthetic and wrapped assets by their claim on the underlying collateral.
+package synthhelper
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// ErrNoCollateral is returned if the collateral ratio of a synthetic asset is not positive.
+var ErrNoCollateral = errors.New("no collateral ratio")
+
+// IsRedeemableOneToOne returns true if a unit of the synthetic asset of @protocol is a claim on one unit of the
+// underlying, such as aTokens whose supply grows with the accrued interest. Otherwise, as for cTokens, the
+// collateral ratio is the exchange rate of the synthetic asset to the underlying.
+func IsRedeemableOneToOne(protocol string) bool {
+	return strings.HasPrefix(protocol, "Aave")
+}
+
+// FairValue returns the price of a synthetic asset backed by @supply given the price @underlyingPrice of its
+// underlying. Claims on one unit of the underlying are valued at most at the price of the underlying and
+// less if the collateral does not cover the supply.
+func FairValue(supply dia.SynthAssetSupply, underlyingPrice float64) (float64, error) {
+	if supply.ColleteralRatio <= 0 || math.IsNaN(supply.ColleteralRatio) || math.IsInf(supply.ColleteralRatio, 0) {
+		return 0, ErrNoCollateral
+	}
+	if IsRedeemableOneToOne(supply.Protocol) {
+		return underlyingPrice * math.Min(supply.ColleteralRatio, 1), nil
+	}
+	return underlyingPrice * supply.ColleteralRatio, nil
+}
+
+// Valuate returns the valuation of the synthetic asset backed by @supply at @t. @marketPrice is the price of
+// the synthetic asset from trades, or zero if there is none. The asset is flagged as depegged if the market
+// price deviates from the fair value by more than @threshold, given as a fraction.
+func Valuate(supply dia.SynthAssetSupply, underlyingPrice float64, marketPrice float64, threshold float64, t time.Time) (valuation dia.SynthAssetValuation, err error) {
+	fairValue, err := FairValue(supply, underlyingPrice)
+	if err != nil {
+		return
+	}
+	valuation = dia.SynthAssetValuation{
+		Asset:           supply.Asset,
+		AssetUnderlying: supply.AssetUnderlying,
+		Protocol:        supply.Protocol,
+		UnderlyingPrice: underlyingPrice,
+		CollateralRatio: supply.ColleteralRatio,
+		FairValue:       fairValue,
+		MarketPrice:     marketPrice,
+		Time:            t,
+	}
+	if marketPrice > 0 && fairValue > 0 {
+		valuation.Deviation = marketPrice/fairValue - 1
+		valuation.Depeg = math.Abs(valuation.Deviation) > threshold
+	}
+	return valuation, nil
+}
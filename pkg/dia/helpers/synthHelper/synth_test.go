@@ -0,0 +1,51 @@
+package synthhelper
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestValuate(t *testing.T) {
+	tol := 1e-9
+	tables := []struct {
+		protocol        string
+		collateralRatio float64
+		underlyingPrice float64
+		marketPrice     float64
+		fairValue       float64
+		deviation       float64
+		depeg           bool
+	}{
+		// aTokens are claims on one unit of the underlying, excess collateral does not add value.
+		{"Aave-V2", 1.02, 1.0, 1.001, 1.0, 0.001, false},
+		// Undercollateralized aTokens are worth less than the underlying.
+		{"Aave-V3", 0.9, 2000, 1980, 1800, 0.1, true},
+		// The collateral ratio of cTokens is their exchange rate.
+		{"Compound", 0.02, 2000, 40, 40, 0, false},
+		// Without market price, no deviation is computed.
+		{"Compound", 0.02, 2000, 0, 40, 0, false},
+	}
+	for _, table := range tables {
+		supply := dia.SynthAssetSupply{Protocol: table.protocol, ColleteralRatio: table.collateralRatio}
+		valuation, err := Valuate(supply, table.underlyingPrice, table.marketPrice, 0.02, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(valuation.FairValue-table.fairValue) > tol {
+			t.Errorf("%s: expected fair value %v, got %v", table.protocol, table.fairValue, valuation.FairValue)
+		}
+		if math.Abs(valuation.Deviation-table.deviation) > tol {
+			t.Errorf("%s: expected deviation %v, got %v", table.protocol, table.deviation, valuation.Deviation)
+		}
+		if valuation.Depeg != table.depeg {
+			t.Errorf("%s: expected depeg %v, got %v", table.protocol, table.depeg, valuation.Depeg)
+		}
+	}
+
+	if _, err := Valuate(dia.SynthAssetSupply{Protocol: "Aave-V2"}, 1, 1, 0.02, time.Now()); err != ErrNoCollateral {
+		t.Errorf("expected %v, got %v", ErrNoCollateral, err)
+	}
+}
@@ -1,6 +1,7 @@
 package nftofferscrapers
 
 import (
+	"errors"
 	"sync"
 
 	"github.com/diadata-org/diadata/pkg/dia"
@@ -10,6 +11,8 @@ import (
 
 type nothing struct{}
 
+var errShutdownRequest = errors.New("shutdown requested")
+
 type NFTOfferScraper interface {
 	// NFT bids should be streamed through dia.NFTBid channel.
 	GetOfferChannel() chan dia.NFTOffer
@@ -31,3 +34,13 @@ type OfferScraper struct {
 	datastore     *models.RelDB
 	chanOffer     chan dia.NFTOffer
 }
+
+// sendOffer sends @offer to the offer channel unless a shutdown is requested meanwhile.
+func (s *OfferScraper) sendOffer(offer dia.NFTOffer) error {
+	select {
+	case s.chanOffer <- offer:
+		return nil
+	case <-s.shutdown:
+		return errShutdownRequest
+	}
+}
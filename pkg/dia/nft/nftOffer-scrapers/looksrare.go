@@ -0,0 +1,489 @@
+package nftofferscrapers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/config/nftContracts/looksrare"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	LooksRareRefreshDelay = time.Minute * 5
+	looksRareFirstBlock   = 13885625
+	// Maximal number of blocks filtered in one request.
+	looksRareBatchSize = 2000
+	// Maximal number of orders per page of the orders endpoint.
+	looksRarePageSize = 150
+	// Identifier of the scraper's state in the scrapers table.
+	looksRareStateKey = "LooksRareOffers"
+	looksRareAPIURL   = "https://api.looksrare.org/api/v1"
+)
+
+var (
+	looksRareContract = common.HexToAddress("0x59728544B08AB483533076417FbBB2fD0B17CE3a")
+	looksRareWETH     = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	// Execution strategy of bids on any item of a collection.
+	looksRareCollectionStrategy = common.HexToAddress("0x86F909F70813CdB1Bc733f4D97Dc6b03B8e7E8F3")
+)
+
+type looksRareScraperState struct {
+	LastBlockNum uint64 `json:"last_block_num"`
+	// Start time of the newest order collected from the API.
+	LastOrderTime int64 `json:"last_order_time"`
+	// Hashes of the collected orders which started at LastOrderTime.
+	LastOrderHashes []string `json:"last_order_hashes"`
+}
+
+// looksRareOrder is a maker order as returned by the LooksRare orders endpoint.
+type looksRareOrder struct {
+	Hash              string `json:"hash"`
+	CollectionAddress string `json:"collectionAddress"`
+	TokenID           string `json:"tokenId"`
+	IsOrderAsk        bool   `json:"isOrderAsk"`
+	Signer            string `json:"signer"`
+	Strategy          string `json:"strategy"`
+	CurrencyAddress   string `json:"currencyAddress"`
+	Price             string `json:"price"`
+	Nonce             string `json:"nonce"`
+	StartTime         int64  `json:"startTime"`
+	EndTime           int64  `json:"endTime"`
+}
+
+type looksRareOrdersResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    []looksRareOrder `json:"data"`
+}
+
+// LooksRareScraper collects listings, item offers and collection offers from LooksRare.
+// Maker orders on LooksRare are signed off-chain and never show up on-chain before they are
+// filled, so they are collected from the LooksRare API. Fills and cancellations are taken from
+// the events of the exchange contract.
+type LooksRareScraper struct {
+	offerScraper OfferScraper
+	ticker       *time.Ticker
+	state        looksRareScraperState
+	metadata     *offerMetadata
+	apiKey       string
+}
+
+func NewLooksRareScraper(rdb *models.RelDB) *LooksRareScraper {
+	connection, err := ethclient.Dial(utils.Getenv("ETH_URI_REST", ""))
+	if err != nil {
+		log.Error("Error connecting Eth Client")
+	}
+
+	offerScraper := OfferScraper{
+		shutdown:      make(chan nothing),
+		shutdownDone:  make(chan nothing),
+		errorLock:     new(sync.RWMutex),
+		error:         nil,
+		ethConnection: connection,
+		datastore:     rdb,
+		chanOffer:     make(chan dia.NFTOffer),
+	}
+	s := &LooksRareScraper{
+		offerScraper: offerScraper,
+		ticker:       time.NewTicker(LooksRareRefreshDelay),
+		apiKey:       utils.Getenv("LOOKSRARE_API_KEY", ""),
+	}
+	s.metadata = newOfferMetadata(&s.offerScraper)
+
+	err = rdb.GetScraperState(context.Background(), looksRareStateKey, &s.state)
+	if err != nil || s.state.LastBlockNum == 0 {
+		log.Warn("no state for looksrare offer scraper found. Start from first block.")
+		s.state.LastBlockNum = uint64(looksRareFirstBlock)
+	}
+
+	log.Info("scraper built. Start main loop.")
+	go s.mainLoop()
+	return s
+}
+
+// mainLoop runs in a goroutine until channel s is closed.
+func (scraper *LooksRareScraper) mainLoop() {
+	err := scraper.FetchOffers()
+	if err != nil && !errors.Is(err, errShutdownRequest) {
+		log.Error("fetching offers: ", err)
+	}
+	for {
+		select {
+		case <-scraper.ticker.C:
+			err := scraper.FetchOffers()
+			if err != nil && !errors.Is(err, errShutdownRequest) {
+				log.Error("fetching offers: ", err)
+			}
+		case <-scraper.offerScraper.shutdown: // user requested shutdown
+			log.Printf("LooksRare offer scraper shutting down")
+			scraper.cleanup(nil)
+			return
+		}
+	}
+}
+
+// FetchOffers collects the orders made since the last run and then processes all fills and
+// cancellations from the last processed block up to the head of the chain.
+func (scraper *LooksRareScraper) FetchOffers() error {
+	// Orders starting in the same second as the last collected ones are fetched again,
+	// so that the ones already sent are recognized by their hashes.
+	sent := make(map[string]bool)
+	for _, hash := range scraper.state.LastOrderHashes {
+		sent[hash] = true
+	}
+	lastOrderTime, lastOrderHashes := scraper.state.LastOrderTime, scraper.state.LastOrderHashes
+	for _, isOrderAsk := range []bool{true, false} {
+		orders, err := scraper.fetchOrders(isOrderAsk, scraper.state.LastOrderTime, sent)
+		if err != nil {
+			return err
+		}
+		for _, order := range orders {
+			switch {
+			case order.StartTime > lastOrderTime:
+				lastOrderTime, lastOrderHashes = order.StartTime, []string{order.Hash}
+			case order.StartTime == lastOrderTime:
+				lastOrderHashes = append(lastOrderHashes, order.Hash)
+			}
+		}
+	}
+	scraper.state.LastOrderTime, scraper.state.LastOrderHashes = lastOrderTime, lastOrderHashes
+	err := scraper.offerScraper.datastore.SetScraperState(context.Background(), looksRareStateKey, &scraper.state)
+	if err != nil {
+		log.Error("set looksrare scraper state: ", err)
+	}
+
+	header, err := scraper.offerScraper.ethConnection.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	// It's a good practise to stay a little behind the head.
+	headBlockNumber := header.Number.Uint64() - blockDelayEthereum
+
+	for scraper.state.LastBlockNum < headBlockNumber {
+		startBlockNumber := scraper.state.LastBlockNum + 1
+		endBlockNumber := startBlockNumber + looksRareBatchSize
+		if endBlockNumber > headBlockNumber {
+			endBlockNumber = headBlockNumber
+		}
+		log.Infof("fetch looksrare events in blocks %d - %d", startBlockNumber, endBlockNumber)
+
+		err = scraper.fetchEvents(startBlockNumber, endBlockNumber)
+		if err != nil {
+			return err
+		}
+
+		scraper.state.LastBlockNum = endBlockNumber
+		err = scraper.offerScraper.datastore.SetScraperState(context.Background(), looksRareStateKey, &scraper.state)
+		if err != nil {
+			log.Error("set looksrare scraper state: ", err)
+		}
+	}
+	return nil
+}
+
+// fetchOrders sends all valid asks resp. bids which started at or after @since and whose hashes are
+// not in @sent, newest first. It returns the collected orders.
+func (scraper *LooksRareScraper) fetchOrders(isOrderAsk bool, since int64, sent map[string]bool) (collected []looksRareOrder, err error) {
+	var cursor string
+	for {
+		var orders []looksRareOrder
+		orders, err = scraper.getOrders(isOrderAsk, cursor)
+		if err != nil {
+			return
+		}
+		for _, order := range orders {
+			if order.StartTime < since {
+				return
+			}
+			if sent[order.Hash] {
+				continue
+			}
+			sent[order.Hash] = true
+			collected = append(collected, order)
+			offer, ok := scraper.makeOffer(order)
+			if !ok {
+				continue
+			}
+			if err = scraper.offerScraper.sendOffer(offer); err != nil {
+				return
+			}
+		}
+		if len(orders) < looksRarePageSize {
+			return
+		}
+		cursor = orders[len(orders)-1].Hash
+	}
+}
+
+// getOrders returns a page of valid orders sorted by start time, starting after the order with hash @cursor.
+func (scraper *LooksRareScraper) getOrders(isOrderAsk bool, cursor string) ([]looksRareOrder, error) {
+	params := url.Values{}
+	params.Set("isOrderAsk", fmt.Sprint(isOrderAsk))
+	params.Set("status[]", "VALID")
+	params.Set("sort", "NEWEST")
+	params.Set("pagination[first]", fmt.Sprint(looksRarePageSize))
+	if cursor != "" {
+		params.Set("pagination[cursor]", cursor)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, looksRareAPIURL+"/orders?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if scraper.apiKey != "" {
+		req.Header.Set("X-Looks-Api-Key", scraper.apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("looksrare orders: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response looksRareOrdersResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	if !response.Success {
+		return nil, fmt.Errorf("looksrare orders: %s", response.Message)
+	}
+	return response.Data, nil
+}
+
+// makeOffer classifies the maker @order as listing, item offer or collection offer.
+func (scraper *LooksRareScraper) makeOffer(order looksRareOrder) (offer dia.NFTOffer, ok bool) {
+	price, ok := new(big.Int).SetString(order.Price, 10)
+	if !ok {
+		log.Errorf("parse price %s of looksrare order %s", order.Price, order.Hash)
+		return
+	}
+	nonce, ok := new(big.Int).SetString(order.Nonce, 10)
+	if !ok {
+		log.Errorf("parse nonce %s of looksrare order %s", order.Nonce, order.Hash)
+		return
+	}
+	ok = false
+
+	tokenID := order.TokenID
+	switch {
+	case order.IsOrderAsk:
+		offer.AuctionType = dia.NFTOfferListing
+	case common.HexToAddress(order.Strategy) == looksRareCollectionStrategy:
+		offer.AuctionType = dia.NFTOfferCollection
+		tokenID = ""
+	default:
+		offer.AuctionType = dia.NFTOfferItem
+	}
+
+	// Asks in WETH can be filled with ETH, so they are stored in ETH as it is done for trades.
+	currency := common.HexToAddress(order.CurrencyAddress)
+	if order.IsOrderAsk && currency == looksRareWETH {
+		currency = common.HexToAddress(zeroAddressHex)
+	}
+
+	nftclass, err := scraper.metadata.getNFTClass(common.HexToAddress(order.CollectionAddress))
+	if err != nil {
+		log.Errorf("get nft class %s: %v", order.CollectionAddress, err)
+		return
+	}
+	if tokenID != "" {
+		err = scraper.metadata.ensureNFT(nftclass, tokenID)
+		if err != nil {
+			log.Errorf("set nft %s - %s: %v", order.CollectionAddress, tokenID, err)
+			return
+		}
+	}
+	curr, err := scraper.metadata.getCurrency(currency)
+	if err != nil {
+		log.Errorf("get currency %s: %v", currency.Hex(), err)
+		return
+	}
+
+	offer.NFT = dia.NFT{NFTClass: nftclass, TokenID: tokenID}
+	offer.StartValue = price
+	offer.EndValue = price
+	offer.FromAddress = common.HexToAddress(order.Signer).Hex()
+	offer.CurrencyAddress = currency.Hex()
+	offer.CurrencySymbol = curr.symbol
+	offer.CurrencyDecimals = curr.decimals
+	offer.Timestamp = time.Unix(order.StartTime, 0).UTC()
+	offer.Expiry = time.Unix(order.EndTime, 0).UTC()
+	offer.Duration = time.Duration(order.EndTime-order.StartTime) * time.Second
+	offer.Exchange = dia.LooksRare
+	offer.OrderHash = order.Hash
+	offer.OrderNonce = nonce
+	ok = true
+	return
+}
+
+// fetchEvents sends the fills and cancellations in the given block range in the order of their appearance on-chain.
+func (scraper *LooksRareScraper) fetchEvents(startBlockNumber, endBlockNumber uint64) error {
+	filterer, err := looksrare.NewContractFilterer(looksRareContract, scraper.offerScraper.ethConnection)
+	if err != nil {
+		return err
+	}
+	opts := &bind.FilterOpts{Start: startBlockNumber, End: &endBlockNumber}
+
+	var events []looksRareEvent
+
+	iterAsk, err := filterer.FilterTakerAsk(opts, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	for iterAsk.Next() {
+		ev := iterAsk.Event
+		events = append(events, looksRareEvent{
+			log: ev.Raw.BlockNumber<<32 | uint64(ev.Raw.Index),
+			offer: dia.NFTOffer{
+				FromAddress: ev.Maker.Hex(),
+				OrderHash:   common.Hash(ev.OrderHash).Hex(),
+				Filled:      true,
+				BlockNumber: ev.Raw.BlockNumber,
+				TxHash:      ev.Raw.TxHash.Hex(),
+			},
+		})
+	}
+	if err = iterAsk.Error(); err != nil {
+		return err
+	}
+
+	iterBid, err := filterer.FilterTakerBid(opts, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	for iterBid.Next() {
+		ev := iterBid.Event
+		events = append(events, looksRareEvent{
+			log: ev.Raw.BlockNumber<<32 | uint64(ev.Raw.Index),
+			offer: dia.NFTOffer{
+				FromAddress: ev.Maker.Hex(),
+				OrderHash:   common.Hash(ev.OrderHash).Hex(),
+				Filled:      true,
+				BlockNumber: ev.Raw.BlockNumber,
+				TxHash:      ev.Raw.TxHash.Hex(),
+			},
+		})
+	}
+	if err = iterBid.Error(); err != nil {
+		return err
+	}
+
+	iterCancelMultiple, err := filterer.FilterCancelMultipleOrders(opts, nil)
+	if err != nil {
+		return err
+	}
+	for iterCancelMultiple.Next() {
+		ev := iterCancelMultiple.Event
+		for _, nonce := range ev.OrderNonces {
+			events = append(events, looksRareEvent{
+				log: ev.Raw.BlockNumber<<32 | uint64(ev.Raw.Index),
+				offer: dia.NFTOffer{
+					FromAddress: ev.User.Hex(),
+					OrderNonce:  nonce,
+					Cancelled:   true,
+					BlockNumber: ev.Raw.BlockNumber,
+					TxHash:      ev.Raw.TxHash.Hex(),
+				},
+			})
+		}
+	}
+	if err = iterCancelMultiple.Error(); err != nil {
+		return err
+	}
+
+	// Cancelling all orders raises the minimal nonce of the user, which invalidates
+	// all of its orders made before.
+	iterCancelAll, err := filterer.FilterCancelAllOrders(opts, nil)
+	if err != nil {
+		return err
+	}
+	for iterCancelAll.Next() {
+		ev := iterCancelAll.Event
+		events = append(events, looksRareEvent{
+			log: ev.Raw.BlockNumber<<32 | uint64(ev.Raw.Index),
+			offer: dia.NFTOffer{
+				FromAddress: ev.User.Hex(),
+				Cancelled:   true,
+				BlockNumber: ev.Raw.BlockNumber,
+				TxHash:      ev.Raw.TxHash.Hex(),
+			},
+		})
+	}
+	if err = iterCancelAll.Error(); err != nil {
+		return err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].log < events[j].log })
+	// Block times are fetched before any event is sent, so that the block range can be retried
+	// as a whole if one of them is not available.
+	for i := range events {
+		events[i].offer.Exchange = dia.LooksRare
+		events[i].offer.BlockPosition = events[i].log & 0xffffffff
+		events[i].offer.Timestamp, err = ethhelper.GetBlockTimeEth(int64(events[i].offer.BlockNumber), scraper.offerScraper.datastore, scraper.offerScraper.ethConnection)
+		if err != nil {
+			return fmt.Errorf("get time of block %d: %w", events[i].offer.BlockNumber, err)
+		}
+	}
+	for _, e := range events {
+		if err = scraper.offerScraper.sendOffer(e.offer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// looksRareEvent is a fill or cancellation together with the position of its log on-chain.
+type looksRareEvent struct {
+	log   uint64
+	offer dia.NFTOffer
+}
+
+// GetOfferChannel returns the scrapers data channel.
+func (scraper *LooksRareScraper) GetOfferChannel() chan dia.NFTOffer {
+	return scraper.offerScraper.chanOffer
+}
+
+// closes all connected Scrapers. Must only be called from mainLoop
+func (scraper *LooksRareScraper) cleanup(err error) {
+	scraper.offerScraper.errorLock.Lock()
+	defer scraper.offerScraper.errorLock.Unlock()
+	scraper.ticker.Stop()
+	if err != nil {
+		scraper.offerScraper.error = err
+	}
+	scraper.offerScraper.closed = true
+	close(scraper.offerScraper.shutdownDone) // signal that shutdown is complete
+}
+
+// Close closes any existing API connections
+func (scraper *LooksRareScraper) Close() error {
+	if scraper.offerScraper.closed {
+		return errors.New("scraper already closed")
+	}
+	close(scraper.offerScraper.shutdown)
+	<-scraper.offerScraper.shutdownDone
+	scraper.offerScraper.errorLock.RLock()
+	defer scraper.offerScraper.errorLock.RUnlock()
+	return scraper.offerScraper.error
+}
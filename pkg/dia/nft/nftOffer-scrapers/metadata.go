@@ -0,0 +1,90 @@
+package nftofferscrapers
+
+import (
+	"github.com/diadata-org/diadata/config/nftContracts/erc20"
+	"github.com/diadata-org/diadata/config/nftContracts/erc721"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const zeroAddressHex = "0x0000000000000000000000000000000000000000"
+
+type offerCurrency struct {
+	symbol   string
+	decimals int32
+}
+
+// offerMetadata caches the metadata of currencies and collections referenced by orders
+// on order book based marketplaces.
+type offerMetadata struct {
+	offerScraper *OfferScraper
+	currencies   map[common.Address]offerCurrency
+	classes      map[common.Address]dia.NFTClass
+}
+
+func newOfferMetadata(offerScraper *OfferScraper) *offerMetadata {
+	return &offerMetadata{
+		offerScraper: offerScraper,
+		currencies: map[common.Address]offerCurrency{
+			common.HexToAddress(zeroAddressHex): {symbol: "ETH", decimals: 18},
+		},
+		classes: make(map[common.Address]dia.NFTClass),
+	}
+}
+
+// getNFTClass returns the nft class with @address and stores it in postgres if it's not there yet.
+func (m *offerMetadata) getNFTClass(address common.Address) (dia.NFTClass, error) {
+	if nftclass, ok := m.classes[address]; ok {
+		return nftclass, nil
+	}
+	nftclass, err := m.offerScraper.datastore.GetNFTClass(address.Hex(), dia.ETHEREUM)
+	if err != nil {
+		nftclass = dia.NFTClass{
+			Address:      address.Hex(),
+			Blockchain:   dia.ETHEREUM,
+			ContractType: "ERC721",
+		}
+		caller, err := erc721.NewERC721MetadataCaller(address, m.offerScraper.ethConnection)
+		if err == nil {
+			nftclass.Name, _ = caller.Name(&bind.CallOpts{})
+			nftclass.Symbol, _ = caller.Symbol(&bind.CallOpts{})
+		}
+		err = m.offerScraper.datastore.SetNFTClass(nftclass)
+		if err != nil {
+			return dia.NFTClass{}, err
+		}
+	}
+	m.classes[address] = nftclass
+	return nftclass, nil
+}
+
+// ensureNFT stores the nft with @tokenID in postgres if it's not there yet.
+func (m *offerMetadata) ensureNFT(nftclass dia.NFTClass, tokenID string) error {
+	_, err := m.offerScraper.datastore.GetNFTID(nftclass.Address, nftclass.Blockchain, tokenID)
+	if err == nil {
+		return nil
+	}
+	return m.offerScraper.datastore.SetNFT(dia.NFT{NFTClass: nftclass, TokenID: tokenID})
+}
+
+func (m *offerMetadata) getCurrency(address common.Address) (offerCurrency, error) {
+	if curr, ok := m.currencies[address]; ok {
+		return curr, nil
+	}
+	caller, err := erc20.NewERC20MetadataCaller(address, m.offerScraper.ethConnection)
+	if err != nil {
+		return offerCurrency{}, err
+	}
+	symbol, err := caller.Symbol(&bind.CallOpts{})
+	if err != nil {
+		return offerCurrency{}, err
+	}
+	decimals, err := caller.Decimals(&bind.CallOpts{})
+	if err != nil {
+		return offerCurrency{}, err
+	}
+	curr := offerCurrency{symbol: symbol, decimals: int32(decimals)}
+	m.currencies[address] = curr
+	return curr, nil
+}
@@ -0,0 +1,443 @@
+package nftofferscrapers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/config/nftContracts/openseaseaport"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	SeaportRefreshDelay = time.Minute * 5
+	seaportFirstBlock   = 14946474
+	// Maximal number of blocks filtered in one request.
+	seaportBatchSize = 2000
+	// Identifier of the scraper's state in the scrapers table.
+	seaportStateKey = "SeaportOffers"
+)
+
+// Seaport item types as defined in ConsiderationEnums.sol.
+const (
+	seaportItemNative uint8 = iota
+	seaportItemERC20
+	seaportItemERC721
+	seaportItemERC1155
+	seaportItemERC721WithCriteria
+	seaportItemERC1155WithCriteria
+)
+
+var (
+	seaportABI abi.ABI
+)
+
+func init() {
+	var err error
+	seaportABI, err = abi.JSON(strings.NewReader(openseaseaport.OpenseaseaportABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+type seaportScraperState struct {
+	LastBlockNum uint64 `json:"last_block_num"`
+}
+
+// SeaportScraper collects listings, item offers and collection offers from orders which are
+// validated on-chain on the Seaport contract. Cancellations, fills and counter increments are
+// forwarded as offers with the Cancelled resp. Filled flag set.
+// Orders which are only signed off-chain never show up on-chain before they are filled and
+// are hence not covered.
+type SeaportScraper struct {
+	offerScraper    OfferScraper
+	contractAddress common.Address
+	ticker          *time.Ticker
+	state           seaportScraperState
+	metadata        *offerMetadata
+}
+
+func NewSeaportScraper(rdb *models.RelDB) *SeaportScraper {
+	connection, err := ethclient.Dial(utils.Getenv("ETH_URI_REST", ""))
+	if err != nil {
+		log.Error("Error connecting Eth Client")
+	}
+
+	offerScraper := OfferScraper{
+		shutdown:      make(chan nothing),
+		shutdownDone:  make(chan nothing),
+		errorLock:     new(sync.RWMutex),
+		error:         nil,
+		ethConnection: connection,
+		datastore:     rdb,
+		chanOffer:     make(chan dia.NFTOffer),
+	}
+	s := &SeaportScraper{
+		contractAddress: common.HexToAddress("0x00000000006c3852cbEf3e08E8dF289169EdE581"),
+		offerScraper:    offerScraper,
+		ticker:          time.NewTicker(SeaportRefreshDelay),
+	}
+	s.metadata = newOfferMetadata(&s.offerScraper)
+
+	err = rdb.GetScraperState(context.Background(), seaportStateKey, &s.state)
+	if err != nil || s.state.LastBlockNum == 0 {
+		log.Warn("no state for seaport offer scraper found. Start from first block.")
+		s.state.LastBlockNum = uint64(seaportFirstBlock)
+	}
+
+	log.Info("scraper built. Start main loop.")
+	go s.mainLoop()
+	return s
+}
+
+// mainLoop runs in a goroutine until channel s is closed.
+func (scraper *SeaportScraper) mainLoop() {
+	err := scraper.FetchOffers()
+	if err != nil && !errors.Is(err, errShutdownRequest) {
+		log.Error("fetching offers: ", err)
+	}
+	for {
+		select {
+		case <-scraper.ticker.C:
+			err := scraper.FetchOffers()
+			if err != nil && !errors.Is(err, errShutdownRequest) {
+				log.Error("fetching offers: ", err)
+			}
+		case <-scraper.offerScraper.shutdown: // user requested shutdown
+			log.Printf("Seaport offer scraper shutting down")
+			scraper.cleanup(nil)
+			return
+		}
+	}
+}
+
+// FetchOffers processes all Seaport events from the last processed block up to the head of the chain.
+func (scraper *SeaportScraper) FetchOffers() error {
+	header, err := scraper.offerScraper.ethConnection.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	// It's a good practise to stay a little behind the head.
+	headBlockNumber := header.Number.Uint64() - blockDelayEthereum
+
+	for scraper.state.LastBlockNum < headBlockNumber {
+		startBlockNumber := scraper.state.LastBlockNum + 1
+		endBlockNumber := startBlockNumber + seaportBatchSize
+		if endBlockNumber > headBlockNumber {
+			endBlockNumber = headBlockNumber
+		}
+		log.Infof("fetch seaport events in blocks %d - %d", startBlockNumber, endBlockNumber)
+
+		err = scraper.fetchEvents(startBlockNumber, endBlockNumber)
+		if err != nil {
+			return err
+		}
+
+		scraper.state.LastBlockNum = endBlockNumber
+		err = scraper.offerScraper.datastore.SetScraperState(context.Background(), seaportStateKey, &scraper.state)
+		if err != nil {
+			log.Error("set seaport scraper state: ", err)
+		}
+	}
+	return nil
+}
+
+// fetchEvents sends the offers derived from all relevant events in the given block range.
+// Events are processed in the order of their appearance on-chain, so that a fill or
+// cancellation never precedes the validation of the same order.
+func (scraper *SeaportScraper) fetchEvents(startBlockNumber, endBlockNumber uint64) error {
+	filterer, err := openseaseaport.NewOpenseaseaportFilterer(scraper.contractAddress, scraper.offerScraper.ethConnection)
+	if err != nil {
+		return err
+	}
+	opts := &bind.FilterOpts{Start: startBlockNumber, End: &endBlockNumber}
+
+	var offers []seaportEvent
+
+	iterValidated, err := filterer.FilterOrderValidated(opts, nil, nil)
+	if err != nil {
+		return err
+	}
+	validated := make(map[common.Hash][]*openseaseaport.OpenseaseaportOrderValidated)
+	for iterValidated.Next() {
+		ev := iterValidated.Event
+		validated[ev.Raw.TxHash] = append(validated[ev.Raw.TxHash], ev)
+	}
+	if err = iterValidated.Error(); err != nil {
+		return err
+	}
+	for txHash, events := range validated {
+		validatedOffers, err := scraper.parseValidatedOrders(txHash, events)
+		if err != nil {
+			log.Errorf("parse validated orders in tx %s: %v", txHash.Hex(), err)
+			continue
+		}
+		offers = append(offers, validatedOffers...)
+	}
+
+	iterCancelled, err := filterer.FilterOrderCancelled(opts, nil, nil)
+	if err != nil {
+		return err
+	}
+	for iterCancelled.Next() {
+		ev := iterCancelled.Event
+		offers = append(offers, seaportEvent{
+			log: ev.Raw.BlockNumber<<32 | uint64(ev.Raw.Index),
+			offer: dia.NFTOffer{
+				FromAddress: ev.Offerer.Hex(),
+				OrderHash:   common.Hash(ev.OrderHash).Hex(),
+				Cancelled:   true,
+				BlockNumber: ev.Raw.BlockNumber,
+				TxHash:      ev.Raw.TxHash.Hex(),
+			},
+		})
+	}
+	if err = iterCancelled.Error(); err != nil {
+		return err
+	}
+
+	iterFulfilled, err := filterer.FilterOrderFulfilled(opts, nil, nil)
+	if err != nil {
+		return err
+	}
+	for iterFulfilled.Next() {
+		ev := iterFulfilled.Event
+		offers = append(offers, seaportEvent{
+			log: ev.Raw.BlockNumber<<32 | uint64(ev.Raw.Index),
+			offer: dia.NFTOffer{
+				FromAddress: ev.Offerer.Hex(),
+				OrderHash:   common.Hash(ev.OrderHash).Hex(),
+				Filled:      true,
+				BlockNumber: ev.Raw.BlockNumber,
+				TxHash:      ev.Raw.TxHash.Hex(),
+			},
+		})
+	}
+	if err = iterFulfilled.Error(); err != nil {
+		return err
+	}
+
+	iterCounter, err := filterer.FilterCounterIncremented(opts, nil)
+	if err != nil {
+		return err
+	}
+	for iterCounter.Next() {
+		ev := iterCounter.Event
+		offers = append(offers, seaportEvent{
+			log: ev.Raw.BlockNumber<<32 | uint64(ev.Raw.Index),
+			offer: dia.NFTOffer{
+				FromAddress: ev.Offerer.Hex(),
+				Cancelled:   true,
+				BlockNumber: ev.Raw.BlockNumber,
+				TxHash:      ev.Raw.TxHash.Hex(),
+			},
+		})
+	}
+	if err = iterCounter.Error(); err != nil {
+		return err
+	}
+
+	sort.Slice(offers, func(i, j int) bool { return offers[i].log < offers[j].log })
+	for _, e := range offers {
+		offer := e.offer
+		offer.Exchange = dia.OpenseaSeaport
+		offer.BlockPosition = e.log & 0xffffffff
+		if offer.Timestamp.IsZero() {
+			offer.Timestamp, err = ethhelper.GetBlockTimeEth(int64(offer.BlockNumber), scraper.offerScraper.datastore, scraper.offerScraper.ethConnection)
+			if err != nil {
+				log.Errorf("getting block time: %+v", err)
+			}
+		}
+		if err = scraper.offerScraper.sendOffer(offer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seaportEvent is an offer together with the position of its log on-chain.
+type seaportEvent struct {
+	log   uint64
+	offer dia.NFTOffer
+}
+
+// parseValidatedOrders decodes the orders passed to validate() in the transaction @txHash and
+// matches them to the OrderValidated @events emitted by it.
+// Orders validated through other entry points, such as by contracts, are skipped.
+func (scraper *SeaportScraper) parseValidatedOrders(txHash common.Hash, events []*openseaseaport.OpenseaseaportOrderValidated) (offers []seaportEvent, err error) {
+	tx, _, err := scraper.offerScraper.ethConnection.TransactionByHash(context.Background(), txHash)
+	if err != nil {
+		return
+	}
+	method, ok := seaportABI.Methods["validate"]
+	if !ok || len(tx.Data()) < 4 || !bytes.Equal(tx.Data()[:4], method.ID) {
+		log.Warnf("tx %s does not call validate. skip.", txHash.Hex())
+		return
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil || len(args) != 1 {
+		return
+	}
+	ordersPtr, ok := abi.ConvertType(args[0], new([]openseaseaport.Order)).(*[]openseaseaport.Order)
+	if !ok {
+		err = errors.New("cannot convert orders")
+		return
+	}
+	orders := *ordersPtr
+
+	// Orders which were already validated do not emit an event, so events are matched
+	// to the orders in sequence by offerer and zone.
+	i := 0
+	for _, ev := range events {
+		for i < len(orders) && (orders[i].Parameters.Offerer != ev.Offerer || orders[i].Parameters.Zone != ev.Zone) {
+			i++
+		}
+		if i == len(orders) {
+			break
+		}
+		offer, ok := scraper.makeOffer(orders[i].Parameters)
+		i++
+		if !ok {
+			continue
+		}
+		offer.OrderHash = common.Hash(ev.OrderHash).Hex()
+		offer.BlockNumber = ev.Raw.BlockNumber
+		offer.TxHash = ev.Raw.TxHash.Hex()
+		offers = append(offers, seaportEvent{log: ev.Raw.BlockNumber<<32 | uint64(ev.Raw.Index), offer: offer})
+	}
+	return
+}
+
+// makeOffer classifies the order given by @params as listing, item offer or collection offer.
+// Only orders paid in a single currency are supported.
+func (scraper *SeaportScraper) makeOffer(params openseaseaport.OrderParameters) (offer dia.NFTOffer, ok bool) {
+	var (
+		nftToken   common.Address
+		tokenID    string
+		currency   *common.Address
+		startValue = big.NewInt(0)
+		endValue   = big.NewInt(0)
+	)
+	addPayment := func(itemType uint8, token common.Address, startAmount, endAmount *big.Int) bool {
+		if itemType != seaportItemNative && itemType != seaportItemERC20 {
+			return false
+		}
+		if currency != nil && *currency != token {
+			return false
+		}
+		currency = &token
+		startValue.Add(startValue, startAmount)
+		endValue.Add(endValue, endAmount)
+		return true
+	}
+
+	switch {
+	case len(params.Offer) == 1 && isSeaportNFTItem(params.Offer[0].ItemType):
+		// The offerer sells a specific nft for the sum of the consideration.
+		offer.AuctionType = dia.NFTOfferListing
+		nftToken = params.Offer[0].Token
+		tokenID = params.Offer[0].IdentifierOrCriteria.String()
+		for _, item := range params.Consideration {
+			if !addPayment(item.ItemType, item.Token, item.StartAmount, item.EndAmount) {
+				return
+			}
+		}
+	case len(params.Offer) == 1 && params.Offer[0].ItemType == seaportItemERC20 && len(params.Consideration) > 0:
+		// The offerer bids an amount of an ERC20 token on the first consideration item.
+		// Further consideration items are fees which are paid from the offered amount.
+		item := params.Consideration[0]
+		switch {
+		case isSeaportNFTItem(item.ItemType):
+			offer.AuctionType = dia.NFTOfferItem
+			tokenID = item.IdentifierOrCriteria.String()
+		case (item.ItemType == seaportItemERC721WithCriteria || item.ItemType == seaportItemERC1155WithCriteria) && item.IdentifierOrCriteria.Sign() == 0:
+			offer.AuctionType = dia.NFTOfferCollection
+		default:
+			return
+		}
+		nftToken = item.Token
+		addPayment(params.Offer[0].ItemType, params.Offer[0].Token, params.Offer[0].StartAmount, params.Offer[0].EndAmount)
+	default:
+		return
+	}
+	if currency == nil {
+		return
+	}
+
+	nftclass, err := scraper.metadata.getNFTClass(nftToken)
+	if err != nil {
+		log.Errorf("get nft class %s: %v", nftToken.Hex(), err)
+		return
+	}
+	if tokenID != "" {
+		err = scraper.metadata.ensureNFT(nftclass, tokenID)
+		if err != nil {
+			log.Errorf("set nft %s - %s: %v", nftToken.Hex(), tokenID, err)
+			return
+		}
+	}
+	curr, err := scraper.metadata.getCurrency(*currency)
+	if err != nil {
+		log.Errorf("get currency %s: %v", currency.Hex(), err)
+		return
+	}
+
+	offer.NFT = dia.NFT{NFTClass: nftclass, TokenID: tokenID}
+	offer.StartValue = startValue
+	offer.EndValue = endValue
+	offer.FromAddress = params.Offerer.Hex()
+	offer.CurrencyAddress = currency.Hex()
+	offer.CurrencySymbol = curr.symbol
+	offer.CurrencyDecimals = curr.decimals
+	if params.EndTime.IsInt64() {
+		offer.Expiry = time.Unix(params.EndTime.Int64(), 0).UTC()
+		offer.Duration = time.Duration(params.EndTime.Int64()-params.StartTime.Int64()) * time.Second
+	}
+	ok = true
+	return
+}
+
+func isSeaportNFTItem(itemType uint8) bool {
+	return itemType == seaportItemERC721 || itemType == seaportItemERC1155
+}
+
+// GetOfferChannel returns the scrapers data channel.
+func (scraper *SeaportScraper) GetOfferChannel() chan dia.NFTOffer {
+	return scraper.offerScraper.chanOffer
+}
+
+// closes all connected Scrapers. Must only be called from mainLoop
+func (scraper *SeaportScraper) cleanup(err error) {
+	scraper.offerScraper.errorLock.Lock()
+	defer scraper.offerScraper.errorLock.Unlock()
+	scraper.ticker.Stop()
+	if err != nil {
+		scraper.offerScraper.error = err
+	}
+	scraper.offerScraper.closed = true
+	close(scraper.offerScraper.shutdownDone) // signal that shutdown is complete
+}
+
+// Close closes any existing API connections
+func (scraper *SeaportScraper) Close() error {
+	if scraper.offerScraper.closed {
+		return errors.New("scraper already closed")
+	}
+	close(scraper.offerScraper.shutdown)
+	<-scraper.offerScraper.shutdownDone
+	scraper.offerScraper.errorLock.RLock()
+	defer scraper.offerScraper.errorLock.RUnlock()
+	return scraper.offerScraper.error
+}
@@ -0,0 +1,1003 @@
+package nfttradescrapers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/config/nftContracts/erc20"
+	"github.com/diadata-org/diadata/config/nftContracts/erc721"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+	"github.com/vincent-petithory/dataurl"
+)
+
+const (
+	// we assume all of the NFTs traded on the marketplaces are ERC721(1155 is an extension of it)
+	marketplaceNFTContractType = "ERC721"
+)
+
+// MarketplaceScraperConfig is the configuration of an on-chain marketplace scraper.
+// It is stored in the scrapers table and reloaded before every batch.
+type MarketplaceScraperConfig struct {
+	// marketplace's exchange contract address on connected blockchain network
+	ContractAddr string `json:"contract_addr"`
+
+	// indicates the batch size during read the filtered events
+	BatchSize int `json:"batch_size"`
+
+	// wait for a while between batch retrieval of filtered events
+	WaitPeriod time.Duration `json:"wait_per_batch"`
+
+	// it enables read contract data from the event's block
+	// height instead of the last state
+	FollowDist int `json:"following_distance_blocks"`
+
+	// if set it will read erc721 attributes at the currently
+	// processing block
+	UseArchiveNode bool `json:"use_archive_node_fetaures"`
+
+	// indicates the number of retries to scrape the target
+	// in case of an unexpected error
+	MaxRetry int `json:"max_retry"`
+
+	// if true the scraper will skip the currently scraping
+	// transaction when retries exceed the value MaxRetry,
+	// otherwise it is retried until it is processed
+	SkipOnErr bool `json:"skip_on_error"`
+
+	// it limits read bytes for NFT's metadata from external url
+	MaxMetadataSize int `json:"max_metadata_size"`
+
+	// it limits duration of read for NFT's metadata from external url
+	MetadataTimeout time.Duration `json:"metadata_timeout"`
+}
+
+// MarketplaceScraperState is the checkpoint of an on-chain marketplace scraper.
+type MarketplaceScraperState struct {
+	// last block number has been processed
+	LastBlockNum uint64 `json:"last_block_num"`
+
+	// last transaction index in the block(curr) has been processed
+	LastTxIndex uint `json:"last_tx_index"`
+
+	// holds the latest error message that occurred while scraping
+	LastErr string `json:"last_error"`
+
+	// indicates the number of consecutive error, reset on any successful operation
+	ErrCounter int `json:"count_of_error"`
+}
+
+// MarketplaceFill is a single NFT sale decoded from a marketplace's fill event.
+type MarketplaceFill struct {
+	// transferred token including its metadata
+	Transfer *erc721Transfer
+
+	// seller and buyer of the token
+	From common.Address
+	To   common.Address
+
+	// price in the smallest unit of the currency
+	Price *big.Int
+
+	// zero address is used for the native coin of the chain
+	CurrencyAddr     common.Address
+	CurrencySymbol   string
+	CurrencyDecimals int
+}
+
+// MarketplaceDecodeFunc extracts the sales contained in a filtered transaction.
+// Returning no fills and no error marks the transaction as skipped.
+type MarketplaceDecodeFunc func(ctx context.Context, s *MarketplaceScraper, tx *utils.EthFilteredTx) ([]*MarketplaceFill, error)
+
+// MarketplaceSpec describes an on-chain NFT marketplace. Everything else, i.e. filtering,
+// checkpointing, NFT bookkeeping and price conversion, is provided by MarketplaceScraper.
+type MarketplaceSpec struct {
+	// identifier of the scraper in conf and state fields in postgres
+	StateKey string
+
+	// human readable name used in logs
+	Name string
+
+	// blockchain the marketplace contract is deployed on
+	Blockchain string
+
+	// marketplace contract ABI and the names of its fill events
+	ABI        abi.ABI
+	FillEvents []string
+
+	// strict mode only accepts erc721 transfer logs with indexed token id
+	// which avoids mistaking erc20 transfers for erc721 transfers
+	StrictTransfers bool
+
+	// wait before the first batch is fetched
+	StartupDelay time.Duration
+
+	// values stored on the first run
+	DefaultConf  MarketplaceScraperConfig
+	DefaultState MarketplaceScraperState
+
+	Decode MarketplaceDecodeFunc
+}
+
+// MarketplaceScraper scrapes trades from an on-chain NFT marketplace described by a MarketplaceSpec.
+type MarketplaceScraper struct {
+	tradeScraper TradeScraper
+
+	mu         sync.Mutex
+	spec       MarketplaceSpec
+	conf       *MarketplaceScraperConfig
+	state      *MarketplaceScraperState
+	exchange   dia.NFTExchange
+	assetCache map[string]dia.Asset
+
+	// source of the usd prices of the payment currencies
+	quotations quotationSource
+}
+
+// quotationSource is the part of the datastore used to convert sale prices into usd.
+type quotationSource interface {
+	GetAssetQuotation(asset dia.Asset, timestamp time.Time) (*models.AssetQuotation, error)
+}
+
+type erc20Transfer struct {
+	TokenAddr   common.Address
+	From        common.Address
+	To          common.Address
+	Amount      *big.Int
+	TokenSymbol *string
+	Decimals    int
+}
+
+type erc721Transfer struct {
+	NFTAddress  common.Address
+	Name        *string
+	Symbol      *string
+	TotalSupply *big.Int
+	From        common.Address
+	To          common.Address
+	TokenID     *big.Int
+	TokenURI    *string
+	TokenAttrs  map[string]interface{}
+}
+
+var (
+	errMarketplaceShutdownRequest = errors.New("shutdown requested")
+
+	erc20ABI  abi.ABI
+	erc721ABI abi.ABI
+)
+
+func init() {
+	var err error
+
+	erc20ABI, err = abi.JSON(strings.NewReader(erc20.ERC20ABI))
+	if err != nil {
+		panic(err)
+	}
+
+	erc721ABI, err = abi.JSON(strings.NewReader(erc721.ERC721ABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// marketplaceStartBlock returns the block set in LAST_BLOCK_NUM, or @defBlockNum if it is not set.
+// It is used as starting point if the scraper state is not set yet.
+func marketplaceStartBlock(defBlockNum uint64) uint64 {
+	initBlockNumString := utils.Getenv("LAST_BLOCK_NUM", "")
+	if initBlockNumString == "" {
+		return defBlockNum
+	}
+	initBlockNum, err := strconv.ParseUint(initBlockNumString, 10, 64)
+	if err != nil {
+		log.Error("parse LAST_BLOCK_NUM: ", err)
+		return defBlockNum
+	}
+	return initBlockNum
+}
+
+// NewMarketplaceScraper returns a scraper for the marketplace given by @spec and starts its main loop.
+func NewMarketplaceScraper(rdb *models.RelDB, exchange dia.NFTExchange, spec MarketplaceSpec) *MarketplaceScraper {
+	ctx := context.Background()
+
+	restURI := "ETH_URI_REST"
+	if spec.Blockchain != dia.ETHEREUM {
+		restURI = strings.ToUpper(spec.Blockchain) + "_URI_REST"
+	}
+	eth, err := ethclient.Dial(utils.Getenv(restURI, ""))
+	if err != nil {
+		log.Error("Error connecting Eth Client")
+	}
+
+	datastore, err := models.NewDataStore()
+	if err != nil {
+		log.Errorf("%s scraper could not connect to the quotation datastore: %s", spec.Name, err.Error())
+		return nil
+	}
+
+	defConf := spec.DefaultConf   // copy
+	defState := spec.DefaultState // copy
+
+	s := &MarketplaceScraper{
+		spec:       spec,
+		conf:       &defConf,
+		state:      &defState,
+		exchange:   exchange,
+		assetCache: make(map[string]dia.Asset),
+		quotations: datastore,
+		tradeScraper: TradeScraper{
+			shutdown:      make(chan nothing),
+			shutdownDone:  make(chan nothing),
+			datastore:     rdb,
+			chanTrade:     make(chan dia.NFTTrade),
+			source:        exchange.Name,
+			ethConnection: eth,
+		},
+	}
+
+	if err := s.initScraper(ctx); err != nil {
+		log.Errorf("%s scraper could not be initialized: %s", spec.Name, err.Error())
+		return nil
+	}
+
+	log.Infof("scraper %s starts at block: %v", spec.StateKey, s.state.LastBlockNum)
+	time.Sleep(spec.StartupDelay)
+	go s.mainLoop()
+
+	return s
+}
+
+// init scraper
+// if there are no values stored previously, use defaults and store them
+func (s *MarketplaceScraper) initScraper(ctx context.Context) error {
+	if err := s.loadConfig(ctx); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Errorf("unable to read scraper config from rdb: %s", err.Error())
+			return err
+		}
+
+		// use & store defaults if there is no record in the scraper table
+
+		defConf := s.spec.DefaultConf // copy
+		s.conf = &defConf
+		if err := s.tradeScraper.datastore.SetScraperConfig(ctx, s.spec.StateKey, s.conf); err != nil {
+			log.Errorf("unable to store scraper config on rdb: %s", err.Error())
+			return err
+		}
+
+		defState := s.spec.DefaultState // copy
+		s.state = &defState
+		if err := s.tradeScraper.datastore.SetScraperState(ctx, s.spec.StateKey, s.state); err != nil {
+			log.Errorf("unable to store scraper state on rdb: %s", err.Error())
+			return err
+		}
+
+		return nil
+	}
+
+	return s.loadState(ctx)
+}
+
+func (s *MarketplaceScraper) loadConfig(ctx context.Context) error {
+	return s.tradeScraper.datastore.GetScraperConfig(ctx, s.spec.StateKey, s.conf)
+}
+
+func (s *MarketplaceScraper) loadState(ctx context.Context) error {
+	return s.tradeScraper.datastore.GetScraperState(ctx, s.spec.StateKey, s.state)
+}
+
+func (s *MarketplaceScraper) storeState(ctx context.Context) error {
+	return s.tradeScraper.datastore.SetScraperState(ctx, s.spec.StateKey, s.state)
+}
+
+func (s *MarketplaceScraper) mainLoop() {
+	defer func() {
+		s.tradeScraper.closed = true
+
+		close(s.tradeScraper.chanTrade)
+		close(s.tradeScraper.shutdownDone)
+	}()
+
+	log.Infof("%s scraper has been started (batch: %d, period: %s)", s.spec.Name, s.conf.BatchSize, s.conf.WaitPeriod.String())
+
+	for stop := false; !stop; {
+		if err := s.FetchTrades(); err != nil {
+			if errors.Is(err, errMarketplaceShutdownRequest) {
+				stop = true
+				continue
+			}
+		}
+
+		log.Debugf("wait for %s", s.conf.WaitPeriod)
+
+		select {
+		case <-time.After(s.conf.WaitPeriod):
+		case <-s.tradeScraper.shutdown:
+			stop = true
+		}
+	}
+}
+
+// retryTx returns true if a transaction which failed ErrCounter times is to be retried. Without
+// SkipOnErr, it is retried until it is processed.
+func (s *MarketplaceScraper) retryTx() bool {
+	return s.state.ErrCounter <= s.conf.MaxRetry || !s.conf.SkipOnErr
+}
+
+// FetchTrades searches for trades on-chain by the next block range
+func (s *MarketplaceScraper) FetchTrades() error {
+	var err error
+
+	// TODO: make FetchTrades context-aware
+	ctx := context.Background()
+
+	// it must be run once at a time
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// read config
+	if err = s.loadConfig(ctx); err != nil {
+		log.Warnf("unable to load scraper config: %s", err.Error())
+		return err
+	}
+
+	// read state
+	if err = s.loadState(ctx); err != nil {
+		log.Warnf("unable to load scraper state: %s", err.Error())
+		return err
+	}
+
+	log.Infof("fetching %s trade transactions from block %d(+%d)", s.spec.Name, s.state.LastBlockNum, s.conf.BatchSize)
+
+	events := make([]common.Hash, 0, len(s.spec.FillEvents))
+	for _, name := range s.spec.FillEvents {
+		events = append(events, s.spec.ABI.Events[name].ID)
+	}
+
+	// fetch trade transactions
+	res, err := utils.EthFilterTXs(ctx, s.tradeScraper.ethConnection, utils.EthTxFilterCriteria{
+		StartBlockNum:      s.state.LastBlockNum,
+		StartTxIndex:       s.state.LastTxIndex,
+		LimitBlocks:        s.conf.BatchSize,
+		BehindHighestBlock: s.conf.FollowDist,
+		EvAddrs:            []common.Address{common.HexToAddress(s.conf.ContractAddr)},
+		Events:             events,
+	})
+
+	if err != nil {
+		log.Warnf("unable to filter %s trades: %s", s.spec.Name, err.Error())
+		return err
+	}
+
+	log.Infof("found %d trade(logs: %d) transactions in %d blocks(from %d [tx index offset: %d] to %d, sync: %t[stay behind: -%d]), exploring details...", res.NumTXs, res.NumLogs, res.NumBlocks, s.state.LastBlockNum, s.state.LastTxIndex, res.LastBlockNum, res.Synced, s.conf.FollowDist)
+
+	numTrades := 0
+
+	// process trade transactions
+	for _, tx := range res.TXs {
+		s.state.LastBlockNum = tx.BlockNum
+		s.state.LastTxIndex = tx.TXIndex
+		s.state.LastErr = ""
+		log.Info("current state.ErrCounter: ", s.state.ErrCounter)
+
+		skipped, err := s.processTx(ctx, tx)
+		if err != nil {
+			if errors.Is(err, errMarketplaceShutdownRequest) {
+				return err
+			}
+
+			s.state.ErrCounter++
+
+			if s.retryTx() {
+				s.state.LastErr = fmt.Sprintf("unable to process trade transaction(%s): %s", tx.TXHash.Hex(), err.Error())
+				log.Error(s.state.LastErr)
+				// store state
+				if err := s.storeState(ctx); err != nil {
+					log.Warnf("unable to store scraper state: %s", err.Error())
+					return err
+				}
+				return err
+			}
+
+			log.Warnf("SKIPPING PERMANENTLY! block: %d, tx index: %d - error: %s", s.state.LastBlockNum, s.state.LastTxIndex, err.Error())
+		}
+
+		if !skipped {
+			numTrades++
+		}
+
+		// reset consecutive error counter
+		s.state.ErrCounter = 0
+
+		// move next
+		s.state.LastTxIndex = tx.TXIndex + 1
+
+		// store state
+		if err := s.storeState(ctx); err != nil {
+			log.Warnf("unable to store scraper state: %s", err.Error())
+			return err
+		}
+	}
+
+	s.state.LastBlockNum = res.LastBlockNum + 1
+	s.state.LastTxIndex = 0
+
+	if err := s.storeState(ctx); err != nil {
+		log.Warnf("unable to store scraper state: %s", err.Error())
+		return err
+	}
+
+	log.Infof("processed %d trades", numTrades)
+
+	return nil
+}
+
+func (s *MarketplaceScraper) processTx(ctx context.Context, tx *utils.EthFilteredTx) (bool, error) {
+	log.Tracef("process tx -> block: %d, tx index: %d, tx hash: %s", tx.BlockNum, tx.TXIndex, tx.TXHash.Hex())
+
+	fills, err := s.spec.Decode(ctx, s, tx)
+	if err != nil {
+		return false, err
+	}
+
+	// skip if the marketplace decoder found no sale
+	if len(fills) == 0 {
+		return true, nil
+	}
+
+	timestamp, err := s.blockTime(tx.BlockNum)
+	if err != nil {
+		log.Errorf("getting block time: %+v", err)
+		return false, err
+	}
+
+	for _, fill := range fills {
+		normPrice := decimal.NewFromBigInt(fill.Price, 0).Div(decimal.NewFromInt(10).Pow(decimal.NewFromInt(int64(fill.CurrencyDecimals))))
+
+		usdPrice, err := s.calcUSDPrice(timestamp, fill.CurrencyAddr, fill.CurrencySymbol, normPrice)
+		if err != nil {
+			log.Errorf("unable to calculate usd price of the event(block: %d, log: %d, tx: %s): %s", tx.BlockNum, tx.TXIndex, tx.TXHash.Hex(), err.Error())
+			return false, err
+		}
+
+		if err := s.notifyTrade(tx, timestamp, fill, usdPrice); err != nil {
+			if !errors.Is(err, errMarketplaceShutdownRequest) {
+				log.Warnf("event(block: %d, tx index: %d, tx: %s) couldn't processed: %s", tx.BlockNum, tx.TXIndex, tx.TXHash.Hex(), err.Error())
+			}
+
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+func (s *MarketplaceScraper) notifyTrade(tx *utils.EthFilteredTx, timestamp time.Time, fill *MarketplaceFill, usdPrice float64) error {
+	nftClass, err := s.createOrReadNFTClass(fill.Transfer)
+	if err != nil {
+		return err
+	}
+
+	nft, err := s.createOrReadNFT(nftClass, fill.Transfer)
+	if err != nil {
+		return err
+	}
+
+	trade := dia.NFTTrade{
+		NFT:         *nft,
+		Price:       fill.Price,
+		PriceUSD:    usdPrice,
+		FromAddress: fill.From.Hex(),
+		ToAddress:   fill.To.Hex(),
+		BlockNumber: tx.BlockNum,
+		Timestamp:   timestamp,
+		TxHash:      tx.TXHash.Hex(),
+		Exchange:    s.exchange.Name,
+	}
+
+	if asset, ok := s.assetCache[s.spec.Blockchain+"-"+fill.CurrencyAddr.Hex()]; ok {
+		trade.Currency = asset
+	} else {
+		currency, err := s.tradeScraper.datastore.GetAsset(fill.CurrencyAddr.Hex(), s.spec.Blockchain)
+		if err != nil {
+			log.Errorf("cannot fetch asset %s -- %s", s.spec.Blockchain, fill.CurrencyAddr.Hex())
+		}
+		trade.Currency = currency
+		s.assetCache[s.spec.Blockchain+"-"+fill.CurrencyAddr.Hex()] = currency
+	}
+
+	log.Info("found trade: ", trade)
+
+	// handle close request if the chanTrade not consumed immediately
+	select {
+	case s.tradeScraper.chanTrade <- trade:
+	case <-s.tradeScraper.shutdown:
+		return errMarketplaceShutdownRequest
+	}
+
+	return nil
+}
+
+// blockTime returns the time of block @blockNum. Ethereum block times are cached in postgres.
+func (s *MarketplaceScraper) blockTime(blockNum uint64) (time.Time, error) {
+	if s.spec.Blockchain == dia.ETHEREUM {
+		return ethhelper.GetBlockTimeEth(int64(blockNum), s.tradeScraper.datastore, s.tradeScraper.ethConnection)
+	}
+
+	block, err := s.tradeScraper.ethConnection.BlockByNumber(context.Background(), new(big.Int).SetUint64(blockNum))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(block.Time()), 0), nil
+}
+
+func (s *MarketplaceScraper) createOrReadNFTClass(transfer *erc721Transfer) (*dia.NFTClass, error) {
+	nftClass, err := s.tradeScraper.datastore.GetNFTClass(transfer.NFTAddress.Hex(), s.spec.Blockchain)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Warnf("unable to read nftclass from reldb: %s", err.Error())
+			return nil, err
+		}
+
+		nftClass = dia.NFTClass{
+			Address:      transfer.NFTAddress.Hex(),
+			Blockchain:   s.spec.Blockchain,
+			ContractType: marketplaceNFTContractType,
+		}
+
+		if transfer.Name != nil {
+			nftClass.Name = *transfer.Name
+		}
+
+		if transfer.Symbol != nil {
+			nftClass.Symbol = *transfer.Symbol
+		}
+
+		if err = s.tradeScraper.datastore.SetNFTClass(nftClass); err != nil {
+			log.Warnf("unable to create nftclass on reldb: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	return &nftClass, nil
+}
+
+func (s *MarketplaceScraper) createOrReadNFT(nftClass *dia.NFTClass, transfer *erc721Transfer) (*dia.NFT, error) {
+	nft, err := s.tradeScraper.datastore.GetNFT(nftClass.Address, s.spec.Blockchain, transfer.TokenID.String())
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Warnf("unable to read nft from reldb: %s", err.Error())
+			return nil, err
+		}
+
+		createdBy, createdAt, err := s.findContractCreationInfo(context.Background(), common.HexToAddress(nftClass.Address))
+		if err != nil {
+			log.Warnf("unable to find the creation info for the nft contract(%s): %s", nftClass.Address, err.Error())
+			return nil, err
+		}
+
+		nft = dia.NFT{
+			NFTClass:       *nftClass,
+			TokenID:        transfer.TokenID.String(),
+			CreationTime:   createdAt,
+			CreatorAddress: createdBy.Hex(),
+			Attributes:     transfer.TokenAttrs,
+		}
+
+		if transfer.TokenURI != nil {
+			nft.URI = *transfer.TokenURI
+		}
+
+		if err = s.tradeScraper.datastore.SetNFT(nft); err != nil {
+			log.Warnf("unable to create nft on reldb: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	return &nft, nil
+}
+
+func (s *MarketplaceScraper) calcUSDPrice(timestamp time.Time, tokenAddr common.Address, symbol string, price decimal.Decimal) (float64, error) {
+	tokenPrice, err := s.findPrice(timestamp, tokenAddr, symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	usdPrice := price.Mul(tokenPrice)
+
+	// using float type is not a good idea to handle prices
+	// we ignore if the price cannot be presentable as float64
+	f, _ := usdPrice.Float64()
+
+	return f, nil
+}
+
+// findPrice returns the usd price of the payment currency at @tokenAddr at the time of the sale.
+// The zero address denotes the native coin of the marketplace's blockchain.
+func (s *MarketplaceScraper) findPrice(timestamp time.Time, tokenAddr common.Address, symbol string) (decimal.Decimal, error) {
+	asset := dia.Asset{
+		Symbol:     symbol,
+		Address:    tokenAddr.Hex(),
+		Blockchain: s.spec.Blockchain,
+	}
+
+	quotation, err := s.quotations.GetAssetQuotation(asset, timestamp)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("no quotation for %s (%s) at %s: %w", symbol, tokenAddr.Hex(), timestamp.Format(time.RFC3339), err)
+	}
+
+	return decimal.NewFromFloat(quotation.Price), nil
+}
+
+// GetTradeChannel returns the scrapers data channel.
+func (s *MarketplaceScraper) GetTradeChannel() chan dia.NFTTrade {
+	return s.tradeScraper.chanTrade
+}
+
+func (s *MarketplaceScraper) Close() error {
+	if s.tradeScraper.closed {
+		return errors.New("scraper already closed")
+	}
+
+	close(s.tradeScraper.shutdown)
+
+	return nil
+}
+
+// it searches the creation transaction for the given contract address using binary search in complexity of o(log n)
+func (s *MarketplaceScraper) findContractCreationInfo(ctx context.Context, contractAddr common.Address) (createdBy common.Address, createdAt time.Time, err error) {
+	if !s.conf.UseArchiveNode {
+		log.Trace("nft contract creation info could not found because UseArchiveNode flag is not set, using zero values")
+		return common.Address{}, time.Time{}, nil
+	}
+
+	var (
+		lo, hi, blockNum uint64
+		code             []byte
+		receipt          *types.Receipt
+		chainID          *big.Int
+		block            *types.Block
+	)
+
+	hi, err = s.tradeScraper.ethConnection.BlockNumber(ctx)
+	if err != nil {
+		return
+	}
+
+	for lo <= hi {
+		blockNum = (lo + hi) / 2
+
+		code, err = s.tradeScraper.ethConnection.CodeAt(ctx, contractAddr, new(big.Int).SetUint64(blockNum))
+		if err != nil {
+			return
+		}
+
+		if len(code) == 0 {
+			lo = blockNum
+		} else {
+			hi = blockNum
+		}
+
+		if hi == lo+1 {
+			blockNum = hi
+			break
+		}
+	}
+
+	block, err = s.tradeScraper.ethConnection.BlockByNumber(ctx, new(big.Int).SetUint64(blockNum))
+	if err != nil {
+		return
+	}
+
+	chainID, err = s.tradeScraper.ethConnection.NetworkID(ctx)
+	if err != nil {
+		return
+	}
+
+	signer := types.NewEIP155Signer(chainID)
+
+	for _, trx := range block.Transactions() {
+		// recipient must be nill for contract creation transactions
+		if trx.To() != nil {
+			continue
+		}
+
+		receipt, err = s.tradeScraper.ethConnection.TransactionReceipt(ctx, trx.Hash())
+		if err != nil {
+			return
+		}
+
+		// note that if the nft was created by another smart contract
+		// we can't find its creation info with this method
+		if bytes.Equal(receipt.ContractAddress.Bytes(), contractAddr.Bytes()) {
+			createdAt = time.Unix(int64(block.Time()), 0).UTC()
+			createdBy, err = types.Sender(signer, trx)
+			if err != nil {
+				return
+			}
+
+			return
+		}
+	}
+
+	return
+}
+
+// transactionReceipt returns the receipt of a mined transaction.
+func (s *MarketplaceScraper) transactionReceipt(ctx context.Context, tx *utils.EthFilteredTx, checkPending bool) (*types.Receipt, *types.Transaction, error) {
+	var txData *types.Transaction
+
+	if checkPending {
+		var (
+			pending bool
+			err     error
+		)
+		txData, pending, err = s.tradeScraper.ethConnection.TransactionByHash(ctx, tx.TXHash)
+		if err != nil {
+			log.Errorf("unable to read transaction(%s): %s", tx.TXHash, err.Error())
+			return nil, nil, err
+
+		} else if pending {
+			err = fmt.Errorf("transaction(%s) status error: pending=true", tx.TXHash)
+			log.Error(err.Error())
+			return nil, nil, err
+		}
+	}
+
+	receipt, err := s.tradeScraper.ethConnection.TransactionReceipt(ctx, tx.TXHash)
+	if err != nil {
+		log.Errorf("unable to read transaction(%s) receipt: %s", tx.TXHash, err.Error())
+		return nil, nil, err
+	}
+
+	return receipt, txData, nil
+}
+
+func (s *MarketplaceScraper) callOpts(ctx context.Context, blockNum uint64) *bind.CallOpts {
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	if s.conf.UseArchiveNode {
+		callOpts.BlockNumber = new(big.Int).SetUint64(blockNum)
+	}
+
+	return callOpts
+}
+
+// fetchERC20Metadata reads symbol and decimals of the erc20 token at @address.
+func (s *MarketplaceScraper) fetchERC20Metadata(ctx context.Context, address common.Address, blockNum uint64) (symbol string, decimals int, err error) {
+	metadata, err := erc20.NewERC20Metadata(address, s.tradeScraper.ethConnection)
+	if err != nil {
+		log.Warnf("unable to bind erc20 metadata contract at address %s: %s", address.Hex(), err.Error())
+		return
+	}
+
+	callOpts := s.callOpts(ctx, blockNum)
+
+	symbol, err = metadata.Symbol(callOpts)
+	if err != nil {
+		log.Warnf("unable to read token symbol from metadata interface of erc20(addr: %s): %s", address.Hex(), err.Error())
+		return
+	}
+
+	dec, err := metadata.Decimals(callOpts)
+	if err != nil {
+		log.Warnf("unable to read token decimals from metadata interface of erc20(addr: %s): %s", address.Hex(), err.Error())
+		return
+	}
+
+	return symbol, int(dec), nil
+}
+
+// it finds the transfer events of ERC20 in the given transaction
+func (s *MarketplaceScraper) findERC20Transfers(ctx context.Context, receipt *types.Receipt, filterAmount *big.Int) ([]*erc20Transfer, error) {
+	transfers := make([]*erc20Transfer, 0, 2)
+
+	for _, txLog := range receipt.Logs {
+		if len(txLog.Topics) < 1 || txLog.Topics[0] != erc20ABI.Events["Transfer"].ID {
+			continue
+		}
+
+		token, err := erc20.NewERC20(txLog.Address, s.tradeScraper.ethConnection)
+		if err != nil {
+			log.Warnf("unable to bind erc720 contract at address %s: %s", txLog.Address.Hex(), err.Error())
+			continue
+		}
+
+		ev, err := token.ParseTransfer(*txLog)
+		if err != nil {
+			log.Tracef("the event cannot comply to erc20's transfer: %s", err)
+			continue
+		}
+
+		if filterAmount != nil && filterAmount.Cmp(ev.Value) != 0 {
+			continue
+		}
+
+		transfer := &erc20Transfer{
+			TokenAddr: txLog.Address,
+			From:      ev.From,
+			To:        ev.To,
+			Amount:    ev.Value,
+			Decimals:  1,
+		}
+
+		transfers = append(transfers, transfer)
+
+		symbol, decimals, err := s.fetchERC20Metadata(ctx, txLog.Address, txLog.BlockNumber)
+		if err != nil {
+			continue
+		}
+
+		transfer.TokenSymbol = &symbol
+		transfer.Decimals = decimals
+	}
+
+	return transfers, nil
+}
+
+// it finds the transfer events of ERC721 in the given transaction
+func (s *MarketplaceScraper) findERC721Transfers(ctx context.Context, receipt *types.Receipt) ([]*erc721Transfer, error) {
+	transfers := make([]*erc721Transfer, 0, 1)
+
+	for _, txLog := range receipt.Logs {
+		if len(txLog.Topics) < 1 || txLog.Topics[0] != erc721ABI.Events["Transfer"].ID {
+			continue
+		}
+
+		// Erc721 Transfers have 4 indexed topics.
+		if s.spec.StrictTransfers && len(txLog.Topics) != 4 {
+			continue
+		}
+
+		nft, err := erc721.NewERC721(txLog.Address, s.tradeScraper.ethConnection)
+		if err != nil {
+			log.Warnf("unable to bind erc721 contract at address %s: %s", txLog.Address.Hex(), err.Error())
+			continue
+		}
+
+		transferLog, err := nft.ParseTransfer(*txLog)
+		if err != nil {
+			// it means this log data not comply to erc721's transfer event
+			//
+			// some old erc721 contracts have unindexed tokenid parameter
+			// so it is not compliant with the eip-721.
+
+			// best effort...
+			compat, err := erc721.NewERC721Compat(txLog.Address, s.tradeScraper.ethConnection)
+			if err != nil {
+				log.Warnf("unable to bind erc721compat contract at address %s: %s", txLog.Address.Hex(), err.Error())
+				continue
+			}
+
+			compatLog, err := compat.ParseTransfer(*txLog)
+			if err != nil {
+				log.Tracef("the event cannot comply to erc721's transfer: %s", err)
+				continue
+			}
+
+			transferLog = &erc721.ERC721Transfer{
+				From:    compatLog.From,
+				To:      compatLog.To,
+				TokenId: compatLog.TokenId,
+				Raw:     compatLog.Raw,
+			}
+		}
+
+		transfer := s.fetchERC721Metadata(ctx, txLog.Address, transferLog.TokenId, txLog.BlockNumber)
+		transfer.From = transferLog.From
+		transfer.To = transferLog.To
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
+
+// fetchERC721Metadata reads name, symbol and token attributes of an erc721 token.
+// Metadata which cannot be read is left empty.
+func (s *MarketplaceScraper) fetchERC721Metadata(ctx context.Context, nftAddress common.Address, tokenID *big.Int, blockNum uint64) *erc721Transfer {
+	transfer := &erc721Transfer{
+		NFTAddress: nftAddress,
+		TokenID:    tokenID,
+		TokenAttrs: make(map[string]interface{}),
+	}
+
+	callOpts := s.callOpts(ctx, blockNum)
+
+	md, err := erc721.NewERC721Metadata(nftAddress, s.tradeScraper.ethConnection)
+	if err != nil {
+		log.Warnf("unable to bind erc721 metadata contract at address %s: %s", nftAddress.Hex(), err.Error())
+		return transfer
+	}
+
+	if nftName, err := md.Name(callOpts); err != nil {
+		log.Warnf("unable to read nft name from metadata interface of erc721(addr: %s): %s", nftAddress.Hex(), err.Error())
+	} else {
+		transfer.Name = &nftName
+	}
+
+	if nftSymbol, err := md.Symbol(callOpts); err != nil {
+		log.Warnf("unable to read nft symbol from metadata interface of nft(addr: %s): %s", nftAddress.Hex(), err.Error())
+	} else {
+		transfer.Symbol = &nftSymbol
+	}
+
+	if tokenURI, err := md.TokenURI(callOpts, tokenID); err != nil {
+		log.Warnf("unable to find token(%s) uri: %s", tokenID.String(), err.Error())
+	} else if attrs, err := s.readNFTAttr(ctx, tokenURI); err != nil {
+		log.Warnf("unable to read token(%s) attributes: %s", tokenID.String(), err.Error())
+	} else {
+		transfer.TokenURI = &tokenURI
+		transfer.TokenAttrs = attrs
+	}
+
+	return transfer
+}
+
+func (s *MarketplaceScraper) readNFTAttr(ctx context.Context, uri string) (map[string]interface{}, error) {
+	if uri == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(uri, "ipfs://") {
+		// TODO: add IPFS support
+		return nil, nil
+	}
+
+	attrs := make(map[string]interface{})
+
+	if strings.HasPrefix(uri, "data:") {
+		data, err := dataurl.DecodeString(uri)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data.Data, &attrs); err != nil {
+			return nil, err
+		}
+		return attrs, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.conf.MetadataTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.New("unable to read token attributes: " + resp.Status)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, int64(s.conf.MaxMetadataSize))).Decode(&attrs); err != nil {
+		return nil, err
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,130 @@
+package nfttradescrapers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+type stubQuotations struct {
+	prices map[string]float64
+	asked  []dia.Asset
+}
+
+func (sq *stubQuotations) GetAssetQuotation(asset dia.Asset, timestamp time.Time) (*models.AssetQuotation, error) {
+	sq.asked = append(sq.asked, asset)
+	price, ok := sq.prices[asset.Blockchain+"-"+asset.Address]
+	if !ok {
+		return nil, errors.New("no assetQuotation in DB")
+	}
+	return &models.AssetQuotation{Asset: asset, Price: price, Time: timestamp}, nil
+}
+
+func TestMarketplaceCalcUSDPrice(t *testing.T) {
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	quotations := &stubQuotations{prices: map[string]float64{
+		dia.ETHEREUM + "-" + ZeroAddress.Hex(): 1500,
+		dia.ETHEREUM + "-" + weth.Hex():        1499,
+	}}
+	s := &MarketplaceScraper{spec: MarketplaceSpec{Blockchain: dia.ETHEREUM}, quotations: quotations}
+
+	cases := []struct {
+		addr  common.Address
+		price string
+		usd   float64
+		fails bool
+	}{
+		{ZeroAddress, "2", 3000, false},
+		{weth, "0.5", 749.5, false},
+		{common.HexToAddress("0x1"), "1", 0, true},
+	}
+	for _, c := range cases {
+		usd, err := s.calcUSDPrice(time.Now(), c.addr, "", decimal.RequireFromString(c.price))
+		if (err != nil) != c.fails {
+			t.Errorf("%s: unexpected error %v", c.addr.Hex(), err)
+			continue
+		}
+		if math.Abs(usd-c.usd) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", c.addr.Hex(), usd, c.usd)
+		}
+	}
+
+	for _, asset := range quotations.asked {
+		if asset.Blockchain != dia.ETHEREUM {
+			t.Errorf("quotation requested on %s, want %s", asset.Blockchain, dia.ETHEREUM)
+		}
+	}
+}
+
+func TestMarketplaceProcessTx(t *testing.T) {
+	decodeErr := errors.New("decode failed")
+	cases := []struct {
+		decode  MarketplaceDecodeFunc
+		skipped bool
+		err     error
+	}{
+		{
+			decode: func(ctx context.Context, s *MarketplaceScraper, tx *utils.EthFilteredTx) ([]*MarketplaceFill, error) {
+				return nil, nil
+			},
+			skipped: true,
+		},
+		{
+			decode: func(ctx context.Context, s *MarketplaceScraper, tx *utils.EthFilteredTx) ([]*MarketplaceFill, error) {
+				return nil, decodeErr
+			},
+			err: decodeErr,
+		},
+	}
+	for i, c := range cases {
+		s := &MarketplaceScraper{spec: MarketplaceSpec{Decode: c.decode}}
+		skipped, err := s.processTx(context.Background(), &utils.EthFilteredTx{})
+		if skipped != c.skipped || !errors.Is(err, c.err) {
+			t.Errorf("case %d: got (%t, %v), want (%t, %v)", i, skipped, err, c.skipped, c.err)
+		}
+	}
+}
+
+func TestMarketplaceRetryTx(t *testing.T) {
+	cases := []struct {
+		errCounter int
+		skipOnErr  bool
+		retry      bool
+	}{
+		{errCounter: 5, skipOnErr: true, retry: true},
+		{errCounter: 6, skipOnErr: true, retry: false},
+		{errCounter: 6, skipOnErr: false, retry: true},
+	}
+	for i, c := range cases {
+		s := &MarketplaceScraper{
+			conf:  &MarketplaceScraperConfig{MaxRetry: 5, SkipOnErr: c.skipOnErr},
+			state: &MarketplaceScraperState{ErrCounter: c.errCounter},
+		}
+		if got := s.retryTx(); got != c.retry {
+			t.Errorf("case %d: got %t, want %t", i, got, c.retry)
+		}
+	}
+}
+
+func TestMarketplaceStartBlock(t *testing.T) {
+	t.Setenv("LAST_BLOCK_NUM", "")
+	if got := marketplaceStartBlock(42); got != 42 {
+		t.Errorf("got %d, want default 42", got)
+	}
+	t.Setenv("LAST_BLOCK_NUM", "1000")
+	if got := marketplaceStartBlock(42); got != 1000 {
+		t.Errorf("got %d, want 1000", got)
+	}
+	t.Setenv("LAST_BLOCK_NUM", "latest")
+	if got := marketplaceStartBlock(42); got != 42 {
+		t.Errorf("got %d, want default 42 on invalid input", got)
+	}
+}
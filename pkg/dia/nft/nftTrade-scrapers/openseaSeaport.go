@@ -553,7 +553,7 @@ func (s *OpenSeaSeaportScraper) notifyTrade(ev *openseaseaport.OpenseaseaportOrd
 				trade.BundleSale = true
 			}
 
-			if asset, ok := assetCacheOpensea[dia.ETHEREUM+"-"+currAddr.Hex()]; ok {
+			if asset, ok := assetCacheOpenseaSeaport[dia.ETHEREUM+"-"+currAddr.Hex()]; ok {
 				trade.Currency = asset
 			} else {
 				currency, err := s.tradeScraper.datastore.GetAsset(currAddr.Hex(), dia.ETHEREUM)
@@ -561,7 +561,7 @@ func (s *OpenSeaSeaportScraper) notifyTrade(ev *openseaseaport.OpenseaseaportOrd
 					log.Errorf("cannot fetch asset %s -- %s | error: %v", dia.ETHEREUM, currAddr.Hex(), err)
 				}
 				trade.Currency = currency
-				assetCacheOpensea[dia.ETHEREUM+"-"+currAddr.Hex()] = currency
+				assetCacheOpenseaSeaport[dia.ETHEREUM+"-"+currAddr.Hex()] = currency
 			}
 
 			// handle close request if the chanTrade not consumed immediately
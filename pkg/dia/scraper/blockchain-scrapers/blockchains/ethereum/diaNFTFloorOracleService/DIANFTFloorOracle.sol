@@ -0,0 +1,48 @@
+// compiled using solidity 0.8.21, evm version london
+
+pragma solidity ^0.8.13;
+
+// DIANFTFloorOracle stores the floor price of nft collections together with
+// metadata which allows consumers to assess how reliable the floor price is.
+// Prices and the dispersion are scaled by 1e8.
+contract DIANFTFloorOracle {
+    struct FloorData {
+        // Floor price in the native currency of the collection's chain.
+        uint64 floor;
+        // Moving average of the floor price.
+        uint64 floorMA;
+        // Coefficient of variation of the sale prices in the floor window.
+        uint64 dispersion;
+        uint64 lastSaleTimestamp;
+        // Number of sales in the floor window.
+        uint32 numSales;
+        // True if there was no sale within the staleness threshold.
+        bool stale;
+        uint64 timestamp;
+    }
+    mapping (string => FloorData) public values;
+    address oracleUpdater;
+
+    event OracleUpdate(string key, uint64 floor, uint64 floorMA, uint64 dispersion, uint64 lastSaleTimestamp, uint32 numSales, bool stale, uint64 timestamp);
+    event UpdaterAddressChange(address newUpdater);
+
+    constructor() {
+        oracleUpdater = msg.sender;
+    }
+
+    function setValue(string memory key, FloorData memory data) public {
+        require(msg.sender == oracleUpdater);
+        values[key] = data;
+        emit OracleUpdate(key, data.floor, data.floorMA, data.dispersion, data.lastSaleTimestamp, data.numSales, data.stale, data.timestamp);
+    }
+
+    function getValue(string memory key) external view returns (FloorData memory) {
+        return values[key];
+    }
+
+    function updateOracleUpdaterAddress(address newOracleUpdaterAddress) public {
+        require(msg.sender == oracleUpdater);
+        oracleUpdater = newOracleUpdaterAddress;
+        emit UpdaterAddressChange(newOracleUpdaterAddress);
+    }
+}
@@ -3,8 +3,9 @@ package scrapers
 import (
 	"sync"
 
+	writers "github.com/diadata-org/diadata/internal/pkg/scraper-writers"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	"github.com/diadata-org/diadata/pkg/model"
-	"github.com/gorilla/websocket"
 	zap "go.uber.org/zap"
 )
 
@@ -24,14 +25,15 @@ type DeribitScraper struct {
 	WaitGroup    *sync.WaitGroup
 	Logger       *zap.SugaredLogger
 	DataStore    *models.DB
-	WsConnection *websocket.Conn
+	Writer       writers.Writer
+	wsClient     *wsclient.Client
 
-	// required for deribit to:
-	// 1. authenticate (trades is a private channel)
-	// 2. referesh the token from step 1., so that the channel isn't closed
+	// only required for private channels. The scraper subscribes to the public trades and ticker channels.
 	AccessKey    string
 	AccessSecret string
 
 	RefreshTokenEvery int16 // how often we refresh the token (in seconds)
 	MarketKind DeribitScraperKind
+	// availableMarkets caches the instruments of MarketKind for the validation of markets.
+	availableMarkets []string
 }
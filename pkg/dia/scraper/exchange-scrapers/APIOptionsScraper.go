@@ -13,6 +13,8 @@ type OptionsScraper interface {
 }
 
 // ComputedCVI is a struct representing our CVI value at a point in time
+//
+// Deprecated: the CVI is computed as dia.CVI by the cvihelper package.
 type ComputedCVI struct {
 	CVI             float64
 	CalculationTime time.Time
@@ -107,6 +107,14 @@ type PairScraper interface {
 	Pair() dia.ExchangePair
 }
 
+// Resubscriber is implemented by pair scrapers whose Close unsubscribes the pair at the exchange.
+// Only these pairs can be subscribed again without duplicating their trades.
+type Resubscriber interface {
+	PairScraper
+	// Resubscribe closes the pair scraper and returns a new one for the same pair.
+	Resubscribe() (PairScraper, error)
+}
+
 // NewAPIScraper returns an API scraper for @exchange. If scrape==true it actually does
 // scraping. Otherwise can be used for pairdiscovery.
 func NewAPIScraper(exchange string, scrape bool, key string, secret string, relDB *models.RelDB) APIScraper {
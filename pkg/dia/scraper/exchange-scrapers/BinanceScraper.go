@@ -4,22 +4,29 @@ import (
 	"encoding/json"
 	"errors"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/adshao/go-binance"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	models "github.com/diadata-org/diadata/pkg/model"
 	utils "github.com/diadata-org/diadata/pkg/utils"
 )
 
-type binancePairScraperSet map[*BinancePairScraper]nothing
+const (
+	binanceWebsocketURL = "wss://stream.binance.com:9443/ws"
+	// Binance accepts at most 5 messages per second and 1024 streams per connection.
+	binanceSubscribeInterval = 250 * time.Millisecond
+	binanceStreamsPerMessage = 200
+)
 
 // BinanceScraper is a Scraper for collecting trades from the Binance websocket API
 type BinanceScraper struct {
-	client *binance.Client
+	client   *binance.Client
+	wsClient *wsclient.Client
 	// signaling channels for session initialization and finishing
-	initDone     chan nothing
 	shutdown     chan nothing
 	shutdownDone chan nothing
 	// error handling; to read error or closed, first acquire read lock
@@ -28,10 +35,7 @@ type BinanceScraper struct {
 	error     error
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
-	// use sync.Maps to concurrently handle multiple pairs
-	pairScrapers sync.Map // dia.ExchangePair -> binancePairScraperSet
-	// pairSubscriptions sync.Map // dia.ExchangePair -> string (subscription ID)
-	// pairLocks         sync.Map // dia.ExchangePair -> sync.Mutex
+	pairScrapers sync.Map // upper case foreign name -> *BinancePairScraper
 	exchangeName string
 	chanTrades   chan *dia.Trade
 	db           *models.RelDB
@@ -42,7 +46,6 @@ func NewBinanceScraper(apiKey string, secretKey string, exchange dia.Exchange, s
 
 	s := &BinanceScraper{
 		client:       binance.NewClient(apiKey, secretKey),
-		initDone:     make(chan nothing),
 		shutdown:     make(chan nothing),
 		shutdownDone: make(chan nothing),
 		exchangeName: exchange.Name,
@@ -50,6 +53,14 @@ func NewBinanceScraper(apiKey string, secretKey string, exchange dia.Exchange, s
 		chanTrades:   make(chan *dia.Trade),
 		db:           relDB,
 	}
+	s.wsClient = wsclient.New(wsclient.Config{
+		Exchange:          exchange.Name,
+		URL:               binanceWebsocketURL,
+		Subscribe:         binancePairSubscription("SUBSCRIBE"),
+		SubscribeAll:      binanceSubscriptions,
+		Unsubscribe:       binancePairSubscription("UNSUBSCRIBE"),
+		SubscribeInterval: binanceSubscribeInterval,
+	})
 
 	// establish connection in the background
 	if scrape {
@@ -58,6 +69,34 @@ func NewBinanceScraper(apiKey string, secretKey string, exchange dia.Exchange, s
 	return s
 }
 
+// binanceSubscription returns the message with @method on the aggregated trade streams of @pairs.
+func binanceSubscription(method string, pairs []dia.ExchangePair) interface{} {
+	var streams []string
+	for _, pair := range pairs {
+		streams = append(streams, strings.ToLower(pair.ForeignName)+"@aggTrade")
+	}
+	return map[string]interface{}{
+		"method": method,
+		"params": streams,
+		"id":     time.Now().UnixNano(),
+	}
+}
+
+// binancePairSubscription returns a function which returns the message with @method on the stream of a pair.
+func binancePairSubscription(method string) func(pair dia.ExchangePair) interface{} {
+	return func(pair dia.ExchangePair) interface{} {
+		return binanceSubscription(method, []dia.ExchangePair{pair})
+	}
+}
+
+// binanceSubscriptions returns the messages which subscribe to the streams of all @pairs after connecting.
+func binanceSubscriptions(pairs []dia.ExchangePair) (messages []interface{}) {
+	for _, batch := range wsclient.Batches(pairs, binanceStreamsPerMessage) {
+		messages = append(messages, binanceSubscription("SUBSCRIBE", batch))
+	}
+	return
+}
+
 func (up *BinanceScraper) NormalizePair(pair dia.ExchangePair) (dia.ExchangePair, error) {
 	if pair.Symbol == "MIOTA" {
 		pair.ForeignName = "M" + pair.ForeignName
@@ -77,13 +116,57 @@ func (up *BinanceScraper) NormalizePair(pair dia.ExchangePair) (dia.ExchangePair
 
 // runs in a goroutine until s is closed
 func (s *BinanceScraper) mainLoop() {
-	close(s.initDone)
-	for range s.shutdown { // user requested shutdown
-		log.Println("BinanceScraper shutting down")
-		s.cleanup()
+	s.wsClient.Run(s.handleMessage)
+	log.Println("BinanceScraper shutting down")
+	s.cleanup()
+}
+
+func (s *BinanceScraper) handleMessage(message []byte) {
+	var event binance.WsAggTradeEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		log.Error("parse message: ", err)
+		return
+	}
+	// Responses to subscriptions have no event type.
+	if event.Event != "aggTrade" {
 		return
 	}
-	select {}
+	value, ok := s.pairScrapers.Load(event.Symbol)
+	if !ok {
+		return
+	}
+	pair := value.(*BinancePairScraper).pair
+
+	volume, err := strconv.ParseFloat(event.Quantity, 64)
+	price, err2 := strconv.ParseFloat(event.Price, 64)
+	if err != nil || err2 != nil {
+		log.Println("ignoring event ", event, err, err2)
+		return
+	}
+	if !event.IsBuyerMaker {
+		volume = -volume
+	}
+	pairNormalized, _ := s.NormalizePair(pair)
+	exchangepair, err := s.db.GetExchangePairCache(s.exchangeName, pair.ForeignName)
+	if err != nil {
+		log.Error(err)
+	}
+	t := &dia.Trade{
+		Symbol:         pairNormalized.Symbol,
+		Pair:           pairNormalized.ForeignName,
+		Price:          price,
+		Volume:         volume,
+		Time:           time.Unix(event.TradeTime/1000, (event.TradeTime%1000)*int64(time.Millisecond)),
+		ForeignTradeID: strconv.FormatInt(event.AggTradeID, 16),
+		Source:         s.exchangeName,
+		VerifiedPair:   exchangepair.Verified,
+		BaseToken:      exchangepair.UnderlyingPair.BaseToken,
+		QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
+	}
+	if exchangepair.Verified {
+		log.Infoln("Got verified trade", t)
+	}
+	s.chanTrades <- t
 }
 
 func (s *BinanceScraper) FillSymbolData(symbol string) (dia.Asset, error) {
@@ -98,9 +181,7 @@ func (s *BinanceScraper) cleanup() {
 	defer s.errorLock.Unlock()
 	// close all channels of PairScraper children
 	s.pairScrapers.Range(func(k, v interface{}) bool {
-		for ps := range v.(binancePairScraperSet) {
-			ps.closed = true
-		}
+		v.(*BinancePairScraper).closed = true
 		s.pairScrapers.Delete(k)
 		return true
 	})
@@ -115,6 +196,10 @@ func (s *BinanceScraper) Close() error {
 	if s.closed {
 		return errors.New("BinanceScraper: Already closed")
 	}
+	err := s.wsClient.Close()
+	if err != nil {
+		log.Error(err)
+	}
 	close(s.shutdown)
 	<-s.shutdownDone
 	s.errorLock.RLock()
@@ -125,8 +210,11 @@ func (s *BinanceScraper) Close() error {
 // ScrapePair returns a PairScraper that can be used to get trades for a single pair from
 // this APIScraper
 func (s *BinanceScraper) ScrapePair(pair dia.ExchangePair) (PairScraper, error) {
-	<-s.initDone // wait until client is connected
-
+	s.errorLock.RLock()
+	defer s.errorLock.RUnlock()
+	if s.error != nil {
+		return nil, s.error
+	}
 	if s.closed {
 		return nil, errors.New("BinanceScraper: Call ScrapePair on closed scraper")
 	}
@@ -135,52 +223,13 @@ func (s *BinanceScraper) ScrapePair(pair dia.ExchangePair) (PairScraper, error)
 		parent: s,
 		pair:   pair,
 	}
+	s.pairScrapers.Store(strings.ToUpper(pair.ForeignName), ps)
 
-	wsAggTradeHandler := func(event *binance.WsAggTradeEvent) {
-		var exchangepair dia.ExchangePair
-
-		volume, err := strconv.ParseFloat(event.Quantity, 64)
-		price, err2 := strconv.ParseFloat(event.Price, 64)
-
-		if err == nil && err2 == nil && event.Event == "aggTrade" {
-			if !event.IsBuyerMaker {
-				volume = -volume
-			}
-			pairNormalized, _ := s.NormalizePair(pair)
-			exchangepair, err = s.db.GetExchangePairCache(s.exchangeName, pair.ForeignName)
-			if err != nil {
-				log.Error(err)
-			}
-			t := &dia.Trade{
-				Symbol:         pairNormalized.Symbol,
-				Pair:           pairNormalized.ForeignName,
-				Price:          price,
-				Volume:         volume,
-				Time:           time.Unix(event.TradeTime/1000, (event.TradeTime%1000)*int64(time.Millisecond)),
-				ForeignTradeID: strconv.FormatInt(event.AggTradeID, 16),
-				Source:         s.exchangeName,
-				VerifiedPair:   exchangepair.Verified,
-				BaseToken:      exchangepair.UnderlyingPair.BaseToken,
-				QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
-			}
-			if exchangepair.Verified {
-				log.Infoln("Got verified trade", t)
-			}
-			ps.parent.chanTrades <- t
-		} else {
-			log.Println("ignoring event ", event, err, err2)
-		}
-	}
-	errHandler := func(err error) {
-		log.Error(err)
-	}
-
-	_, _, err := binance.WsAggTradeServe(pair.ForeignName, wsAggTradeHandler, errHandler)
-	if err != nil {
-		log.Errorf("serving pair %s", pair.ForeignName)
+	if err := s.wsClient.Subscribe(pair); err != nil {
+		log.Errorf("subscribe to %s: %v", pair.ForeignName, err)
 	}
 
-	return ps, err
+	return ps, nil
 }
 func (s *BinanceScraper) normalizeSymbol(p dia.ExchangePair, foreignName string, params ...string) (pair dia.ExchangePair, err error) {
 	// symbol := p.Symbol
@@ -256,12 +305,20 @@ func (ps *BinancePairScraper) Close() error {
 		return errors.New("BinancePairScraper: Already closed")
 	}
 
-	// TODO stop collection for the pair
-
+	s.pairScrapers.Delete(strings.ToUpper(ps.pair.ForeignName))
+	err = s.wsClient.Unsubscribe(ps.pair)
 	ps.closed = true
 	return err
 }
 
+// Resubscribe unsubscribes the pair and subscribes to it again.
+func (ps *BinancePairScraper) Resubscribe() (PairScraper, error) {
+	if err := ps.Close(); err != nil {
+		return nil, err
+	}
+	return ps.parent.ScrapePair(ps.pair)
+}
+
 // Channel returns a channel that can be used to receive trades
 func (ps *BinanceScraper) Channel() chan *dia.Trade {
 	return ps.chanTrades
@@ -258,6 +258,14 @@ func (ps *BitMartPairScraper) Error() error {
 	return ps.parent.error()
 }
 
+// Resubscribe unsubscribes the pair and subscribes to it again.
+func (ps *BitMartPairScraper) Resubscribe() (PairScraper, error) {
+	if err := ps.Close(); err != nil {
+		return nil, err
+	}
+	return ps.parent.ScrapePair(ps.pair)
+}
+
 // Pair returns the pair this scraper is subscribed to
 func (ps *BitMartPairScraper) Pair() dia.ExchangePair {
 	return ps.pair
@@ -558,6 +558,14 @@ func (p *BitMexPairScraper) Error() error {
 	return p.parent.error()
 }
 
+// Resubscribe unsubscribes the pair and subscribes to it again.
+func (p *BitMexPairScraper) Resubscribe() (PairScraper, error) {
+	if err := p.Close(); err != nil {
+		return nil, err
+	}
+	return p.parent.ScrapePair(p.pair)
+}
+
 // Pair returns the pair this scraper is subscribed to
 func (p *BitMexPairScraper) Pair() dia.ExchangePair {
 	return p.pair
@@ -5,11 +5,12 @@ import (
 	"errors"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 	gdax "github.com/preichenberger/go-coinbasepro/v2"
 )
 
@@ -23,7 +24,7 @@ type CoinBaseScraper struct {
 	error        error
 	closed       bool
 	pairScrapers map[string]*CoinBasePairScraper // pc.ExchangePair -> pairScraperSet
-	wsConn       *ws.Conn
+	wsClient     *wsclient.Client
 	exchangeName string
 	chanTrades   chan *dia.Trade
 	db           *models.RelDB
@@ -50,76 +51,100 @@ func NewCoinBaseScraper(exchange dia.Exchange, scrape bool, relDB *models.RelDB)
 		chanTrades:   make(chan *dia.Trade),
 		db:           relDB,
 	}
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial("wss://ws-feed.pro.coinbase.com", nil)
-	if err != nil {
-		println(err.Error())
-	}
-	s.wsConn = SwConn
+	s.wsClient = wsclient.New(wsclient.Config{
+		Exchange:    exchange.Name,
+		URL:         "wss://ws-feed.pro.coinbase.com",
+		Subscribe:   coinBaseSubscription("subscribe"),
+		Unsubscribe: coinBaseSubscription("unsubscribe"),
+		// The heartbeat channel sends a message per second and pair.
+		ReadTimeout: 30 * time.Second,
+	})
 	if scrape {
 		go s.mainLoop()
 	}
 	return s
 }
 
+// coinBaseSubscription returns a function which returns the message of type @messageType
+// on the heartbeat and ticker channels of a pair.
+func coinBaseSubscription(messageType string) func(pair dia.ExchangePair) interface{} {
+	return func(pair dia.ExchangePair) interface{} {
+		return gdax.Message{
+			Type: messageType,
+			Channels: []gdax.MessageChannel{
+				{
+					Name:       ChannelHeartbeat,
+					ProductIds: []string{pair.ForeignName},
+				},
+				{
+					Name:       ChannelTicker,
+					ProductIds: []string{pair.ForeignName},
+				},
+			},
+		}
+	}
+}
+
 // mainLoop runs in a goroutine until channel s is closed.
 func (s *CoinBaseScraper) mainLoop() {
+	s.wsClient.Run(s.handleMessage)
+	s.cleanup(nil)
+}
+
+func (s *CoinBaseScraper) handleMessage(data []byte) {
 	var err error
-	for {
-		message := gdax.Message{}
-		if err = s.wsConn.ReadJSON(&message); err != nil {
-			println(err.Error())
-			break
-		}
-		if message.Type == ChannelTicker {
-			ps, ok := s.pairScrapers[message.ProductID]
-			if ok {
-				var f64Price float64
-				var f64Volume float64
-				var exchangepair dia.ExchangePair
-				f64Price, err = strconv.ParseFloat(message.Price, 64)
+	message := gdax.Message{}
+	if err = json.Unmarshal(data, &message); err != nil {
+		log.Error("parse message: ", err)
+		return
+	}
+	if message.Type == ChannelTicker {
+		ps, ok := s.pairScrapers[message.ProductID]
+		if ok {
+			var f64Price float64
+			var f64Volume float64
+			var exchangepair dia.ExchangePair
+			f64Price, err = strconv.ParseFloat(message.Price, 64)
+			if err == nil {
+				f64Volume, err = strconv.ParseFloat(message.LastSize, 64)
 				if err == nil {
-					f64Volume, err = strconv.ParseFloat(message.LastSize, 64)
-					if err == nil {
-						if message.TradeID != 0 {
-							if message.Side == "sell" {
-								f64Volume = -f64Volume
-							}
+					if message.TradeID != 0 {
+						if message.Side == "sell" {
+							f64Volume = -f64Volume
+						}
 
-							exchangepair, err = s.db.GetExchangePairCache(s.exchangeName, message.ProductID)
-							if err != nil {
-								log.Error("get exchangepair from cache: ", err)
-							}
-							t := &dia.Trade{
-								Symbol:         ps.pair.Symbol,
-								Pair:           message.ProductID,
-								Price:          f64Price,
-								Volume:         f64Volume,
-								Time:           message.Time.Time(),
-								ForeignTradeID: strconv.FormatInt(int64(message.TradeID), 16),
-								Source:         s.exchangeName,
-								VerifiedPair:   exchangepair.Verified,
-								BaseToken:      exchangepair.UnderlyingPair.BaseToken,
-								QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
-							}
-							if t.VerifiedPair {
-								log.Info("got verified trade: ", t)
-							}
-							log.Info("go trade: ", t)
-							ps.parent.chanTrades <- t
+						exchangepair, err = s.db.GetExchangePairCache(s.exchangeName, message.ProductID)
+						if err != nil {
+							log.Error("get exchangepair from cache: ", err)
+						}
+						t := &dia.Trade{
+							Symbol:         ps.pair.Symbol,
+							Pair:           message.ProductID,
+							Price:          f64Price,
+							Volume:         f64Volume,
+							Time:           message.Time.Time(),
+							ForeignTradeID: strconv.FormatInt(int64(message.TradeID), 16),
+							Source:         s.exchangeName,
+							VerifiedPair:   exchangepair.Verified,
+							BaseToken:      exchangepair.UnderlyingPair.BaseToken,
+							QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
 						}
-					} else {
-						log.Error("error parsing LastSize " + message.LastSize)
+						if t.VerifiedPair {
+							log.Info("got verified trade: ", t)
+						}
+						log.Info("go trade: ", t)
+						ps.parent.chanTrades <- t
 					}
 				} else {
-					log.Error("error parsing price " + message.Price)
+					log.Error("error parsing LastSize " + message.LastSize)
 				}
 			} else {
-				log.Error("unknown productError" + message.ProductID)
+				log.Error("error parsing price " + message.Price)
 			}
+		} else {
+			log.Error("unknown productError" + message.ProductID)
 		}
 	}
-	s.cleanup(err)
 }
 
 // closes all connected PairScrapers
@@ -140,7 +165,7 @@ func (s *CoinBaseScraper) Close() error {
 	if s.closed {
 		return errors.New("CoinBaseScraper: Already closed")
 	}
-	err := s.wsConn.Close()
+	err := s.wsClient.Close()
 	if err != nil {
 		log.Error(err)
 	}
@@ -236,25 +261,8 @@ func (s *CoinBaseScraper) ScrapePair(pair dia.ExchangePair) (PairScraper, error)
 
 	s.pairScrapers[pair.ForeignName] = ps
 
-	subscribe := gdax.Message{
-		Type: "subscribe",
-		Channels: []gdax.MessageChannel{
-			{
-				Name: ChannelHeartbeat,
-				ProductIds: []string{
-					pair.ForeignName,
-				},
-			},
-			{
-				Name: ChannelTicker,
-				ProductIds: []string{
-					pair.ForeignName,
-				},
-			},
-		},
-	}
-	if err := s.wsConn.WriteJSON(subscribe); err != nil {
-		println(err.Error())
+	if err := s.wsClient.Subscribe(pair); err != nil {
+		log.Errorf("subscribe to %s: %v", pair.ForeignName, err)
 	}
 
 	return ps, nil
@@ -267,7 +275,7 @@ func (ps *CoinBaseScraper) Channel() chan *dia.Trade {
 
 func (ps *CoinBasePairScraper) Close() error {
 	ps.closed = true
-	return nil
+	return ps.parent.wsClient.Unsubscribe(ps.pair)
 }
 
 // Error returns an error when the channel Channel() is closed
@@ -279,6 +287,14 @@ func (ps *CoinBasePairScraper) Error() error {
 	return s.error
 }
 
+// Resubscribe unsubscribes the pair and subscribes to it again.
+func (ps *CoinBasePairScraper) Resubscribe() (PairScraper, error) {
+	if err := ps.Close(); err != nil {
+		return nil, err
+	}
+	return ps.parent.ScrapePair(ps.pair)
+}
+
 // Pair returns the pair this scraper is subscribed to
 func (ps *CoinBasePairScraper) Pair() dia.ExchangePair {
 	return ps.pair
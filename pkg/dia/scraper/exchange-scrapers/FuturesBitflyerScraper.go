@@ -25,10 +25,13 @@ type BitflyerScraper struct {
 	Logger    *zap.SugaredLogger
 }
 
-// NewBitflyerFuturesScraper - returns an instance of an options scraper.
-func NewBitflyerFuturesScraper(markets []string) FuturesScraper {
+// NewBitflyerFuturesScraper - returns an instance of an options scraper. Messages are written to @writer,
+// or to daily text files if @writer is nil.
+func NewBitflyerFuturesScraper(markets []string, writer writers.Writer) FuturesScraper {
 	wg := sync.WaitGroup{}
-	writer := writers.FileWriter{}
+	if writer == nil {
+		writer = &writers.FileWriter{}
+	}
 	logger := zap.NewExample().Sugar() // or NewProduction, or NewDevelopment
 	defer func() {
 		err := logger.Sync()
@@ -40,7 +43,7 @@ func NewBitflyerFuturesScraper(markets []string) FuturesScraper {
 	var scraper FuturesScraper = &BitflyerScraper{
 		WaitGroup: &wg,
 		Markets:   markets,
-		Writer:    &writer,
+		Writer:    writer,
 		Logger:    logger,
 	}
 
@@ -69,7 +72,7 @@ func (s *BitflyerScraper) ScraperClose(market string, connection interface{}) er
 	switch c := connection.(type) {
 	case *websocket.Conn:
 		// unsubscribe from the channel
-		err := s.send(&map[string]interface{}{"jsonrpc": "2.0", "method": "unsubscribe", "params": &map[string]interface{}{"channel": "lightning_ticker_" + market}}, market, c)
+		err := s.send(&map[string]interface{}{"jsonrpc": "2.0", "method": "unsubscribe", "params": &map[string]interface{}{"channel": bitflyerChannel(market)}}, market, c)
 		if err != nil {
 			s.Logger.Errorf("could not send a channel unsubscription message, err: %s", err)
 			return err
@@ -137,7 +140,7 @@ func (s *BitflyerScraper) Scrape(market string) {
 				s.Logger.Debugf("received a pong frame")
 				return nil
 			})
-			err = s.send(&map[string]interface{}{"jsonrpc": "2.0", "method": "subscribe", "params": &map[string]interface{}{"channel": "lightning_ticker_" + market}}, market, ws)
+			err = s.send(&map[string]interface{}{"jsonrpc": "2.0", "method": "subscribe", "params": &map[string]interface{}{"channel": bitflyerChannel(market)}}, market, ws)
 			if err != nil {
 				s.Logger.Errorf("could not send a channel subscription message. retrying, err: %s", err)
 				return
@@ -181,6 +184,11 @@ func (s *BitflyerScraper) Scrape(market string) {
 	}
 }
 
+// bitflyerChannel returns the channel with the executions of market.
+func bitflyerChannel(market string) string {
+	return "lightning_executions_" + market
+}
+
 // write's primary purpose is to write a ping frame op code to keep the websocket connection alive
 func (s *BitflyerScraper) write(mt int, payload []byte, ws *websocket.Conn) error {
 	err := ws.SetWriteDeadline(time.Now().Add(15 * time.Second))
@@ -202,7 +210,7 @@ func (s *BitflyerScraper) ScrapeMarkets() {
 // usage example
 // func main() {
 // 	wg := sync.WaitGroup{}
-// 	futuresBitflyer := scrapers.NewBitflyerFuturesScraper([]string{"BTCJPY27DEC2019", "BTCJPY03JAN2020", "BTCJPY27MAR2020"})
+// 	futuresBitflyer := scrapers.NewBitflyerFuturesScraper([]string{"BTCJPY27DEC2019", "BTCJPY03JAN2020", "BTCJPY27MAR2020"}, nil)
 // 	futuresBitflyer.ScrapeMarkets()
 // 	wg.Wait()
 // }
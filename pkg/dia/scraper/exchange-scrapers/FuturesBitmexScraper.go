@@ -25,10 +25,13 @@ type BitmexScraper struct {
 	Logger    *zap.SugaredLogger
 }
 
-// NewBitmexFuturesScraper - returns an instance of an options scraper.
-func NewBitmexFuturesScraper(markets []string) FuturesScraper {
+// NewBitmexFuturesScraper - returns an instance of an options scraper. Messages are written to @writer,
+// or to daily text files if @writer is nil.
+func NewBitmexFuturesScraper(markets []string, writer writers.Writer) FuturesScraper {
 	wg := sync.WaitGroup{}
-	writer := writers.FileWriter{}
+	if writer == nil {
+		writer = &writers.FileWriter{}
+	}
 	logger := zap.NewExample().Sugar() // or NewProduction, or NewDevelopment
 	defer func() {
 		err := logger.Sync()
@@ -40,7 +43,7 @@ func NewBitmexFuturesScraper(markets []string) FuturesScraper {
 	var scraper FuturesScraper = &BitmexScraper{
 		WaitGroup: &wg,
 		Markets:   markets,
-		Writer:    &writer,
+		Writer:    writer,
 		Logger:    logger,
 	}
 
@@ -69,7 +72,7 @@ func (s *BitmexScraper) ScraperClose(market string, connection interface{}) erro
 	switch c := connection.(type) {
 	case *websocket.Conn:
 		// unsubscribe from the channel
-		err := s.send(&map[string]interface{}{"op": "unsubscribe", "args": []string{"trade:" + market}}, market, c)
+		err := s.send(&map[string]interface{}{"op": "unsubscribe", "args": bitmexTopics(market)}, market, c)
 		if err != nil {
 			s.Logger.Errorf("could not send a channel unsubscription message, err: %s", err)
 			return err
@@ -137,7 +140,7 @@ func (s *BitmexScraper) Scrape(market string) {
 				s.Logger.Debugf("received a pong frame")
 				return nil
 			})
-			err = s.send(&map[string]interface{}{"op": "subscribe", "args": []string{"trade:" + market}}, market, ws)
+			err = s.send(&map[string]interface{}{"op": "subscribe", "args": bitmexTopics(market)}, market, ws)
 			if err != nil {
 				s.Logger.Errorf("could not send a channel subscription message. retrying, err: %s", err)
 				return
@@ -181,6 +184,12 @@ func (s *BitmexScraper) Scrape(market string) {
 	}
 }
 
+// bitmexTopics returns the subscribed topics of market: trades, mark price and open interest from
+// the instrument table and funding rates.
+func bitmexTopics(market string) []string {
+	return []string{"trade:" + market, "instrument:" + market, "funding:" + market}
+}
+
 // write's primary purpose is to write a ping frame op code to keep the websocket connection alive
 func (s *BitmexScraper) write(mt int, payload []byte, ws *websocket.Conn) error {
 	err := ws.SetWriteDeadline(time.Now().Add(15 * time.Second))
@@ -202,7 +211,7 @@ func (s *BitmexScraper) ScrapeMarkets() {
 // usage example
 // func main() {
 // 	wg := sync.WaitGroup{}
-// 	futuresBitmex := scrapers.NewBitmexFuturesScraper([]string{"XBTUSD", "XBTZ19", "XBTH20", "XBTM20", "ETHUSD", "ETHZ19", "ETHH20"})
+// 	futuresBitmex := scrapers.NewBitmexFuturesScraper([]string{"XBTUSD", "XBTZ19", "XBTH20", "XBTM20", "ETHUSD", "ETHZ19", "ETHH20"}, nil)
 // 	futuresBitmex.ScrapeMarkets()
 // 	wg.Wait()
 // }
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	writers "github.com/diadata-org/diadata/internal/pkg/scraper-writers"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	"github.com/gorilla/websocket"
 	zap "go.uber.org/zap"
 )
 
@@ -67,8 +70,25 @@ type ParsedDeribitOptionOrderbookEntry struct {
 	Asks           [][]float64 `json:"asks"`
 }
 
+type deribitChannelMessage struct {
+	Params struct {
+		Channel string `json:"channel"`
+	} `json:"params"`
+}
+
 // NewDeribitFuturesScraper - creates a deribit futures scraper for you for the markets that you supply. Some of the markets available are: "BTC-PERPETUAL" and "ETH-PERPETUAL".
-func NewDeribitFuturesScraper(markets []string, accessKey string, accessSecret string) FuturesScraper {
+// Messages are written to @writer, or to daily text files if @writer is nil.
+func NewDeribitFuturesScraper(markets []string, accessKey string, accessSecret string, writer writers.Writer) FuturesScraper {
+	return newDeribitScraper(markets, accessKey, accessSecret, writer, DeribitFuture)
+}
+
+// NewDeribitOptionsScraper - creates a deribit options scraper for the markets that you supply, such as "BTC-25DEC20-20000-C".
+// Messages are written to @writer, or to daily text files if @writer is nil.
+func NewDeribitOptionsScraper(markets []string, accessKey string, accessSecret string, writer writers.Writer) FuturesScraper {
+	return newDeribitScraper(markets, accessKey, accessSecret, writer, DeribitOption)
+}
+
+func newDeribitScraper(markets []string, accessKey string, accessSecret string, writer writers.Writer, marketKind DeribitScraperKind) FuturesScraper {
 	wg := sync.WaitGroup{}
 	logger := zap.NewExample().Sugar() // or NewProduction, or NewDevelopment
 	defer func() {
@@ -77,103 +97,124 @@ func NewDeribitFuturesScraper(markets []string, accessKey string, accessSecret s
 			log.Error(err)
 		}
 	}()
+	if writer == nil {
+		writer = &writers.FileWriter{}
+	}
 
 	var scraper DeribitScraper = DeribitScraper{
 		WaitGroup: &wg,
 		Markets:   markets, // e.g. []string{"BTC-PERPETUAL", "ETH-PERPETUAL"}
 		Logger:    logger,
+		Writer:    writer,
 
 		AccessKey:    accessKey,
 		AccessSecret: accessSecret,
 
 		// expiry is 900 seconds
 		RefreshTokenEvery: 800,
-		MarketKind:        DeribitFuture, // DO NOT change this.
+		MarketKind:        marketKind,
 	}
+	scraper.wsClient = wsclient.New(wsclient.Config{
+		Exchange: "Deribit",
+		URL:      "wss://www.deribit.com/ws/api/v2",
+		Subscribe: func(pair dia.ExchangePair) interface{} {
+			return deribitRequest("public/subscribe", scraper.channels(pair.ForeignName))
+		},
+		Unsubscribe: func(pair dia.ExchangePair) interface{} {
+			return deribitRequest("public/unsubscribe", scraper.channels(pair.ForeignName))
+		},
+		Ping: func() interface{} {
+			return map[string]interface{}{"jsonrpc": "2.0", "method": "public/test", "id": 0}
+		},
+		PingInterval: 20 * time.Second,
+	})
 
 	return &scraper
 }
 
-func (s *DeribitScraper) send(message *map[string]interface{}, websocketConn *websocket.Conn) error {
-	err := websocketConn.WriteJSON(*message)
-	if err != nil {
-		return err
+// channels returns the public channels of @market. Tickers of options contain the top of the order book
+// and the price of the underlying, tickers of futures contain mark price, open interest and funding.
+func (s *DeribitScraper) channels(market string) []string {
+	if s.MarketKind == DeribitOption {
+		return []string{"ticker." + market + ".100ms"}
 	}
+	return []string{"trades." + market + ".100ms", "ticker." + market + ".100ms"}
+}
+
+func deribitRequest(method string, channels []string) map[string]interface{} {
+	return map[string]interface{}{
+		"method": method,
+		"params": map[string]interface{}{
+			"channels": channels,
+		},
+		"jsonrpc": "2.0",
+		"id":      0,
+	}
+}
+
+// Authenticate - placeholder here, since the scraper only subscribes to public channels.
+func (s *DeribitScraper) Authenticate(market string, connection interface{}) error {
 	return nil
 }
 
 // ScraperClose - responsible for closing out the scraper for a market
 func (s *DeribitScraper) ScraperClose(market string, websocketConnection interface{}) error {
-	switch c := websocketConnection.(type) {
-	case *websocket.Conn:
-		err := c.WriteJSON(map[string]string{"op": "unsubscribe", "channel": "trades", "market": market})
-		if err != nil {
-			return err
-		}
-		log.Infof("gracefully shutdown deribit scraper on market: %s", market)
-		time.Sleep(time.Duration(retryIn) * time.Second)
-		return nil
-	default:
-		return fmt.Errorf("unknown connection type, expected gorilla/websocket, got: %T", c)
+	err := s.wsClient.Unsubscribe(dia.ExchangePair{ForeignName: market})
+	if err != nil {
+		return err
 	}
+	log.Infof("gracefully shutdown deribit scraper on market: %s", market)
+	return nil
 }
 
-// Scrape starts a websocket scraper for market
+// Scrape subscribes to the channels of market
 func (s *DeribitScraper) Scrape(market string) {
 	err := s.validateMarket(market, s.MarketKind)
 	if err != nil {
+		log.Errorf("deribit market %s: %v", market, err)
 		return
 	}
-	s.validateRefreshEveryToken()
-
-	optionRequest := &map[string]interface{}{
-		"method": "public/subscribe",
-		"params": &map[string]interface{}{
-			"channels": []string{"book." + market + ".none.1.100ms"}, // will give us orderbook snapshots every 100 ms
-		},
-		"jsonrpc": "2.0",
-		"id":      0,
-	}
-	futureRequest := &map[string]interface{}{
-		"method": "private/subscribe",
-		"params": &map[string]interface{}{
-			"channels": []string{"trades." + market + ".raw"},
-		},
-		"jsonrpc": "2.0",
-		"id":      0,
-	}
-
-	switch s.MarketKind {
-	case DeribitFuture:
-		err = s.send(futureRequest, s.WsConnection)
-	case DeribitOption:
-		err = s.send(optionRequest, s.WsConnection)
-	default:
-		panic("unknown market kind")
-	}
-
+	err = s.wsClient.Subscribe(dia.ExchangePair{ForeignName: market})
 	if err != nil {
-		log.Errorf("could not send ws message. restarting the websocket, err: %s", err)
-		return
+		log.Errorf("could not subscribe to deribit market %s: %v", market, err)
 	}
 }
 
 // ScrapeMarkets - will scrape the markets specified during instantiation
 func (s *DeribitScraper) ScrapeMarkets() {
 	for _, market := range s.Markets {
-		s.WaitGroup.Add(1)
-		go s.Scrape(market)
+		s.Scrape(market)
+	}
+	s.wsClient.Run(s.handleMessage)
+}
+
+func (s *DeribitScraper) handleMessage(message []byte) {
+	var channelMessage deribitChannelMessage
+	if err := json.Unmarshal(message, &channelMessage); err != nil {
+		log.Error("unmarshal deribit message: ", err)
+		return
+	}
+	// channels are of the form kind.instrument.interval
+	parts := strings.Split(channelMessage.Params.Channel, ".")
+	if len(parts) < 2 {
+		return
+	}
+	_, err := s.Writer.Write(string(message)+"\n", s.Writer.GetWriteFileName("Deribit", parts[1]))
+	if err != nil {
+		log.Errorf("could not write deribit message: %v", err)
 	}
-	s.WaitGroup.Wait()
 }
 
 // marketKind can be "future" or "option"
 func (s *DeribitScraper) validateMarket(market string, marketKind DeribitScraperKind) error {
-	allFuturesMarketsDeribit, err := allDeribitMarketsOfKind(marketKind)
-	if err != nil {
-		return err
+	if s.availableMarkets == nil {
+		markets, err := allDeribitMarketsOfKind(marketKind)
+		if err != nil {
+			return err
+		}
+		s.availableMarkets = markets
 	}
-	containsMarket := utils.Contains(&allFuturesMarketsDeribit, market)
+	containsMarket := utils.Contains(&s.availableMarkets, market)
 	if !containsMarket {
 		return errors.New(market + " market is unavailable")
 	}
@@ -218,6 +259,11 @@ func deribitMarkets(market string, marketKind DeribitScraperKind) ([]string, err
 	return allMarkets, nil
 }
 
+// DeribitMarkets returns the active instruments of @kind on @currency, which is either BTC or ETH.
+func DeribitMarkets(currency string, kind DeribitScraperKind) ([]string, error) {
+	return deribitMarkets(currency, kind)
+}
+
 func allDeribitMarketsOfKind(marketKind DeribitScraperKind) ([]string, error) {
 	BTCMarkets, err := deribitMarkets("BTC", marketKind)
 	if err != nil {
@@ -36,10 +36,13 @@ type tradeMessageFTX struct {
 	Type string `json:"type"`
 }
 
-// NewFTXFuturesScraper - returns an instance of the FTX scraper
-func NewFTXFuturesScraper(markets []string) FuturesScraper {
+// NewFTXFuturesScraper - returns an instance of the FTX scraper. Messages are written to @writer,
+// or to daily text files if @writer is nil.
+func NewFTXFuturesScraper(markets []string, writer writers.Writer) FuturesScraper {
 	wg := sync.WaitGroup{}
-	writer := writers.FileWriter{}
+	if writer == nil {
+		writer = &writers.FileWriter{}
+	}
 	logger := zap.NewExample().Sugar() // or NewProduction, or NewDevelopment
 	defer func() {
 		err := logger.Sync()
@@ -51,7 +54,7 @@ func NewFTXFuturesScraper(markets []string) FuturesScraper {
 	var scraper FuturesScraper = &FTXFuturesScraper{
 		WaitGroup: &wg,
 		Markets:   markets, // []string{"BNB-PERP", "ETH-PERP", "BTC-PERP", "EOS-PERP"}
-		Writer:    &writer,
+		Writer:    writer,
 		Logger:    logger,
 	}
 
@@ -49,10 +49,13 @@ type HuobiFuturesScraper struct {
 
 // --------------------------------------------------------------------------------------------
 
-// NewHuobiFuturesScraper - returns an instance of the Huobi scraper
-func NewHuobiFuturesScraper(markets []string) FuturesScraper {
+// NewHuobiFuturesScraper - returns an instance of the Huobi scraper. Messages are written to @writer,
+// or to daily text files if @writer is nil.
+func NewHuobiFuturesScraper(markets []string, writer writers.Writer) FuturesScraper {
 	wg := sync.WaitGroup{}
-	writer := writers.FileWriter{}
+	if writer == nil {
+		writer = &writers.FileWriter{}
+	}
 	logger := zap.NewExample().Sugar() // or NewProduction, or NewDevelopment
 	defer func() {
 		err := logger.Sync()
@@ -64,7 +67,7 @@ func NewHuobiFuturesScraper(markets []string) FuturesScraper {
 	var scraper FuturesScraper = &HuobiFuturesScraper{
 		WaitGroup: &wg,
 		Markets:   markets, // []string{"BNB-PERP", "ETH-PERP", "BTC-PERP", "EOS-PERP"}
-		Writer:    &writer,
+		Writer:    writer,
 		Logger:    logger,
 	}
 
@@ -147,8 +150,6 @@ func (s *HuobiFuturesScraper) Scrape(market string) {
 				s.Logger.Errorf("problem subscriping to the [%s] trade channel, err: %s", market, err)
 				return
 			}
-			// create the conduit for the received messages
-			var msg = make([]byte, 512)
 			for {
 				select {
 				case <-userCancelled:
@@ -159,20 +160,21 @@ func (s *HuobiFuturesScraper) Scrape(market string) {
 					}
 					os.Exit(0)
 				default:
-					m, err := ws.Read(msg)
+					// read whole messages, as trade messages exceed any fixed buffer
+					var newmsg []byte
+					err := websocket.Message.Receive(ws, &newmsg)
 					if err != nil {
 						s.Logger.Errorf("[%s] %s", market, err)
 						// an error reading means we may have lost the connection
 						// return out and just try again
 						return
 					}
-					newmsg := msg[:m]
 					unzipmsg, err := parseGzip(newmsg)
 					if err != nil {
 						s.Logger.Errorf("[%s] problem saving to %s, err: %s", market, s.Writer.GetWriteFileName("huobi", market), err)
 						return
 					}
-					s.Logger.Debugf("[%s] byteLen:%d, unzipLen:%d %s", market, m, len(unzipmsg), unzipmsg)
+					s.Logger.Debugf("[%s] byteLen:%d, unzipLen:%d %s", market, len(newmsg), len(unzipmsg), unzipmsg)
 					if len(unzipmsg) == pingMsgLengthHuobi {
 						if string(unzipmsg[2:6]) == "ping" {
 							_, err := s.pong(string(unzipmsg[8:21]), market, ws)
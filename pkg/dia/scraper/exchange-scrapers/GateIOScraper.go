@@ -10,9 +10,9 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	models "github.com/diadata-org/diadata/pkg/model"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
 var _GateIOsocketurl string = "wss://api.gateio.ws/ws/v4/"
@@ -55,7 +55,7 @@ type SubscribeGate struct {
 }
 
 type GateIOScraper struct {
-	wsClient *ws.Conn
+	wsClient *wsclient.Client
 	// signaling channels for session initialization and finishing
 	//initDone     chan nothing
 	shutdown     chan nothing
@@ -88,12 +88,16 @@ func NewGateIOScraper(exchange dia.Exchange, scrape bool, relDB *models.RelDB) *
 		isTickerMapInitialised: false,
 		db:                     relDB,
 	}
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_GateIOsocketurl, nil)
-	if err != nil {
-		println(err.Error())
-	}
-	s.wsClient = SwConn
+	s.wsClient = wsclient.New(wsclient.Config{
+		Exchange:    exchange.Name,
+		URL:         _GateIOsocketurl,
+		Subscribe:   gateIOSubscription("subscribe"),
+		Unsubscribe: gateIOSubscription("unsubscribe"),
+		Ping: func() interface{} {
+			return &SubscribeGate{Time: time.Now().Unix(), Channel: "spot.ping"}
+		},
+		PingInterval: 10 * time.Second,
+	})
 
 	if scrape {
 		go s.mainLoop()
@@ -101,6 +105,18 @@ func NewGateIOScraper(exchange dia.Exchange, scrape bool, relDB *models.RelDB) *
 	return s
 }
 
+// gateIOSubscription returns a function which returns the message with event @event on the trades channel of a pair.
+func gateIOSubscription(event string) func(pair dia.ExchangePair) interface{} {
+	return func(pair dia.ExchangePair) interface{} {
+		return &SubscribeGate{
+			Event:   event,
+			Time:    time.Now().Unix(),
+			Channel: "spot.trades",
+			Payload: []string{pair.ForeignName},
+		}
+	}
+}
+
 type GateIPPairResponse []GateIOPair
 
 type GateIOPair struct {
@@ -134,89 +150,59 @@ type GateIOResponseTrade struct {
 
 // runs in a goroutine until s is closed
 func (s *GateIOScraper) mainLoop() {
-	var (
-		gresponse GateIPPairResponse
-	)
+	s.wsClient.Run(s.handleMessage)
+	s.cleanup(nil)
+}
 
-	b, _, err := utils.GetRequest("https://api.gateio.ws/api/v4/spot/currency_pairs")
-	if err != nil {
-		log.Error(err)
+func (s *GateIOScraper) handleMessage(data []byte) {
+	var message GateIOResponseTrade
+	if err := json.Unmarshal(data, &message); err != nil {
+		log.Error(err.Error())
+		return
 	}
-	err = json.Unmarshal(b, &gresponse)
+
+	ps, ok := s.pairScrapers[message.Result.CurrencyPair]
+	if !ok {
+		return
+	}
+	f64Price, err := strconv.ParseFloat(message.Result.Price, 64)
 	if err != nil {
-		log.Error(err)
+		log.Errorln("error parsing float Price", err)
+		return
 	}
 
-	for _, v := range gresponse {
-
-		a := &SubscribeGate{
-			Event:   "subscribe",
-			Time:    time.Now().Unix(),
-			Channel: "spot.trades",
-			Payload: []string{v.ID},
-		}
-		log.Infof("Subscribed for Pair %v", v.ID)
-		if err = s.wsClient.WriteJSON(a); err != nil {
-			log.Error(err.Error())
-		}
+	f64Volume, err := strconv.ParseFloat(message.Result.Amount, 64)
+	if err != nil {
+		log.Errorln("error parsing float Price", err)
+		return
 	}
 
-	for {
-
-		var message GateIOResponseTrade
-		if err = s.wsClient.ReadJSON(&message); err != nil {
-			log.Error(err.Error())
-			break
-		}
-
-		ps, ok := s.pairScrapers[message.Result.CurrencyPair]
-		if ok {
-			var f64Price float64
-			var f64Volume float64
-			var exchangepair dia.ExchangePair
-			f64Price, err = strconv.ParseFloat(message.Result.Price, 64)
-			if err != nil {
-				log.Errorln("error parsing float Price", err)
-				continue
-			}
-
-			f64Volume, err = strconv.ParseFloat(message.Result.Amount, 64)
-			if err != nil {
-				log.Errorln("error parsing float Price", err)
-				continue
-			}
-
-			if message.Result.Side == "sell" {
-				f64Volume = -f64Volume
-			}
-
-			exchangepair, err = s.db.GetExchangePairCache(s.exchangeName, message.Result.CurrencyPair)
-			if err != nil {
-				log.Error(err)
-			}
-
-			t := &dia.Trade{
-				Symbol:         ps.pair.Symbol,
-				Pair:           message.Result.CurrencyPair,
-				Price:          f64Price,
-				Volume:         f64Volume,
-				Time:           time.Unix(int64(message.Result.CreateTime), 0),
-				ForeignTradeID: strconv.FormatInt(int64(message.Result.ID), 16),
-				Source:         s.exchangeName,
-				VerifiedPair:   exchangepair.Verified,
-				BaseToken:      exchangepair.UnderlyingPair.BaseToken,
-				QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
-			}
-			if exchangepair.Verified {
-				log.Infoln("Got verified trade", t)
-			}
-			ps.parent.chanTrades <- t
-			log.Infoln("got trade", t)
+	if message.Result.Side == "sell" {
+		f64Volume = -f64Volume
+	}
 
-		}
+	exchangepair, err := s.db.GetExchangePairCache(s.exchangeName, message.Result.CurrencyPair)
+	if err != nil {
+		log.Error(err)
+	}
 
+	t := &dia.Trade{
+		Symbol:         ps.pair.Symbol,
+		Pair:           message.Result.CurrencyPair,
+		Price:          f64Price,
+		Volume:         f64Volume,
+		Time:           time.Unix(int64(message.Result.CreateTime), 0),
+		ForeignTradeID: strconv.FormatInt(int64(message.Result.ID), 16),
+		Source:         s.exchangeName,
+		VerifiedPair:   exchangepair.Verified,
+		BaseToken:      exchangepair.UnderlyingPair.BaseToken,
+		QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
+	}
+	if exchangepair.Verified {
+		log.Infoln("Got verified trade", t)
 	}
-	s.cleanup(err)
+	ps.parent.chanTrades <- t
+	log.Infoln("got trade", t)
 }
 
 func (s *GateIOScraper) cleanup(err error) {
@@ -272,6 +258,10 @@ func (s *GateIOScraper) ScrapePair(pair dia.ExchangePair) (PairScraper, error) {
 
 	s.pairScrapers[pair.ForeignName] = ps
 
+	if err := s.wsClient.Subscribe(pair); err != nil {
+		log.Errorf("subscribe to %s: %v", pair.ForeignName, err)
+	}
+
 	return ps, nil
 }
 
@@ -373,7 +363,7 @@ type GateIOPairScraper struct {
 // Close stops listening for trades of the pair associated with s
 func (ps *GateIOPairScraper) Close() error {
 	ps.closed = true
-	return nil
+	return ps.parent.wsClient.Unsubscribe(ps.pair)
 }
 
 // Channel returns a channel that can be used to receive trades
@@ -3,7 +3,6 @@ package scrapers
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -11,9 +10,9 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	models "github.com/diadata-org/diadata/pkg/model"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
 var _socketurl string = "wss://api.hitbtc.com/api/2/ws"
@@ -43,7 +42,7 @@ type HitBTCAsset struct {
 }
 
 type HitBTCScraper struct {
-	wsClient *ws.Conn
+	wsClient *wsclient.Client
 	// signaling channels for session initialization and finishing
 	shutdown     chan nothing
 	shutdownDone chan nothing
@@ -72,84 +71,99 @@ func NewHitBTCScraper(exchange dia.Exchange, scrape bool, relDB *models.RelDB) *
 		db:           relDB,
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_socketurl, nil)
-	if err != nil {
-		println(err.Error())
-	}
-	s.wsClient = SwConn
+	s.wsClient = wsclient.New(wsclient.Config{
+		Exchange:    exchange.Name,
+		URL:         _socketurl,
+		Subscribe:   hitBTCSubscription("subscribeTrades"),
+		Unsubscribe: hitBTCSubscription("unsubscribeTrades"),
+	})
 	if scrape {
 		go s.mainLoop()
 	}
 	return s
 }
 
+// hitBTCSubscription returns a function which returns the message calling @method for a pair.
+func hitBTCSubscription(method string) func(pair dia.ExchangePair) interface{} {
+	return func(pair dia.ExchangePair) interface{} {
+		return &Event{
+			Method: method,
+			Params: map[string]interface{}{
+				"symbol": pair.ForeignName,
+			},
+			Id: int(time.Now().Unix()) * 1000,
+		}
+	}
+}
+
 // runs in a goroutine until s is closed
 func (s *HitBTCScraper) mainLoop() {
+	s.wsClient.Run(s.handleMessage)
+	s.cleanup(nil)
+}
+
+func (s *HitBTCScraper) handleMessage(data []byte) {
 	var err error
-	for {
-		message := &Event{}
-		if err = s.wsClient.ReadJSON(&message); err != nil {
-			log.Error(err.Error())
-			break
-		}
-		if message.Method == "updateTrades" {
-			md := message.Params.(map[string]interface{})
-			ps, ok := s.pairScrapers[md["symbol"].(string)]
-			if ok {
-				mdData := md["data"].([]interface{})
-				for _, v := range mdData {
-					var f64Price float64
-					var f64Volume float64
-					var exchangepair dia.ExchangePair
-					mdElement := v.(map[string]interface{})
-					f64PriceString := mdElement["price"].(string)
-					f64Price, err = strconv.ParseFloat(f64PriceString, 64)
+	message := &Event{}
+	if err = json.Unmarshal(data, &message); err != nil {
+		log.Error(err.Error())
+		return
+	}
+	if message.Method == "updateTrades" {
+		md := message.Params.(map[string]interface{})
+		ps, ok := s.pairScrapers[md["symbol"].(string)]
+		if ok {
+			mdData := md["data"].([]interface{})
+			for _, v := range mdData {
+				var f64Price float64
+				var f64Volume float64
+				var exchangepair dia.ExchangePair
+				mdElement := v.(map[string]interface{})
+				f64PriceString := mdElement["price"].(string)
+				f64Price, err = strconv.ParseFloat(f64PriceString, 64)
+				if err == nil {
+					f64VolumeString := mdElement["quantity"].(string)
+					f64Volume, err = strconv.ParseFloat(f64VolumeString, 64)
 					if err == nil {
-						f64VolumeString := mdElement["quantity"].(string)
-						f64Volume, err = strconv.ParseFloat(f64VolumeString, 64)
-						if err == nil {
-							timeStamp, _ := time.Parse(time.RFC3339, mdElement["timestamp"].(string))
-							if mdElement["id"] != 0 {
-								if mdElement["side"] == "sell" {
-									f64Volume = -f64Volume
-								}
-
-								exchangepair, err = s.db.GetExchangePairCache(s.exchangeName, md["symbol"].(string))
-								if err != nil {
-									log.Error(err)
-								}
-								t := &dia.Trade{
-									Symbol:         ps.pair.Symbol,
-									Pair:           md["symbol"].(string),
-									Price:          f64Price,
-									Volume:         f64Volume,
-									Time:           timeStamp,
-									ForeignTradeID: strconv.FormatInt(int64(mdElement["id"].(float64)), 16),
-									Source:         s.exchangeName,
-									VerifiedPair:   exchangepair.Verified,
-									BaseToken:      exchangepair.UnderlyingPair.BaseToken,
-									QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
-								}
-								if exchangepair.Verified {
-									log.Infoln("Got verified trade: ", t)
-								}
-								log.Info("got trade: ", t)
-								ps.parent.chanTrades <- t
+						timeStamp, _ := time.Parse(time.RFC3339, mdElement["timestamp"].(string))
+						if mdElement["id"] != 0 {
+							if mdElement["side"] == "sell" {
+								f64Volume = -f64Volume
+							}
+
+							exchangepair, err = s.db.GetExchangePairCache(s.exchangeName, md["symbol"].(string))
+							if err != nil {
+								log.Error(err)
+							}
+							t := &dia.Trade{
+								Symbol:         ps.pair.Symbol,
+								Pair:           md["symbol"].(string),
+								Price:          f64Price,
+								Volume:         f64Volume,
+								Time:           timeStamp,
+								ForeignTradeID: strconv.FormatInt(int64(mdElement["id"].(float64)), 16),
+								Source:         s.exchangeName,
+								VerifiedPair:   exchangepair.Verified,
+								BaseToken:      exchangepair.UnderlyingPair.BaseToken,
+								QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
 							}
-						} else {
-							log.Error("error parsing volume " + mdElement["quantity"].(string))
+							if exchangepair.Verified {
+								log.Infoln("Got verified trade: ", t)
+							}
+							log.Info("got trade: ", t)
+							ps.parent.chanTrades <- t
 						}
 					} else {
-						log.Error("error parsing price " + mdElement["price"].(string))
+						log.Error("error parsing volume " + mdElement["quantity"].(string))
 					}
+				} else {
+					log.Error("error parsing price " + mdElement["price"].(string))
 				}
-			} else {
-				log.Error("Unknown Pair " + md["symbol"].(string))
 			}
+		} else {
+			log.Error("Unknown Pair " + md["symbol"].(string))
 		}
 	}
-	s.cleanup(err)
 }
 
 func (s *HitBTCScraper) cleanup(err error) {
@@ -204,16 +218,8 @@ func (s *HitBTCScraper) ScrapePair(pair dia.ExchangePair) (PairScraper, error) {
 
 	s.pairScrapers[pair.ForeignName] = ps
 
-	a := &Event{
-		Method: "subscribeTrades",
-		Params: map[string]interface{}{
-			"symbol": pair.ForeignName,
-		},
-		Id: int(time.Now().Unix()) * 1000,
-	}
-
-	if err := s.wsClient.WriteJSON(a); err != nil {
-		fmt.Println(err.Error())
+	if err := s.wsClient.Subscribe(pair); err != nil {
+		log.Errorf("subscribe to %s: %v", pair.ForeignName, err)
 	}
 
 	return ps, nil
@@ -293,7 +299,7 @@ type HitBTCPairScraper struct {
 // Close stops listening for trades of the pair associated with s
 func (ps *HitBTCPairScraper) Close() error {
 	ps.closed = true
-	return nil
+	return ps.parent.wsClient.Unsubscribe(ps.pair)
 }
 
 // Channel returns a channel that can be used to receive trades
@@ -1,6 +1,7 @@
 package scrapers
 
 import (
+	"encoding/json"
 	"errors"
 	"math"
 	"strconv"
@@ -9,11 +10,15 @@ import (
 
 	krakenapi "github.com/beldur/kraken-go-api-client"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 )
 
 const (
-	krakenRefreshDelay = time.Second * 30 * 1
+	krakenWebsocketURL    = "wss://ws.kraken.com"
+	krakenAssetPairsURL   = "https://api.kraken.com/0/public/AssetPairs"
+	krakenPairsPerMessage = 100
 )
 
 type KrakenScraper struct {
@@ -25,9 +30,11 @@ type KrakenScraper struct {
 	errorLock    sync.RWMutex
 	error        error
 	closed       bool
-	pairScrapers map[string]*KrakenPairScraper // pc.ExchangePair -> pairScraperSet
-	api          *krakenapi.KrakenApi
-	ticker       *time.Ticker
+	pairScrapers sync.Map // websocket name -> *KrakenPairScraper
+	// wsNames maps the foreign names of the REST API to the names of pairs in the websocket API.
+	wsNames      map[string]string
+	wsNamesOnce  sync.Once
+	wsClient     *wsclient.Client
 	exchangeName string
 	chanTrades   chan *dia.Trade
 	db           *models.RelDB
@@ -39,14 +46,20 @@ func NewKrakenScraper(key string, secret string, exchange dia.Exchange, scrape b
 	s := &KrakenScraper{
 		shutdown:     make(chan nothing),
 		shutdownDone: make(chan nothing),
-		pairScrapers: make(map[string]*KrakenPairScraper),
-		api:          krakenapi.New(key, secret),
-		ticker:       time.NewTicker(krakenRefreshDelay),
 		exchangeName: exchange.Name,
 		error:        nil,
 		chanTrades:   make(chan *dia.Trade),
 		db:           relDB,
 	}
+	s.wsClient = wsclient.New(wsclient.Config{
+		Exchange:     exchange.Name,
+		URL:          krakenWebsocketURL,
+		Subscribe:    s.pairSubscription("subscribe"),
+		SubscribeAll: s.subscriptions,
+		Unsubscribe:  s.pairSubscription("unsubscribe"),
+		// Kraken sends heartbeats every second without other messages.
+		ReadTimeout: 30 * time.Second,
+	})
 	if scrape {
 		go s.mainLoop()
 	}
@@ -69,18 +82,158 @@ func FloatToString(input_num float64) string {
 	return strconv.FormatFloat(input_num, 'f', -1, 64)
 }
 
+// krakenSubscription is a (un)subscription message of the websocket API.
+type krakenSubscription struct {
+	Event        string   `json:"event"`
+	Pair         []string `json:"pair"`
+	Subscription struct {
+		Name string `json:"name"`
+	} `json:"subscription"`
+}
+
+// pairSubscription returns a function which returns the message with @event on the trades of a pair.
+func (s *KrakenScraper) pairSubscription(event string) func(pair dia.ExchangePair) interface{} {
+	return func(pair dia.ExchangePair) interface{} {
+		return s.subscription(event, []dia.ExchangePair{pair})
+	}
+}
+
+// subscriptions returns the messages which subscribe to the trades of all @pairs after connecting.
+func (s *KrakenScraper) subscriptions(pairs []dia.ExchangePair) (messages []interface{}) {
+	for _, batch := range wsclient.Batches(pairs, krakenPairsPerMessage) {
+		messages = append(messages, s.subscription("subscribe", batch))
+	}
+	return
+}
+
+func (s *KrakenScraper) subscription(event string, pairs []dia.ExchangePair) krakenSubscription {
+	message := krakenSubscription{Event: event}
+	message.Subscription.Name = "trade"
+	for _, pair := range pairs {
+		message.Pair = append(message.Pair, s.wsName(pair))
+	}
+	return message
+}
+
+// wsName returns the name of @pair in the websocket API, such as XBT/USD for XXBTZUSD.
+func (s *KrakenScraper) wsName(pair dia.ExchangePair) string {
+	s.wsNamesOnce.Do(func() {
+		var err error
+		s.wsNames, err = fetchKrakenWSNames()
+		if err != nil {
+			log.Error("fetch websocket names of pairs: ", err)
+		}
+	})
+	if wsName, ok := s.wsNames[pair.ForeignName]; ok {
+		return wsName
+	}
+	return pair.ForeignName
+}
+
+// fetchKrakenWSNames returns the websocket names of all pairs by their names and alternative names in the REST API.
+func fetchKrakenWSNames() (map[string]string, error) {
+	data, _, err := utils.GetRequest(krakenAssetPairsURL)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			Altname string `json:"altname"`
+			WSName  string `json:"wsname"`
+		} `json:"result"`
+	}
+	if err = json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Error) > 0 {
+		return nil, errors.New(response.Error[0])
+	}
+	wsNames := make(map[string]string)
+	for name, info := range response.Result {
+		if info.WSName == "" {
+			continue
+		}
+		wsNames[name] = info.WSName
+		wsNames[info.Altname] = info.WSName
+	}
+	return wsNames, nil
+}
+
 // mainLoop runs in a goroutine until channel s is closed.
 func (s *KrakenScraper) mainLoop() {
-	for {
-		select {
-		case <-s.ticker.C:
-			s.Update()
-		case <-s.shutdown: // user requested shutdown
-			log.Printf("KrakenScraper shutting down")
-			s.cleanup(nil)
+	s.wsClient.Run(s.handleMessage)
+	log.Printf("KrakenScraper shutting down")
+	s.cleanup(nil)
+}
+
+// handleMessage parses trade messages of the form [channelID, [[price, volume, time, side, orderType, misc]], "trade", pair].
+// Events such as heartbeats are objects and ignored.
+func (s *KrakenScraper) handleMessage(data []byte) {
+	var message []json.RawMessage
+	if err := json.Unmarshal(data, &message); err != nil || len(message) != 4 {
+		return
+	}
+	var channelName, wsName string
+	var trades [][]interface{}
+	if json.Unmarshal(message[2], &channelName) != nil || channelName != "trade" {
+		return
+	}
+	if err := json.Unmarshal(message[3], &wsName); err != nil {
+		log.Error("parse pair of trade message: ", err)
+		return
+	}
+	if err := json.Unmarshal(message[1], &trades); err != nil {
+		log.Error("parse trades: ", err)
+		return
+	}
+	value, ok := s.pairScrapers.Load(wsName)
+	if !ok {
+		return
+	}
+	ps := value.(*KrakenPairScraper)
+
+	for _, trade := range trades {
+		info, timestamp, err := parseKrakenTrade(trade)
+		if err != nil {
+			log.Errorf("parse trade %v: %v", trade, err)
+			continue
+		}
+		t := NewTrade(ps.pair, info, strconv.FormatInt(timestamp.UnixNano(), 16), s.db)
+		t.Time = timestamp
+		s.chanTrades <- t
+	}
+}
+
+// parseKrakenTrade returns the trade info and the exact time of a trade in a websocket message.
+func parseKrakenTrade(trade []interface{}) (info krakenapi.TradeInfo, timestamp time.Time, err error) {
+	if len(trade) < 4 {
+		err = errors.New("too few fields")
+		return
+	}
+	fields := make([]string, 4)
+	for i := range fields {
+		var ok bool
+		if fields[i], ok = trade[i].(string); !ok {
+			err = errors.New("field is not a string")
 			return
 		}
 	}
+	if info.PriceFloat, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return
+	}
+	if info.VolumeFloat, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return
+	}
+	seconds, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return
+	}
+	timestamp = time.Unix(0, int64(seconds*1e9))
+	info.Time = timestamp.Unix()
+	info.Sell = fields[3] == "s"
+	info.Buy = fields[3] == "b"
+	return
 }
 
 // closes all connected PairScrapers
@@ -104,6 +257,10 @@ func (s *KrakenScraper) Close() error {
 	if s.closed {
 		return errors.New("KrakenScraper: Already closed")
 	}
+	err := s.wsClient.Close()
+	if err != nil {
+		log.Error(err)
+	}
 	close(s.shutdown)
 	<-s.shutdownDone
 	s.errorLock.RLock()
@@ -113,10 +270,9 @@ func (s *KrakenScraper) Close() error {
 
 // KrakenPairScraper implements PairScraper for Kraken
 type KrakenPairScraper struct {
-	parent     *KrakenScraper
-	pair       dia.ExchangePair
-	closed     bool
-	lastRecord int64
+	parent *KrakenScraper
+	pair   dia.ExchangePair
+	closed bool
 }
 
 // ScrapePair returns a PairScraper that can be used to get trades for a single pair from
@@ -132,12 +288,14 @@ func (s *KrakenScraper) ScrapePair(pair dia.ExchangePair) (PairScraper, error) {
 		return nil, errors.New("KrakenScraper: Call ScrapePair on closed scraper")
 	}
 	ps := &KrakenPairScraper{
-		parent:     s,
-		pair:       pair,
-		lastRecord: 0, //TODO FIX to figure out the last we got...
+		parent: s,
+		pair:   pair,
 	}
+	s.pairScrapers.Store(s.wsName(pair), ps)
 
-	s.pairScrapers[pair.Symbol] = ps
+	if err := s.wsClient.Subscribe(pair); err != nil {
+		log.Errorf("subscribe to %s: %v", pair.ForeignName, err)
+	}
 
 	return ps, nil
 }
@@ -184,9 +342,19 @@ func (ps *KrakenScraper) Channel() chan *dia.Trade {
 	return ps.chanTrades
 }
 
+// Close stops listening for trades of the pair associated with s
 func (ps *KrakenPairScraper) Close() error {
+	ps.parent.pairScrapers.Delete(ps.parent.wsName(ps.pair))
 	ps.closed = true
-	return nil
+	return ps.parent.wsClient.Unsubscribe(ps.pair)
+}
+
+// Resubscribe unsubscribes the pair and subscribes to it again.
+func (ps *KrakenPairScraper) Resubscribe() (PairScraper, error) {
+	if err := ps.Close(); err != nil {
+		return nil, err
+	}
+	return ps.parent.ScrapePair(ps.pair)
 }
 
 // Error returns an error when the channel Channel() is closed
@@ -229,27 +397,3 @@ func NewTrade(pair dia.ExchangePair, info krakenapi.TradeInfo, foreignTradeID st
 	}
 	return t
 }
-
-func (s *KrakenScraper) Update() {
-
-	for _, ps := range s.pairScrapers {
-
-		r, err := s.api.Trades(ps.pair.ForeignName, ps.lastRecord)
-
-		if err != nil {
-			log.Printf("err on collect trades %v %v", err, ps.pair.ForeignName)
-			time.Sleep(1 * time.Minute)
-		} else {
-			if r != nil {
-				ps.lastRecord = r.Last
-				for _, ti := range r.Trades {
-					// p, _ := s.NormalizePair(ps.pair)
-					t := NewTrade(ps.pair, ti, strconv.FormatInt(r.Last, 16), s.db)
-					ps.parent.chanTrades <- t
-				}
-			} else {
-				log.Printf("r nil")
-			}
-		}
-	}
-}
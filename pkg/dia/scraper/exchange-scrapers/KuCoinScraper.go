@@ -1,6 +1,7 @@
 package scrapers
 
 import (
+	"encoding/json"
 	"errors"
 	"strconv"
 	"strings"
@@ -9,9 +10,19 @@ import (
 
 	"github.com/Kucoin/kucoin-go-sdk"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	models "github.com/diadata-org/diadata/pkg/model"
 )
 
+const (
+	// KuCoin allows 300 topics per connection, 100 symbols per topic and 100 messages per 10 seconds.
+	kucoinPairsPerConnection = 300
+	kucoinPairsPerTopic      = 100
+	kucoinSubscribeInterval  = 150 * time.Millisecond
+	kucoinPingInterval       = 18 * time.Second
+	kucoinMatchTopic         = "/market/match:"
+)
+
 var (
 	bufferSize = 20
 )
@@ -57,8 +68,7 @@ type KuExchangePair struct {
 }
 
 type KuCoinScraper struct {
-	// signaling channels for session initialization and finishing
-	initDone     chan nothing
+	// signaling channels for session finishing
 	shutdown     chan nothing
 	shutdownDone chan nothing
 	// error handling; to read error or closed, first acquire read lock
@@ -68,28 +78,33 @@ type KuCoinScraper struct {
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
 	// use sync.Maps to concurrently handle multiple pairs
-	pairScrapers map[string]*KuCoinPairScraper // dia.ExchangePair -> KuCoinPairScraper
-	// pairSubscriptions sync.Map                      // dia.ExchangePair -> string (subscription ID)
-	// pairLocks         sync.Map                      // dia.ExchangePair -> sync.Mutex
-	exchangeName string
-	chanTrades   chan *dia.Trade
-	apiService   *kucoin.ApiService
-	db           *models.RelDB
+	pairScrapers sync.Map // foreign name -> *KuCoinPairScraper
+	// clients holds the websocket connections, each with at most kucoinPairsPerConnection pairs.
+	clientsLock sync.Mutex
+	clients     []*wsclient.Client
+	// lastTradeTimes and tradeCounts make trade times unique per pair.
+	tradeTimesLock sync.Mutex
+	lastTradeTimes map[dia.Pair]time.Time
+	tradeCounts    map[dia.Pair]int
+	exchangeName   string
+	chanTrades     chan *dia.Trade
+	apiService     *kucoin.ApiService
+	db             *models.RelDB
 }
 
 func NewKuCoinScraper(apiKey string, secretKey string, exchange dia.Exchange, scrape bool, relDB *models.RelDB) *KuCoinScraper {
 	apiService := kucoin.NewApiService()
 
 	s := &KuCoinScraper{
-		initDone:     make(chan nothing),
-		shutdown:     make(chan nothing),
-		shutdownDone: make(chan nothing),
-		exchangeName: exchange.Name,
-		pairScrapers: make(map[string]*KuCoinPairScraper),
-		error:        nil,
-		chanTrades:   make(chan *dia.Trade),
-		apiService:   apiService,
-		db:           relDB,
+		shutdown:       make(chan nothing),
+		shutdownDone:   make(chan nothing),
+		exchangeName:   exchange.Name,
+		lastTradeTimes: make(map[dia.Pair]time.Time),
+		tradeCounts:    make(map[dia.Pair]int),
+		error:          nil,
+		chanTrades:     make(chan *dia.Trade),
+		apiService:     apiService,
+		db:             relDB,
 	}
 
 	// establish connection in the background
@@ -101,268 +116,137 @@ func NewKuCoinScraper(apiKey string, secretKey string, exchange dia.Exchange, sc
 
 // runs in a goroutine until s is closed
 func (s *KuCoinScraper) mainLoop() {
-	var channelsForClient1, channelsForClient2, channelsForClient3 []*kucoin.WebSocketSubscribeMessage
-
-	close(s.initDone)
+	<-s.shutdown
+	log.Println("KuCoin shutting down")
+	s.cleanup(nil)
+}
 
-	lastTradeMap := make(map[dia.Pair]time.Time)
-	countMap := make(map[dia.Pair]int)
+// newClient returns a websocket client for a new connection, which runs until s is closed.
+func (s *KuCoinScraper) newClient() *wsclient.Client {
+	client := wsclient.New(wsclient.Config{
+		Exchange:          s.exchangeName,
+		Endpoint:          s.endpoint,
+		Subscribe:         kucoinPairSubscription(kucoin.SubscribeMessage),
+		SubscribeAll:      kucoinSubscriptions,
+		SubscribeInterval: kucoinSubscribeInterval,
+		Unsubscribe:       kucoinPairSubscription(kucoin.UnsubscribeMessage),
+		Ping:              func() interface{} { return kucoin.NewPingMessage() },
+		PingInterval:      kucoinPingInterval,
+	})
+	go client.Run(s.handleMessage)
+	return client
+}
 
+// endpoint returns the URL of a new connection with a public token.
+func (s *KuCoinScraper) endpoint() (string, error) {
 	rsp, err := s.apiService.WebSocketPublicToken()
 	if err != nil {
-		// Handle error
-		log.Error("Error WebSocketPublicToken", err)
+		return "", err
 	}
-
 	tk := &kucoin.WebSocketTokenModel{}
 	if err = rsp.ReadData(tk); err != nil {
-		log.Error("Error Reading data", err)
+		return "", err
 	}
+	if len(tk.Servers) == 0 {
+		return "", errors.New("no websocket server in token response")
+	}
+	return tk.Servers[0].Endpoint + "?token=" + tk.Token + "&connectId=" + strconv.FormatInt(time.Now().UnixNano(), 10), nil
+}
 
-	client1 := s.apiService.NewWebSocketClient(tk)
-	client2 := s.apiService.NewWebSocketClient(tk)
-	client3 := s.apiService.NewWebSocketClient(tk)
+// kucoinPairSubscription returns a function which returns the message of @messageType on the trades of a pair.
+func kucoinPairSubscription(messageType string) func(pair dia.ExchangePair) interface{} {
+	return func(pair dia.ExchangePair) interface{} {
+		return kucoinSubscription(messageType, []dia.ExchangePair{pair})
+	}
+}
 
-	client1DownStream, _, err := client1.Connect()
-	if err != nil {
-		log.Error("Error Reading data", err)
+// kucoinSubscriptions returns the messages which subscribe to the trades of all @pairs after connecting.
+func kucoinSubscriptions(pairs []dia.ExchangePair) (messages []interface{}) {
+	for _, batch := range wsclient.Batches(pairs, kucoinPairsPerTopic) {
+		messages = append(messages, kucoinSubscription(kucoin.SubscribeMessage, batch))
 	}
-	client2DownStream, _, err := client2.Connect()
+	return
+}
+
+func kucoinSubscription(messageType string, pairs []dia.ExchangePair) interface{} {
+	var symbols []string
+	for _, pair := range pairs {
+		symbols = append(symbols, pair.ForeignName)
+	}
+	message := kucoin.NewSubscribeMessage(kucoinMatchTopic+strings.Join(symbols, ","), false)
+	message.Type = messageType
+	return message
+}
+
+// handleMessage sends the trade of a match message. Other messages such as acks and pongs are ignored.
+func (s *KuCoinScraper) handleMessage(data []byte) {
+	msg := &kucoin.WebSocketDownstreamMessage{}
+	if err := json.Unmarshal(data, msg); err != nil {
+		log.Error("parse message: ", err)
+		return
+	}
+	if msg.WebSocketMessage == nil || msg.Type != kucoin.Message || !strings.HasPrefix(msg.Topic, kucoinMatchTopic) {
+		return
+	}
+	t := &KucoinMarketMatch{}
+	if err := msg.ReadData(t); err != nil {
+		log.Errorf("Failure to read: %v", err)
+		return
+	}
+	if _, ok := s.pairScrapers.Load(t.Symbol); !ok {
+		return
+	}
+	asset := strings.Split(t.Symbol, "-")
+	f64Price, _ := strconv.ParseFloat(t.Price, 64)
+	f64Volume, _ := strconv.ParseFloat(t.Size, 64)
+	timeOrder, err := strconv.ParseInt(t.Time, 10, 64)
 	if err != nil {
-		log.Error("Error Reading data", err)
+		log.Error("parse trade time: ", err)
+	}
+	// WS returns different lengths of Unix timestamps. Adjust to nanoseconds if returns milliseconds.
+	if len(t.Time) == 13 {
+		timeOrder *= 1e6
 	}
-	client3DownStream, _, err := client3.Connect()
+
+	if t.Side == "sell" {
+		f64Volume = -f64Volume
+	}
+
+	exchangepair, err := s.db.GetExchangePairCache(s.exchangeName, t.Symbol)
 	if err != nil {
-		log.Error("Error Reading data", err)
+		log.Error(err)
 	}
 
-	count := 0
-	for pair := range s.pairScrapers {
-		ch := kucoin.NewSubscribeMessage("/market/match:"+pair, false)
-		if count >= 598 {
-			channelsForClient3 = append(channelsForClient3, ch)
-			count++
-			continue
-		}
-		if count >= 299 {
-			channelsForClient2 = append(channelsForClient2, ch)
-			count++
-			continue
-		} else {
-			channelsForClient1 = append(channelsForClient1, ch)
-			count++
-		}
+	pair := dia.Pair{QuoteToken: exchangepair.UnderlyingPair.QuoteToken, BaseToken: exchangepair.UnderlyingPair.BaseToken}
+	trade := &dia.Trade{
+		Symbol:         asset[0],
+		Pair:           t.Symbol,
+		Price:          f64Price,
+		Time:           s.uniqueTradeTime(pair, time.Unix(0, timeOrder)),
+		Volume:         f64Volume,
+		Source:         s.exchangeName,
+		VerifiedPair:   exchangepair.Verified,
+		BaseToken:      exchangepair.UnderlyingPair.BaseToken,
+		QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
+		ForeignTradeID: t.TradeID,
+	}
+	if exchangepair.Verified {
+		log.Info("Got verified trade: ", trade)
 	}
+	s.chanTrades <- trade
+}
 
-	log.Info("number of pairs: ", count)
-
-	if err := client1.Subscribe(channelsForClient1...); err != nil {
-		log.Fatal("Error while subscribing client1 ", err)
-	}
-	if err := client2.Subscribe(channelsForClient2...); err != nil {
-		log.Fatal("Error while subscribing client2 ", err)
-	}
-	if err := client3.Subscribe(channelsForClient3...); err != nil {
-		log.Fatal("Error while subscribing client3 ", err)
-	}
-
-	go func() {
-		var msg *kucoin.WebSocketDownstreamMessage
-		for {
-			select {
-			case msg = <-client1DownStream:
-				if msg == nil {
-					continue
-				}
-				t := &KucoinMarketMatch{}
-				if err := msg.ReadData(t); err != nil {
-					log.Printf("Failure to read: %s", err.Error())
-					return
-				}
-				asset := strings.Split(t.Symbol, "-")
-				f64Price, _ := strconv.ParseFloat(t.Price, 64)
-				f64Volume, _ := strconv.ParseFloat(t.Size, 64)
-				timeOrder, err := strconv.ParseInt(t.Time, 10, 64)
-				if err != nil {
-					log.Error("parse trade time: ", err)
-				}
-				// WS returns different lengths of Unix timestamps. Adjust to nanoseconds if returns milliseconds.
-				if len(t.Time) == 13 {
-					timeOrder *= 1e6
-				}
-
-				if t.Side == "sell" {
-					f64Volume = -f64Volume
-				}
-
-				exchangepair, err := s.db.GetExchangePairCache(s.exchangeName, t.Symbol)
-				if err != nil {
-					log.Error(err)
-				}
-
-				// Make trade times unique
-				tradeTime := time.Unix(0, timeOrder)
-				pair := dia.Pair{QuoteToken: exchangepair.UnderlyingPair.QuoteToken, BaseToken: exchangepair.UnderlyingPair.BaseToken}
-				if _, ok := lastTradeMap[pair]; ok {
-					if lastTradeMap[pair] != tradeTime {
-						lastTradeMap[pair] = tradeTime
-						countMap[pair] = 0
-					} else {
-						tradeTime = tradeTime.Add(time.Duration((countMap[pair] + 1)) * time.Nanosecond)
-						countMap[pair] += 1
-					}
-				} else {
-					lastTradeMap[pair] = tradeTime
-				}
-
-				trade := &dia.Trade{
-					Symbol:         asset[0],
-					Pair:           t.Symbol,
-					Price:          f64Price,
-					Time:           tradeTime,
-					Volume:         f64Volume,
-					Source:         s.exchangeName,
-					VerifiedPair:   exchangepair.Verified,
-					BaseToken:      exchangepair.UnderlyingPair.BaseToken,
-					QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
-					ForeignTradeID: t.TradeID,
-				}
-				if exchangepair.Verified {
-					log.Info("Got verified trade from stream 1: ", trade)
-				}
-				s.chanTrades <- trade
-
-			case msg = <-client2DownStream:
-				if msg == nil {
-					continue
-				}
-				t := &KucoinMarketMatch{}
-				if err := msg.ReadData(t); err != nil {
-					log.Errorf("Failure to read: %v", err)
-					return
-				}
-				asset := strings.Split(t.Symbol, "-")
-				f64Price, _ := strconv.ParseFloat(t.Price, 64)
-				f64Volume, _ := strconv.ParseFloat(t.Size, 64)
-				timeOrder, err := strconv.ParseInt(t.Time, 10, 64)
-				if err != nil {
-					log.Error("parse trade time: ", err)
-				}
-				// WS returns different lengths of Unix timestamps. Adjust to nanoseconds if returns milliseconds.
-				if len(t.Time) == 13 {
-					timeOrder *= 1e6
-				}
-
-				if t.Side == "sell" {
-					f64Volume = -f64Volume
-				}
-
-				exchangepair, err := s.db.GetExchangePairCache(s.exchangeName, t.Symbol)
-				if err != nil {
-					log.Error(err)
-				}
-
-				// Make trade times unique
-				tradeTime := time.Unix(0, timeOrder)
-				pair := dia.Pair{QuoteToken: exchangepair.UnderlyingPair.QuoteToken, BaseToken: exchangepair.UnderlyingPair.BaseToken}
-				if _, ok := lastTradeMap[pair]; ok {
-					if lastTradeMap[pair] != tradeTime {
-						lastTradeMap[pair] = tradeTime
-						countMap[pair] = 0
-					} else {
-						tradeTime = tradeTime.Add(time.Duration((countMap[pair] + 1)) * time.Nanosecond)
-						countMap[pair] += 1
-					}
-				} else {
-					lastTradeMap[pair] = tradeTime
-				}
-
-				trade := &dia.Trade{
-					Symbol:         asset[0],
-					Pair:           t.Symbol,
-					Price:          f64Price,
-					Time:           time.Unix(0, timeOrder),
-					Volume:         f64Volume,
-					Source:         s.exchangeName,
-					VerifiedPair:   exchangepair.Verified,
-					BaseToken:      exchangepair.UnderlyingPair.BaseToken,
-					QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
-					ForeignTradeID: t.TradeID,
-				}
-				if exchangepair.Verified {
-					log.Info("Got verified trade from stream 2: ", trade)
-				}
-				s.chanTrades <- trade
-
-			case msg = <-client3DownStream:
-				if msg == nil {
-					continue
-				}
-				t := &KucoinMarketMatch{}
-				if err := msg.ReadData(t); err != nil {
-					log.Errorf("Failure to read: %v", err)
-					return
-				}
-				asset := strings.Split(t.Symbol, "-")
-				f64Price, _ := strconv.ParseFloat(t.Price, 64)
-				f64Volume, _ := strconv.ParseFloat(t.Size, 64)
-				timeOrder, err := strconv.ParseInt(t.Time, 10, 64)
-				if err != nil {
-					log.Error("parse trade time: ", err)
-				}
-				// WS returns different lengths of Unix timestamps. Adjust to nanoseconds if returns milliseconds.
-				if len(t.Time) == 13 {
-					timeOrder *= 1e6
-				}
-
-				if t.Side == "sell" {
-					f64Volume = -f64Volume
-				}
-
-				exchangepair, err := s.db.GetExchangePairCache(s.exchangeName, t.Symbol)
-				if err != nil {
-					log.Error(err)
-				}
-
-				// Make trade times unique
-				tradeTime := time.Unix(0, timeOrder)
-				pair := dia.Pair{QuoteToken: exchangepair.UnderlyingPair.QuoteToken, BaseToken: exchangepair.UnderlyingPair.BaseToken}
-				if _, ok := lastTradeMap[pair]; ok {
-					if lastTradeMap[pair] != tradeTime {
-						lastTradeMap[pair] = tradeTime
-						countMap[pair] = 0
-					} else {
-						tradeTime = tradeTime.Add(time.Duration((countMap[pair] + 1)) * time.Nanosecond)
-						countMap[pair] += 1
-					}
-				} else {
-					lastTradeMap[pair] = tradeTime
-				}
-
-				trade := &dia.Trade{
-					Symbol:         asset[0],
-					Pair:           t.Symbol,
-					Price:          f64Price,
-					Time:           time.Unix(0, timeOrder),
-					Volume:         f64Volume,
-					Source:         s.exchangeName,
-					VerifiedPair:   exchangepair.Verified,
-					BaseToken:      exchangepair.UnderlyingPair.BaseToken,
-					QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
-					ForeignTradeID: t.TradeID,
-				}
-				if exchangepair.Verified {
-					log.Info("Got verified trade from stream 3: ", trade)
-				}
-				s.chanTrades <- trade
-
-			case <-s.shutdown: // user requested shutdown
-				log.Println("KuCoin shutting down")
-				s.cleanup(nil)
-				return
-			}
-		}
-	}()
+// uniqueTradeTime shifts @tradeTime by a nanosecond per previous trade of @pair at the same time.
+func (s *KuCoinScraper) uniqueTradeTime(pair dia.Pair, tradeTime time.Time) time.Time {
+	s.tradeTimesLock.Lock()
+	defer s.tradeTimesLock.Unlock()
+	if lastTradeTime, ok := s.lastTradeTimes[pair]; ok && lastTradeTime == tradeTime {
+		s.tradeCounts[pair]++
+		return tradeTime.Add(time.Duration(s.tradeCounts[pair]) * time.Nanosecond)
+	}
+	s.lastTradeTimes[pair] = tradeTime
+	s.tradeCounts[pair] = 0
+	return tradeTime
 }
 
 func (s *KuCoinScraper) NormalizePair(pair dia.ExchangePair) (dia.ExchangePair, error) {
@@ -389,6 +273,13 @@ func (s *KuCoinScraper) Close() error {
 	if s.closed {
 		return errors.New("KuCoinScraper: Already closed")
 	}
+	s.clientsLock.Lock()
+	for _, client := range s.clients {
+		if err := client.Close(); err != nil {
+			log.Error(err)
+		}
+	}
+	s.clientsLock.Unlock()
 	close(s.shutdown)
 	<-s.shutdownDone
 	s.errorLock.RLock()
@@ -411,11 +302,31 @@ func (s *KuCoinScraper) ScrapePair(pair dia.ExchangePair) (PairScraper, error) {
 	ps := &KuCoinPairScraper{
 		parent: s,
 		pair:   pair,
+		client: s.clientWithCapacity(),
+	}
+	s.pairScrapers.Store(pair.ForeignName, ps)
+
+	if err := ps.client.Subscribe(pair); err != nil {
+		log.Errorf("subscribe to %s: %v", pair.ForeignName, err)
 	}
-	s.pairScrapers[pair.ForeignName] = ps
 	return ps, nil
 }
 
+// clientWithCapacity returns a client with less than kucoinPairsPerConnection pairs and opens a new
+// connection if all clients are full.
+func (s *KuCoinScraper) clientWithCapacity() *wsclient.Client {
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+	for _, client := range s.clients {
+		if len(client.Pairs()) < kucoinPairsPerConnection {
+			return client
+		}
+	}
+	client := s.newClient()
+	s.clients = append(s.clients, client)
+	return client
+}
+
 // FetchAvailablePairs returns all traded pairs on kucoin.
 func (s *KuCoinScraper) FetchAvailablePairs() (pairs []dia.ExchangePair, err error) {
 	response, err := s.apiService.Symbols("")
@@ -462,12 +373,12 @@ func (s *KuCoinScraper) FillSymbolData(symbol string) (asset dia.Asset, err erro
 type KuCoinPairScraper struct {
 	parent *KuCoinScraper
 	pair   dia.ExchangePair
+	client *wsclient.Client
 	closed bool
 }
 
 // Close stops listening for trades of the pair associated with s
 func (ps *KuCoinPairScraper) Close() error {
-	var err error
 	s := ps.parent
 	// if parent already errored, return early
 	s.errorLock.RLock()
@@ -479,10 +390,17 @@ func (ps *KuCoinPairScraper) Close() error {
 		return errors.New("KuCoinPairScraper: Already closed")
 	}
 
-	// TODO stop collection for the pair
-
+	s.pairScrapers.Delete(ps.pair.ForeignName)
 	ps.closed = true
-	return err
+	return ps.client.Unsubscribe(ps.pair)
+}
+
+// Resubscribe unsubscribes the pair and subscribes to it again.
+func (ps *KuCoinPairScraper) Resubscribe() (PairScraper, error) {
+	if err := ps.Close(); err != nil {
+		return nil, err
+	}
+	return ps.parent.ScrapePair(ps.pair)
 }
 
 // Channel returns a channel that can be used to receive trades
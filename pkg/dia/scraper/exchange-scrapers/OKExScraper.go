@@ -13,13 +13,19 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/wsclient"
 	models "github.com/diadata-org/diadata/pkg/model"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
 var _OKExSocketURL = "wss://ws.okex.com:8443/ws/v5/public"
 
+const (
+	// OKEx limits subscription requests to 4096 bytes.
+	okexPairsPerMessage   = 60
+	okexSubscribeInterval = 250 * time.Millisecond
+)
+
 //var _OKExSocketURL = url.URL{Scheme: "wss", Host: "real.okex.com:10441", Path: "/ws/v1", RawQuery: "compress=true"}
 
 type Response struct {
@@ -40,10 +46,12 @@ type OKEXArgs struct {
 	InstID  string `json:"instId"`
 }
 
+// OKExScraper subscribes to the trades channel of each scraped pair. Before, all SPOT instruments
+// were subscribed after the first reconnect and trades of pairs without pair scraper were dropped,
+// so only scraped pairs are subscribed now, which yields the same trades with less traffic.
 type OKExScraper struct {
-	wsClient *ws.Conn
+	wsClient *wsclient.Client
 	// signaling channels for session initialization and finishing
-	run          bool
 	shutdown     chan nothing
 	shutdownDone chan nothing
 	// error handling; to read error or closed, first acquire read lock
@@ -71,93 +79,44 @@ func NewOKExScraper(exchange dia.Exchange, scrape bool, relDB *models.RelDB) *OK
 		db:           relDB,
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_OKExSocketURL, nil)
-	if err != nil {
-		log.Error("dial:", err)
-	}
-
-	s.wsClient = SwConn
+	s.wsClient = wsclient.New(wsclient.Config{
+		Exchange:          exchange.Name,
+		URL:               _OKExSocketURL,
+		Subscribe:         okexSubscription("subscribe"),
+		SubscribeAll:      okexSubscriptions,
+		SubscribeInterval: okexSubscribeInterval,
+		Unsubscribe:       okexSubscription("unsubscribe"),
+		// OKEx closes connections without messages within 30 seconds.
+		Ping:         func() interface{} { return []byte("ping") },
+		PingInterval: 20 * time.Second,
+		ReadTimeout:  time.Minute,
+	})
 	if scrape {
 		go s.mainLoop()
 	}
 	return s
 }
 
-// Useful to reconnect to ws when the connection is down
-func (s *OKExScraper) reconnectToWS() {
-
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_OKExSocketURL, nil)
-
-	if err != nil {
-		log.Error("dial:", err)
+// okexSubscription returns a function which returns the message with operation @op on the trades channel of a pair.
+func okexSubscription(op string) func(pair dia.ExchangePair) interface{} {
+	return func(pair dia.ExchangePair) interface{} {
+		return &Subscribe{
+			OP:   op,
+			Args: []OKEXArgs{{Channel: "trades", InstID: pair.ForeignName}},
+		}
 	}
-
-	s.wsClient = SwConn
-}
-
-type OKEXMarket struct {
-	Alias     string `json:"alias"`
-	BaseCcy   string `json:"baseCcy"`
-	Category  string `json:"category"`
-	CtMult    string `json:"ctMult"`
-	CtType    string `json:"ctType"`
-	CtVal     string `json:"ctVal"`
-	CtValCcy  string `json:"ctValCcy"`
-	ExpTime   string `json:"expTime"`
-	InstID    string `json:"instId"`
-	InstType  string `json:"instType"`
-	Lever     string `json:"lever"`
-	ListTime  string `json:"listTime"`
-	LotSz     string `json:"lotSz"`
-	MinSz     string `json:"minSz"`
-	OptType   string `json:"optType"`
-	QuoteCcy  string `json:"quoteCcy"`
-	SettleCcy string `json:"settleCcy"`
-	State     string `json:"state"`
-	Stk       string `json:"stk"`
-	TickSz    string `json:"tickSz"`
-	Uly       string `json:"uly"`
 }
 
-type AllOKEXMarketResponse struct {
-	Code string       `json:"code"`
-	Data []OKEXMarket `json:"data"`
-	Msg  string       `json:"msg"`
-}
-
-// Subscribe again to all channels
-func (s *OKExScraper) subscribeToALL() {
-
-	var (
-		resp     AllOKEXMarketResponse
-		allPairs []OKEXArgs
-	)
-
-	b, _, err := utils.GetRequest("https://aws.okex.com/api/v5/public/instruments?instType=SPOT")
-	if err != nil {
-		log.Errorln("Error getting OKex market", err)
-	}
-
-	err = json.Unmarshal(b, &resp)
-	if err != nil {
-		log.Errorln("Error Unmarshalling OKex market json", err)
-	}
-
-	for _, v := range resp.Data {
-		allPairs = append(allPairs, OKEXArgs{Channel: "trades", InstID: v.InstID})
-	}
-
-	a := &Subscribe{
-		OP:   "subscribe",
-		Args: allPairs,
-	}
-
-	if err := s.wsClient.WriteJSON(a); err != nil {
-		log.Errorln(err.Error())
+// okexSubscriptions returns the messages which subscribe to the trades of all @pairs after connecting.
+func okexSubscriptions(pairs []dia.ExchangePair) (messages []interface{}) {
+	for _, batch := range wsclient.Batches(pairs, okexPairsPerMessage) {
+		message := &Subscribe{OP: "subscribe"}
+		for _, pair := range batch {
+			message.Args = append(message.Args, OKEXArgs{Channel: "trades", InstID: pair.ForeignName})
+		}
+		messages = append(messages, message)
 	}
-
+	return
 }
 
 type OKEXWSResponse struct {
@@ -177,77 +136,65 @@ type OKEXWSResponse struct {
 
 // runs in a goroutine until s is closed
 func (s *OKExScraper) mainLoop() {
+	s.wsClient.Run(s.handleMessage)
+	s.cleanup(errors.New("main loop terminated by Close()"))
+}
 
-	s.run = true
-	for s.run {
-		var message OKEXWSResponse
-		messageType, messageTemp, err := s.wsClient.ReadMessage()
-		if err != nil {
-			log.Warning("reconnect the scraping to ws, ", err, ":", message)
-			s.reconnectToWS()
-			s.subscribeToALL()
-		} else {
-			switch messageType {
-			case ws.TextMessage:
-				// no need uncompressed
-				err := json.Unmarshal(messageTemp, &message)
-				if err != nil {
-					log.Errorln("Error parsing response")
-				}
-				ps, ok := s.pairScrapers[message.Arg.InstID]
-
-				if ok && len(message.Data) > 0 {
-
-					f64PriceString := message.Data[0].Px
-					f64Price, err := strconv.ParseFloat(f64PriceString, 64)
-
-					if err == nil {
-
-						f64VolumeString := message.Data[0].Sz
-						f64Volume, err := strconv.ParseFloat(f64VolumeString, 64)
-
-						if err == nil {
-
-							ts, _ := strconv.ParseInt(message.Data[0].Ts, 10, 64)
-							timeStamp := time.Unix(int64(ts)/1e3, 0)
-							if message.Data[0].Side == "sell" {
-								f64Volume = -f64Volume
-							}
-
-							exchangepair, err := s.db.GetExchangePairCache(s.exchangeName, message.Arg.InstID)
-							if err != nil {
-								log.Error(err)
-							}
-
-							t := &dia.Trade{
-								Symbol:         ps.pair.Symbol,
-								Pair:           message.Arg.InstID,
-								Price:          f64Price,
-								Volume:         f64Volume,
-								Time:           timeStamp,
-								ForeignTradeID: message.Data[0].TradeID,
-								Source:         s.exchangeName,
-								VerifiedPair:   exchangepair.Verified,
-								BaseToken:      exchangepair.UnderlyingPair.BaseToken,
-								QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
-							}
-							if exchangepair.Verified {
-								log.Infoln("Got verified trade", t)
-							}
-							ps.parent.chanTrades <- t
-						} else {
-							log.Errorf("parsing volume %v", f64VolumeString)
-						}
-
-					} else {
-						log.Errorf("parsing price %v", f64PriceString)
-					}
-				}
+func (s *OKExScraper) handleMessage(messageTemp []byte) {
+	if string(messageTemp) == "pong" {
+		return
+	}
+	var message OKEXWSResponse
+	err := json.Unmarshal(messageTemp, &message)
+	if err != nil {
+		log.Errorln("Error parsing response")
+		return
+	}
+	ps, ok := s.pairScrapers[message.Arg.InstID]
+	if !ok || len(message.Data) == 0 {
+		return
+	}
 
-			}
-		}
+	f64PriceString := message.Data[0].Px
+	f64Price, err := strconv.ParseFloat(f64PriceString, 64)
+	if err != nil {
+		log.Errorf("parsing price %v", f64PriceString)
+		return
 	}
-	s.cleanup(errors.New("main loop terminated by Close()"))
+	f64VolumeString := message.Data[0].Sz
+	f64Volume, err := strconv.ParseFloat(f64VolumeString, 64)
+	if err != nil {
+		log.Errorf("parsing volume %v", f64VolumeString)
+		return
+	}
+
+	ts, _ := strconv.ParseInt(message.Data[0].Ts, 10, 64)
+	timeStamp := time.Unix(int64(ts)/1e3, 0)
+	if message.Data[0].Side == "sell" {
+		f64Volume = -f64Volume
+	}
+
+	exchangepair, err := s.db.GetExchangePairCache(s.exchangeName, message.Arg.InstID)
+	if err != nil {
+		log.Error(err)
+	}
+
+	t := &dia.Trade{
+		Symbol:         ps.pair.Symbol,
+		Pair:           message.Arg.InstID,
+		Price:          f64Price,
+		Volume:         f64Volume,
+		Time:           timeStamp,
+		ForeignTradeID: message.Data[0].TradeID,
+		Source:         s.exchangeName,
+		VerifiedPair:   exchangepair.Verified,
+		BaseToken:      exchangepair.UnderlyingPair.BaseToken,
+		QuoteToken:     exchangepair.UnderlyingPair.QuoteToken,
+	}
+	if exchangepair.Verified {
+		log.Infoln("Got verified trade", t)
+	}
+	ps.parent.chanTrades <- t
 }
 
 func GzipDecode(in []byte) (content []byte, err error) {
@@ -284,8 +231,6 @@ func (s *OKExScraper) Close() error {
 	}
 
 	close(s.shutdown)
-	// Set false first to prevent reconnect
-	s.run = false
 	err := s.wsClient.Close()
 	if err != nil {
 		return err
@@ -318,15 +263,9 @@ func (s *OKExScraper) ScrapePair(pair dia.ExchangePair) (PairScraper, error) {
 
 	s.pairScrapers[pair.ForeignName] = ps
 
-	//a := &Subscribe{
-	//	OP:      "addChannel",
-	//	Args: "ok_sub_spot_" + strings.ToLower(pair.ForeignName) + "_deals",
-	//}
-	//
-	//subByteString := `{"channel":` + `"` + a.Channel + `"` + `,"event":` + `"` + a.Event + `"}`
-	//if err := s.wsClient.WriteMessage(ws.TextMessage, []byte(subByteString)); err != nil {
-	//	fmt.Println(err.Error())
-	//}
+	if err := s.wsClient.Subscribe(pair); err != nil {
+		log.Errorf("subscribe to %s: %v", pair.ForeignName, err)
+	}
 
 	return ps, nil
 }
@@ -422,7 +361,15 @@ type OKExPairScraper struct {
 // Close stops listening for trades of the pair associated with s
 func (ps *OKExPairScraper) Close() error {
 	ps.closed = true
-	return nil
+	return ps.parent.wsClient.Unsubscribe(ps.pair)
+}
+
+// Resubscribe unsubscribes the pair and subscribes to it again.
+func (ps *OKExPairScraper) Resubscribe() (PairScraper, error) {
+	if err := ps.Close(); err != nil {
+		return nil, err
+	}
+	return ps.parent.ScrapePair(ps.pair)
 }
 
 // Channel returns a channel that can be used to receive trades
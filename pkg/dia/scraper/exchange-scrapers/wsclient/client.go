@@ -0,0 +1,371 @@
+// Package wsclient provides a websocket client for exchange scrapers which reconnects with exponential
+// backoff, resubscribes all active pairs after reconnecting and keeps connections alive with heartbeats.
+package wsclient
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	ws "github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMinBackoff   = time.Second
+	defaultMaxBackoff   = 2 * time.Minute
+	defaultPingInterval = 30 * time.Second
+	defaultReadTimeout  = 2 * time.Minute
+	writeTimeout        = 10 * time.Second
+)
+
+var (
+	log = logrus.New()
+	// ErrClosed is returned by calls on a closed client.
+	ErrClosed = errors.New("websocket client closed")
+	// ErrNotConnected is returned by writes while the client is reconnecting.
+	ErrNotConnected = errors.New("websocket not connected")
+)
+
+// Config describes the websocket API of an exchange. Messages returned by its functions are written
+// as JSON, except for messages of type []byte, which are written verbatim as text messages.
+type Config struct {
+	// Exchange is the name of the exchange in logs and metrics.
+	Exchange string
+	URL      string
+	// Endpoint returns the URL of a new connection, for APIs which require a token per connection.
+	// If nil, URL is used.
+	Endpoint func() (string, error)
+	// Subscribe returns the message which subscribes to trades of a pair.
+	Subscribe func(pair dia.ExchangePair) interface{}
+	// SubscribeAll returns the messages which subscribe to trades of all pairs after connecting. If nil,
+	// Subscribe is written for each pair.
+	SubscribeAll func(pairs []dia.ExchangePair) []interface{}
+	// SubscribeInterval is the minimal duration between two (un)subscription messages, for APIs which
+	// limit the rate of incoming messages.
+	SubscribeInterval time.Duration
+	// Unsubscribe returns the message which unsubscribes from trades of a pair. If nil, unsubscribed
+	// pairs are just not resubscribed after the next reconnect.
+	Unsubscribe func(pair dia.ExchangePair) interface{}
+	// Ping returns the heartbeat message of the exchange's API, which is written every PingInterval.
+	// If nil, websocket ping frames are sent instead.
+	Ping         func() interface{}
+	PingInterval time.Duration
+	// ReadTimeout is the maximal duration without any message before the connection is considered
+	// dead and reconnected.
+	ReadTimeout time.Duration
+	// Backoff between reconnects doubles from MinBackoff up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Client is a websocket connection to an exchange with a set of subscribed pairs. Run reconnects after
+// the connection is lost and subscribes to the current set of pairs again. A change of the set by
+// Subscribe or Unsubscribe thus takes effect at the latest on the next reconnect, and callers need not
+// treat a failed (un)subscription message as fatal.
+type Client struct {
+	config Config
+	dialer ws.Dialer
+
+	mu     sync.Mutex
+	conn   *ws.Conn
+	pairs  map[string]dia.ExchangePair
+	closed bool
+	// writeLock serializes writes, as gorilla connections support only one concurrent writer.
+	writeLock sync.Mutex
+	// subscribeLock serializes (un)subscriptions to keep SubscribeInterval between them.
+	subscribeLock    sync.Mutex
+	lastSubscription time.Time
+	done             chan struct{}
+}
+
+// New returns a client for the API described by @config. The connection is established by Run.
+func New(config Config) *Client {
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = defaultMinBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.PingInterval <= 0 {
+		config.PingInterval = defaultPingInterval
+	}
+	if config.ReadTimeout <= 0 {
+		config.ReadTimeout = defaultReadTimeout
+	}
+	return &Client{
+		config: config,
+		dialer: ws.Dialer{HandshakeTimeout: 45 * time.Second},
+		pairs:  make(map[string]dia.ExchangePair),
+		done:   make(chan struct{}),
+	}
+}
+
+// Subscribe adds @pair to the subscribed pairs and subscribes to it if the client is connected.
+func (c *Client) Subscribe(pair dia.ExchangePair) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	c.pairs[pair.ForeignName] = pair
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil || c.config.Subscribe == nil {
+		return nil
+	}
+	return c.writeSubscription(conn, c.config.Subscribe(pair))
+}
+
+// Unsubscribe removes @pair from the subscribed pairs and unsubscribes from it if the client is connected.
+func (c *Client) Unsubscribe(pair dia.ExchangePair) error {
+	c.mu.Lock()
+	delete(c.pairs, pair.ForeignName)
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil || c.config.Unsubscribe == nil {
+		return nil
+	}
+	return c.writeSubscription(conn, c.config.Unsubscribe(pair))
+}
+
+// Pairs returns the subscribed pairs ordered by foreign name.
+func (c *Client) Pairs() (pairs []dia.ExchangePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, pair := range c.pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].ForeignName < pairs[j].ForeignName })
+	return
+}
+
+// Batches splits @pairs into batches of at most @size pairs, for APIs which limit the number of pairs per subscription.
+func Batches(pairs []dia.ExchangePair, size int) (batches [][]dia.ExchangePair) {
+	for len(pairs) > size {
+		batches = append(batches, pairs[:size])
+		pairs = pairs[size:]
+	}
+	if len(pairs) > 0 {
+		batches = append(batches, pairs)
+	}
+	return
+}
+
+// Write writes the message @v to the current connection.
+func (c *Client) Write(v interface{}) error {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if conn == nil {
+		return ErrNotConnected
+	}
+	return c.write(conn, v)
+}
+
+func (c *Client) write(conn *ws.Conn, v interface{}) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	err := conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err != nil {
+		return err
+	}
+	if message, ok := v.([]byte); ok {
+		return conn.WriteMessage(ws.TextMessage, message)
+	}
+	return conn.WriteJSON(v)
+}
+
+// writeSubscription writes the (un)subscription message @v at least SubscribeInterval after the previous one.
+func (c *Client) writeSubscription(conn *ws.Conn, v interface{}) error {
+	c.subscribeLock.Lock()
+	defer c.subscribeLock.Unlock()
+	if wait := c.config.SubscribeInterval - time.Since(c.lastSubscription); wait > 0 {
+		if !c.sleep(wait) {
+			return ErrClosed
+		}
+	}
+	c.lastSubscription = time.Now()
+	return c.write(conn, v)
+}
+
+// Run connects and passes all received messages to @handle until Close is called. Read errors and
+// missing messages within the read timeout lead to a reconnect, after which all subscribed pairs are
+// resubscribed. The backoff between reconnects doubles as long as connections fail before the first
+// message is received, so that a rejecting exchange is not dialed in a tight loop.
+func (c *Client) Run(handle func(message []byte)) {
+	backoff := c.config.MinBackoff
+	connected := false
+	for {
+		if connected {
+			metrics.WebsocketReconnects.WithLabelValues(c.config.Exchange).Inc()
+		}
+		conn, err := c.connect()
+		if err == ErrClosed {
+			return
+		}
+		if err == nil {
+			connected = true
+			var received bool
+			received, err = c.read(conn, handle)
+			if c.isClosed() {
+				return
+			}
+			if received {
+				log.Warnf("%s websocket disconnected: %v. Reconnect...", c.config.Exchange, err)
+				backoff = c.config.MinBackoff
+				continue
+			}
+		}
+		log.Errorf("connect to %s websocket: %v. Retry in %v", c.config.Exchange, err, backoff)
+		if !c.sleep(backoff) {
+			return
+		}
+		backoff *= 2
+		if backoff > c.config.MaxBackoff {
+			backoff = c.config.MaxBackoff
+		}
+	}
+}
+
+// connect dials the exchange and subscribes to all pairs.
+func (c *Client) connect() (*ws.Conn, error) {
+	if c.isClosed() {
+		return nil, ErrClosed
+	}
+	url := c.config.URL
+	if c.config.Endpoint != nil {
+		var err error
+		url, err = c.config.Endpoint()
+		if err != nil {
+			return nil, err
+		}
+	}
+	conn, _, err := c.dialer.Dial(url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		conn.Close()
+		return nil, ErrClosed
+	}
+	c.conn = conn
+	c.mu.Unlock()
+
+	var subscriptions []interface{}
+	if c.config.SubscribeAll != nil {
+		if pairs := c.Pairs(); len(pairs) > 0 {
+			subscriptions = c.config.SubscribeAll(pairs)
+		}
+	} else if c.config.Subscribe != nil {
+		for _, pair := range c.Pairs() {
+			subscriptions = append(subscriptions, c.config.Subscribe(pair))
+		}
+	}
+	for _, subscription := range subscriptions {
+		if err = c.writeSubscription(conn, subscription); err != nil {
+			c.disconnect(conn)
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// read passes messages of @conn to @handle until reading fails and sends heartbeats meanwhile.
+// It returns whether at least one message was received.
+func (c *Client) read(conn *ws.Conn, handle func(message []byte)) (received bool, err error) {
+	defer c.disconnect(conn)
+
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(c.config.ReadTimeout))
+	})
+	stopHeartbeat := make(chan struct{})
+	defer close(stopHeartbeat)
+	go c.heartbeat(conn, stopHeartbeat)
+
+	for {
+		err = conn.SetReadDeadline(time.Now().Add(c.config.ReadTimeout))
+		if err != nil {
+			return
+		}
+		var message []byte
+		_, message, err = conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		received = true
+		metrics.WebsocketMessages.WithLabelValues(c.config.Exchange).Inc()
+		handle(message)
+	}
+}
+
+func (c *Client) heartbeat(conn *ws.Conn, stop chan struct{}) {
+	ticker := time.NewTicker(c.config.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			var err error
+			if c.config.Ping != nil {
+				err = c.write(conn, c.config.Ping())
+			} else {
+				err = conn.WriteControl(ws.PingMessage, nil, time.Now().Add(writeTimeout))
+			}
+			if err != nil {
+				log.Warnf("send heartbeat to %s websocket: %v", c.config.Exchange, err)
+			}
+		}
+	}
+}
+
+func (c *Client) disconnect(conn *ws.Conn) {
+	c.mu.Lock()
+	if c.conn == conn {
+		c.conn = nil
+	}
+	c.mu.Unlock()
+	conn.Close()
+}
+
+// sleep waits for @d and returns false if the client was closed meanwhile.
+func (c *Client) sleep(d time.Duration) bool {
+	select {
+	case <-c.done:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func (c *Client) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// Close closes the connection and stops Run.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	c.closed = true
+	conn := c.conn
+	c.mu.Unlock()
+
+	close(c.done)
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package wsclient
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+type subscription struct {
+	Op   string `json:"op"`
+	Pair string `json:"pair,omitempty"`
+}
+
+func waitFor(t *testing.T, c <-chan struct{}, what string) {
+	select {
+	case <-c:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for " + what)
+	}
+}
+
+func TestClientResubscribesAfterReconnect(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+
+	client := New(Config{
+		Exchange:  "Test",
+		URL:       fs.URL(),
+		Subscribe: func(pair dia.ExchangePair) interface{} { return subscription{Op: "subscribe", Pair: pair.ForeignName} },
+		Unsubscribe: func(pair dia.ExchangePair) interface{} {
+			return subscription{Op: "unsubscribe", Pair: pair.ForeignName}
+		},
+		MinBackoff: 10 * time.Millisecond,
+	})
+	if err := client.Subscribe(dia.ExchangePair{ForeignName: "BTC-USD"}); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := make(chan string, 10)
+	runDone := make(chan struct{})
+	go func() {
+		client.Run(func(message []byte) { messages <- string(message) })
+		close(runDone)
+	}()
+
+	waitFor(t, fs.Connected(), "connection")
+	waitFor(t, fs.ReceivedNew(), "subscription")
+
+	if err := client.Subscribe(dia.ExchangePair{ForeignName: "ETH-USD"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, fs.ReceivedNew(), "subscription")
+
+	if err := fs.Send("trade"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case message := <-messages:
+		if message != "trade" {
+			t.Errorf("unexpected message %s", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+
+	if err := client.Unsubscribe(dia.ExchangePair{ForeignName: "ETH-USD"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, fs.ReceivedNew(), "unsubscription")
+
+	// Only the remaining pair is resubscribed after the connection is dropped.
+	fs.Drop()
+	waitFor(t, fs.Connected(), "reconnection")
+	waitFor(t, fs.ReceivedNew(), "resubscription")
+
+	expected := []string{
+		`{"op":"subscribe","pair":"BTC-USD"}`,
+		`{"op":"subscribe","pair":"ETH-USD"}`,
+		`{"op":"unsubscribe","pair":"ETH-USD"}`,
+		`{"op":"subscribe","pair":"BTC-USD"}`,
+	}
+	if received := fs.Received(); strings.Join(received, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected messages %v, got %v", expected, received)
+	}
+	if fs.Connections() != 2 {
+		t.Errorf("expected 2 connections, got %d", fs.Connections())
+	}
+
+	if err := client.Close(); err != nil {
+		t.Error(err)
+	}
+	select {
+	case <-runDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after Close")
+	}
+	if err := client.Subscribe(dia.ExchangePair{ForeignName: "BTC-USD"}); err != ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestClientReconnectsOnReadTimeout(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+
+	client := New(Config{
+		Exchange:     "Test",
+		URL:          fs.URL(),
+		Ping:         func() interface{} { return subscription{Op: "ping"} },
+		PingInterval: time.Hour,
+		ReadTimeout:  50 * time.Millisecond,
+		MinBackoff:   10 * time.Millisecond,
+	})
+	go client.Run(func([]byte) {})
+	defer client.Close()
+
+	// The server never sends messages, so the client reconnects.
+	waitFor(t, fs.Connected(), "connection")
+	waitFor(t, fs.Connected(), "reconnection")
+}
+
+func TestClientSendsHeartbeats(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+
+	client := New(Config{
+		Exchange:     "Test",
+		URL:          fs.URL(),
+		Ping:         func() interface{} { return subscription{Op: "ping"} },
+		PingInterval: 20 * time.Millisecond,
+	})
+	go client.Run(func([]byte) {})
+	defer client.Close()
+
+	waitFor(t, fs.ReceivedNew(), "heartbeat")
+	if received := fs.Received(); received[0] != `{"op":"ping"}` {
+		t.Errorf("unexpected heartbeat %s", received[0])
+	}
+}
+
+func TestClientBacksOffWithoutMessages(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+
+	minBackoff := 50 * time.Millisecond
+	client := New(Config{
+		Exchange:   "Test",
+		URL:        fs.URL(),
+		MinBackoff: minBackoff,
+	})
+	go client.Run(func([]byte) {})
+	defer client.Close()
+
+	// Connections which are dropped before the first message double the backoff.
+	var connects []time.Time
+	for i := 0; i < 3; i++ {
+		waitFor(t, fs.Connected(), "connection")
+		connects = append(connects, time.Now())
+		fs.Drop()
+	}
+	if gap := connects[1].Sub(connects[0]); gap < minBackoff {
+		t.Errorf("first reconnect after %v, want at least %v", gap, minBackoff)
+	}
+	if gap := connects[2].Sub(connects[1]); gap < 2*minBackoff {
+		t.Errorf("second reconnect after %v, want at least %v", gap, 2*minBackoff)
+	}
+
+	// A received message resets the backoff.
+	waitFor(t, fs.Connected(), "connection")
+	if err := fs.Send("trade"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	dropped := time.Now()
+	fs.Drop()
+	waitFor(t, fs.Connected(), "reconnection")
+	if gap := time.Since(dropped); gap >= 2*minBackoff {
+		t.Errorf("reconnect after a received message took %v", gap)
+	}
+}
+
+func TestClientSubscribesInBatches(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+
+	endpoints := 0
+	interval := 30 * time.Millisecond
+	client := New(Config{
+		Exchange: "Test",
+		Endpoint: func() (string, error) {
+			endpoints++
+			return fs.URL(), nil
+		},
+		Subscribe: func(pair dia.ExchangePair) interface{} { return subscription{Op: "subscribe", Pair: pair.ForeignName} },
+		SubscribeAll: func(pairs []dia.ExchangePair) (messages []interface{}) {
+			for _, batch := range Batches(pairs, 2) {
+				var names []string
+				for _, pair := range batch {
+					names = append(names, pair.ForeignName)
+				}
+				messages = append(messages, subscription{Op: "subscribe", Pair: strings.Join(names, ",")})
+			}
+			return
+		},
+		SubscribeInterval: interval,
+	})
+	for _, foreignName := range []string{"C-USD", "A-USD", "B-USD"} {
+		if err := client.Subscribe(dia.ExchangePair{ForeignName: foreignName}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	go client.Run(func([]byte) {})
+	defer client.Close()
+
+	waitFor(t, fs.Connected(), "connection")
+	waitFor(t, fs.ReceivedNew(), "subscription")
+	first := time.Now()
+	waitFor(t, fs.ReceivedNew(), "subscription")
+	if gap := time.Since(first); gap < interval-5*time.Millisecond {
+		t.Errorf("subscriptions %v apart, want at least %v", gap, interval)
+	}
+
+	// Pairs subscribed while connected are subscribed one by one.
+	if err := client.Subscribe(dia.ExchangePair{ForeignName: "D-USD"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, fs.ReceivedNew(), "subscription")
+
+	expected := []string{
+		`{"op":"subscribe","pair":"A-USD,B-USD"}`,
+		`{"op":"subscribe","pair":"C-USD"}`,
+		`{"op":"subscribe","pair":"D-USD"}`,
+	}
+	if received := fs.Received(); strings.Join(received, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected messages %v, got %v", expected, received)
+	}
+	if endpoints != 1 {
+		t.Errorf("endpoint requested %d times, want 1", endpoints)
+	}
+}
@@ -0,0 +1,117 @@
+package wsclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	ws "github.com/gorilla/websocket"
+)
+
+// FakeServer is a websocket server for tests of scrapers. It records all messages received from
+// clients and lets tests send messages to and drop the current connection.
+type FakeServer struct {
+	server   *httptest.Server
+	upgrader ws.Upgrader
+
+	mu          sync.Mutex
+	conn        *ws.Conn
+	connections int
+	received    [][]byte
+	connected   chan struct{}
+	receivedNew chan struct{}
+}
+
+// NewFakeServer starts a fake websocket server. It must be closed with Close.
+func NewFakeServer() *FakeServer {
+	fs := &FakeServer{
+		connected:   make(chan struct{}, 100),
+		receivedNew: make(chan struct{}, 1000),
+	}
+	fs.server = httptest.NewServer(http.HandlerFunc(fs.serve))
+	return fs
+}
+
+// URL returns the websocket URL of the server.
+func (fs *FakeServer) URL() string {
+	return "ws" + strings.TrimPrefix(fs.server.URL, "http")
+}
+
+func (fs *FakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := fs.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	fs.mu.Lock()
+	fs.conn = conn
+	fs.connections++
+	fs.mu.Unlock()
+	fs.connected <- struct{}{}
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		fs.mu.Lock()
+		fs.received = append(fs.received, message)
+		fs.mu.Unlock()
+		fs.receivedNew <- struct{}{}
+	}
+}
+
+// Connected returns a channel which receives a value for each new connection.
+func (fs *FakeServer) Connected() <-chan struct{} {
+	return fs.connected
+}
+
+// ReceivedNew returns a channel which receives a value for each message received from clients.
+func (fs *FakeServer) ReceivedNew() <-chan struct{} {
+	return fs.receivedNew
+}
+
+// Connections returns the number of connections since the start of the server.
+func (fs *FakeServer) Connections() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.connections
+}
+
+// Received returns all messages received from clients.
+func (fs *FakeServer) Received() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	var messages []string
+	for _, message := range fs.received {
+		messages = append(messages, strings.TrimSpace(string(message)))
+	}
+	return messages
+}
+
+// Send writes @message to the current connection.
+func (fs *FakeServer) Send(message string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.conn == nil {
+		return ErrNotConnected
+	}
+	return fs.conn.WriteMessage(ws.TextMessage, []byte(message))
+}
+
+// Drop closes the current connection without a close handshake, as a failing exchange would.
+func (fs *FakeServer) Drop() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.conn != nil {
+		fs.conn.Close()
+		fs.conn = nil
+	}
+}
+
+// Close stops the server.
+func (fs *FakeServer) Close() {
+	fs.Drop()
+	fs.server.CloseClientConnections()
+	fs.server.Close()
+}
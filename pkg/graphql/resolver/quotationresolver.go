@@ -0,0 +1,71 @@
+package resolver
+
+import (
+	"context"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type AssetQuotationResolver struct {
+	q models.AssetQuotationBatchItem
+}
+
+func (qr *AssetQuotationResolver) Symbol(ctx context.Context) (*string, error) {
+	return &qr.q.Symbol, nil
+}
+
+func (qr *AssetQuotationResolver) Name(ctx context.Context) (*string, error) {
+	return &qr.q.Name, nil
+}
+
+func (qr *AssetQuotationResolver) Address(ctx context.Context) (*string, error) {
+	return &qr.q.Address, nil
+}
+
+func (qr *AssetQuotationResolver) Blockchain(ctx context.Context) (*string, error) {
+	return &qr.q.Blockchain, nil
+}
+
+func (qr *AssetQuotationResolver) Price(ctx context.Context) (*float64, error) {
+	if qr.q.Error != "" {
+		return nil, nil
+	}
+	return &qr.q.Price, nil
+}
+
+func (qr *AssetQuotationResolver) VolumeYesterdayUSD(ctx context.Context) (*float64, error) {
+	if qr.q.Error != "" {
+		return nil, nil
+	}
+	return &qr.q.VolumeYesterdayUSD, nil
+}
+
+func (qr *AssetQuotationResolver) Time(ctx context.Context) (*graphql.Time, error) {
+	if qr.q.Error != "" {
+		return nil, nil
+	}
+	return &graphql.Time{Time: qr.q.Time}, nil
+}
+
+func (qr *AssetQuotationResolver) DataAgeSeconds(ctx context.Context) (*int32, error) {
+	if qr.q.Error != "" {
+		return nil, nil
+	}
+	age := int32(qr.q.DataAgeSeconds)
+	return &age, nil
+}
+
+func (qr *AssetQuotationResolver) Source(ctx context.Context) (*string, error) {
+	if qr.q.Error != "" {
+		return nil, nil
+	}
+	return &qr.q.Source, nil
+}
+
+func (qr *AssetQuotationResolver) Error(ctx context.Context) (*string, error) {
+	if qr.q.Error == "" {
+		return nil, nil
+	}
+	return &qr.q.Error, nil
+}
@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
@@ -15,6 +16,9 @@ import (
 
 var log = logrus.New()
 
+// Maximal number of assets in a GetAssetQuotations query.
+const maxBatchQuotations = 1000
+
 // Resolver is the root resolver
 type DiaResolver struct {
 	DS              models.DB
@@ -424,6 +428,38 @@ func (r *DiaResolver) GetVWALP(ctx context.Context, args struct {
 	return &VWALPResolver{q: response}, nil
 }
 
+// GetAssetQuotations returns quotations of a batch of assets, optionally at a historical time.
+// Assets which cannot be quoted get an error in their item instead of failing the whole query.
+func (r *DiaResolver) GetAssetQuotations(ctx context.Context, args struct {
+	Assets []BaseAssetInput
+	Time   graphql.NullTime
+}) (*[]*AssetQuotationResolver, error) {
+	if len(args.Assets) > maxBatchQuotations {
+		return nil, fmt.Errorf("number of assets must not exceed %d", maxBatchQuotations)
+	}
+	var assets []dia.Asset
+	for _, baseAsset := range args.Assets {
+		var asset dia.Asset
+		if baseAsset.Address.Value != nil {
+			asset.Address = *baseAsset.Address.Value
+		}
+		if baseAsset.BlockChain.Value != nil {
+			asset.Blockchain = *baseAsset.BlockChain.Value
+		}
+		assets = append(assets, asset)
+	}
+	var timestamp time.Time
+	if args.Time.Value != nil {
+		timestamp = args.Time.Value.Time
+	}
+
+	var qr []*AssetQuotationResolver
+	for _, item := range r.DS.GetAssetQuotationBatchItems(assets, timestamp, &r.RelDB) {
+		qr = append(qr, &AssetQuotationResolver{q: item})
+	}
+	return &qr, nil
+}
+
 // GetNFT returns an NFT by address, blockchain and token_id.
 func (r *DiaResolver) GetNFT(ctx context.Context, args struct {
 	Address    graphql.NullString
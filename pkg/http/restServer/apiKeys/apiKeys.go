@@ -0,0 +1,464 @@
+package apiKeys
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// Scopes of api keys. A key with ScopeAdmin is granted all other scopes.
+const (
+	ScopeRead           = "read"
+	ScopeWriteSupply    = "write:supply"
+	ScopeWriteQuotation = "write:quotation"
+	ScopeExport         = "export"
+	ScopeAdmin          = "admin"
+
+	apiKeyHeader = "X-API-KEY"
+	// Key records are re-read from postgres after this time, so that revocations take effect.
+	apiKeyCacheTTL       = time.Minute
+	apiKeyUsageFlushTime = time.Minute
+
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 24
+
+	// Context key under which the authenticated api key is stored.
+	ContextAPIKey = "apikey"
+)
+
+var (
+	errMissingAPIKey = errors.New("missing api key")
+	errInvalidAPIKey = errors.New("invalid api key")
+	// Without a key, the stored hashes of secrets could be brute-forced offline.
+	errMissingHashKey = errors.New("API_KEY_HASH_SECRET not set")
+)
+
+// apiKeyStore is the storage of api keys and their usage. It is implemented by models.RelDB.
+type apiKeyStore interface {
+	SetAPIKey(key dia.APIKey) (string, error)
+	GetAPIKey(prefix string) (dia.APIKey, error)
+	SetAPIKeyExpiry(prefix string, expiry time.Time) error
+	DeactivateAPIKey(prefix string) error
+	AddAPIKeyUsage(keyID string, endpoint string, day time.Time, requests int64) error
+	GetAPIKeyUsage(keyID string, starttime time.Time) ([]dia.APIKeyUsage, error)
+	IncrAPIKeyRequests(keyID string, month time.Time) (int64, error)
+}
+
+// Auth authenticates requests by api keys stored in postgres. It enforces the scopes,
+// monthly quotas and rate limits of the keys and accounts the usage per key and endpoint.
+// Monthly quotas are counted in redis, rate limits per instance.
+type Auth struct {
+	store apiKeyStore
+	// Key of the HMAC with which the secrets of api keys are hashed.
+	hashKey []byte
+	mu      sync.Mutex
+	keys    map[string]*apiKeyEntry
+	usage   map[apiKeyUsageKey]int64
+}
+
+type apiKeyEntry struct {
+	key     dia.APIKey
+	fetched time.Time
+	bucket  tokenBucket
+}
+
+type apiKeyUsageKey struct {
+	keyID    string
+	endpoint string
+	day      time.Time
+}
+
+// NewAuth returns an api key authenticator and starts flushing usage to postgres.
+// Secrets are hashed with the key in API_KEY_HASH_SECRET, which must be set.
+func NewAuth(relDB *models.RelDB) (*Auth, error) {
+	hashKey := utils.Getenv("API_KEY_HASH_SECRET", "")
+	if hashKey == "" {
+		return nil, errMissingHashKey
+	}
+	auth := newAuth(relDB, []byte(hashKey))
+	go func() {
+		for range time.Tick(apiKeyUsageFlushTime) {
+			auth.flushUsage()
+		}
+	}()
+	return auth, nil
+}
+
+func newAuth(store apiKeyStore, hashKey []byte) *Auth {
+	return &Auth{
+		store:   store,
+		hashKey: hashKey,
+		keys:    make(map[string]*apiKeyEntry),
+		usage:   make(map[apiKeyUsageKey]int64),
+	}
+}
+
+// Require returns a middleware which only admits requests with a valid api key with @scope.
+// If a request does not carry an api key and @fallback is not nil, the request is handed to @fallback,
+// which allows to keep other authentication methods such as jwt in place.
+func (auth *Auth) Require(scope string, fallback gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(apiKeyHeader) == "" && fallback != nil {
+			fallback(c)
+			return
+		}
+		auth.authenticate(c, scope)
+	}
+}
+
+// Optional returns a middleware which admits anonymous requests. Requests with an api key are
+// admitted only if the key is valid and has @scope. They are subject to the key's limits.
+func (auth *Auth) Optional(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(apiKeyHeader) == "" {
+			c.Next()
+			return
+		}
+		auth.authenticate(c, scope)
+	}
+}
+
+func (auth *Auth) authenticate(c *gin.Context, scope string) {
+	rawKey := c.GetHeader(apiKeyHeader)
+	if rawKey == "" {
+		abortAPIKey(c, http.StatusUnauthorized, errMissingAPIKey)
+		return
+	}
+	prefix, secret, ok := splitAPIKey(rawKey)
+	if !ok {
+		abortAPIKey(c, http.StatusUnauthorized, errInvalidAPIKey)
+		return
+	}
+
+	now := time.Now()
+	entry, err := auth.getEntry(prefix, now)
+	if err != nil {
+		abortAPIKey(c, http.StatusUnauthorized, errInvalidAPIKey)
+		return
+	}
+
+	auth.mu.Lock()
+	key := entry.key
+	auth.mu.Unlock()
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(auth.hashSecret(secret))) != 1 {
+		abortAPIKey(c, http.StatusUnauthorized, errInvalidAPIKey)
+		return
+	}
+
+	if !key.Active || (!key.Expiry.IsZero() && now.After(key.Expiry)) {
+		abortAPIKey(c, http.StatusUnauthorized, errors.New("api key expired or revoked"))
+		return
+	}
+	if !HasScope(key.Scopes, scope) {
+		abortAPIKey(c, http.StatusForbidden, errors.New("api key lacks scope "+scope))
+		return
+	}
+
+	auth.mu.Lock()
+	if !entry.bucket.allow(now) {
+		wait := entry.bucket.wait()
+		auth.mu.Unlock()
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		abortAPIKey(c, http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+		return
+	}
+	auth.mu.Unlock()
+
+	if key.MonthlyQuota > 0 {
+		requests, err := auth.store.IncrAPIKeyRequests(key.ID, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			// Do not lock out all keys with a quota when redis is unavailable.
+			log.Error("count requests of api key: ", err)
+		} else if requests > key.MonthlyQuota {
+			abortAPIKey(c, http.StatusTooManyRequests, errors.New("monthly quota exhausted"))
+			return
+		}
+	}
+
+	auth.mu.Lock()
+	auth.usage[apiKeyUsageKey{keyID: key.ID, endpoint: c.FullPath(), day: now.UTC().Truncate(24 * time.Hour)}]++
+	auth.mu.Unlock()
+
+	c.Set(ContextAPIKey, key)
+	c.Next()
+}
+
+// getEntry returns the cached entry of the key with @prefix and refreshes it from postgres if necessary.
+func (auth *Auth) getEntry(prefix string, now time.Time) (*apiKeyEntry, error) {
+	auth.mu.Lock()
+	entry, ok := auth.keys[prefix]
+	auth.mu.Unlock()
+	if ok && now.Sub(entry.fetched) < apiKeyCacheTTL {
+		return entry, nil
+	}
+
+	key, err := auth.store.GetAPIKey(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+	if ok {
+		entry.key = key
+		entry.fetched = now
+		entry.bucket.setLimit(key.RateLimit, key.RateBurst)
+		return entry, nil
+	}
+
+	entry = &apiKeyEntry{key: key, fetched: now}
+	entry.bucket.setLimit(key.RateLimit, key.RateBurst)
+	auth.keys[prefix] = entry
+	return entry, nil
+}
+
+func (auth *Auth) flushUsage() {
+	auth.mu.Lock()
+	usage := auth.usage
+	auth.usage = make(map[apiKeyUsageKey]int64)
+	auth.mu.Unlock()
+
+	for k, requests := range usage {
+		err := auth.store.AddAPIKeyUsage(k.keyID, k.endpoint, k.day, requests)
+		if err != nil {
+			log.Errorf("add usage of api key %s: %v", k.keyID, err)
+		}
+	}
+}
+
+// CreateAPIKey creates a new api key. The key is returned only once in the response.
+func (auth *Auth) CreateAPIKey(c *gin.Context) {
+	type request struct {
+		Owner        string    `json:"owner" binding:"required"`
+		Scopes       []string  `json:"scopes" binding:"required"`
+		MonthlyQuota int64     `json:"monthlyQuota"`
+		RateLimit    float64   `json:"rateLimit"`
+		RateBurst    int       `json:"rateBurst"`
+		Expiry       time.Time `json:"expiry"`
+	}
+	var req request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortAPIKey(c, http.StatusBadRequest, err)
+		return
+	}
+	for _, scope := range req.Scopes {
+		switch scope {
+		case ScopeRead, ScopeWriteSupply, ScopeWriteQuotation, ScopeExport, ScopeAdmin:
+		default:
+			abortAPIKey(c, http.StatusBadRequest, errors.New("unknown scope "+scope))
+			return
+		}
+	}
+	key := dia.APIKey{
+		Owner:        req.Owner,
+		Scopes:       req.Scopes,
+		MonthlyQuota: req.MonthlyQuota,
+		RateLimit:    req.RateLimit,
+		RateBurst:    req.RateBurst,
+		Active:       true,
+		Expiry:       req.Expiry,
+	}
+	if key.RateLimit <= 0 {
+		key.RateLimit = 10
+	}
+	if key.RateBurst <= 0 {
+		key.RateBurst = 20
+	}
+	auth.issueAPIKey(c, key)
+}
+
+// RotateAPIKey replaces the key with the given prefix by a new key with the same settings.
+// The old key stays valid for gracePeriod seconds, 24h per default.
+func (auth *Auth) RotateAPIKey(c *gin.Context) {
+	gracePeriod, err := strconv.ParseInt(c.DefaultQuery("gracePeriod", "86400"), 10, 64)
+	if err != nil {
+		abortAPIKey(c, http.StatusBadRequest, err)
+		return
+	}
+	oldKey, err := auth.store.GetAPIKey(c.Param("prefix"))
+	if err != nil || !oldKey.Active {
+		abortAPIKey(c, http.StatusNotFound, errors.New("api key not found"))
+		return
+	}
+
+	newKey := oldKey
+	newKey.RotatedFrom = oldKey.ID
+	expiry := time.Now().Add(time.Duration(gracePeriod) * time.Second)
+	if !oldKey.Expiry.IsZero() && oldKey.Expiry.Before(expiry) {
+		expiry = oldKey.Expiry
+	}
+	if !auth.issueAPIKey(c, newKey) {
+		return
+	}
+	err = auth.store.SetAPIKeyExpiry(oldKey.Prefix, expiry)
+	if err != nil {
+		log.Errorf("set expiry of rotated api key %s: %v", oldKey.Prefix, err)
+	}
+	auth.invalidate(oldKey.Prefix)
+}
+
+// RevokeAPIKey deactivates the key with the given prefix.
+func (auth *Auth) RevokeAPIKey(c *gin.Context) {
+	prefix := c.Param("prefix")
+	err := auth.store.DeactivateAPIKey(prefix)
+	if err != nil {
+		abortAPIKey(c, http.StatusInternalServerError, err)
+		return
+	}
+	auth.invalidate(prefix)
+	c.JSON(http.StatusOK, gin.H{"prefix": prefix, "active": false})
+}
+
+// GetAPIKeyUsage returns the number of requests per endpoint and day of the key with the given prefix
+// over the last @days days, 30 per default.
+func (auth *Auth) GetAPIKeyUsage(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		abortAPIKey(c, http.StatusBadRequest, err)
+		return
+	}
+	key, err := auth.store.GetAPIKey(c.Param("prefix"))
+	if err != nil {
+		abortAPIKey(c, http.StatusNotFound, errors.New("api key not found"))
+		return
+	}
+	usage, err := auth.store.GetAPIKeyUsage(key.ID, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		abortAPIKey(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"prefix": key.Prefix, "owner": key.Owner, "usage": usage})
+}
+
+// issueAPIKey generates the secret of @key, stores it and sends the full key.
+func (auth *Auth) issueAPIKey(c *gin.Context, key dia.APIKey) bool {
+	prefix, err := randomHex(apiKeyPrefixBytes)
+	if err != nil {
+		abortAPIKey(c, http.StatusInternalServerError, err)
+		return false
+	}
+	secret, err := randomHex(apiKeySecretBytes)
+	if err != nil {
+		abortAPIKey(c, http.StatusInternalServerError, err)
+		return false
+	}
+	key.Prefix = prefix
+	key.Hash = auth.hashSecret(secret)
+	key.ID, err = auth.store.SetAPIKey(key)
+	if err != nil {
+		abortAPIKey(c, http.StatusInternalServerError, err)
+		return false
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"key":          prefix + "." + secret,
+		"prefix":       prefix,
+		"owner":        key.Owner,
+		"scopes":       key.Scopes,
+		"monthlyQuota": key.MonthlyQuota,
+		"rateLimit":    key.RateLimit,
+		"rateBurst":    key.RateBurst,
+		"expiry":       key.Expiry,
+	})
+	return true
+}
+
+func (auth *Auth) invalidate(prefix string) {
+	auth.mu.Lock()
+	delete(auth.keys, prefix)
+	auth.mu.Unlock()
+}
+
+func abortAPIKey(c *gin.Context, code int, err error) {
+	c.AbortWithStatusJSON(code, gin.H{
+		"code":    code,
+		"message": err.Error(),
+	})
+}
+
+// splitAPIKey splits a key of the form <prefix>.<secret>.
+func splitAPIKey(rawKey string) (prefix string, secret string, ok bool) {
+	parts := strings.SplitN(rawKey, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return
+	}
+	return parts[0], parts[1], true
+}
+
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// hashSecret returns the hex encoded HMAC-SHA256 of @secret. As secrets are random, a fast hash suffices
+// and keeps verification cheap enough to be done on every request.
+func (auth *Auth) hashSecret(secret string) string {
+	mac := hmac.New(sha256.New, auth.hashKey)
+	mac.Write([]byte(secret))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// tokenBucket admits requests at a sustained rate of rate per second with bursts up to burst requests.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) setLimit(rate float64, burst int) {
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	}
+	b.rate = rate
+	b.burst = float64(burst)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// wait returns the time until the next token is available.
+func (b *tokenBucket) wait() time.Duration {
+	if b.rate <= 0 || b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
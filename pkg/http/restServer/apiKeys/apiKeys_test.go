@@ -0,0 +1,179 @@
+package apiKeys
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeAPIKeyStore keeps api keys in memory. requests is shared by all authenticators
+// using the store, as the redis counter is shared by all instances of the api.
+type fakeAPIKeyStore struct {
+	keys     map[string]dia.APIKey
+	requests map[string]int64
+	lookups  int
+}
+
+func (s *fakeAPIKeyStore) SetAPIKey(key dia.APIKey) (string, error) {
+	key.ID = "id-" + key.Prefix
+	s.keys[key.Prefix] = key
+	return key.ID, nil
+}
+
+func (s *fakeAPIKeyStore) GetAPIKey(prefix string) (dia.APIKey, error) {
+	s.lookups++
+	key, ok := s.keys[prefix]
+	if !ok {
+		return dia.APIKey{}, errors.New("no rows in result set")
+	}
+	return key, nil
+}
+
+func (s *fakeAPIKeyStore) SetAPIKeyExpiry(prefix string, expiry time.Time) error {
+	key := s.keys[prefix]
+	key.Expiry = expiry
+	s.keys[prefix] = key
+	return nil
+}
+
+func (s *fakeAPIKeyStore) DeactivateAPIKey(prefix string) error {
+	key := s.keys[prefix]
+	key.Active = false
+	s.keys[prefix] = key
+	return nil
+}
+
+func (s *fakeAPIKeyStore) AddAPIKeyUsage(keyID string, endpoint string, day time.Time, requests int64) error {
+	return nil
+}
+
+func (s *fakeAPIKeyStore) GetAPIKeyUsage(keyID string, starttime time.Time) ([]dia.APIKeyUsage, error) {
+	return nil, nil
+}
+
+func (s *fakeAPIKeyStore) IncrAPIKeyRequests(keyID string, month time.Time) (int64, error) {
+	s.requests[keyID+month.Format("2006-01")]++
+	return s.requests[keyID+month.Format("2006-01")], nil
+}
+
+func newTestAPIKeyAuth(keys ...dia.APIKey) (*Auth, *fakeAPIKeyStore) {
+	store := &fakeAPIKeyStore{keys: make(map[string]dia.APIKey), requests: make(map[string]int64)}
+	auth := newAuth(store, []byte("test"))
+	for _, key := range keys {
+		key.Hash = auth.hashSecret("secret")
+		key.ID, _ = store.SetAPIKey(key)
+	}
+	return auth, store
+}
+
+func doAPIKeyRequest(handler gin.HandlerFunc, apiKey string) int {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/test", handler, func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	if apiKey != "" {
+		req.Header.Set(apiKeyHeader, apiKey)
+	}
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	auth, _ := newTestAPIKeyAuth(
+		dia.APIKey{Prefix: "reader", Scopes: []string{ScopeRead}, RateLimit: 100, RateBurst: 100, Active: true},
+		dia.APIKey{Prefix: "admin", Scopes: []string{ScopeAdmin}, RateLimit: 100, RateBurst: 100, Active: true},
+		dia.APIKey{Prefix: "revoked", Scopes: []string{ScopeRead}, RateLimit: 100, RateBurst: 100, Active: false},
+		dia.APIKey{Prefix: "expired", Scopes: []string{ScopeRead}, RateLimit: 100, RateBurst: 100, Active: true, Expiry: time.Now().Add(-time.Hour)},
+	)
+
+	cases := []struct {
+		apiKey string
+		scope  string
+		code   int
+	}{
+		{"reader.secret", ScopeRead, http.StatusOK},
+		{"reader.secret", ScopeExport, http.StatusForbidden},
+		{"admin.secret", ScopeExport, http.StatusOK},
+		{"reader.wrong", ScopeRead, http.StatusUnauthorized},
+		{"unknown.secret", ScopeRead, http.StatusUnauthorized},
+		{"reader", ScopeRead, http.StatusUnauthorized},
+		{"revoked.secret", ScopeRead, http.StatusUnauthorized},
+		{"expired.secret", ScopeRead, http.StatusUnauthorized},
+		{"", ScopeRead, http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		if code := doAPIKeyRequest(auth.Require(c.scope, nil), c.apiKey); code != c.code {
+			t.Errorf("key %q with scope %s: got status %d, want %d", c.apiKey, c.scope, code, c.code)
+		}
+	}
+
+	// Anonymous requests pass optional authentication, invalid keys do not.
+	if code := doAPIKeyRequest(auth.Optional(ScopeRead), ""); code != http.StatusOK {
+		t.Errorf("anonymous request: got status %d, want %d", code, http.StatusOK)
+	}
+	if code := doAPIKeyRequest(auth.Optional(ScopeRead), "reader.wrong"); code != http.StatusUnauthorized {
+		t.Errorf("invalid key on optional route: got status %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestAPIKeyQuota(t *testing.T) {
+	key := dia.APIKey{Prefix: "quota", Scopes: []string{ScopeRead}, MonthlyQuota: 3, RateLimit: 100, RateBurst: 100, Active: true}
+	auth, store := newTestAPIKeyAuth(key)
+	// A second instance of the api shares the quota counter.
+	other := newAuth(store, []byte("test"))
+
+	codes := []int{
+		doAPIKeyRequest(auth.Require(ScopeRead, nil), "quota.secret"),
+		doAPIKeyRequest(other.Require(ScopeRead, nil), "quota.secret"),
+		doAPIKeyRequest(auth.Require(ScopeRead, nil), "quota.secret"),
+		doAPIKeyRequest(other.Require(ScopeRead, nil), "quota.secret"),
+	}
+	want := []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i := range codes {
+		if codes[i] != want[i] {
+			t.Errorf("request %d: got status %d, want %d", i, codes[i], want[i])
+		}
+	}
+}
+
+func TestAPIKeyRateLimit(t *testing.T) {
+	auth, store := newTestAPIKeyAuth(dia.APIKey{Prefix: "rate", Scopes: []string{ScopeRead}, RateLimit: 1, RateBurst: 2, Active: true})
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		codes = append(codes, doAPIKeyRequest(auth.Require(ScopeRead, nil), "rate.secret"))
+	}
+	want := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i := range codes {
+		if codes[i] != want[i] {
+			t.Errorf("request %d: got status %d, want %d", i, codes[i], want[i])
+		}
+	}
+	if store.lookups != 1 {
+		t.Errorf("got %d lookups of the key, want 1", store.lookups)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	var b tokenBucket
+	b.setLimit(2, 2)
+	now := time.Now()
+	if !b.allow(now) || !b.allow(now) {
+		t.Fatal("burst not admitted")
+	}
+	if b.allow(now) {
+		t.Fatal("request beyond burst admitted")
+	}
+	if wait := b.wait(); wait != 500*time.Millisecond {
+		t.Errorf("got wait %v, want 500ms", wait)
+	}
+	if !b.allow(now.Add(500 * time.Millisecond)) {
+		t.Error("request after refill not admitted")
+	}
+}
@@ -0,0 +1,167 @@
+package diaApi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxDerivativesTimerange is the maximal time range of requests for derivatives data.
+const maxDerivativesTimerange = time.Duration(24*7) * time.Hour
+
+// derivativesTimerange returns the time range given by the query parameters starttime and endtime.
+// ok is false and status 400 is sent if a time is not a Unix timestamp or the range exceeds 7 days.
+func derivativesTimerange(c *gin.Context, defaultRange time.Duration) (starttime time.Time, endtime time.Time, ok bool) {
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), defaultRange)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if endtime.Sub(starttime) > maxDerivativesTimerange {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("time range must not exceed 7 days"))
+		return
+	}
+	return starttime, endtime, true
+}
+
+// GetFuturesTrades returns the trades of a futures or perpetual contract in the time range given by
+// the query parameters starttime and endtime. Default is the last hour.
+func (env *Env) GetFuturesTrades(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	exchange, instrument := c.Param("exchange"), c.Param("instrument")
+	starttime, endtime, ok := derivativesTimerange(c, time.Hour)
+	if !ok {
+		return
+	}
+	trades, err := env.DataStore.GetFuturesTrades(exchange, instrument, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(trades) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no trades for "+instrument+" on "+exchange))
+		return
+	}
+	c.JSON(http.StatusOK, trades)
+}
+
+// GetFundingRates returns the funding rates of a perpetual contract in the time range given by
+// the query parameters starttime and endtime. Default is the last 24 hours.
+func (env *Env) GetFundingRates(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	exchange, instrument := c.Param("exchange"), c.Param("instrument")
+	starttime, endtime, ok := derivativesTimerange(c, time.Duration(24)*time.Hour)
+	if !ok {
+		return
+	}
+	rates, err := env.DataStore.GetFundingRates(exchange, instrument, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(rates) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no funding rates for "+instrument+" on "+exchange))
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+// GetOpenInterest returns the open interest of a derivative instrument in the time range given by
+// the query parameters starttime and endtime. Default is the last hour.
+func (env *Env) GetOpenInterest(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	exchange, instrument := c.Param("exchange"), c.Param("instrument")
+	starttime, endtime, ok := derivativesTimerange(c, time.Hour)
+	if !ok {
+		return
+	}
+	openInterests, err := env.DataStore.GetOpenInterest(exchange, instrument, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(openInterests) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no open interest for "+instrument+" on "+exchange))
+		return
+	}
+	c.JSON(http.StatusOK, openInterests)
+}
+
+// GetDerivativePrices returns mark and index prices of a derivative instrument in the time range given by
+// the query parameters starttime and endtime. Default is the last hour.
+func (env *Env) GetDerivativePrices(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	exchange, instrument := c.Param("exchange"), c.Param("instrument")
+	starttime, endtime, ok := derivativesTimerange(c, time.Hour)
+	if !ok {
+		return
+	}
+	prices, err := env.DataStore.GetDerivativePrices(exchange, instrument, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(prices) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no prices for "+instrument+" on "+exchange))
+		return
+	}
+	c.JSON(http.StatusOK, prices)
+}
+
+// GetOptionOrderbooks returns the latest order book snapshot of all unexpired options on an underlying
+// within the time range given by the query parameters starttime and endtime. Default is the last 10 minutes.
+func (env *Env) GetOptionOrderbooks(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	exchange, underlying := c.Param("exchange"), c.Param("underlying")
+	starttime, endtime, ok := derivativesTimerange(c, time.Duration(10)*time.Minute)
+	if !ok {
+		return
+	}
+	snapshots, err := env.DataStore.GetOptionOrderbookSnapshots(exchange, underlying, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(snapshots) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no option order books for "+underlying+" on "+exchange))
+		return
+	}
+	c.JSON(http.StatusOK, snapshots)
+}
+
+// GetCVIIndex returns the values of the crypto volatility index of the underlying given by the query
+// parameter symbol (default BTC) in the time range given by starttime and endtime. Default is the last 24 hours.
+func (env *Env) GetCVIIndex(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	underlying := c.DefaultQuery("symbol", "BTC")
+	starttime, endtime, ok := derivativesTimerange(c, time.Duration(24)*time.Hour)
+	if !ok {
+		return
+	}
+	cvis, err := env.DataStore.GetCVI(underlying, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(cvis) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no CVI values for "+underlying))
+		return
+	}
+	c.JSON(http.StatusOK, cvis)
+}
@@ -14,8 +14,13 @@ import (
 	"time"
 
 	filters "github.com/diadata-org/diadata/internal/pkg/filtersBlockService"
+	markethours "github.com/diadata-org/diadata/internal/pkg/marketHours"
+	ratederivatives "github.com/diadata-org/diadata/internal/pkg/rateDerivatives"
+	stockscrapers "github.com/diadata-org/diadata/internal/pkg/stock-scrapers"
 
 	"github.com/diadata-org/diadata/pkg/dia"
+	fiathelper "github.com/diadata-org/diadata/pkg/dia/helpers/fiatHelper"
+	queryhelper "github.com/diadata-org/diadata/pkg/dia/helpers/queryHelper"
 	"github.com/diadata-org/diadata/pkg/http/restApi"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/diadata-org/diadata/pkg/utils"
@@ -25,6 +30,13 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// Maximal number of assets in a request to PostAssetQuotations.
+	maxBatchQuotations = 1000
+	// Maximal time range of requests for stock quotations.
+	maxStockTimerange = time.Duration(24*30) * time.Hour
+)
+
 var (
 	DECIMALS_CACHE = make(map[dia.Asset]uint8)
 	ASSET_CACHE    = make(map[string]dia.Asset)
@@ -129,6 +141,12 @@ func (env *Env) SetQuotation(c *gin.Context) {
 	}
 }
 
+// Sources of the quotations returned by GetAssetQuotation.
+const (
+	quotationSourceMarket    = "market"
+	quotationSourceFairValue = "fairvalue"
+)
+
 // GetAssetQuotation returns quotation of asset with highest market cap among
 // all assets with symbol ticker @symbol.
 func (env *Env) GetAssetQuotation(c *gin.Context) {
@@ -138,6 +156,10 @@ func (env *Env) GetAssetQuotation(c *gin.Context) {
 
 	blockchain := c.Param("blockchain")
 	address := makeAddressEIP55Compliant(c.Param("address"), blockchain)
+	currency, ok := fiatCurrency(c)
+	if !ok {
+		return
+	}
 
 	var (
 		err               error
@@ -153,19 +175,38 @@ func (env *Env) GetAssetQuotation(c *gin.Context) {
 		return
 	}
 
-	// Get quotation for asset.
-	quotation, err := env.DataStore.GetAssetQuotation(asset, timestamp)
-	if err != nil {
-		restApi.SendError(c, http.StatusNotFound, err)
+	// Get quotation for asset. The fair value of synthetic assets derived from their collateral is
+	// returned for the query parameter source=fairvalue instead of the market price from trades.
+	var quotation *models.AssetQuotation
+	switch c.DefaultQuery("source", quotationSourceMarket) {
+	case quotationSourceMarket:
+		quotation, err = env.DataStore.GetAssetQuotation(asset, timestamp)
+		if err != nil {
+			restApi.SendError(c, http.StatusNotFound, err)
+			return
+		}
+		quotationYesterday, err := env.DataStore.GetAssetQuotation(asset, timestamp.AddDate(0, 0, -1))
+		if err != nil {
+			log.Warn("get quotation yesterday: ", err)
+		} else {
+			quotationExtended.PriceYesterday = quotationYesterday.Price
+		}
+	case quotationSourceFairValue:
+		quotation, err = env.DataStore.GetSynthAssetQuotationCache(asset)
+		if err != nil {
+			restApi.SendError(c, http.StatusNotFound, err)
+			return
+		}
+		valuations, err := env.DataStore.GetSynthAssetValuations(asset.Blockchain, asset.Address, timestamp.AddDate(0, 0, -2), timestamp.AddDate(0, 0, -1))
+		if err != nil {
+			log.Warn("get fair value yesterday: ", err)
+		} else if len(valuations) > 0 {
+			quotationExtended.PriceYesterday = valuations[0].FairValue
+		}
+	default:
+		restApi.SendError(c, http.StatusBadRequest, errors.New("source must be market or fairvalue"))
 		return
 	}
-
-	quotationYesterday, err := env.DataStore.GetAssetQuotation(asset, timestamp.AddDate(0, 0, -1))
-	if err != nil {
-		log.Warn("get quotation yesterday: ", err)
-	} else {
-		quotationExtended.PriceYesterday = quotationYesterday.Price
-	}
 	volumeYesterday, err := env.RelDB.GetAssetVolume24H(asset)
 	if err != nil {
 		log.Warn("get volume yesterday: ", err)
@@ -182,10 +223,144 @@ func (env *Env) GetAssetQuotation(c *gin.Context) {
 	quotationExtended.Time = quotation.Time
 	quotationExtended.Source = quotation.Source
 
+	if currency != fiathelper.USD {
+		if err := env.convertQuotation(&quotationExtended, currency, timestamp); err != nil {
+			restApi.SendError(c, http.StatusNotFound, err)
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, quotationExtended)
 
 }
 
+// GetHistoricalQuotation returns the price of an asset at an arbitrary point in time.
+// The price is computed from the filter points around the requested time according to the query parameter policy:
+// lastBefore (default), nearest, linear or dailyClose. For dailyClose, the day is taken in the timezone given by
+// the query parameter timezone, default is UTC.
+func (env *Env) GetHistoricalQuotation(c *gin.Context) {
+	if !validateInputParamsExcept(c, "timezone") {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := makeAddressEIP55Compliant(c.Param("address"), blockchain)
+	policy := c.DefaultQuery("policy", queryhelper.PolicyLastBefore)
+	filter := c.DefaultQuery("filter", dia.FilterKing)
+
+	if !queryhelper.ValidPricePolicy(policy) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("policy must be one of lastBefore, nearest, linear, dailyClose"))
+		return
+	}
+	timestampInt, err := strconv.ParseInt(c.Query("timestamp"), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("timestamp must be a unix timestamp"))
+		return
+	}
+	maxDistanceInt, err := strconv.ParseInt(c.DefaultQuery("maxDistance", "86400"), 10, 64)
+	if err != nil || maxDistanceInt <= 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("maxDistance must be a positive number of seconds"))
+		return
+	}
+	location, err := time.LoadLocation(c.DefaultQuery("timezone", "UTC"))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("unknown timezone"))
+		return
+	}
+
+	timestamp := time.Unix(timestampInt, 0)
+	if policy == queryhelper.PolicyDailyClose {
+		timestamp = queryhelper.DailyCloseTime(timestamp, location)
+	}
+	if timestamp.After(time.Now()) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("requested time is in the future"))
+		return
+	}
+
+	asset, err := env.RelDB.GetAsset(address, blockchain)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+
+	before, after, err := env.DataStore.GetFilterPointsAround(filter, asset, timestamp, time.Duration(maxDistanceInt)*time.Second)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	price, points, err := queryhelper.PriceAt(policy, timestamp, before, after)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+
+	quotation := models.HistoricalQuotation{
+		Symbol:     asset.Symbol,
+		Name:       asset.Name,
+		Address:    asset.Address,
+		Blockchain: asset.Blockchain,
+		Price:      price,
+		Time:       timestamp,
+		Policy:     policy,
+		Filter:     filter,
+		Source:     dia.Diadata,
+	}
+	for _, point := range points {
+		quotation.PointTimes = append(quotation.PointTimes, point.Time)
+		age := int64(math.Abs(timestamp.Sub(point.Time).Seconds()))
+		if age > quotation.DataAgeSeconds {
+			quotation.DataAgeSeconds = age
+		}
+	}
+	c.JSON(http.StatusOK, quotation)
+}
+
+// PostAssetQuotations returns quotations of a batch of assets given by blockchain and address.
+// If a unix timestamp is given, historical quotations at this time are returned.
+// Assets which cannot be quoted get an error in their item instead of failing the whole batch.
+func (env *Env) PostAssetQuotations(c *gin.Context) {
+	type batchRequest struct {
+		Assets []struct {
+			Blockchain string
+			Address    string
+		}
+		Timestamp int64
+	}
+
+	var request batchRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(request.Assets) == 0 || len(request.Assets) > maxBatchQuotations {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("number of assets must be between 1 and %d", maxBatchQuotations))
+		return
+	}
+
+	var timestamp time.Time
+	if request.Timestamp > 0 {
+		timestamp = time.Unix(request.Timestamp, 0)
+		if timestamp.After(time.Now()) {
+			restApi.SendError(c, http.StatusBadRequest, errors.New("timestamp is in the future"))
+			return
+		}
+	}
+
+	var assets []dia.Asset
+	for _, a := range request.Assets {
+		if containsSpecialChars(a.Blockchain) || containsSpecialChars(a.Address) {
+			restApi.SendError(c, http.StatusBadRequest, errors.New("invalid input params"))
+			return
+		}
+		assets = append(assets, dia.Asset{
+			Blockchain: a.Blockchain,
+			Address:    a.Address,
+		})
+	}
+	c.JSON(http.StatusOK, env.DataStore.GetAssetQuotationBatchItems(assets, timestamp, &env.RelDB))
+}
+
 // GetQuotation returns quotation of asset with highest market cap among
 // all assets with symbol ticker @symbol.
 func (env *Env) GetQuotation(c *gin.Context) {
@@ -194,6 +369,10 @@ func (env *Env) GetQuotation(c *gin.Context) {
 	}
 
 	symbol := c.Param("symbol")
+	currency, ok := fiatCurrency(c)
+	if !ok {
+		return
+	}
 
 	timestamp := time.Now()
 	var quotationExtended models.AssetQuotationFull
@@ -235,6 +414,13 @@ func (env *Env) GetQuotation(c *gin.Context) {
 	quotationExtended.Time = quotation.Time
 	quotationExtended.Source = quotation.Source
 
+	if currency != fiathelper.USD {
+		if err := env.convertQuotation(&quotationExtended, currency, timestamp); err != nil {
+			restApi.SendError(c, http.StatusNotFound, err)
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, quotationExtended)
 }
 
@@ -600,6 +786,36 @@ func (env *Env) GetExchanges(c *gin.Context) {
 	c.JSON(http.StatusOK, exchangereturns)
 }
 
+// GetExchangeHealth returns the health scores of an exchange in the time range given by the query parameters
+// starttime and endtime. Default is the last 24 hours.
+func (env *Env) GetExchangeHealth(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	exchange := c.Param("exchange")
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), time.Duration(24)*time.Hour)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if endtime.Sub(starttime) > time.Duration(24*30)*time.Hour {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("time range must not exceed 30 days"))
+		return
+	}
+
+	healths, err := env.DataStore.GetExchangeHealth(exchange, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(healths) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no health scores for exchange "+exchange))
+		return
+	}
+	c.JSON(http.StatusOK, healths)
+}
+
 // GetNFTExchanges is the delegate method for fetching all exchanges available in Postgres.
 func (env *Env) GetNFTExchanges(c *gin.Context) {
 	type exchangeReturn struct {
@@ -655,6 +871,10 @@ func (env *Env) GetAssetChartPoints(c *gin.Context) {
 	if !validateInputParams(c) {
 		return
 	}
+	currency, ok := fiatCurrency(c)
+	if !ok {
+		return
+	}
 
 	filter := c.Param("filter")
 	blockchain := c.Param("blockchain")
@@ -710,9 +930,17 @@ func (env *Env) GetAssetChartPoints(c *gin.Context) {
 	p, err := env.DataStore.GetFilterPointsAsset(filter, exchange, address, blockchain, starttime, endtime)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
-	} else {
-		c.JSON(http.StatusOK, p)
+		return
 	}
+	if currency != fiathelper.USD {
+		series, err := env.fiatRateSeries(currency, starttime, endtime)
+		if err != nil {
+			restApi.SendError(c, http.StatusNotFound, err)
+			return
+		}
+		convertFilterPoints(p, series)
+	}
+	c.JSON(http.StatusOK, p)
 }
 
 // GetChartPoints godoc
@@ -721,6 +949,10 @@ func (env *Env) GetChartPoints(c *gin.Context) {
 	if !validateInputParams(c) {
 		return
 	}
+	currency, ok := fiatCurrency(c)
+	if !ok {
+		return
+	}
 
 	filter := c.Param("filter")
 	exchange := c.Param("exchange")
@@ -775,9 +1007,17 @@ func (env *Env) GetChartPoints(c *gin.Context) {
 	p, err := env.DataStore.GetFilterPoints(filter, exchange, symbol, scale, starttime, endtime)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
-	} else {
-		c.JSON(http.StatusOK, p)
+		return
 	}
+	if currency != fiathelper.USD {
+		series, err := env.fiatRateSeries(currency, starttime, endtime)
+		if err != nil {
+			restApi.SendError(c, http.StatusNotFound, err)
+			return
+		}
+		convertFilterPoints(p, series)
+	}
+	c.JSON(http.StatusOK, p)
 }
 
 // GetChartPointsAllExchanges godoc
@@ -786,6 +1026,10 @@ func (env *Env) GetChartPointsAllExchanges(c *gin.Context) {
 	if !validateInputParams(c) {
 		return
 	}
+	currency, ok := fiatCurrency(c)
+	if !ok {
+		return
+	}
 
 	filter := c.Param("filter")
 	symbol := c.Param("symbol")
@@ -839,9 +1083,17 @@ func (env *Env) GetChartPointsAllExchanges(c *gin.Context) {
 	p, err := env.DataStore.GetFilterPoints(filter, "", symbol, scale, starttime, endtime)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
-	} else {
-		c.JSON(http.StatusOK, p)
+		return
 	}
+	if currency != fiathelper.USD {
+		series, err := env.fiatRateSeries(currency, starttime, endtime)
+		if err != nil {
+			restApi.SendError(c, http.StatusNotFound, err)
+			return
+		}
+		convertFilterPoints(p, series)
+	}
+	c.JSON(http.StatusOK, p)
 }
 
 // GetAllSymbols returns all Symbols on @exchange.
@@ -1271,222 +1523,210 @@ func (env *Env) GetInterestRate(c *gin.Context) {
 	}
 }
 
-// GetCompoundedRate is the delegate method to fetch compounded rate values for interest rates
-func (env *Env) GetCompoundedRate(c *gin.Context) {
-
-	// Import and cast input from API call
-	symbol := c.Param("symbol")
-	dpy := c.Param("dpy")
-	daysPerYear, err := strconv.Atoi(dpy)
+// rateConvention returns the business day calendar of the rate given by the path parameter symbol and the
+// compounding convention given by the path parameter dpy (days per year) and the optional query parameters
+// lookback (business days), shift (observation shift) and rounding (decimals).
+// ok is false and status 400 is sent if dpy, lookback or rounding is not an integer or shift is not a
+// boolean; status 404 if the rate has no calendar.
+func (env *Env) rateConvention(c *gin.Context) (calendar *dia.Calendar, convention dia.CompoundingConvention, ok bool) {
+	var err error
+	convention.DaysPerYear, err = strconv.Atoi(c.Param("dpy"))
 	if err != nil {
-		restApi.SendError(c, http.StatusInternalServerError, err)
+		restApi.SendError(c, http.StatusBadRequest, errors.New("days per year must be an integer"))
+		return
 	}
-	datestring := c.Param("time")
-
-	// Add optional query parameters for requesting a range of values
-	dateInitstring := c.DefaultQuery("dateInit", "noRange")
-	dateFinalstring := c.Query("dateFinal")
-
-	// Retrieve rounding convention for @symbol
-	rounding := 0
-
-	if dateInitstring == "noRange" {
-
-		var date time.Time
-		if datestring == "" {
-			// If date is omitted in API call, take most recent date
-			date = time.Now()
-		} else {
-			date, err = time.Parse("2006-01-02", datestring)
-		}
-		if err != nil {
-			restApi.SendError(c, http.StatusInternalServerError, err)
-		}
+	convention.Lookback, err = strconv.Atoi(c.DefaultQuery("lookback", "0"))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("lookback must be an integer"))
+		return
+	}
+	convention.ObservationShift, err = strconv.ParseBool(c.DefaultQuery("shift", "false"))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("shift must be a boolean"))
+		return
+	}
+	convention.Rounding, err = strconv.Atoi(c.DefaultQuery("rounding", "0"))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("rounding must be an integer"))
+		return
+	}
+	calendar, err = env.RelDB.GetRateCalendar(c.Param("symbol"))
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	return calendar, convention, true
+}
 
-		q, err := env.DataStore.GetCompoundedIndex(symbol, date, daysPerYear, rounding)
+// rateDates returns the date given by the path parameter time (default today) and the range given by
+// the query parameters dateInit and dateFinal. isRange is false if dateInit is omitted.
+// ok is false and status 400 is sent if a date is not formatted as 2006-01-02 or dateFinal is before dateInit.
+func rateDates(c *gin.Context) (date time.Time, dateInit time.Time, dateFinal time.Time, isRange bool, ok bool) {
+	var err error
+	date = time.Now().UTC().Truncate(24 * time.Hour)
+	if datestring := c.Param("time"); datestring != "" {
+		date, err = time.Parse("2006-01-02", datestring)
 		if err != nil {
-			if errors.Is(err, redis.Nil) {
-				restApi.SendError(c, http.StatusNotFound, err)
-			} else {
-				restApi.SendError(c, http.StatusInternalServerError, err)
-			}
-		} else {
-			c.JSON(http.StatusOK, q)
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
 		}
+	}
+	dateInitstring := c.DefaultQuery("dateInit", "noRange")
+	if dateInitstring == "noRange" {
+		return date, dateInit, dateFinal, false, true
+	}
+	dateInit, err = time.Parse("2006-01-02", dateInitstring)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	dateFinal, err = time.Parse("2006-01-02", c.Query("dateFinal"))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if dateFinal.Before(dateInit) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("dateFinal must not be before dateInit"))
+		return
+	}
+	return date, dateInit, dateFinal, true, true
+}
 
+// sendRateError sends @err to the client with status not found if rate data is missing.
+func sendRateError(c *gin.Context, err error) {
+	if errors.Is(err, redis.Nil) || errors.Is(err, ratederivatives.ErrMissingRate) {
+		restApi.SendError(c, http.StatusNotFound, err)
 	} else {
-
-		dateInit, err := time.Parse("2006-01-02", dateInitstring)
-		if err != nil {
-			restApi.SendError(c, http.StatusInternalServerError, err)
-		}
-		dateFinal, err := time.Parse("2006-01-02", dateFinalstring)
-		if err != nil {
-			restApi.SendError(c, http.StatusInternalServerError, err)
-		}
-
-		q, err := env.DataStore.GetCompoundedIndexRange(symbol, dateInit, dateFinal, daysPerYear, rounding)
-		if err != nil {
-			if errors.Is(err, redis.Nil) {
-				restApi.SendError(c, http.StatusNotFound, err)
-			} else {
-				restApi.SendError(c, http.StatusInternalServerError, err)
-			}
-		} else {
-			c.JSON(http.StatusOK, q)
-		}
-
+		restApi.SendError(c, http.StatusInternalServerError, err)
 	}
 }
 
-// GetCompoundedAvg is the delegate method to fetch averaged compounded rate values for interest rates
-func (env *Env) GetCompoundedAvg(c *gin.Context) {
-
-	tInit := time.Now()
-
-	// Import and cast input from API call
+// GetCompoundedRate is the delegate method to fetch the compounded index of interest rates, compounded in
+// arrears on the business days of the rate's calendar since its first publication.
+func (env *Env) GetCompoundedRate(c *gin.Context) {
 	symbol := c.Param("symbol")
-	datestring := c.Param("time")
-	date, _ := time.Parse("2006-01-02", datestring)
-	days := c.Param("days")
-	calDays, err := strconv.Atoi(days)
-	if err != nil {
-		restApi.SendError(c, http.StatusInternalServerError, err)
+	calendar, convention, ok := env.rateConvention(c)
+	if !ok {
+		return
 	}
-	dpy := c.Param("dpy")
-	daysPerYear, err := strconv.Atoi(dpy)
-	if err != nil {
-		restApi.SendError(c, http.StatusInternalServerError, err)
+	date, dateInit, dateFinal, isRange, ok := rateDates(c)
+	if !ok {
+		return
 	}
 
-	// Add optional query parameters for requesting a range of values
-	dateInitstring := c.DefaultQuery("dateInit", "noRange")
-	dateFinalstring := c.Query("dateFinal")
-
-	rounding := 0
-
-	if dateInitstring == "noRange" {
-
-		// Compute compunded rate and return if no error
-		q, err := env.DataStore.GetCompoundedAvg(symbol, date, calDays, daysPerYear, rounding)
+	if !isRange {
+		q, err := env.DataStore.GetCompoundedIndex(symbol, calendar, date, convention)
 		if err != nil {
-			if errors.Is(err, redis.Nil) {
-				restApi.SendError(c, http.StatusNotFound, err)
-			} else {
-				restApi.SendError(c, http.StatusInternalServerError, err)
-			}
-		} else {
-			c.JSON(http.StatusOK, q)
+			sendRateError(c, err)
+			return
 		}
+		c.JSON(http.StatusOK, q)
+		return
+	}
+	q, err := env.DataStore.GetCompoundedIndexRange(symbol, calendar, dateInit, dateFinal, convention)
+	if err != nil {
+		sendRateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, q)
+}
 
-	} else {
-
-		dateInit, err := time.Parse("2006-01-02", dateInitstring)
-		if err != nil {
-			restApi.SendError(c, http.StatusInternalServerError, err)
-		}
-		dateFinal, err := time.Parse("2006-01-02", dateFinalstring)
-		if err != nil {
-			restApi.SendError(c, http.StatusInternalServerError, err)
-		}
+// GetCompoundedAvg is the delegate method to fetch averaged compounded rate values for interest rates
+// over the past @days calendar days, as published by FED and BOE.
+func (env *Env) GetCompoundedAvg(c *gin.Context) {
+	symbol := c.Param("symbol")
+	calDays, err := strconv.Atoi(c.Param("days"))
+	if err != nil || calDays <= 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("days must be a positive integer"))
+		return
+	}
+	calendar, convention, ok := env.rateConvention(c)
+	if !ok {
+		return
+	}
+	date, dateInit, dateFinal, isRange, ok := rateDates(c)
+	if !ok {
+		return
+	}
 
-		q, err := env.DataStore.GetCompoundedAvgRange(symbol, dateInit, dateFinal, calDays, daysPerYear, rounding)
+	if !isRange {
+		q, err := env.DataStore.GetCompoundedAvg(symbol, calendar, date, calDays, convention)
 		if err != nil {
-			if errors.Is(err, redis.Nil) {
-				restApi.SendError(c, http.StatusNotFound, err)
-			} else {
-				restApi.SendError(c, http.StatusInternalServerError, err)
-			}
-		} else {
-			c.JSON(http.StatusOK, q)
+			sendRateError(c, err)
+			return
 		}
-
+		c.JSON(http.StatusOK, q)
+		return
 	}
-
-	tFinal := time.Now()
-	fmt.Println("time elapsed in API call: ", tFinal.Sub(tInit))
+	q, err := env.DataStore.GetCompoundedAvgRange(symbol, calendar, dateInit, dateFinal, calDays, convention)
+	if err != nil {
+		sendRateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, q)
 }
 
 // GetCompoundedAvgDIA is the delegate method to fetch averaged compounded rate values for interest rates
+// over the past @days calendar days, compounded on every calendar day.
 func (env *Env) GetCompoundedAvgDIA(c *gin.Context) {
-
-	tInit := time.Now()
-
-	// Import and cast input from API call
 	symbol := c.Param("symbol")
-	datestring := c.Param("time")
-	date, _ := time.Parse("2006-01-02", datestring)
-	days := c.Param("days")
-	calDays, err := strconv.Atoi(days)
-	if err != nil {
-		restApi.SendError(c, http.StatusInternalServerError, err)
+	calDays, err := strconv.Atoi(c.Param("days"))
+	if err != nil || calDays <= 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("days must be a positive integer"))
+		return
 	}
-	dpy := c.Param("dpy")
-	daysPerYear, err := strconv.Atoi(dpy)
-	if err != nil {
-		restApi.SendError(c, http.StatusInternalServerError, err)
+	calendar, convention, ok := env.rateConvention(c)
+	if !ok {
+		return
+	}
+	date, dateInit, dateFinal, isRange, ok := rateDates(c)
+	if !ok {
+		return
 	}
 
-	// Add optional query parameters for requesting a range of values
-	dateInitstring := c.DefaultQuery("dateInit", "noRange")
-	dateFinalstring := c.Query("dateFinal")
-
-	rounding := 0
-
-	if dateInitstring == "noRange" {
-
-		// In this method, there is a rate for every calendar day. Hence, the compounded rate
+	if !isRange {
+		// There is a rate for every calendar day. Hence, the compounded rate
 		// for a particular day can be retrieved by the range method easily.
-		dateFinal := date.AddDate(0, 0, 1)
-		q, err := env.DataStore.GetCompoundedAvgDIARange(symbol, date, dateFinal, calDays, daysPerYear, rounding)
-
-		if err != nil {
-			if errors.Is(err, redis.Nil) {
-				restApi.SendError(c, http.StatusNotFound, err)
-			} else {
-				restApi.SendError(c, http.StatusInternalServerError, err)
-			}
-		} else {
-			c.JSON(http.StatusOK, q)
-		}
-
-	} else {
-
-		dateInit, err := time.Parse("2006-01-02", dateInitstring)
-		if err != nil {
-			restApi.SendError(c, http.StatusInternalServerError, err)
-		}
-		dateFinal, err := time.Parse("2006-01-02", dateFinalstring)
-		if err != nil {
-			restApi.SendError(c, http.StatusInternalServerError, err)
-		}
-
-		q, err := env.DataStore.GetCompoundedAvgDIARange(symbol, dateInit, dateFinal, calDays, daysPerYear, rounding)
-		if err != nil {
-			if errors.Is(err, redis.Nil) {
-				restApi.SendError(c, http.StatusNotFound, err)
-			} else {
-				restApi.SendError(c, http.StatusInternalServerError, err)
-			}
-		} else {
-			c.JSON(http.StatusOK, q)
-		}
-
+		dateInit, dateFinal = date, date.AddDate(0, 0, 1)
+	}
+	q, err := env.DataStore.GetCompoundedAvgDIARange(symbol, calendar, dateInit, dateFinal, calDays, convention.DaysPerYear, convention.Rounding)
+	if err != nil {
+		sendRateError(c, err)
+		return
+	}
+	if !isRange {
+		c.JSON(http.StatusOK, q[0])
+		return
 	}
+	c.JSON(http.StatusOK, q)
+}
 
-	tFinal := time.Now()
-	fmt.Println("time elapsed in API call: ", tFinal.Sub(tInit))
+// GetRateHolidays returns the holidays of the business day calendar of the rate @symbol.
+func (env *Env) GetRateHolidays(c *gin.Context) {
+	name, err := ratederivatives.RateCalendar(c.Param("symbol"))
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	holidays, err := env.RelDB.GetRateHolidays(name)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, holidays)
 }
 
 // GetRates is the delegate method for fetching all rate types
 // present in the (redis) database.
 func (env *Env) GetRates(c *gin.Context) {
 	q, err := env.DataStore.GetRatesMeta()
-	if len(q) == 0 {
-		restApi.SendError(c, http.StatusInternalServerError, nil)
-	}
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(q) == 0 {
+		restApi.SendError(c, http.StatusInternalServerError, nil)
+		return
 	}
 	c.JSON(http.StatusOK, q)
 }
@@ -1541,8 +1781,12 @@ func (env *Env) GetStockSymbols(c *gin.Context) {
 
 // GetStockQuotation is the delegate method to fetch the value(s) of
 // quotations of asset with @symbol from @source.
-// Last value is retrieved. Otional query parameters allow to obtain data in a time range.
+// Last value is retrieved. Otional query parameters dateInit and dateFinal allow to obtain data in a
+// time range of at most maxStockTimerange.
 func (env *Env) GetStockQuotation(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
 	source := c.Param("source")
 	symbol := c.Param("symbol")
 	date := c.Param("time")
@@ -1552,50 +1796,109 @@ func (env *Env) GetStockQuotation(c *gin.Context) {
 
 	if dateInit == "noRange" {
 		// Return most recent data point
-		endtime := time.Time{}
-		var err error
-		if date == "" {
-			endtime = time.Now()
-		} else {
-			// Convert unix time int/string to time
+		endtime := time.Now()
+		if date != "" {
+			var err error
 			endtime, err = utils.StrToUnixtime(date)
 			if err != nil {
 				restApi.SendError(c, http.StatusNotFound, err)
+				return
 			}
 		}
-		starttime := endtime.AddDate(0, 0, -1)
-
-		q, err := env.DataStore.GetStockQuotation(source, symbol, starttime, endtime)
-		if err != nil {
-			if err == redis.Nil {
-				restApi.SendError(c, http.StatusNotFound, err)
-			} else {
-				restApi.SendError(c, http.StatusInternalServerError, err)
-			}
-		} else {
-			c.JSON(http.StatusOK, q[0])
-		}
-	} else {
-		starttime, err := utils.StrToUnixtime(dateInit)
+		q, err := env.DataStore.GetStockQuotationLatest(source, symbol, endtime)
 		if err != nil {
 			restApi.SendError(c, http.StatusNotFound, err)
+			return
 		}
-		endtime, err := utils.StrToUnixtime(dateFinal)
+		q.MarketClosed = !markethours.MarketOf(symbol).IsOpen(endtime)
+		c.JSON(http.StatusOK, q)
+		return
+	}
+
+	starttime, err := utils.StrToUnixtime(dateInit)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	endtime, err := utils.StrToUnixtime(dateFinal)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	if endtime.Sub(starttime) > maxStockTimerange {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("time range must not exceed 30 days"))
+		return
+	}
+	q, err := env.DataStore.GetStockQuotation(source, symbol, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(q) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no quotations of "+symbol+" from "+source))
+		return
+	}
+	c.JSON(http.StatusOK, q)
+}
+
+// GetStockPrice returns the median of the latest quotations of @symbol from all stock sources at the
+// optional unix time @time. Quotations older than a day and before the last trading session are not
+// considered. MarketClosed is set if the market of @symbol is closed, in which case the price is the
+// last one of the previous session.
+func (env *Env) GetStockPrice(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	symbol := stockSymbol(c.Param("symbol"))
+	timestamp := time.Now()
+	if date := c.Param("time"); date != "" {
+		var err error
+		timestamp, err = utils.StrToUnixtime(date)
 		if err != nil {
 			restApi.SendError(c, http.StatusNotFound, err)
+			return
 		}
+	}
+	market := markethours.MarketOf(symbol)
+	// Outside of trading hours, the last quotations of the previous session are aggregated.
+	oldest := timestamp.Add(-24 * time.Hour)
+	if session := market.SessionStart(timestamp); session.Before(oldest) {
+		oldest = session
+	}
 
-		q, err := env.DataStore.GetStockQuotation(source, symbol, starttime, endtime)
-		if err != nil {
-			if err == redis.Nil {
-				restApi.SendError(c, http.StatusNotFound, err)
-			} else {
-				restApi.SendError(c, http.StatusInternalServerError, err)
-			}
-		} else {
-			c.JSON(http.StatusOK, q)
+	var quotations []models.StockQuotation
+	for _, source := range stockscrapers.Sources {
+		quotation, err := env.DataStore.GetStockQuotationLatest(source, symbol, timestamp)
+		if err != nil || quotation.Time.Before(oldest) {
+			continue
 		}
+		quotations = append(quotations, quotation)
+	}
+	price, err := stockscrapers.MedianQuotation(quotations)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no recent quotations of "+symbol))
+		return
+	}
+	price.MarketClosed = !market.IsOpen(timestamp)
+	c.JSON(http.StatusOK, price)
+}
+
+// stockSymbol returns the stored symbol of the path parameter @symbol. Currency pairs such as EUR/USD
+// are given as EUR-USD in paths. Other symbols such as BRK-B are returned unchanged.
+func stockSymbol(symbol string) string {
+	parts := strings.Split(symbol, "-")
+	if len(parts) != 2 {
+		return symbol
+	}
+	base, err := fiathelper.NormalizeCurrency(parts[0])
+	if err != nil {
+		return symbol
+	}
+	quote, err := fiathelper.NormalizeCurrency(parts[1])
+	if err != nil {
+		return symbol
 	}
+	return base + "/" + quote
 }
 
 // -----------------------------------------------------------------------------
@@ -2120,6 +2423,104 @@ func (env *Env) GetNFTFloor(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetNFTFloorStats returns the floor price of a collection before @timestamp together with the number of sales,
+// the time of the last sale, the dispersion of sale prices and a staleness flag.
+func (env *Env) GetNFTFloorStats(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := makeAddressEIP55Compliant(c.Param("address"), blockchain)
+
+	timestamp := time.Now()
+	timestampUnixString := c.Query("timestamp")
+	if timestampUnixString != "" {
+		timestampUnix, err := strconv.ParseInt(timestampUnixString, 10, 64)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
+		}
+		timestamp = time.Unix(timestampUnix, 0)
+	}
+
+	// Floor window is 24h per default.
+	floorWindow, err := strconv.ParseInt(c.DefaultQuery("floorWindow", "86400"), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Floor price is considered stale if there was no sale within the floor window per default.
+	staleAfter, err := strconv.ParseInt(c.DefaultQuery("staleAfter", strconv.FormatInt(floorWindow, 10)), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Exclude bundle sales by default.
+	bundles, err := strconv.ParseBool(c.DefaultQuery("bundles", "false"))
+	if err != nil {
+		log.Error("parse bundles string: ", err)
+	}
+
+	nftClass := dia.NFTClass{Address: address, Blockchain: blockchain}
+	stats, err := env.RelDB.GetNFTFloorStats(nftClass, timestamp, time.Duration(floorWindow)*time.Second, time.Duration(staleAfter)*time.Second, !bundles)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+
+	type returnStruct struct {
+		Floor       float64   `json:"Floor_Price"`
+		NumSales    int       `json:"Number_Sales"`
+		LastSale    time.Time `json:"Last_Sale"`
+		Dispersion  float64   `json:"Dispersion"`
+		Stale       bool      `json:"Stale"`
+		FloorWindow int64     `json:"Floor_Window_Seconds"`
+		Time        time.Time `json:"Time"`
+		Source      string    `json:"Source"`
+	}
+	c.JSON(http.StatusOK, returnStruct{
+		Floor:       stats.Floor,
+		NumSales:    stats.NumSales,
+		LastSale:    stats.LastSale,
+		Dispersion:  stats.Dispersion,
+		Stale:       stats.Stale,
+		FloorWindow: floorWindow,
+		Time:        timestamp,
+		Source:      dia.Diadata,
+	})
+}
+
+// GetNFTListings returns the floor ask and the depth of active listings of a collection at @timestamp.
+func (env *Env) GetNFTListings(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := makeAddressEIP55Compliant(c.Param("address"), blockchain)
+
+	timestamp := time.Now()
+	timestampUnixString := c.Query("timestamp")
+	if timestampUnixString != "" {
+		timestampUnix, err := strconv.ParseInt(timestampUnixString, 10, 64)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
+		}
+		timestamp = time.Unix(timestampUnix, 0)
+	}
+
+	book, err := env.RelDB.GetNFTListingBook(address, blockchain, timestamp, []float64{0.05, 0.1, 0.25})
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, book)
+}
+
 // GetNFTFloorMA returns the moving average floor price of the nft class over the last 30 days.
 func (env *Env) GetNFTFloorMA(c *gin.Context) {
 
@@ -3107,6 +3508,55 @@ func (env *Env) GetSyntheticAsset(c *gin.Context) {
 	}
 }
 
+// GetSynthAssetValuation returns the fair values of a synthetic asset derived from its collateral along with
+// the deviation of the market price in the time range given by the query parameters starttime and endtime.
+// Default is the last 24 hours.
+func (env *Env) GetSynthAssetValuation(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	address := makeAddressEIP55Compliant(c.Param("address"), blockchain)
+	starttime, endtime, ok := derivativesTimerange(c, time.Duration(24)*time.Hour)
+	if !ok {
+		return
+	}
+	valuations, err := env.DataStore.GetSynthAssetValuations(blockchain, address, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(valuations) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no valuations for "+address+" on "+blockchain))
+		return
+	}
+	c.JSON(http.StatusOK, valuations)
+}
+
+// GetProofOfReserve returns the reserves of a bridged asset locked on its source chain and the supply minted on
+// destination chains in the time range given by the query parameters starttime and endtime. Default is the last 24 hours.
+func (env *Env) GetProofOfReserve(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	address := makeAddressEIP55Compliant(c.Param("address"), blockchain)
+	starttime, endtime, ok := derivativesTimerange(c, time.Duration(24)*time.Hour)
+	if !ok {
+		return
+	}
+	pors, err := env.DataStore.GetProofOfReserve(blockchain, address, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(pors) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no proof of reserve for "+address+" on "+blockchain))
+		return
+	}
+	c.JSON(http.StatusOK, pors)
+}
+
 func validTimeRange(starttime time.Time, endtime time.Time, maxDuration time.Duration) (ok bool, err error) {
 	if endtime.Sub(starttime) < maxDuration {
 		ok = true
@@ -3116,6 +3566,12 @@ func validTimeRange(starttime time.Time, endtime time.Time, maxDuration time.Dur
 }
 
 func validateInputParams(c *gin.Context) bool {
+	return validateInputParamsExcept(c)
+}
+
+// validateInputParamsExcept validates all input parameters except the query parameters @skipQuery,
+// which have to be validated by the caller.
+func validateInputParamsExcept(c *gin.Context, skipQuery ...string) bool {
 
 	// Validate input parameters.
 	for _, input := range c.Params {
@@ -3126,7 +3582,10 @@ func validateInputParams(c *gin.Context) bool {
 	}
 
 	// Validate query parameters.
-	for _, value := range c.Request.URL.Query() {
+	for key, value := range c.Request.URL.Query() {
+		if utils.Contains(&skipQuery, key) {
+			continue
+		}
 		for _, input := range value {
 			if containsSpecialChars(input) {
 				restApi.SendError(c, http.StatusInternalServerError, errors.New("invalid input params"))
@@ -0,0 +1,184 @@
+package diaApi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/export"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/diadata-org/diadata/pkg/http/restServer/apiKeys"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Maximal time range of a single export job.
+	maxExportRange = 366 * 24 * time.Hour
+
+	// ContextJWTSubject is the context key under which the subject of a jwt authenticated request is stored.
+	ContextJWTSubject = "jwtSubject"
+)
+
+// exportDir is the directory shared with the export workers. Each job writes into a subdirectory named by its ID.
+var exportDir = utils.Getenv("EXPORT_DIR", "/exports")
+
+// PostExport creates an export job. The job is processed asynchronously by the export service,
+// its status and files can be retrieved with GetExport.
+func (env *Env) PostExport(c *gin.Context) {
+	type exportRequest struct {
+		Dataset    string `json:"dataset" binding:"required"`
+		Blockchain string `json:"blockchain" binding:"required"`
+		Address    string `json:"address" binding:"required"`
+		Exchange   string `json:"exchange"`
+		Filter     string `json:"filter"`
+		Format     string `json:"format"`
+		Starttime  int64  `json:"starttime" binding:"required"`
+		Endtime    int64  `json:"endtime" binding:"required"`
+	}
+	var req exportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if req.Format == "" {
+		req.Format = export.FormatParquet
+	}
+	owner, ok := exportOwner(c)
+	if !ok {
+		restApi.SendError(c, http.StatusUnauthorized, errors.New("unknown owner of export"))
+		return
+	}
+
+	asset, err := env.RelDB.GetAsset(makeAddressEIP55Compliant(req.Address, req.Blockchain), req.Blockchain)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, errors.New("asset not found"))
+		return
+	}
+	request := export.Request{
+		Dataset:   req.Dataset,
+		Asset:     asset,
+		Exchange:  req.Exchange,
+		Filter:    req.Filter,
+		Format:    req.Format,
+		Starttime: time.Unix(req.Starttime, 0),
+		Endtime:   time.Unix(req.Endtime, 0),
+	}
+	if err = request.Validate(); err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if request.Exchange != "" {
+		if _, err = env.RelDB.GetExchange(request.Exchange); err != nil {
+			restApi.SendError(c, http.StatusBadRequest, errors.New("unknown exchange "+request.Exchange))
+			return
+		}
+	}
+	if request.Endtime.Sub(request.Starttime) > maxExportRange {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("time range of an export must not exceed 366 days"))
+		return
+	}
+
+	job := dia.ExportJob{
+		Owner:      owner,
+		Dataset:    request.Dataset,
+		Blockchain: asset.Blockchain,
+		Address:    asset.Address,
+		Exchange:   request.Exchange,
+		Filter:     request.Filter,
+		Format:     request.Format,
+		Starttime:  request.Starttime,
+		Endtime:    request.Endtime,
+	}
+	job.ID, err = env.RelDB.SetExportJob(job)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	job.Status = dia.ExportStatusPending
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetExport returns the status of an export job and, once it is done, its manifest.
+func (env *Env) GetExport(c *gin.Context) {
+	job, ok := env.getOwnExportJob(c)
+	if !ok {
+		return
+	}
+	response := struct {
+		dia.ExportJob
+		Manifest *export.Manifest `json:",omitempty"`
+	}{ExportJob: job}
+
+	if job.Status == dia.ExportStatusDone {
+		content, err := ioutil.ReadFile(filepath.Join(exportDir, job.ID, export.ManifestFilename))
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, errors.New("manifest not found"))
+			return
+		}
+		var manifest export.Manifest
+		if err = json.Unmarshal(content, &manifest); err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		response.Manifest = &manifest
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetExportFile downloads a file of a finished export job.
+func (env *Env) GetExportFile(c *gin.Context) {
+	job, ok := env.getOwnExportJob(c)
+	if !ok {
+		return
+	}
+	if job.Status != dia.ExportStatusDone {
+		restApi.SendError(c, http.StatusConflict, errors.New("export is "+job.Status))
+		return
+	}
+	// Only plain file names within the directory of the job are served.
+	name := c.Param("name")
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("invalid file name"))
+		return
+	}
+	path := filepath.Join(exportDir, job.ID, name)
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		restApi.SendError(c, http.StatusNotFound, errors.New("file not found"))
+		return
+	}
+	c.FileAttachment(path, name)
+}
+
+// getOwnExportJob returns the export job given by the id parameter. Jobs of other owners are reported
+// as not found, unless the request is authenticated by an admin key.
+func (env *Env) getOwnExportJob(c *gin.Context) (job dia.ExportJob, ok bool) {
+	job, err := env.RelDB.GetExportJob(c.Param("id"))
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, errors.New("export not found"))
+		return
+	}
+	if key, isKey := c.Get(apiKeys.ContextAPIKey); isKey && apiKeys.HasScope(key.(dia.APIKey).Scopes, apiKeys.ScopeAdmin) {
+		return job, true
+	}
+	if owner, isOwner := exportOwner(c); !isOwner || owner != job.Owner {
+		restApi.SendError(c, http.StatusNotFound, errors.New("export not found"))
+		return
+	}
+	return job, true
+}
+
+// exportOwner returns the owner of the api key of a request or the subject of its jwt.
+// ok is false if the request carries neither.
+func exportOwner(c *gin.Context) (owner string, ok bool) {
+	if key, isKey := c.Get(apiKeys.ContextAPIKey); isKey {
+		return key.(dia.APIKey).Owner, true
+	}
+	owner = c.GetString(ContextJWTSubject)
+	return owner, owner != ""
+}
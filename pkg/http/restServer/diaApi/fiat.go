@@ -0,0 +1,236 @@
+package diaApi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	fiathelper "github.com/diadata-org/diadata/pkg/dia/helpers/fiatHelper"
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetFiatCrossRate returns the price of the fiat currency @base in units of the fiat currency @quote at
+// the optional unix time @time, triangulated from the USD prices of both currencies.
+func (env *Env) GetFiatCrossRate(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	base, err := fiathelper.NormalizeCurrency(c.Param("base"))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	quote, err := fiathelper.NormalizeCurrency(c.Param("quote"))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	timestamp := time.Now()
+	if date := c.Param("time"); date != "" {
+		timestamp, err = utils.StrToUnixtime(date)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	baseRate, err := env.fiatRate(base, timestamp)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	quoteRate, err := env.fiatRate(quote, timestamp)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	crossRate, err := fiathelper.CrossRate(baseRate, quoteRate)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, crossRate)
+}
+
+// fiatCurrency returns the normalized query parameter currency, or USD if it is not given.
+// ok is false and status 400 is sent if currency is not a supported fiat currency code.
+func fiatCurrency(c *gin.Context) (currency string, ok bool) {
+	currency, err := fiathelper.NormalizeCurrency(c.DefaultQuery("currency", fiathelper.USD))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return "", false
+	}
+	return currency, true
+}
+
+// convertQuotation converts the prices of @quotation requested at @timestamp from USD to @currency with
+// the rates at their respective times.
+func (env *Env) convertQuotation(quotation *models.AssetQuotationFull, currency string, timestamp time.Time) error {
+	rate, err := env.fiatRate(currency, quotation.Time)
+	if err != nil {
+		return err
+	}
+	quotation.Price = fiathelper.ConvertUSD(quotation.Price, rate)
+	if quotation.PriceYesterday > 0 {
+		rateYesterday, err := env.fiatRate(currency, timestamp.AddDate(0, 0, -1))
+		if err != nil {
+			log.Warn("get fiat rate yesterday: ", err)
+			quotation.PriceYesterday = 0
+		} else {
+			quotation.PriceYesterday = fiathelper.ConvertUSD(quotation.PriceYesterday, rateYesterday)
+		}
+	}
+	quotation.Currency = currency
+	quotation.FiatRate = &rate
+	return nil
+}
+
+const (
+	// USD prices of fiat assets are written by the ECB scraper only.
+	fiatSourceECB = "ECB"
+	// Forex quotations of the TwelveData stock scraper with symbols such as EUR/USD.
+	fiatSourceTwelveData = "TwelveData"
+	// Forex trades of the FinageForex exchange scraper with pairs such as EUR-USD.
+	fiatSourceFinageForex = "FinageForex"
+	// fiatRateGrouping is the resolution of the forex quotations used to convert price series.
+	fiatRateGrouping = "1h"
+)
+
+// fiatRate returns the latest USD price of the fiat currency @symbol at @timestamp among the rates of the
+// ECB and the forex quotations of TwelveData and FinageForex.
+func (env *Env) fiatRate(symbol string, timestamp time.Time) (dia.FiatRate, error) {
+	if symbol == fiathelper.USD {
+		return fiathelper.USDRate(timestamp), nil
+	}
+	var rates []dia.FiatRate
+	asset, err := env.RelDB.GetFiatAssetBySymbol(symbol)
+	if err == nil {
+		quotation, err := env.DataStore.GetAssetQuotation(asset, timestamp)
+		if err == nil && quotation.Price > 0 {
+			rates = append(rates, dia.FiatRate{Symbol: symbol, PriceUSD: quotation.Price, Source: fiatSourceECB, Time: quotation.Time})
+		}
+	}
+	for _, pair := range fiathelper.USDPairs(symbol, "/") {
+		quotation, err := env.DataStore.GetStockQuotationLatest(fiatSourceTwelveData, pair, timestamp)
+		if err != nil {
+			continue
+		}
+		rate, err := fiathelper.PairRate(symbol, pair, "/", quotation.PriceBid, fiatSourceTwelveData, quotation.Time)
+		if err == nil {
+			rates = append(rates, rate)
+		}
+	}
+	for _, pair := range fiathelper.USDPairs(symbol, "-") {
+		trade, err := env.DataStore.GetLastTradePair(fiatSourceFinageForex, pair, timestamp, fiathelper.MaxRateAge)
+		if err != nil {
+			continue
+		}
+		rate, err := fiathelper.PairRate(symbol, pair, "-", trade.Price, fiatSourceFinageForex, trade.Time)
+		if err == nil {
+			rates = append(rates, rate)
+		}
+	}
+	if len(rates) == 0 {
+		return dia.FiatRate{}, errors.New("no price of " + symbol)
+	}
+	return fiathelper.LatestRate(rates, timestamp)
+}
+
+// fiatRateSeries returns the USD prices of the fiat currency @symbol from the ECB, TwelveData and
+// FinageForex needed to convert prices in [@starttime, @endtime].
+func (env *Env) fiatRateSeries(symbol string, starttime time.Time, endtime time.Time) (fiathelper.RateSeries, error) {
+	starttime = starttime.Add(-fiathelper.MaxRateAge)
+	var rates []dia.FiatRate
+	asset, err := env.RelDB.GetFiatAssetBySymbol(symbol)
+	if err == nil {
+		quotations, err := env.DataStore.GetAssetQuotations(asset, starttime, endtime)
+		if err != nil {
+			log.Warnf("get %s quotations of %s: %v", fiatSourceECB, symbol, err)
+		}
+		for _, quotation := range quotations {
+			rates = append(rates, dia.FiatRate{Symbol: symbol, PriceUSD: quotation.Price, Source: fiatSourceECB, Time: quotation.Time})
+		}
+	}
+	for _, pair := range fiathelper.USDPairs(symbol, "/") {
+		quotations, err := env.DataStore.GetStockQuotationSeries(fiatSourceTwelveData, pair, starttime, endtime, fiatRateGrouping)
+		if err != nil {
+			log.Warnf("get %s quotations of %s: %v", fiatSourceTwelveData, pair, err)
+		}
+		for _, quotation := range quotations {
+			rate, err := fiathelper.PairRate(symbol, pair, "/", quotation.PriceBid, fiatSourceTwelveData, quotation.Time)
+			if err == nil {
+				rates = append(rates, rate)
+			}
+		}
+	}
+	for _, pair := range fiathelper.USDPairs(symbol, "-") {
+		trades, err := env.DataStore.GetLastTradesPairSeries(fiatSourceFinageForex, pair, starttime, endtime, fiatRateGrouping)
+		if err != nil {
+			log.Warnf("get %s trades of %s: %v", fiatSourceFinageForex, pair, err)
+		}
+		for _, trade := range trades {
+			rate, err := fiathelper.PairRate(symbol, pair, "-", trade.Price, fiatSourceFinageForex, trade.Time)
+			if err == nil {
+				rates = append(rates, rate)
+			}
+		}
+	}
+	if len(rates) == 0 {
+		return nil, errors.New("no prices of " + symbol)
+	}
+	return fiathelper.NewRateSeries(rates), nil
+}
+
+// convertFilterPoints converts the values of @points from USD to the currency of @series in place,
+// using the last rate before each point. Points without a recent rate are removed.
+func convertFilterPoints(points *models.Points, series fiathelper.RateSeries) {
+	for i := range points.DataPoints {
+		for j := range points.DataPoints[i].Series {
+			row := &points.DataPoints[i].Series[j]
+			timeIndex, valueIndex := -1, -1
+			for k, column := range row.Columns {
+				switch column {
+				case "time":
+					timeIndex = k
+				case "value":
+					valueIndex = k
+				}
+			}
+			if timeIndex < 0 || valueIndex < 0 {
+				continue
+			}
+			var values [][]interface{}
+			for _, value := range row.Values {
+				timeString, ok := value[timeIndex].(string)
+				if !ok {
+					continue
+				}
+				t, err := time.Parse(time.RFC3339, timeString)
+				if err != nil {
+					continue
+				}
+				rate, ok := series.At(t)
+				if !ok {
+					continue
+				}
+				number, ok := value[valueIndex].(json.Number)
+				if !ok {
+					continue
+				}
+				priceUSD, err := number.Float64()
+				if err != nil {
+					continue
+				}
+				value[valueIndex] = fiathelper.ConvertUSD(priceUSD, rate)
+				values = append(values, value)
+			}
+			row.Values = values
+		}
+	}
+}
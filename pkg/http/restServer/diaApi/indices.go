@@ -0,0 +1,69 @@
+package diaApi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRebalancesTimerange is the time range of rebalancings returned if no starttime is given.
+const defaultRebalancesTimerange = 365 * 24 * time.Hour
+
+// GetIndex returns the latest index block of the index @symbol computed by the index service, including
+// the current weights, prices and supplies of its constituents.
+func (env *Env) GetIndex(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	symbol := c.Param("symbol")
+	block, err := env.DataStore.GetIndexBlockCache(symbol)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no value of index "+symbol))
+		return
+	}
+	c.JSON(http.StatusOK, block)
+}
+
+// GetIndexRebalances returns the rebalancings of the index @symbol with the weights, units and divisors
+// set at each of them, in the time range given by the query parameters starttime and endtime.
+// Default is the last year.
+func (env *Env) GetIndexRebalances(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	symbol := c.Param("symbol")
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), defaultRebalancesTimerange)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	rebalances, err := env.RelDB.GetIndexRebalances(symbol, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(rebalances) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no rebalancings of index "+symbol))
+		return
+	}
+	c.JSON(http.StatusOK, rebalances)
+}
+
+// GetIndexProposal returns the latest composition of the index @symbol proposed by its selection rules,
+// with the evaluation of each candidate and the justification of each change.
+func (env *Env) GetIndexProposal(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	symbol := c.Param("symbol")
+	proposal, err := env.DataStore.GetIndexProposalCache(symbol)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no rebalance proposal of index "+symbol))
+		return
+	}
+	c.JSON(http.StatusOK, proposal)
+}
@@ -0,0 +1,341 @@
+// Package diaApiV2 implements the v2 REST API. In contrast to v1, all endpoints are described by the
+// OpenAPI spec in openapi.go, responses are typed, errors are uniform objects with a code and lists
+// are paginated by opaque cursors.
+package diaApiV2
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+type Env struct {
+	DataStore   models.Datastore
+	RelDB       models.RelDatastore
+	blockchains map[string]dia.BlockChain
+}
+
+// route is an endpoint of the v2 API. Every route must be described in the OpenAPI spec.
+type route struct {
+	method  string
+	path    string
+	handler gin.HandlerFunc
+}
+
+// NewEnv returns an environment for the v2 handlers.
+func NewEnv(datastore models.Datastore, relDB models.RelDatastore) *Env {
+	env := &Env{
+		DataStore:   datastore,
+		RelDB:       relDB,
+		blockchains: make(map[string]dia.BlockChain),
+	}
+	chains, err := relDB.GetAllBlockchains(false)
+	if err != nil {
+		log.Error("get all blockchains: ", err)
+	}
+	for _, chain := range chains {
+		env.blockchains[chain.Name] = chain
+	}
+	return env
+}
+
+func (env *Env) routes() []route {
+	return []route{
+		{http.MethodGet, "/blockchains", env.GetBlockchains},
+		{http.MethodGet, "/exchanges", env.GetExchanges},
+		{http.MethodGet, "/assets", env.GetAssets},
+		{http.MethodGet, "/assets/:blockchain/:address", env.GetAsset},
+		{http.MethodGet, "/assets/:blockchain/:address/quotation", env.GetQuotation},
+		{http.MethodGet, "/assets/:blockchain/:address/supply", env.GetSupply},
+		{http.MethodGet, "/nftClasses", env.GetNFTClasses},
+	}
+}
+
+// RegisterRoutes registers all v2 endpoints and the OpenAPI spec on @group.
+// Data endpoints are wrapped by @wrap, which can be used for caching.
+func (env *Env) RegisterRoutes(group *gin.RouterGroup, wrap func(gin.HandlerFunc) gin.HandlerFunc) {
+	group.GET("/openapi.json", GetSpec)
+	for _, r := range env.routes() {
+		handler := r.handler
+		if wrap != nil {
+			handler = wrap(handler)
+		}
+		group.Handle(r.method, r.path, validateParams, handler)
+	}
+}
+
+// GetSpec serves the OpenAPI spec of the v2 API.
+func GetSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(openAPISpec))
+}
+
+// GetBlockchains returns all blockchains known to DIA.
+func (env *Env) GetBlockchains(c *gin.Context) {
+	chains, err := env.RelDB.GetAllBlockchains(true)
+	if err != nil {
+		log.Error("get all blockchains: ", err)
+		sendError(c, http.StatusInternalServerError, ErrCodeInternal, "could not fetch blockchains")
+		return
+	}
+	response := BlockchainList{Data: []Blockchain{}}
+	for _, chain := range chains {
+		response.Data = append(response.Data, Blockchain{
+			Name:        chain.Name,
+			ChainID:     chain.ChainID,
+			NativeToken: newAsset(chain.NativeToken),
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetExchanges returns all exchanges from which DIA collects trades.
+func (env *Env) GetExchanges(c *gin.Context) {
+	exchanges, err := env.RelDB.GetAllExchanges()
+	if err != nil {
+		log.Error("get all exchanges: ", err)
+		sendError(c, http.StatusInternalServerError, ErrCodeInternal, "could not fetch exchanges")
+		return
+	}
+	response := ExchangeList{Data: []Exchange{}}
+	for _, exchange := range exchanges {
+		response.Data = append(response.Data, Exchange{
+			Name:        exchange.Name,
+			Centralized: exchange.Centralized,
+			Bridge:      exchange.Bridge,
+			Blockchain:  exchange.BlockChain.Name,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAssets returns assets sorted by their trading volume of the last 24h in descending order.
+func (env *Env) GetAssets(c *gin.Context) {
+	cur, limit, ok := parsePage(c)
+	if !ok {
+		return
+	}
+	cexOnly, err := strconv.ParseBool(c.DefaultQuery("cexOnly", "false"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, ErrCodeInvalidParameter, "cexOnly must be a boolean")
+		return
+	}
+
+	// Fetch one more asset in order to know whether there is a next page.
+	assetVolumes, err := env.RelDB.GetAssetsWithVOL(limit+1, cur.Offset, cexOnly, "")
+	if err != nil {
+		log.Error("get assets with volume: ", err)
+		sendError(c, http.StatusInternalServerError, ErrCodeInternal, "could not fetch assets")
+		return
+	}
+
+	response := AssetVolumeList{Data: []AssetVolume{}}
+	if int64(len(assetVolumes)) > limit {
+		assetVolumes = assetVolumes[:limit]
+		response.NextCursor = cursor{Offset: cur.Offset + limit}.encode()
+	}
+	for _, av := range assetVolumes {
+		item := AssetVolume{
+			Asset:              newAsset(av.Asset),
+			VolumeYesterdayUSD: av.Volume,
+		}
+		quotation, err := env.DataStore.GetAssetQuotationLatest(av.Asset)
+		if err != nil {
+			log.Warnf("get latest quotation of %s: %v", av.Asset.Symbol, err)
+		} else {
+			item.Price = &quotation.Price
+		}
+		response.Data = append(response.Data, item)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAsset returns the asset with the given blockchain and address.
+func (env *Env) GetAsset(c *gin.Context) {
+	asset, ok := env.assetFromParams(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, newAsset(asset))
+}
+
+// GetQuotation returns the latest price of an asset along with price and volume of the day before.
+func (env *Env) GetQuotation(c *gin.Context) {
+	asset, ok := env.assetFromParams(c)
+	if !ok {
+		return
+	}
+	timestamp := time.Now()
+
+	quotation, err := env.DataStore.GetAssetQuotation(asset, timestamp)
+	if err != nil {
+		sendError(c, http.StatusNotFound, ErrCodeNotFound, "no quotation for asset")
+		return
+	}
+	response := Quotation{
+		Asset:  newAsset(asset),
+		Price:  quotation.Price,
+		Source: quotation.Source,
+		Time:   quotation.Time,
+	}
+
+	quotationYesterday, err := env.DataStore.GetAssetQuotation(asset, timestamp.AddDate(0, 0, -1))
+	if err != nil {
+		log.Warn("get quotation yesterday: ", err)
+	} else {
+		response.PriceYesterday = &quotationYesterday.Price
+	}
+	volumeYesterday, err := env.RelDB.GetAssetVolume24H(asset)
+	if err != nil {
+		log.Warn("get volume yesterday: ", err)
+	} else {
+		response.VolumeYesterdayUSD = &volumeYesterday
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSupply returns the latest supply of an asset.
+func (env *Env) GetSupply(c *gin.Context) {
+	asset, ok := env.assetFromParams(c)
+	if !ok {
+		return
+	}
+	supplies, err := env.DataStore.GetSupplyInflux(asset, time.Time{}, time.Time{})
+	if err != nil {
+		log.Error("get supply: ", err)
+		sendError(c, http.StatusInternalServerError, ErrCodeInternal, "could not fetch supply")
+		return
+	}
+	if len(supplies) == 0 {
+		sendError(c, http.StatusNotFound, ErrCodeNotFound, "no supply for asset")
+		return
+	}
+
+	supply := supplies[0]
+	response := Supply{
+		Asset:             newAsset(asset),
+		Supply:            supply.Supply,
+		CirculatingSupply: supply.CirculatingSupply,
+		Exclusions:        []SupplyExclusion{},
+		Source:            supply.Source,
+		Time:              supply.Time,
+	}
+	for _, exclusion := range supply.Exclusions {
+		response.Exclusions = append(response.Exclusions, SupplyExclusion(exclusion))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetNFTClasses returns NFT classes, optionally restricted to a blockchain.
+func (env *Env) GetNFTClasses(c *gin.Context) {
+	cur, limit, ok := parsePage(c)
+	if !ok {
+		return
+	}
+	if cur.After != "" && !isUUID(cur.After) {
+		sendError(c, http.StatusBadRequest, ErrCodeInvalidCursor, "cursor is malformed")
+		return
+	}
+
+	nftClasses, IDs, err := env.RelDB.GetNFTClassesAfter(c.Query("blockchain"), cur.After, uint64(limit+1))
+	if err != nil {
+		log.Error("get nft classes: ", err)
+		sendError(c, http.StatusInternalServerError, ErrCodeInternal, "could not fetch nft classes")
+		return
+	}
+
+	response := NFTClassList{Data: []NFTClass{}}
+	if int64(len(nftClasses)) > limit {
+		nftClasses = nftClasses[:limit]
+		response.NextCursor = cursor{After: IDs[limit-1]}.encode()
+	}
+	for _, nftClass := range nftClasses {
+		response.Data = append(response.Data, NFTClass{
+			Address:      nftClass.Address,
+			Symbol:       nftClass.Symbol,
+			Name:         nftClass.Name,
+			Blockchain:   nftClass.Blockchain,
+			ContractType: nftClass.ContractType,
+			Category:     nftClass.Category,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// assetFromParams returns the asset given by the path parameters blockchain and address.
+// It sends an error response and returns false if there is no such asset.
+func (env *Env) assetFromParams(c *gin.Context) (dia.Asset, bool) {
+	blockchain := c.Param("blockchain")
+	address := c.Param("address")
+	if strings.Contains(env.blockchains[blockchain].ChainID, "Ethereum") {
+		if !common.IsHexAddress(address) {
+			sendError(c, http.StatusBadRequest, ErrCodeInvalidParameter, "address is not a valid hex address")
+			return dia.Asset{}, false
+		}
+		address = common.HexToAddress(address).Hex()
+	}
+
+	asset, err := env.RelDB.GetAsset(address, blockchain)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			sendError(c, http.StatusNotFound, ErrCodeNotFound, "asset not found")
+		} else {
+			log.Error("get asset: ", err)
+			sendError(c, http.StatusInternalServerError, ErrCodeInternal, "could not fetch asset")
+		}
+		return dia.Asset{}, false
+	}
+	return asset, true
+}
+
+// validateParams rejects requests with special characters in path or query parameters.
+func validateParams(c *gin.Context) {
+	for _, param := range c.Params {
+		if containsSpecialChars(param.Value) {
+			sendError(c, http.StatusBadRequest, ErrCodeInvalidParameter, "invalid path parameter "+param.Key)
+			return
+		}
+	}
+	for key, values := range c.Request.URL.Query() {
+		if key == "cursor" {
+			continue
+		}
+		for _, value := range values {
+			if containsSpecialChars(value) {
+				sendError(c, http.StatusBadRequest, ErrCodeInvalidParameter, "invalid query parameter "+key)
+				return
+			}
+		}
+	}
+}
+
+func containsSpecialChars(s string) bool {
+	return strings.ContainsAny(s, "!@#$%^&*()'\"|{}[];><?/`~,")
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package diaApiV2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type specDoc struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func parseSpec(t *testing.T) specDoc {
+	var spec specDoc
+	if err := json.Unmarshal([]byte(openAPISpec), &spec); err != nil {
+		t.Fatalf("spec is not valid json: %v", err)
+	}
+	return spec
+}
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	spec := parseSpec(t)
+	pathParam := regexp.MustCompile(`:(\w+)`)
+
+	env := &Env{}
+	routed := make(map[string]bool)
+	for _, r := range env.routes() {
+		path := pathParam.ReplaceAllString(r.path, "{$1}")
+		method := strings.ToLower(r.method)
+		routed[method+" "+path] = true
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("route %s %s is not described in the spec", r.method, path)
+		}
+	}
+	for path, methods := range spec.Paths {
+		for method := range methods {
+			if !routed[method+" "+path] {
+				t.Errorf("spec describes %s %s, which is not routed", method, path)
+			}
+		}
+	}
+}
+
+func TestSpecMatchesTypes(t *testing.T) {
+	spec := parseSpec(t)
+	types := []interface{}{
+		Asset{}, Blockchain{}, BlockchainList{}, Exchange{}, ExchangeList{}, AssetVolume{}, AssetVolumeList{},
+		Quotation{}, SupplyExclusion{}, Supply{}, NFTClass{}, NFTClassList{},
+	}
+	for _, v := range types {
+		typ := reflect.TypeOf(v)
+		schema, ok := spec.Components.Schemas[typ.Name()]
+		if !ok {
+			t.Errorf("no schema for %s", typ.Name())
+			continue
+		}
+		fields := make(map[string]bool)
+		for i := 0; i < typ.NumField(); i++ {
+			name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+			fields[name] = true
+			if _, ok := schema.Properties[name]; !ok {
+				t.Errorf("schema %s lacks property %s", typ.Name(), name)
+			}
+		}
+		for name := range schema.Properties {
+			if !fields[name] {
+				t.Errorf("schema %s has property %s, which %s lacks", typ.Name(), name, typ.Name())
+			}
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	for _, cur := range []cursor{{Offset: 200}, {After: "0b6a5c5e-4e0d-4c44-8c4e-0f3c6b1e5a2d"}} {
+		decoded, err := decodeCursor(cur.encode())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded != cur {
+			t.Errorf("decoded cursor %v, want %v", decoded, cur)
+		}
+	}
+	if _, err := decodeCursor("not a cursor"); err == nil {
+		t.Error("expected error for malformed cursor")
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		query  string
+		status int
+		code   string
+	}{
+		{"", http.StatusOK, ""},
+		{"limit=1000", http.StatusOK, ""},
+		{"limit=0", http.StatusBadRequest, ErrCodeInvalidParameter},
+		{"limit=abc", http.StatusBadRequest, ErrCodeInvalidParameter},
+		{"cursor=%25%25", http.StatusBadRequest, ErrCodeInvalidCursor},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/assets?"+tc.query, nil)
+		_, _, ok := parsePage(c)
+		if ok != (tc.status == http.StatusOK) {
+			t.Errorf("%q: ok=%v", tc.query, ok)
+		}
+		if tc.status == http.StatusOK {
+			continue
+		}
+		var body Error
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != tc.status || body.Error.Code != tc.code || body.Error.Status != tc.status {
+			t.Errorf("%q: got %d %+v", tc.query, w.Code, body.Error)
+		}
+	}
+}
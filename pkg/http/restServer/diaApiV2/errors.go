@@ -0,0 +1,33 @@
+package diaApiV2
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of the v2 API. Clients should branch on the code, not on the message.
+const (
+	ErrCodeInvalidParameter = "invalid_parameter"
+	ErrCodeInvalidCursor    = "invalid_cursor"
+	ErrCodeNotFound         = "not_found"
+	ErrCodeInternal         = "internal_error"
+)
+
+// Error is the body of all error responses of the v2 API.
+type Error struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// sendError aborts the request with a uniform error object.
+func sendError(c *gin.Context, status int, code string, message string) {
+	c.AbortWithStatusJSON(status, Error{Error: ErrorDetail{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}})
+}
@@ -0,0 +1,253 @@
+package diaApiV2
+
+// openAPISpec describes the v2 API. It is served at /v2/openapi.json. TestSpecMatchesRoutes makes sure
+// that it lists exactly the registered routes, so it has to be updated along with the handlers.
+const openAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "DIA API",
+    "version": "2.0.0",
+    "description": "Typed endpoints with uniform errors and cursor pagination. Lists return a nextCursor as long as there are more items, which is passed as cursor parameter to fetch the next page. Cursors are opaque."
+  },
+  "servers": [{"url": "https://api.diadata.org/v2"}],
+  "components": {
+    "securitySchemes": {
+      "apiKey": {"type": "apiKey", "in": "header", "name": "X-API-KEY"}
+    },
+    "parameters": {
+      "blockchain": {"name": "blockchain", "in": "path", "required": true, "schema": {"type": "string"}, "example": "Ethereum"},
+      "address": {"name": "address", "in": "path", "required": true, "schema": {"type": "string"}, "example": "0x84cA8bc7997272c7CfB4D0Cd3D55cd942B3c9419"},
+      "cursor": {"name": "cursor", "in": "query", "required": false, "schema": {"type": "string"}, "description": "nextCursor of the previous page."},
+      "limit": {"name": "limit", "in": "query", "required": false, "schema": {"type": "integer", "minimum": 1, "maximum": 1000, "default": 100}}
+    },
+    "responses": {
+      "BadRequest": {"description": "Invalid parameter or cursor.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}},
+      "NotFound": {"description": "The requested resource does not exist.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}},
+      "InternalError": {"description": "Internal error.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "required": ["error"],
+        "properties": {
+          "error": {
+            "type": "object",
+            "required": ["status", "code", "message"],
+            "properties": {
+              "status": {"type": "integer"},
+              "code": {"type": "string", "enum": ["invalid_parameter", "invalid_cursor", "not_found", "internal_error"]},
+              "message": {"type": "string"}
+            }
+          }
+        }
+      },
+      "Asset": {
+        "type": "object",
+        "properties": {
+          "symbol": {"type": "string"},
+          "name": {"type": "string"},
+          "address": {"type": "string"},
+          "blockchain": {"type": "string"},
+          "decimals": {"type": "integer"}
+        }
+      },
+      "Blockchain": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "chainId": {"type": "string"},
+          "nativeToken": {"$ref": "#/components/schemas/Asset"}
+        }
+      },
+      "BlockchainList": {
+        "type": "object",
+        "properties": {
+          "data": {"type": "array", "items": {"$ref": "#/components/schemas/Blockchain"}}
+        }
+      },
+      "Exchange": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "centralized": {"type": "boolean"},
+          "bridge": {"type": "boolean"},
+          "blockchain": {"type": "string"}
+        }
+      },
+      "ExchangeList": {
+        "type": "object",
+        "properties": {
+          "data": {"type": "array", "items": {"$ref": "#/components/schemas/Exchange"}}
+        }
+      },
+      "AssetVolume": {
+        "type": "object",
+        "properties": {
+          "asset": {"$ref": "#/components/schemas/Asset"},
+          "price": {"type": "number", "nullable": true},
+          "volumeYesterdayUSD": {"type": "number"}
+        }
+      },
+      "AssetVolumeList": {
+        "type": "object",
+        "properties": {
+          "data": {"type": "array", "items": {"$ref": "#/components/schemas/AssetVolume"}},
+          "nextCursor": {"type": "string"}
+        }
+      },
+      "Quotation": {
+        "type": "object",
+        "properties": {
+          "asset": {"$ref": "#/components/schemas/Asset"},
+          "price": {"type": "number"},
+          "priceYesterday": {"type": "number", "nullable": true},
+          "volumeYesterdayUSD": {"type": "number", "nullable": true},
+          "source": {"type": "string"},
+          "time": {"type": "string", "format": "date-time"}
+        }
+      },
+      "SupplyExclusion": {
+        "type": "object",
+        "properties": {
+          "blockchain": {"type": "string"},
+          "address": {"type": "string"},
+          "type": {"type": "string"},
+          "balance": {"type": "number"}
+        }
+      },
+      "Supply": {
+        "type": "object",
+        "properties": {
+          "asset": {"$ref": "#/components/schemas/Asset"},
+          "supply": {"type": "number"},
+          "circulatingSupply": {"type": "number"},
+          "exclusions": {"type": "array", "items": {"$ref": "#/components/schemas/SupplyExclusion"}},
+          "source": {"type": "string"},
+          "time": {"type": "string", "format": "date-time"}
+        }
+      },
+      "NFTClass": {
+        "type": "object",
+        "properties": {
+          "address": {"type": "string"},
+          "symbol": {"type": "string"},
+          "name": {"type": "string"},
+          "blockchain": {"type": "string"},
+          "contractType": {"type": "string"},
+          "category": {"type": "string"}
+        }
+      },
+      "NFTClassList": {
+        "type": "object",
+        "properties": {
+          "data": {"type": "array", "items": {"$ref": "#/components/schemas/NFTClass"}},
+          "nextCursor": {"type": "string"}
+        }
+      }
+    }
+  },
+  "security": [{}, {"apiKey": []}],
+  "paths": {
+    "/blockchains": {
+      "get": {
+        "operationId": "getBlockchains",
+        "summary": "All blockchains known to DIA.",
+        "responses": {
+          "200": {"description": "Blockchains.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/BlockchainList"}}}},
+          "500": {"$ref": "#/components/responses/InternalError"}
+        }
+      }
+    },
+    "/exchanges": {
+      "get": {
+        "operationId": "getExchanges",
+        "summary": "All exchanges from which DIA collects trades.",
+        "responses": {
+          "200": {"description": "Exchanges.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ExchangeList"}}}},
+          "500": {"$ref": "#/components/responses/InternalError"}
+        }
+      }
+    },
+    "/assets": {
+      "get": {
+        "operationId": "getAssets",
+        "summary": "Assets sorted by trading volume of the last 24h in descending order.",
+        "parameters": [
+          {"$ref": "#/components/parameters/cursor"},
+          {"$ref": "#/components/parameters/limit"},
+          {"name": "cexOnly", "in": "query", "required": false, "schema": {"type": "boolean", "default": false}, "description": "Only take into account volume on centralized exchanges."}
+        ],
+        "responses": {
+          "200": {"description": "A page of assets.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/AssetVolumeList"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "500": {"$ref": "#/components/responses/InternalError"}
+        }
+      }
+    },
+    "/assets/{blockchain}/{address}": {
+      "get": {
+        "operationId": "getAsset",
+        "summary": "Asset with the given blockchain and address.",
+        "parameters": [
+          {"$ref": "#/components/parameters/blockchain"},
+          {"$ref": "#/components/parameters/address"}
+        ],
+        "responses": {
+          "200": {"description": "The asset.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Asset"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "500": {"$ref": "#/components/responses/InternalError"}
+        }
+      }
+    },
+    "/assets/{blockchain}/{address}/quotation": {
+      "get": {
+        "operationId": "getQuotation",
+        "summary": "Latest price of an asset in USD.",
+        "parameters": [
+          {"$ref": "#/components/parameters/blockchain"},
+          {"$ref": "#/components/parameters/address"}
+        ],
+        "responses": {
+          "200": {"description": "The quotation.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Quotation"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "500": {"$ref": "#/components/responses/InternalError"}
+        }
+      }
+    },
+    "/assets/{blockchain}/{address}/supply": {
+      "get": {
+        "operationId": "getSupply",
+        "summary": "Latest total and circulating supply of an asset.",
+        "parameters": [
+          {"$ref": "#/components/parameters/blockchain"},
+          {"$ref": "#/components/parameters/address"}
+        ],
+        "responses": {
+          "200": {"description": "The supply.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Supply"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "500": {"$ref": "#/components/responses/InternalError"}
+        }
+      }
+    },
+    "/nftClasses": {
+      "get": {
+        "operationId": "getNFTClasses",
+        "summary": "NFT classes, optionally restricted to a blockchain.",
+        "parameters": [
+          {"$ref": "#/components/parameters/cursor"},
+          {"$ref": "#/components/parameters/limit"},
+          {"name": "blockchain", "in": "query", "required": false, "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"description": "A page of NFT classes.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NFTClassList"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "500": {"$ref": "#/components/responses/InternalError"}
+        }
+      }
+    }
+  }
+}
+`
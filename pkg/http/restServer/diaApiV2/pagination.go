@@ -0,0 +1,66 @@
+package diaApiV2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// cursor is the position after the last item of a page. Clients receive it base64 encoded
+// and must treat it as opaque. Offset is used for lists without a stable key, such as
+// assets sorted by volume, After for lists with a stable key.
+type cursor struct {
+	Offset int64  `json:"o,omitempty"`
+	After  string `json:"a,omitempty"`
+}
+
+func (cur cursor) encode() string {
+	b, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cur cursor, err error) {
+	if s == "" {
+		return
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(b, &cur)
+	if err != nil {
+		return
+	}
+	if cur.Offset < 0 {
+		err = errors.New("negative offset")
+	}
+	return
+}
+
+// parsePage reads the query parameters cursor and limit. It sends an error response and
+// returns false if they are invalid.
+func parsePage(c *gin.Context) (cur cursor, limit int64, ok bool) {
+	cur, err := decodeCursor(c.Query("cursor"))
+	if err != nil {
+		sendError(c, http.StatusBadRequest, ErrCodeInvalidCursor, "cursor is malformed")
+		return
+	}
+	limit = defaultPageLimit
+	if limitString := c.Query("limit"); limitString != "" {
+		limit, err = strconv.ParseInt(limitString, 10, 64)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			sendError(c, http.StatusBadRequest, ErrCodeInvalidParameter, "limit must be an integer between 1 and "+strconv.Itoa(maxPageLimit))
+			return
+		}
+	}
+	return cur, limit, true
+}
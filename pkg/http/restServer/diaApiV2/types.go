@@ -0,0 +1,98 @@
+package diaApiV2
+
+import (
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Response types of the v2 API. They are described by the schemas of the same name in openapi.go.
+
+type Asset struct {
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	Blockchain string `json:"blockchain"`
+	Decimals   uint8  `json:"decimals"`
+}
+
+type Blockchain struct {
+	Name        string `json:"name"`
+	ChainID     string `json:"chainId,omitempty"`
+	NativeToken Asset  `json:"nativeToken"`
+}
+
+type BlockchainList struct {
+	Data []Blockchain `json:"data"`
+}
+
+type Quotation struct {
+	Asset              Asset     `json:"asset"`
+	Price              float64   `json:"price"`
+	PriceYesterday     *float64  `json:"priceYesterday"`
+	VolumeYesterdayUSD *float64  `json:"volumeYesterdayUSD"`
+	Source             string    `json:"source"`
+	Time               time.Time `json:"time"`
+}
+
+type AssetVolume struct {
+	Asset              Asset    `json:"asset"`
+	Price              *float64 `json:"price"`
+	VolumeYesterdayUSD float64  `json:"volumeYesterdayUSD"`
+}
+
+type AssetVolumeList struct {
+	Data       []AssetVolume `json:"data"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+type SupplyExclusion struct {
+	Blockchain string  `json:"blockchain"`
+	Address    string  `json:"address"`
+	Type       string  `json:"type"`
+	Balance    float64 `json:"balance"`
+}
+
+type Supply struct {
+	Asset             Asset             `json:"asset"`
+	Supply            float64           `json:"supply"`
+	CirculatingSupply float64           `json:"circulatingSupply"`
+	Exclusions        []SupplyExclusion `json:"exclusions"`
+	Source            string            `json:"source"`
+	Time              time.Time         `json:"time"`
+}
+
+type Exchange struct {
+	Name        string `json:"name"`
+	Centralized bool   `json:"centralized"`
+	Bridge      bool   `json:"bridge"`
+	Blockchain  string `json:"blockchain,omitempty"`
+}
+
+type ExchangeList struct {
+	Data []Exchange `json:"data"`
+}
+
+type NFTClass struct {
+	Address      string `json:"address"`
+	Symbol       string `json:"symbol"`
+	Name         string `json:"name"`
+	Blockchain   string `json:"blockchain"`
+	ContractType string `json:"contractType"`
+	Category     string `json:"category"`
+}
+
+type NFTClassList struct {
+	Data       []NFTClass `json:"data"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+func newAsset(asset dia.Asset) Asset {
+	return Asset{
+		Symbol:     asset.Symbol,
+		Name:       asset.Name,
+		Address:    asset.Address,
+		Blockchain: asset.Blockchain,
+		Decimals:   asset.Decimals,
+	}
+}
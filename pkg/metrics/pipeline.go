@@ -0,0 +1,135 @@
+// Package metrics provides the Prometheus metrics of scrapers, services and oracles.
+// The metrics are registered in the default registry, which is served on /metrics by the probes server
+// or, in binaries without probes, by Serve.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons for dropped trades.
+const (
+	DropReasonCheckTrade          = "checkTrade"
+	DropReasonStablecoinTolerance = "stablecoinTolerance"
+	DropReasonPreviousBlock       = "previousBlock"
+)
+
+// Outcomes of oracle updates.
+const (
+	OracleUpdateSuccess = "success"
+	OracleUpdateError   = "error"
+)
+
+// Metrics of scrapers and the data pipeline.
+var (
+	TradesReceived = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dia_trades_received_total",
+			Help: "Trades received from exchange scrapers.",
+		},
+		[]string{"exchange"},
+	)
+	TradesDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dia_trades_dropped_total",
+			Help: "Trades which were not added to a trades block.",
+		},
+		[]string{"exchange", "reason"},
+	)
+	KafkaWriteDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dia_kafka_write_duration_seconds",
+			Help:    "Latency of writes to kafka.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"topic"},
+	)
+	KafkaWriteErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dia_kafka_write_errors_total",
+			Help: "Failed writes to kafka.",
+		},
+		[]string{"topic"},
+	)
+	FilterComputeDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dia_filter_compute_duration_seconds",
+			Help:    "Time spent by the filters block service per trades block.",
+			Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+		},
+		[]string{"stage"},
+	)
+	FlushErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dia_flush_errors_total",
+			Help: "Failed flushes of batched writes.",
+		},
+		[]string{"store"},
+	)
+	ScraperPairs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dia_scraper_pairs",
+			Help: "Pairs subscribed by the collector by health state.",
+		},
+		[]string{"exchange", "state"},
+	)
+	ScraperResubscriptions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dia_scraper_resubscriptions_total",
+			Help: "Resubscriptions of pairs without trades within the watchdog delay.",
+		},
+		[]string{"exchange"},
+	)
+	WebsocketMessages = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dia_websocket_messages_total",
+			Help: "Messages received from websocket APIs of exchanges.",
+		},
+		[]string{"exchange"},
+	)
+	WebsocketReconnects = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dia_websocket_reconnects_total",
+			Help: "Reconnection attempts to websocket APIs of exchanges.",
+		},
+		[]string{"exchange"},
+	)
+	OracleUpdates = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dia_oracle_updates_total",
+			Help: "Updates of oracle contracts by outcome.",
+		},
+		[]string{"key", "outcome"},
+	)
+	SynthPriceDeviation = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dia_synth_price_deviation",
+			Help: "Relative deviation of the market price of synthetic assets from their fair value.",
+		},
+		[]string{"blockchain", "asset"},
+	)
+	ReserveCollateralRatio = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dia_reserve_collateral_ratio",
+			Help: "Ratio of the reserves locked on the source chain to the supply minted on destination chains of bridged assets.",
+		},
+		[]string{"blockchain", "asset"},
+	)
+)
+
+// ObserveOracleUpdate counts an update of @key in an oracle contract which failed with @err or succeeded if @err is nil.
+func ObserveOracleUpdate(key string, err error) {
+	if err != nil {
+		OracleUpdates.WithLabelValues(key, OracleUpdateError).Inc()
+		return
+	}
+	OracleUpdates.WithLabelValues(key, OracleUpdateSuccess).Inc()
+}
+
+// ObserveSince records the time elapsed since @start in seconds.
+func ObserveSince(observer prometheus.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveOracleUpdate(t *testing.T) {
+	successes := OracleUpdates.WithLabelValues("BTC/USD", OracleUpdateSuccess)
+	failures := OracleUpdates.WithLabelValues("BTC/USD", OracleUpdateError)
+	successesBefore, failuresBefore := testutil.ToFloat64(successes), testutil.ToFloat64(failures)
+
+	ObserveOracleUpdate("BTC/USD", nil)
+	ObserveOracleUpdate("BTC/USD", nil)
+	ObserveOracleUpdate("BTC/USD", errors.New("nonce too low"))
+
+	if got := testutil.ToFloat64(successes) - successesBefore; got != 2 {
+		t.Errorf("got %v successful updates, want 2", got)
+	}
+	if got := testutil.ToFloat64(failures) - failuresBefore; got != 1 {
+		t.Errorf("got %v failed updates, want 1", got)
+	}
+}
@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+// Serve serves /metrics on the probes port given by LISTEN_PORT_PROBES in the background.
+// Binaries which run the probes server get /metrics from there and must not call Serve.
+func Serve() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		err := http.ListenAndServe(utils.Getenv("LISTEN_PORT_PROBES", ":2345"), mux)
+		if err != nil {
+			log.Error("serve metrics: ", err)
+		}
+	}()
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const apikeyVars = "apikey_id,key_prefix,key_hash,owner,scopes,monthly_quota,rate_limit,rate_burst,is_active,creation_time,expiry_time,rotated_from"
+
+// SetAPIKey stores @key in postgres and returns its ID.
+func (rdb *RelDB) SetAPIKey(key dia.APIKey) (ID string, err error) {
+	var expiry interface{}
+	if !key.Expiry.IsZero() {
+		expiry = key.Expiry
+	}
+	var rotatedFrom interface{}
+	if key.RotatedFrom != "" {
+		rotatedFrom = key.RotatedFrom
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (key_prefix,key_hash,owner,scopes,monthly_quota,rate_limit,rate_burst,is_active,expiry_time,rotated_from)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING apikey_id`,
+		apikeyTable,
+	)
+	err = rdb.postgresClient.QueryRow(
+		context.Background(),
+		query,
+		key.Prefix,
+		key.Hash,
+		key.Owner,
+		key.Scopes,
+		key.MonthlyQuota,
+		key.RateLimit,
+		key.RateBurst,
+		key.Active,
+		expiry,
+		rotatedFrom,
+	).Scan(&ID)
+	return
+}
+
+// GetAPIKey returns the api key with public part @prefix.
+func (rdb *RelDB) GetAPIKey(prefix string) (key dia.APIKey, err error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE key_prefix=$1", apikeyVars, apikeyTable)
+	var (
+		expiry      sql.NullTime
+		rotatedFrom sql.NullString
+	)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, prefix).Scan(
+		&key.ID,
+		&key.Prefix,
+		&key.Hash,
+		&key.Owner,
+		&key.Scopes,
+		&key.MonthlyQuota,
+		&key.RateLimit,
+		&key.RateBurst,
+		&key.Active,
+		&key.Created,
+		&expiry,
+		&rotatedFrom,
+	)
+	if err != nil {
+		return
+	}
+	if expiry.Valid {
+		key.Expiry = expiry.Time
+	}
+	if rotatedFrom.Valid {
+		key.RotatedFrom = rotatedFrom.String
+	}
+	return
+}
+
+// SetAPIKeyExpiry sets the expiry time of the api key with @prefix. Keys expire immediately if @expiry is in the past.
+func (rdb *RelDB) SetAPIKeyExpiry(prefix string, expiry time.Time) error {
+	query := fmt.Sprintf("UPDATE %s SET expiry_time=$1 WHERE key_prefix=$2", apikeyTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, expiry, prefix)
+	return err
+}
+
+// DeactivateAPIKey revokes the api key with @prefix.
+func (rdb *RelDB) DeactivateAPIKey(prefix string) error {
+	query := fmt.Sprintf("UPDATE %s SET is_active=false WHERE key_prefix=$1", apikeyTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, prefix)
+	return err
+}
+
+// AddAPIKeyUsage adds @requests to the number of requests done with the key with @keyID on @endpoint at @day.
+func (rdb *RelDB) AddAPIKeyUsage(keyID string, endpoint string, day time.Time, requests int64) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (apikey_id,endpoint,day,requests) VALUES ($1,$2,$3,$4)
+	ON CONFLICT (apikey_id,endpoint,day) DO UPDATE SET requests=%s.requests+EXCLUDED.requests`,
+		apikeyUsageTable,
+		apikeyUsageTable,
+	)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, keyID, endpoint, day.Format("2006-01-02"), requests)
+	return err
+}
+
+// GetAPIKeyUsage returns the usage of the key with @keyID per endpoint and day since @starttime.
+func (rdb *RelDB) GetAPIKeyUsage(keyID string, starttime time.Time) (usage []dia.APIKeyUsage, err error) {
+	query := fmt.Sprintf("SELECT endpoint,day,requests FROM %s WHERE apikey_id=$1 AND day>=$2 ORDER BY day DESC,endpoint", apikeyUsageTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, keyID, starttime.Format("2006-01-02"))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u dia.APIKeyUsage
+		err = rows.Scan(&u.Endpoint, &u.Day, &u.Requests)
+		if err != nil {
+			return
+		}
+		usage = append(usage, u)
+	}
+	return
+}
+
+// GetAPIKeyRequests returns the total number of requests done with the key with @keyID since @starttime.
+func (rdb *RelDB) GetAPIKeyRequests(keyID string, starttime time.Time) (requests int64, err error) {
+	query := fmt.Sprintf("SELECT COALESCE(SUM(requests),0) FROM %s WHERE apikey_id=$1 AND day>=$2", apikeyUsageTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, keyID, starttime.Format("2006-01-02")).Scan(&requests)
+	return
+}
+
+// IncrAPIKeyRequests increments the number of requests done with the key with @keyID in the month starting at @month
+// and returns the new count. The counter is kept in redis so that it is shared by all instances of the api.
+// A missing counter is initialized from the usage stored in postgres.
+func (rdb *RelDB) IncrAPIKeyRequests(keyID string, month time.Time) (int64, error) {
+	key := keyAPIKeyRequests + keyID + "_" + month.Format("2006-01")
+	exists, err := rdb.redisClient.Exists(key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		requests, err := rdb.GetAPIKeyRequests(keyID, month)
+		if err != nil {
+			return 0, err
+		}
+		// Keep the counter a few days beyond the end of the month.
+		ttl := time.Until(month.AddDate(0, 1, 7))
+		if err = rdb.redisClient.SetNX(key, requests, ttl).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return rdb.redisClient.Incr(key).Result()
+}
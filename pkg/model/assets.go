@@ -138,6 +138,48 @@ func (rdb *RelDB) GetAsset(address, blockchain string) (asset dia.Asset, err err
 	return
 }
 
+// GetAssetsBatch returns the full assets of all @assets given by address and blockchain in a single query.
+// Unknown assets are omitted from the result.
+func (rdb *RelDB) GetAssetsBatch(assets []dia.Asset) (fullAssets []dia.Asset, err error) {
+	if len(assets) == 0 {
+		return
+	}
+	var addresses, blockchains []string
+	for _, asset := range assets {
+		addresses = append(addresses, asset.Address)
+		blockchains = append(blockchains, asset.Blockchain)
+	}
+	query := fmt.Sprintf(`
+	SELECT symbol,name,address,decimals,blockchain FROM %s
+	WHERE (address,blockchain) IN (SELECT * FROM unnest($1::text[],$2::text[]))`,
+		assetTable,
+	)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, addresses, blockchains)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals string
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		decimalsInt, err := strconv.Atoi(decimals)
+		if err != nil {
+			log.Errorf("decimals of asset %s on %s: %v", asset.Address, asset.Blockchain, err)
+			continue
+		}
+		asset.Decimals = uint8(decimalsInt)
+		fullAssets = append(fullAssets, asset)
+	}
+	return
+}
+
 // GetAssetByID returns an asset by its uuid
 func (rdb *RelDB) GetAssetByID(assetID string) (asset dia.Asset, err error) {
 	var decimals string
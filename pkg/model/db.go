@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia/helpers/db"
+	"github.com/diadata-org/diadata/pkg/metrics"
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/go-redis/redis"
@@ -27,6 +28,11 @@ type Datastore interface {
 	SaveSynthSupplyInflux(*dia.SynthAssetSupply) error
 	GetSynthSupplyInflux(string, string, string, int, time.Time, time.Time) ([]dia.SynthAssetSupply, error)
 	GetSynthAssets(string, string) ([]string, error)
+	SaveSynthAssetValuation(dia.SynthAssetValuation) error
+	GetSynthAssetValuations(string, string, time.Time, time.Time) ([]dia.SynthAssetValuation, error)
+	GetSynthAssetQuotationCache(dia.Asset) (*AssetQuotation, error)
+	SaveProofOfReserve(dia.ProofOfReserve) error
+	GetProofOfReserve(string, string, time.Time, time.Time) ([]dia.ProofOfReserve, error)
 
 	SetDiaTotalSupply(totalSupply float64) error
 	GetDiaTotalSupply() (float64, error)
@@ -36,16 +42,34 @@ type Datastore interface {
 	GetSymbols(exchange string) ([]string, error)
 	GetLastTradeTimeForExchange(asset dia.Asset, exchange string) (*time.Time, error)
 	SetLastTradeTimeForExchange(asset dia.Asset, exchange string, t time.Time) error
+	SetExchangeHealth(health dia.ExchangeHealth) error
+	GetExchangeHealth(exchange string, starttime time.Time, endtime time.Time) ([]dia.ExchangeHealth, error)
+
+	SaveFuturesTrade(trade dia.FuturesTrade) error
+	SaveFundingRate(rate dia.FundingRate) error
+	SaveOpenInterest(openInterest dia.OpenInterest) error
+	SaveDerivativePrice(price dia.DerivativePrice) error
+	SaveOptionOrderbookSnapshot(snapshot dia.OptionOrderbookSnapshot) error
+	GetFuturesTrades(exchange string, instrument string, starttime time.Time, endtime time.Time) ([]dia.FuturesTrade, error)
+	GetFundingRates(exchange string, instrument string, starttime time.Time, endtime time.Time) ([]dia.FundingRate, error)
+	GetOpenInterest(exchange string, instrument string, starttime time.Time, endtime time.Time) ([]dia.OpenInterest, error)
+	GetDerivativePrices(exchange string, instrument string, starttime time.Time, endtime time.Time) ([]dia.DerivativePrice, error)
+	GetOptionOrderbookSnapshots(exchange string, underlying string, starttime time.Time, endtime time.Time) ([]dia.OptionOrderbookSnapshot, error)
+	SaveCVI(cvi dia.CVI) error
+	GetCVI(underlying string, starttime time.Time, endtime time.Time) ([]dia.CVI, error)
 	GetFirstTradeDate(table string) (time.Time, error)
 	SaveTradeInflux(t *dia.Trade) error
 	SaveTradeInfluxToTable(t *dia.Trade, table string) error
 	GetTradeInflux(dia.Asset, string, time.Time, time.Duration) (*dia.Trade, error)
 	SaveFilterInflux(filter string, asset dia.Asset, exchange string, value float64, t time.Time) error
 	GetLastTrades(asset dia.Asset, exchange string, maxTrades int, fullAsset bool) ([]dia.Trade, error)
+	GetLastTradePair(exchange string, pair string, endtime time.Time, window time.Duration) (dia.Trade, error)
+	GetLastTradesPairSeries(exchange string, pair string, starttime time.Time, endtime time.Time, grouping string) ([]dia.Trade, error)
 	GetAllTrades(t time.Time, maxTrades int) ([]dia.Trade, error)
 	GetTradesByExchanges(asset dia.Asset, baseAssets []dia.Asset, exchange []string, startTime, endTime time.Time) ([]dia.Trade, error)
 	GetTradesByExchangesFull(asset dia.Asset, baseAssets []dia.Asset, exchanges []string, returnBasetoken bool, startTime, endTime time.Time) ([]dia.Trade, error)
 	GetTradesByExchangesBatched(asset dia.Asset, baseAssets []dia.Asset, exchanges []string, startTimes, endTimes []time.Time) ([]dia.Trade, error)
+	GetTradesInRange(asset dia.Asset, exchange string, starttime time.Time, endtime time.Time) ([]dia.Trade, error)
 	GetTradesByExchangesBatchedFull(asset dia.Asset, baseAssets []dia.Asset, exchanges []string, returnBasetoken bool, startTimes, endTimes []time.Time) ([]dia.Trade, error)
 	GetActiveExchangesAndPairs(address string, blockchain string, starttime time.Time, endtime time.Time) (map[string][]dia.Pair, error)
 	GetOldTradesFromInflux(table string, exchange string, verified bool, timeInit, timeFinal time.Time) ([]dia.Trade, error)
@@ -56,6 +80,8 @@ type Datastore interface {
 	FlushRedisPipe() error
 	GetFilterPoints(filter string, exchange string, symbol string, scale string, starttime time.Time, endtime time.Time) (*Points, error)
 	GetFilterPointsAsset(filter string, exchange string, address string, blockchain string, starttime time.Time, endtime time.Time) (*Points, error)
+	GetFilterPointsInRange(filter string, asset dia.Asset, exchange string, starttime time.Time, endtime time.Time) ([]dia.FilterPoint, error)
+	GetFilterPointsAround(filter string, asset dia.Asset, timestamp time.Time, maxDistance time.Duration) (before *dia.FilterPoint, after *dia.FilterPoint, err error)
 	SetFilter(filterName string, asset dia.Asset, exchange string, value float64, t time.Time) error
 	GetLastPriceBefore(asset dia.Asset, filter string, exchange string, timestamp time.Time) (Price, error)
 	SetAvailablePairs(exchange string, pairs []dia.ExchangePair) error
@@ -88,6 +114,8 @@ type Datastore interface {
 	GetAssetQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) ([]AssetQuotation, error)
 	GetAssetQuotationLatest(asset dia.Asset) (*AssetQuotation, error)
 	GetSortedAssetQuotations(assets []dia.Asset) ([]AssetQuotation, error)
+	GetAssetQuotationsBatch(assets []dia.Asset, timestamp time.Time) []AssetQuotationResult
+	GetAssetQuotationBatchItems(assets []dia.Asset, timestamp time.Time, relDB RelDatastore) []AssetQuotationBatchItem
 	AddAssetQuotationsToBatch(quotations []*AssetQuotation) error
 	SetAssetQuotationCache(quotation *AssetQuotation, check bool) (bool, error)
 	GetAssetQuotationCache(asset dia.Asset) (*AssetQuotation, error)
@@ -108,11 +136,11 @@ type Datastore interface {
 	GetInterestRate(symbol, date string) (*InterestRate, error)
 	GetInterestRateRange(symbol, dateInit, dateFinal string) ([]*InterestRate, error)
 	GetRatesMeta() (RatesMeta []InterestRateMeta, err error)
-	GetCompoundedIndex(symbol string, date time.Time, daysPerYear int, rounding int) (*InterestRate, error)
-	GetCompoundedIndexRange(symbol string, dateInit, dateFinal time.Time, daysPerYear int, rounding int) ([]*InterestRate, error)
-	GetCompoundedAvg(symbol string, date time.Time, calDays, daysPerYear int, rounding int) (*InterestRate, error)
-	GetCompoundedAvgRange(symbol string, dateInit, dateFinal time.Time, calDays, daysPerYear int, rounding int) ([]*InterestRate, error)
-	GetCompoundedAvgDIARange(symbol string, dateInit, dateFinal time.Time, calDays, daysPerYear int, rounding int) ([]*InterestRate, error)
+	GetCompoundedIndex(symbol string, calendar *dia.Calendar, date time.Time, convention dia.CompoundingConvention) (*InterestRate, error)
+	GetCompoundedIndexRange(symbol string, calendar *dia.Calendar, dateInit, dateFinal time.Time, convention dia.CompoundingConvention) ([]*InterestRate, error)
+	GetCompoundedAvg(symbol string, calendar *dia.Calendar, date time.Time, calDays int, convention dia.CompoundingConvention) (*InterestRate, error)
+	GetCompoundedAvgRange(symbol string, calendar *dia.Calendar, dateInit, dateFinal time.Time, calDays int, convention dia.CompoundingConvention) ([]*InterestRate, error)
+	GetCompoundedAvgDIARange(symbol string, calendar *dia.Calendar, dateInit, dateFinal time.Time, calDays, daysPerYear int, rounding int) ([]*InterestRate, error)
 
 	// Foreign quotation methods
 	SaveForeignQuotationInflux(fq ForeignQuotation) error
@@ -125,6 +153,11 @@ type Datastore interface {
 
 	SaveIndexEngineTimeInflux(map[string]string, map[string]interface{}, time.Time) error
 	GetBenchmarkedIndexValuesInflux(string, time.Time, time.Time) (BenchmarkedIndex, error)
+	SetIndexValue(symbol string, value float64, timestamp time.Time) error
+	SetIndexBlockCache(symbol string, block *dia.IndexBlock) error
+	GetIndexBlockCache(symbol string) (dia.IndexBlock, error)
+	SetIndexProposalCache(proposal *dia.IndexRebalanceProposal) error
+	GetIndexProposalCache(symbol string) (dia.IndexRebalanceProposal, error)
 	// Token methods
 	// SaveTokenDetailInflux(tk Token) error
 	// GetTokenDetailInflux(symbol, source string, timestamp time.Time) (Token, error)
@@ -133,6 +166,8 @@ type Datastore interface {
 	// Stock methods
 	SetStockQuotation(sq StockQuotation) error
 	GetStockQuotation(source string, symbol string, timeInit time.Time, timeFinal time.Time) ([]StockQuotation, error)
+	GetStockQuotationLatest(source string, symbol string, timestamp time.Time) (StockQuotation, error)
+	GetStockQuotationSeries(source string, symbol string, timeInit time.Time, timeFinal time.Time, grouping string) ([]StockQuotation, error)
 	GetStockSymbols() (map[Stock]string, error)
 }
 
@@ -161,6 +196,9 @@ const (
 	influxDbBenchmarkedIndexTableName = "benchmarkedIndexValues"
 	influxDbVwapFireflyTable          = "vwapFirefly"
 	influxDbSynthSupplyTable          = "synthsupply"
+	influxDbSynthValuationTable       = "synthvaluation"
+	influxDbProofOfReserveTable       = "proofofreserve"
+	influxDbExchangeHealthTable       = "exchangeHealth"
 
 	influxDBDefaultURL = "http://influxdb:8086"
 )
@@ -256,6 +294,7 @@ func (datastore *DB) Flush() error {
 func (datastore *DB) WriteBatchInflux() (err error) {
 	err = datastore.influxClient.Write(datastore.influxBatchPoints)
 	if err != nil {
+		metrics.FlushErrors.WithLabelValues("influx").Inc()
 		log.Errorln("WriteBatchInflux", err)
 		return
 	}
@@ -279,6 +318,9 @@ func (datastore *DB) addPoint(pt *clientInfluxdb.Point) {
 func (datastore *DB) ExecuteRedisPipe() (err error) {
 	// TO DO: Handle first return value for read requests.
 	_, err = datastore.redisPipe.Exec()
+	if err != nil {
+		metrics.FlushErrors.WithLabelValues("redis").Inc()
+	}
 	return
 }
 
@@ -0,0 +1,315 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+const (
+	influxDbFuturesTradesTable    = "futuresTrades"
+	influxDbFundingRatesTable     = "fundingRates"
+	influxDbOpenInterestTable     = "openInterest"
+	influxDbDerivativePricesTable = "derivativePrices"
+	influxDbOptionOrderbooksTable = "optionOrderbooks"
+	influxDbCVITable              = "cvi"
+
+	optionOrderbookFields = "BidPrice,BidSize,AskPrice,AskSize,Strike,Expiry,UnderlyingPrice"
+)
+
+// SaveFuturesTrade stores a trade of a futures or perpetual contract in influx. Flushed when more than maxPoints in batch.
+func (datastore *DB) SaveFuturesTrade(trade dia.FuturesTrade) error {
+	tags := map[string]string{
+		"exchange":   trade.Exchange,
+		"instrument": trade.Instrument,
+		"underlying": trade.Underlying,
+		"kind":       trade.Kind,
+	}
+	fields := map[string]interface{}{
+		"Price":          trade.Price,
+		"Volume":         trade.Volume,
+		"ForeignTradeID": trade.ForeignTradeID,
+	}
+	return datastore.addDerivativePoint("SaveFuturesTrade", influxDbFuturesTradesTable, tags, fields, trade.Time)
+}
+
+// SaveFundingRate stores the funding rate of a perpetual contract in influx.
+func (datastore *DB) SaveFundingRate(rate dia.FundingRate) error {
+	tags := map[string]string{
+		"exchange":   rate.Exchange,
+		"instrument": rate.Instrument,
+	}
+	fields := map[string]interface{}{
+		"Rate": rate.Rate,
+	}
+	return datastore.addDerivativePoint("SaveFundingRate", influxDbFundingRatesTable, tags, fields, rate.Time)
+}
+
+// SaveOpenInterest stores the open interest of a derivative instrument in influx.
+func (datastore *DB) SaveOpenInterest(openInterest dia.OpenInterest) error {
+	tags := map[string]string{
+		"exchange":   openInterest.Exchange,
+		"instrument": openInterest.Instrument,
+	}
+	fields := map[string]interface{}{
+		"OpenInterest": openInterest.OpenInterest,
+	}
+	return datastore.addDerivativePoint("SaveOpenInterest", influxDbOpenInterestTable, tags, fields, openInterest.Time)
+}
+
+// SaveDerivativePrice stores mark and index price of a derivative instrument in influx.
+func (datastore *DB) SaveDerivativePrice(price dia.DerivativePrice) error {
+	tags := map[string]string{
+		"exchange":   price.Exchange,
+		"instrument": price.Instrument,
+	}
+	fields := map[string]interface{}{
+		"MarkPrice":  price.MarkPrice,
+		"IndexPrice": price.IndexPrice,
+	}
+	return datastore.addDerivativePoint("SaveDerivativePrice", influxDbDerivativePricesTable, tags, fields, price.Time)
+}
+
+// SaveOptionOrderbookSnapshot stores the top of the order book of an option in influx.
+func (datastore *DB) SaveOptionOrderbookSnapshot(snapshot dia.OptionOrderbookSnapshot) error {
+	tags := map[string]string{
+		"exchange":   snapshot.Exchange,
+		"instrument": snapshot.Instrument,
+		"underlying": snapshot.Underlying,
+		"optionType": snapshot.OptionType,
+	}
+	fields := map[string]interface{}{
+		"BidPrice":        snapshot.BidPrice,
+		"BidSize":         snapshot.BidSize,
+		"AskPrice":        snapshot.AskPrice,
+		"AskSize":         snapshot.AskSize,
+		"Strike":          snapshot.Strike,
+		"Expiry":          snapshot.Expiry.Unix(),
+		"UnderlyingPrice": snapshot.UnderlyingPrice,
+	}
+	return datastore.addDerivativePoint("SaveOptionOrderbookSnapshot", influxDbOptionOrderbooksTable, tags, fields, snapshot.Time)
+}
+
+func (datastore *DB) addDerivativePoint(caller string, table string, tags map[string]string, fields map[string]interface{}, t time.Time) error {
+	pt, err := clientInfluxdb.NewPoint(table, tags, fields, t)
+	if err != nil {
+		log.Errorln(caller+":", err)
+		return err
+	}
+	datastore.addPoint(pt)
+	return nil
+}
+
+// GetFuturesTrades returns the trades of @instrument on @exchange in the time range (@starttime, @endtime] in descending order.
+func (datastore *DB) GetFuturesTrades(exchange string, instrument string, starttime time.Time, endtime time.Time) ([]dia.FuturesTrade, error) {
+	trades := []dia.FuturesTrade{}
+	rows, err := datastore.queryDerivative("time,Price,Volume,ForeignTradeID,underlying,kind", influxDbFuturesTradesTable, exchange, instrument, starttime, endtime)
+	if err != nil {
+		return trades, err
+	}
+	for _, row := range rows {
+		trade := dia.FuturesTrade{Exchange: exchange, Instrument: instrument}
+		trade.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return trades, err
+		}
+		trade.Price = influxFloat(row[1])
+		trade.Volume = influxFloat(row[2])
+		trade.ForeignTradeID = influxString(row[3])
+		trade.Underlying = influxString(row[4])
+		trade.Kind = influxString(row[5])
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+// GetFundingRates returns the funding rates of @instrument on @exchange in the time range (@starttime, @endtime] in descending order.
+func (datastore *DB) GetFundingRates(exchange string, instrument string, starttime time.Time, endtime time.Time) ([]dia.FundingRate, error) {
+	rates := []dia.FundingRate{}
+	rows, err := datastore.queryDerivative("time,Rate", influxDbFundingRatesTable, exchange, instrument, starttime, endtime)
+	if err != nil {
+		return rates, err
+	}
+	for _, row := range rows {
+		rate := dia.FundingRate{Exchange: exchange, Instrument: instrument}
+		rate.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return rates, err
+		}
+		rate.Rate = influxFloat(row[1])
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// GetOpenInterest returns the open interest of @instrument on @exchange in the time range (@starttime, @endtime] in descending order.
+func (datastore *DB) GetOpenInterest(exchange string, instrument string, starttime time.Time, endtime time.Time) ([]dia.OpenInterest, error) {
+	openInterests := []dia.OpenInterest{}
+	rows, err := datastore.queryDerivative("time,OpenInterest", influxDbOpenInterestTable, exchange, instrument, starttime, endtime)
+	if err != nil {
+		return openInterests, err
+	}
+	for _, row := range rows {
+		openInterest := dia.OpenInterest{Exchange: exchange, Instrument: instrument}
+		openInterest.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return openInterests, err
+		}
+		openInterest.OpenInterest = influxFloat(row[1])
+		openInterests = append(openInterests, openInterest)
+	}
+	return openInterests, nil
+}
+
+// GetDerivativePrices returns mark and index prices of @instrument on @exchange in the time range (@starttime, @endtime] in descending order.
+func (datastore *DB) GetDerivativePrices(exchange string, instrument string, starttime time.Time, endtime time.Time) ([]dia.DerivativePrice, error) {
+	prices := []dia.DerivativePrice{}
+	rows, err := datastore.queryDerivative("time,MarkPrice,IndexPrice", influxDbDerivativePricesTable, exchange, instrument, starttime, endtime)
+	if err != nil {
+		return prices, err
+	}
+	for _, row := range rows {
+		price := dia.DerivativePrice{Exchange: exchange, Instrument: instrument}
+		price.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return prices, err
+		}
+		price.MarkPrice = influxFloat(row[1])
+		price.IndexPrice = influxFloat(row[2])
+		prices = append(prices, price)
+	}
+	return prices, nil
+}
+
+func (datastore *DB) queryDerivative(columns string, table string, exchange string, instrument string, starttime time.Time, endtime time.Time) ([][]interface{}, error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE exchange='%s' AND instrument='%s' AND time>%d AND time<=%d ORDER BY DESC",
+		columns,
+		table,
+		exchange,
+		instrument,
+		starttime.UnixNano(),
+		endtime.UnixNano(),
+	)
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return nil, nil
+	}
+	return res[0].Series[0].Values, nil
+}
+
+// GetOptionOrderbookSnapshots returns the latest order book snapshot of each option on @underlying on @exchange
+// in the time range (@starttime, @endtime]. Options expired at @endtime are omitted.
+func (datastore *DB) GetOptionOrderbookSnapshots(exchange string, underlying string, starttime time.Time, endtime time.Time) ([]dia.OptionOrderbookSnapshot, error) {
+	snapshots := []dia.OptionOrderbookSnapshot{}
+	q := fmt.Sprintf("SELECT time,%s FROM %s WHERE exchange='%s' AND underlying='%s' AND Expiry>%d AND time>%d AND time<=%d GROUP BY instrument,optionType ORDER BY DESC LIMIT 1",
+		optionOrderbookFields,
+		influxDbOptionOrderbooksTable,
+		exchange,
+		underlying,
+		endtime.Unix(),
+		starttime.UnixNano(),
+		endtime.UnixNano(),
+	)
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return snapshots, err
+	}
+	if len(res) == 0 {
+		return snapshots, nil
+	}
+	for _, series := range res[0].Series {
+		if len(series.Values) == 0 || len(series.Values[0]) < 8 {
+			continue
+		}
+		row := series.Values[0]
+		snapshot := dia.OptionOrderbookSnapshot{
+			Exchange:   exchange,
+			Instrument: series.Tags["instrument"],
+			Underlying: underlying,
+			OptionType: series.Tags["optionType"],
+		}
+		snapshot.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return snapshots, err
+		}
+		snapshot.BidPrice = influxFloat(row[1])
+		snapshot.BidSize = influxFloat(row[2])
+		snapshot.AskPrice = influxFloat(row[3])
+		snapshot.AskSize = influxFloat(row[4])
+		snapshot.Strike = influxFloat(row[5])
+		snapshot.Expiry = time.Unix(int64(influxFloat(row[6])), 0).UTC()
+		snapshot.UnderlyingPrice = influxFloat(row[7])
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// SaveCVI stores a value of the crypto volatility index in influx.
+func (datastore *DB) SaveCVI(cvi dia.CVI) error {
+	tags := map[string]string{
+		"underlying": cvi.Underlying,
+	}
+	fields := map[string]interface{}{
+		"Value":          cvi.Value,
+		"NearTermExpiry": cvi.NearTermExpiry.Unix(),
+		"NextTermExpiry": cvi.NextTermExpiry.Unix(),
+	}
+	return datastore.addDerivativePoint("SaveCVI", influxDbCVITable, tags, fields, cvi.Time)
+}
+
+// GetCVI returns the values of the crypto volatility index of @underlying in the time range (@starttime, @endtime] in descending order.
+func (datastore *DB) GetCVI(underlying string, starttime time.Time, endtime time.Time) ([]dia.CVI, error) {
+	cvis := []dia.CVI{}
+	q := fmt.Sprintf("SELECT time,Value,NearTermExpiry,NextTermExpiry FROM %s WHERE underlying='%s' AND time>%d AND time<=%d ORDER BY DESC",
+		influxDbCVITable,
+		underlying,
+		starttime.UnixNano(),
+		endtime.UnixNano(),
+	)
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return cvis, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return cvis, nil
+	}
+	for _, row := range res[0].Series[0].Values {
+		cvi := dia.CVI{Underlying: underlying}
+		cvi.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return cvis, err
+		}
+		cvi.Value = influxFloat(row[1])
+		cvi.NearTermExpiry = time.Unix(int64(influxFloat(row[2])), 0).UTC()
+		cvi.NextTermExpiry = time.Unix(int64(influxFloat(row[3])), 0).UTC()
+		cvis = append(cvis, cvi)
+	}
+	return cvis, nil
+}
+
+// influxFloat returns the value of a numeric influx column and 0 for missing values.
+func influxFloat(value interface{}) float64 {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0
+	}
+	f, _ := number.Float64()
+	return f
+}
+
+// influxString returns the value of a string influx column and the empty string for missing values.
+func influxString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+const exchangeHealthFields = "Score,TradeFrequencyScore,OutlierScore,DeviationScore,VolumeScore,Trades,OutlierShare,PriceDeviation,VolumeUSD,VolumeZScore,SecondsSinceTrade"
+
+// SetExchangeHealth stores the health score of an exchange in influx. Flushed when more than maxPoints in batch.
+func (datastore *DB) SetExchangeHealth(health dia.ExchangeHealth) error {
+	tags := map[string]string{
+		"exchange": health.Exchange,
+	}
+	fields := map[string]interface{}{
+		"Score":               health.Score,
+		"TradeFrequencyScore": health.TradeFrequencyScore,
+		"OutlierScore":        health.OutlierScore,
+		"DeviationScore":      health.DeviationScore,
+		"VolumeScore":         health.VolumeScore,
+		"Trades":              health.Trades,
+		"OutlierShare":        health.OutlierShare,
+		"PriceDeviation":      health.PriceDeviation,
+		"VolumeUSD":           health.VolumeUSD,
+		"VolumeZScore":        health.VolumeZScore,
+		"SecondsSinceTrade":   health.SecondsSinceTrade,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbExchangeHealthTable, tags, fields, health.Time)
+	if err != nil {
+		log.Errorln("SetExchangeHealth:", err)
+	} else {
+		datastore.addPoint(pt)
+	}
+	return err
+}
+
+// GetExchangeHealth returns the health scores of @exchange in the time range (@starttime, @endtime] in descending order.
+func (datastore *DB) GetExchangeHealth(exchange string, starttime time.Time, endtime time.Time) ([]dia.ExchangeHealth, error) {
+	healths := []dia.ExchangeHealth{}
+	q := fmt.Sprintf("SELECT time,%s FROM %s WHERE exchange='%s' AND time>%d AND time<=%d ORDER BY DESC",
+		exchangeHealthFields,
+		influxDbExchangeHealthTable,
+		exchange,
+		starttime.UnixNano(),
+		endtime.UnixNano(),
+	)
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return healths, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return healths, nil
+	}
+	for _, row := range res[0].Series[0].Values {
+		if len(row) < 12 {
+			continue
+		}
+		health := dia.ExchangeHealth{Exchange: exchange}
+		health.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return healths, err
+		}
+		values := make([]float64, len(row)-1)
+		for i := range values {
+			if number, ok := row[i+1].(json.Number); ok {
+				values[i], _ = number.Float64()
+			}
+		}
+		health.Score = values[0]
+		health.TradeFrequencyScore = values[1]
+		health.OutlierScore = values[2]
+		health.DeviationScore = values[3]
+		health.VolumeScore = values[4]
+		health.Trades = int64(values[5])
+		health.OutlierShare = values[6]
+		health.PriceDeviation = values[7]
+		health.VolumeUSD = values[8]
+		health.VolumeZScore = values[9]
+		health.SecondsSinceTrade = values[10]
+		healths = append(healths, health)
+	}
+	return healths, nil
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+const exportJobVars = "export_id,owner,dataset,blockchain,address,exchange,filter,format,starttime,endtime,status,error,creation_time,finish_time"
+
+// SetExportJob stores a pending export @job and returns its ID.
+func (rdb *RelDB) SetExportJob(job dia.ExportJob) (ID string, err error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (owner,dataset,blockchain,address,exchange,filter,format,starttime,endtime,status)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING export_id`,
+		exportJobTable,
+	)
+	err = rdb.postgresClient.QueryRow(
+		context.Background(),
+		query,
+		job.Owner,
+		job.Dataset,
+		job.Blockchain,
+		job.Address,
+		job.Exchange,
+		job.Filter,
+		job.Format,
+		job.Starttime,
+		job.Endtime,
+		dia.ExportStatusPending,
+	).Scan(&ID)
+	return
+}
+
+// GetExportJob returns the export job with @ID.
+func (rdb *RelDB) GetExportJob(ID string) (dia.ExportJob, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE export_id=$1", exportJobVars, exportJobTable)
+	return scanExportJob(rdb.postgresClient.QueryRow(context.Background(), query, ID))
+}
+
+// ClaimExportJob sets the oldest pending export job to running and returns it.
+// Concurrent workers never claim the same job. ok is false if there is no pending job.
+func (rdb *RelDB) ClaimExportJob() (job dia.ExportJob, ok bool, err error) {
+	query := fmt.Sprintf(`
+	UPDATE %s SET status=$1
+	WHERE export_id=(
+		SELECT export_id FROM %s WHERE status=$2 ORDER BY creation_time LIMIT 1 FOR UPDATE SKIP LOCKED
+	)
+	RETURNING %s`,
+		exportJobTable,
+		exportJobTable,
+		exportJobVars,
+	)
+	job, err = scanExportJob(rdb.postgresClient.QueryRow(context.Background(), query, dia.ExportStatusRunning, dia.ExportStatusPending))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return job, false, nil
+		}
+		return
+	}
+	return job, true, nil
+}
+
+// FinishExportJob sets the final @status of the export job with @ID.
+func (rdb *RelDB) FinishExportJob(ID string, status string, errorMessage string) error {
+	query := fmt.Sprintf("UPDATE %s SET status=$1,error=$2,finish_time=$3 WHERE export_id=$4", exportJobTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, status, errorMessage, time.Now(), ID)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanExportJob(row rowScanner) (job dia.ExportJob, err error) {
+	var finished sql.NullTime
+	err = row.Scan(
+		&job.ID,
+		&job.Owner,
+		&job.Dataset,
+		&job.Blockchain,
+		&job.Address,
+		&job.Exchange,
+		&job.Filter,
+		&job.Format,
+		&job.Starttime,
+		&job.Endtime,
+		&job.Status,
+		&job.Error,
+		&job.Created,
+		&finished,
+	)
+	if finished.Valid {
+		job.Finished = finished.Time
+	}
+	return
+}
@@ -46,6 +46,77 @@ func (datastore *DB) GetFilterPointsAsset(filter string, exchange string, addres
 	}, err
 }
 
+// GetFilterPointsAround returns the last filter point of @asset across all exchanges at or before @timestamp
+// and the first one after @timestamp. Only points at most @maxDistance away from @timestamp are considered.
+// before resp. after is nil if there is no such point.
+func (datastore *DB) GetFilterPointsAround(filter string, asset dia.Asset, timestamp time.Time, maxDistance time.Duration) (before *dia.FilterPoint, after *dia.FilterPoint, err error) {
+	queryString := "SELECT value,symbol FROM %s WHERE filter='%s' AND exchange='' AND address='%s' AND blockchain='%s' AND time%s%d AND time%s%d ORDER BY %s LIMIT 1"
+	qBefore := fmt.Sprintf(queryString, influxDbFiltersTable, filter, asset.Address, asset.Blockchain, ">", timestamp.Add(-maxDistance).UnixNano(), "<=", timestamp.UnixNano(), "DESC")
+	qAfter := fmt.Sprintf(queryString, influxDbFiltersTable, filter, asset.Address, asset.Blockchain, ">", timestamp.UnixNano(), "<=", timestamp.Add(maxDistance).UnixNano(), "ASC")
+
+	before, err = datastore.queryFilterPoint(qBefore, filter, asset)
+	if err != nil {
+		return
+	}
+	after, err = datastore.queryFilterPoint(qAfter, filter, asset)
+	return
+}
+
+// queryFilterPoint returns the first filter point returned by influx query @q or nil if there is none.
+func (datastore *DB) queryFilterPoint(q string, filter string, asset dia.Asset) (*dia.FilterPoint, error) {
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 || len(res[0].Series[0].Values) == 0 {
+		return nil, nil
+	}
+	row := res[0].Series[0].Values[0]
+	fp := dia.FilterPoint{Asset: asset, Name: filter}
+	fp.Time, err = time.Parse(time.RFC3339, row[0].(string))
+	if err != nil {
+		return nil, err
+	}
+	fp.Value, err = row[1].(json.Number).Float64()
+	if err != nil {
+		return nil, err
+	}
+	if symbol, ok := row[2].(string); ok && fp.Asset.Symbol == "" {
+		fp.Asset.Symbol = symbol
+	}
+	return &fp, nil
+}
+
+// GetFilterPointsInRange returns all values of @filter for @asset on @exchange in the time range (@starttime, @endtime]
+// in ascending order. If @exchange is empty, the values across all exchanges are returned.
+func (datastore *DB) GetFilterPointsInRange(filter string, asset dia.Asset, exchange string, starttime time.Time, endtime time.Time) ([]dia.FilterPoint, error) {
+	var filterPoints []dia.FilterPoint
+	q := fmt.Sprintf("SELECT value,symbol FROM %s WHERE filter='%s' AND exchange='%s' AND address='%s' AND blockchain='%s' AND time>%d AND time<=%d ORDER BY ASC",
+		influxDbFiltersTable, filter, exchange, asset.Address, asset.Blockchain, starttime.UnixNano(), endtime.UnixNano())
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return filterPoints, err
+	}
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, row := range res[0].Series[0].Values {
+			fp := dia.FilterPoint{Asset: asset, Name: filter}
+			fp.Time, err = time.Parse(time.RFC3339, row[0].(string))
+			if err != nil {
+				return filterPoints, err
+			}
+			fp.Value, err = row[1].(json.Number).Float64()
+			if err != nil {
+				return filterPoints, err
+			}
+			if symbol, ok := row[2].(string); ok && fp.Asset.Symbol == "" {
+				fp.Asset.Symbol = symbol
+			}
+			filterPoints = append(filterPoints, fp)
+		}
+	}
+	return filterPoints, nil
+}
+
 // GetFilterPoints returns filter points from either a specific exchange or all exchanges.
 // symbol is mapped to the underlying asset with biggest market cap.
 func (datastore *DB) GetFilterPoints(filter string, exchange string, symbol string, scale string, starttime time.Time, endtime time.Time) (*Points, error) {
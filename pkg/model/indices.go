@@ -0,0 +1,312 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// -------------------------------------------------------------
+// Postgres methods
+// -------------------------------------------------------------
+
+// SetIndexDefinition stores @definition and replaces the constituents of the index. The constituents must
+// be stored in the asset table.
+func (rdb *RelDB) SetIndexDefinition(definition dia.IndexDefinition) error {
+	tx, err := rdb.postgresClient.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(context.Background()); err != nil && err.Error() != "tx is closed" {
+			log.Error("rollback index definition: ", err)
+		}
+	}()
+
+	query := fmt.Sprintf(`INSERT INTO %s (symbol,name,weighting,max_weight,rebalancing,base_value) VALUES ($1,$2,$3,$4,$5,$6)
+		ON CONFLICT (symbol) DO UPDATE SET name=EXCLUDED.name,weighting=EXCLUDED.weighting,max_weight=EXCLUDED.max_weight,rebalancing=EXCLUDED.rebalancing,base_value=EXCLUDED.base_value`, indexDefinitionTable)
+	_, err = tx.Exec(context.Background(), query, definition.Symbol, definition.Name, definition.Weighting, definition.MaxWeight, definition.Rebalancing, definition.BaseValue)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(context.Background(), fmt.Sprintf("DELETE FROM %s WHERE index_symbol=$1", indexConstituentTable), definition.Symbol)
+	if err != nil {
+		return err
+	}
+	query = fmt.Sprintf("INSERT INTO %s (index_symbol,asset_id) SELECT $1,asset_id FROM %s WHERE address=$2 AND blockchain=$3", indexConstituentTable, assetTable)
+	for _, asset := range definition.Constituents {
+		tag, err := tx.Exec(context.Background(), query, definition.Symbol, asset.Address, asset.Blockchain)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("constituent %s on %s not in asset table", asset.Address, asset.Blockchain)
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+// GetIndexDefinitions returns the definitions of all active indices with their constituents.
+func (rdb *RelDB) GetIndexDefinitions() (definitions []dia.IndexDefinition, err error) {
+	query := fmt.Sprintf("SELECT symbol,name,weighting,max_weight,rebalancing,base_value FROM %s WHERE active=true ORDER BY symbol", indexDefinitionTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	for rows.Next() {
+		var definition dia.IndexDefinition
+		err = rows.Scan(&definition.Symbol, &definition.Name, &definition.Weighting, &definition.MaxWeight, &definition.Rebalancing, &definition.BaseValue)
+		if err != nil {
+			rows.Close()
+			return
+		}
+		definitions = append(definitions, definition)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for i := range definitions {
+		definitions[i].Constituents, err = rdb.getIndexAssets(indexConstituentTable, definitions[i].Symbol)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// getIndexAssets returns the assets of the index @symbol in @table, which is either the table of constituents
+// or the table of candidates.
+func (rdb *RelDB) getIndexAssets(table string, symbol string) (assets []dia.Asset, err error) {
+	query := fmt.Sprintf("SELECT a.symbol,a.name,a.address,a.decimals,a.blockchain FROM %s c INNER JOIN %s a ON c.asset_id=a.asset_id WHERE c.index_symbol=$1 ORDER BY a.blockchain,a.address", table, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, symbol)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals string
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		decimalsInt, err := strconv.Atoi(decimals)
+		if err == nil {
+			asset.Decimals = uint8(decimalsInt)
+		}
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
+
+// SetIndexRebalance stores @rebalance with the weights of all constituents.
+func (rdb *RelDB) SetIndexRebalance(rebalance dia.IndexRebalance) error {
+	tx, err := rdb.postgresClient.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(context.Background()); err != nil && err.Error() != "tx is closed" {
+			log.Error("rollback index rebalance: ", err)
+		}
+	}()
+
+	var rebalanceID string
+	query := fmt.Sprintf("INSERT INTO %s (index_symbol,time,reason,index_value,divisor,previous_divisor) VALUES ($1,$2,$3,$4,$5,$6) RETURNING rebalance_id", indexRebalanceTable)
+	err = tx.QueryRow(context.Background(), query, rebalance.Symbol, rebalance.Time, rebalance.Reason, rebalance.IndexValue, rebalance.Divisor, rebalance.PreviousDivisor).Scan(&rebalanceID)
+	if err != nil {
+		return err
+	}
+	query = fmt.Sprintf("INSERT INTO %s (rebalance_id,asset_id,weight,units,price,supply) SELECT $1,asset_id,$2,$3,$4,$5 FROM %s WHERE address=$6 AND blockchain=$7", indexWeightTable, assetTable)
+	for _, constituent := range rebalance.Constituents {
+		_, err = tx.Exec(context.Background(), query, rebalanceID, constituent.Weight, constituent.Units, constituent.Price, constituent.Supply, constituent.Asset.Address, constituent.Asset.Blockchain)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+// GetIndexRebalances returns the rebalancings of the index @symbol in the time range (@starttime, @endtime]
+// in descending order of time.
+func (rdb *RelDB) GetIndexRebalances(symbol string, starttime time.Time, endtime time.Time) ([]dia.IndexRebalance, error) {
+	query := fmt.Sprintf("SELECT rebalance_id,time,reason,index_value,divisor,previous_divisor FROM %s WHERE index_symbol=$1 AND time>$2 AND time<=$3 ORDER BY time DESC", indexRebalanceTable)
+	return rdb.queryIndexRebalances(symbol, query, symbol, starttime, endtime)
+}
+
+// GetLatestIndexRebalance returns the last rebalancing of the index @symbol.
+func (rdb *RelDB) GetLatestIndexRebalance(symbol string) (dia.IndexRebalance, error) {
+	query := fmt.Sprintf("SELECT rebalance_id,time,reason,index_value,divisor,previous_divisor FROM %s WHERE index_symbol=$1 ORDER BY time DESC LIMIT 1", indexRebalanceTable)
+	rebalances, err := rdb.queryIndexRebalances(symbol, query, symbol)
+	if err != nil {
+		return dia.IndexRebalance{}, err
+	}
+	if len(rebalances) == 0 {
+		return dia.IndexRebalance{}, fmt.Errorf("no rebalancing of index %s", symbol)
+	}
+	return rebalances[0], nil
+}
+
+func (rdb *RelDB) queryIndexRebalances(symbol string, query string, args ...interface{}) (rebalances []dia.IndexRebalance, err error) {
+	rows, err := rdb.postgresClient.Query(context.Background(), query, args...)
+	if err != nil {
+		return
+	}
+	var rebalanceIDs []string
+	for rows.Next() {
+		var (
+			rebalanceID string
+			rebalance   = dia.IndexRebalance{Symbol: symbol}
+		)
+		err = rows.Scan(&rebalanceID, &rebalance.Time, &rebalance.Reason, &rebalance.IndexValue, &rebalance.Divisor, &rebalance.PreviousDivisor)
+		if err != nil {
+			rows.Close()
+			return
+		}
+		rebalanceIDs = append(rebalanceIDs, rebalanceID)
+		rebalances = append(rebalances, rebalance)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	query = fmt.Sprintf("SELECT a.symbol,a.name,a.address,a.blockchain,w.weight,w.units,w.price,w.supply FROM %s w INNER JOIN %s a ON w.asset_id=a.asset_id WHERE w.rebalance_id=$1 ORDER BY a.blockchain,a.address", indexWeightTable, assetTable)
+	for i, rebalanceID := range rebalanceIDs {
+		rows, err = rdb.postgresClient.Query(context.Background(), query, rebalanceID)
+		if err != nil {
+			return
+		}
+		for rows.Next() {
+			var constituent dia.IndexConstituent
+			err = rows.Scan(&constituent.Asset.Symbol, &constituent.Asset.Name, &constituent.Asset.Address, &constituent.Asset.Blockchain, &constituent.Weight, &constituent.Units, &constituent.Price, &constituent.Supply)
+			if err != nil {
+				rows.Close()
+				return
+			}
+			rebalances[i].Constituents = append(rebalances[i].Constituents, constituent)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// SetIndexSelectionRules stores the rules by which the constituents of the index rules.Symbol are selected.
+func (rdb *RelDB) SetIndexSelectionRules(rules dia.IndexSelectionRules) error {
+	query := fmt.Sprintf(`INSERT INTO %s (index_symbol,volatility_threshold,volatility_window,lookback_days,max_days_above,min_avg_volume,min_market_cap,max_constituents) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+		ON CONFLICT (index_symbol) DO UPDATE SET volatility_threshold=EXCLUDED.volatility_threshold,volatility_window=EXCLUDED.volatility_window,lookback_days=EXCLUDED.lookback_days,max_days_above=EXCLUDED.max_days_above,min_avg_volume=EXCLUDED.min_avg_volume,min_market_cap=EXCLUDED.min_market_cap,max_constituents=EXCLUDED.max_constituents`, indexSelectionRuleTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, rules.Symbol, rules.VolatilityThreshold, rules.VolatilityWindow, rules.LookbackDays, rules.MaxDaysAbove, rules.MinAvgVolume, rules.MinMarketCap, rules.MaxConstituents)
+	return err
+}
+
+// GetIndexSelectionRules returns the selection rules of all indices which have them.
+func (rdb *RelDB) GetIndexSelectionRules() (allRules []dia.IndexSelectionRules, err error) {
+	query := fmt.Sprintf("SELECT index_symbol,volatility_threshold,volatility_window,lookback_days,max_days_above,min_avg_volume,min_market_cap,max_constituents FROM %s ORDER BY index_symbol", indexSelectionRuleTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rules dia.IndexSelectionRules
+		err = rows.Scan(&rules.Symbol, &rules.VolatilityThreshold, &rules.VolatilityWindow, &rules.LookbackDays, &rules.MaxDaysAbove, &rules.MinAvgVolume, &rules.MinMarketCap, &rules.MaxConstituents)
+		if err != nil {
+			return
+		}
+		allRules = append(allRules, rules)
+	}
+	return allRules, rows.Err()
+}
+
+// SetIndexCandidates replaces the candidate constituents of the index @symbol with @candidates.
+func (rdb *RelDB) SetIndexCandidates(symbol string, candidates []dia.Asset) error {
+	tx, err := rdb.postgresClient.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(context.Background()); err != nil && err.Error() != "tx is closed" {
+			log.Error("rollback index candidates: ", err)
+		}
+	}()
+
+	_, err = tx.Exec(context.Background(), fmt.Sprintf("DELETE FROM %s WHERE index_symbol=$1", indexCandidateTable), symbol)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s (index_symbol,asset_id) SELECT $1,asset_id FROM %s WHERE address=$2 AND blockchain=$3", indexCandidateTable, assetTable)
+	for _, asset := range candidates {
+		tag, err := tx.Exec(context.Background(), query, symbol, asset.Address, asset.Blockchain)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("candidate %s on %s not in asset table", asset.Address, asset.Blockchain)
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+// GetIndexCandidates returns the candidate constituents of the index @symbol.
+func (rdb *RelDB) GetIndexCandidates(symbol string) ([]dia.Asset, error) {
+	return rdb.getIndexAssets(indexCandidateTable, symbol)
+}
+
+// -------------------------------------------------------------
+// Influx and redis methods
+// -------------------------------------------------------------
+
+func getKeyIndexBlock(symbol string) string {
+	return "dia_indexblock_" + symbol
+}
+
+func getKeyIndexProposal(symbol string) string {
+	return "dia_indexproposal_" + symbol
+}
+
+// SetIndexValue stores the value of the index @symbol computed by the index engine in the table of
+// benchmarked index values.
+func (datastore *DB) SetIndexValue(symbol string, value float64, timestamp time.Time) error {
+	tags := map[string]string{
+		"symbol": symbol,
+	}
+	fields := map[string]interface{}{
+		"name":  symbol,
+		"value": strconv.FormatFloat(value, 'f', -1, 64),
+	}
+	return datastore.SaveIndexEngineTimeInflux(tags, fields, timestamp)
+}
+
+// SetIndexBlockCache stores the latest index block of the index @symbol in redis.
+func (datastore *DB) SetIndexBlockCache(symbol string, block *dia.IndexBlock) error {
+	return datastore.redisClient.Set(getKeyIndexBlock(symbol), block, 0).Err()
+}
+
+// GetIndexBlockCache returns the latest index block of the index @symbol.
+func (datastore *DB) GetIndexBlockCache(symbol string) (dia.IndexBlock, error) {
+	var block dia.IndexBlock
+	err := datastore.redisClient.Get(getKeyIndexBlock(symbol)).Scan(&block)
+	return block, err
+}
+
+// SetIndexProposalCache stores the latest rebalance proposal of the index proposal.Symbol in redis.
+func (datastore *DB) SetIndexProposalCache(proposal *dia.IndexRebalanceProposal) error {
+	return datastore.redisClient.Set(getKeyIndexProposal(proposal.Symbol), proposal, 0).Err()
+}
+
+// GetIndexProposalCache returns the latest rebalance proposal of the index @symbol.
+func (datastore *DB) GetIndexProposalCache(symbol string) (dia.IndexRebalanceProposal, error) {
+	var proposal dia.IndexRebalanceProposal
+	err := datastore.redisClient.Get(getKeyIndexProposal(symbol)).Scan(&proposal)
+	return proposal, err
+}
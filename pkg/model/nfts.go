@@ -112,6 +112,42 @@ func (rdb *RelDB) GetNFTClasses(limit, offset uint64) (nftClasses []dia.NFTClass
 	return
 }
 
+// GetNFTClassesAfter returns up to @limit NFT classes on @blockchain ordered by their ID, starting after the class with ID @afterID.
+// If @blockchain is empty, classes on all blockchains are returned. If @afterID is empty, the first classes are returned.
+// The IDs are returned alongside the classes, so they can be used as cursors.
+func (rdb *RelDB) GetNFTClassesAfter(blockchain string, afterID string, limit uint64) (nftClasses []dia.NFTClass, IDs []string, err error) {
+	query := fmt.Sprintf(`
+	SELECT nftclass_id,address,symbol,name,blockchain,contract_type,category
+	FROM %s
+	WHERE ($1='' OR blockchain=$1)
+	AND ($2='' OR nftclass_id>NULLIF($2,'')::uuid)
+	ORDER BY nftclass_id
+	LIMIT $3`,
+		nftclassTable,
+	)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, blockchain, afterID, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			nftClass dia.NFTClass
+			ID       string
+			category pgtype.Unknown
+		)
+		err = rows.Scan(&ID, &nftClass.Address, &nftClass.Symbol, &nftClass.Name, &nftClass.Blockchain, &nftClass.ContractType, &category)
+		if err != nil {
+			return
+		}
+		nftClass.Category = category.String
+		nftClasses = append(nftClasses, nftClass)
+		IDs = append(IDs, ID)
+	}
+	return
+}
+
 func (rdb *RelDB) UpdateNFTClassCategory(nftclassID string, category string) (bool, error) {
 	query := fmt.Sprintf("UPDATE %s SET category=$1 WHERE nftclass_id=$2", nftclassTable)
 	resp, err := rdb.postgresClient.Exec(context.Background(), query, category, nftclassID)
@@ -475,8 +511,13 @@ func (rdb *RelDB) GetNFTFloorLevel(
 
 // GetNFTFloor returns the floor price of @nftclass w.r.t. the last 24h.
 func (rdb *RelDB) GetNFTFloor(nftclass dia.NFTClass, timestamp time.Time, floorWindowSeconds time.Duration, noBundles bool) (floor float64, err error) {
-	var paymentCurrencies []dia.Asset
-	switch nftclass.Blockchain {
+	return rdb.GetNFTFloorLevel(nftclass, timestamp, floorWindowSeconds, nftPaymentCurrencies(nftclass.Blockchain), float64(0), noBundles)
+}
+
+// nftPaymentCurrencies returns the native currency of @blockchain and its wrapped version.
+// Only sales paid in these currencies are taken into account for floor prices.
+func nftPaymentCurrencies(blockchain string) (paymentCurrencies []dia.Asset) {
+	switch blockchain {
 	case dia.ETHEREUM:
 		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.ETHEREUM, Address: "0x0000000000000000000000000000000000000000"})
 		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.ETHEREUM, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"})
@@ -487,7 +528,105 @@ func (rdb *RelDB) GetNFTFloor(nftclass dia.NFTClass, timestamp time.Time, floorW
 		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.BINANCESMARTCHAIN, Address: "0x0000000000000000000000000000000000000000"})
 		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.BINANCESMARTCHAIN, Address: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"})
 	}
-	return rdb.GetNFTFloorLevel(nftclass, timestamp, floorWindowSeconds, paymentCurrencies, float64(0), noBundles)
+	return
+}
+
+// GetNFTFloorStats returns the floor price of @nftclass in the window before @timestamp together with
+// the number of sales in the window, the time of the last sale and the dispersion of the sale prices.
+// If there are no sales in the window, the floor price of earlier windows is returned. The result is
+// flagged as stale if the last sale is older than @staleAfter.
+func (rdb *RelDB) GetNFTFloorStats(nftclass dia.NFTClass, timestamp time.Time, floorWindowSeconds time.Duration, staleAfter time.Duration, noBundles bool) (stats dia.NFTFloorStats, err error) {
+	stats.NFTClass = nftclass
+	stats.Time = timestamp
+	stats.FloorWindow = floorWindowSeconds
+
+	trades, err := rdb.GetNFTTradesCollection(nftclass.Address, nftclass.Blockchain, timestamp.Add(-floorWindowSeconds), timestamp)
+	if err != nil {
+		return
+	}
+	// As in GetNFTFloorLevel, payment currencies are only restricted on Ethereum and Astar.
+	filterCurrencies := nftclass.Blockchain == dia.ETHEREUM || nftclass.Blockchain == dia.ASTAR
+	paymentCurrencies := nftPaymentCurrencies(nftclass.Blockchain)
+
+	var prices []float64
+	for _, trade := range trades {
+		if noBundles && trade.BundleSale {
+			continue
+		}
+		if filterCurrencies && !isNFTPaymentCurrency(trade.Currency, paymentCurrencies) {
+			continue
+		}
+		price, _ := new(big.Float).Quo(new(big.Float).SetInt(trade.Price), new(big.Float).SetFloat64(math.Pow10(18))).Float64()
+		if price <= 0 {
+			continue
+		}
+		prices = append(prices, price)
+		if trade.Timestamp.After(stats.LastSale) {
+			stats.LastSale = trade.Timestamp
+		}
+	}
+
+	if len(prices) > 0 {
+		stats.NumSales = len(prices)
+		stats.Floor = prices[0]
+		var sum float64
+		for _, price := range prices {
+			sum += price
+			if price < stats.Floor {
+				stats.Floor = price
+			}
+		}
+		mean := sum / float64(len(prices))
+		var variance float64
+		for _, price := range prices {
+			variance += (price - mean) * (price - mean)
+		}
+		stats.Dispersion = math.Sqrt(variance/float64(len(prices))) / mean
+	} else {
+		stepBackLimit := 40
+		stats.Floor, err = rdb.GetNFTFloorRecursive(nftclass, timestamp, floorWindowSeconds, stepBackLimit, noBundles)
+		if err != nil {
+			return
+		}
+		stats.LastSale, err = rdb.GetLastNFTTradeTime(nftclass, timestamp)
+		if err != nil {
+			return
+		}
+	}
+	stats.Stale = timestamp.Sub(stats.LastSale) > staleAfter
+	return
+}
+
+func isNFTPaymentCurrency(asset dia.Asset, paymentCurrencies []dia.Asset) bool {
+	for _, currency := range paymentCurrencies {
+		if strings.EqualFold(asset.Address, currency.Address) && asset.Blockchain == currency.Blockchain {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLastNFTTradeTime returns the time of the last trade of any nft in @nftclass before @timestamp.
+func (rdb *RelDB) GetLastNFTTradeTime(nftclass dia.NFTClass, timestamp time.Time) (lastTrade time.Time, err error) {
+	query := fmt.Sprintf(`
+	SELECT max(tr.trade_time)
+	FROM %s tr INNER JOIN %s n
+	ON tr.nftclass_id=n.nftclass_id
+	WHERE n.address=$1 AND n.blockchain=$2 AND tr.trade_time<=$3`,
+		NfttradeCurrTable,
+		nftclassTable,
+	)
+	var t sql.NullTime
+	err = rdb.postgresClient.QueryRow(context.Background(), query, nftclass.Address, nftclass.Blockchain, timestamp).Scan(&t)
+	if err != nil {
+		return
+	}
+	if !t.Valid {
+		err = errors.New("no trade found")
+		return
+	}
+	lastTrade = t.Time
+	return
 }
 
 // GetNFTFloorRecursive returns the floor price of @nftclass. If necessary, it iterates back in time until it finds a floor price.
@@ -960,17 +1099,34 @@ func (rdb *RelDB) GetLastBlockNFTOffer(nftclass dia.NFTClass) (blocknumber uint6
 }
 
 // SetNFTOffer stores @offer in postgres.
+// Offers on an entire collection are stored without reference to an nft.
 func (rdb *RelDB) SetNFTOffer(offer dia.NFTOffer) error {
-	nftID, err := rdb.GetNFTID(offer.NFT.NFTClass.Address, offer.NFT.NFTClass.Blockchain, offer.NFT.TokenID)
+	nftclassID, err := rdb.GetNFTClassID(offer.NFT.NFTClass.Address, offer.NFT.NFTClass.Blockchain)
 	if err != nil {
 		return err
 	}
-	bidVars := "nft_id,start_value,end_value,duration,from_address,auction_type,currency_symbol,currency_address,currency_decimals,blocknumber,blockposition,offer_time,tx_hash,marketplace"
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)", nftofferTable, bidVars)
+	var nftID interface{}
+	if offer.NFT.TokenID != "" {
+		nftID, err = rdb.GetNFTID(offer.NFT.NFTClass.Address, offer.NFT.NFTClass.Blockchain, offer.NFT.TokenID)
+		if err != nil {
+			return err
+		}
+	}
+	var expiry interface{}
+	if !offer.Expiry.IsZero() {
+		expiry = offer.Expiry
+	}
+	var nonce interface{}
+	if offer.OrderNonce != nil {
+		nonce = offer.OrderNonce.String()
+	}
+	bidVars := "nft_id,nftclass_id,start_value,end_value,duration,from_address,auction_type,currency_symbol,currency_address,currency_decimals,blocknumber,blockposition,offer_time,tx_hash,marketplace,order_hash,order_nonce,expiry_time,cancelled,filled"
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20)", nftofferTable, bidVars)
 	_, err = rdb.postgresClient.Exec(
 		context.Background(),
 		query,
 		nftID,
+		nftclassID,
 		offer.StartValue.String(),
 		offer.EndValue.String(),
 		offer.Duration,
@@ -984,6 +1140,11 @@ func (rdb *RelDB) SetNFTOffer(offer dia.NFTOffer) error {
 		offer.Timestamp,
 		offer.TxHash,
 		offer.Exchange,
+		offer.OrderHash,
+		nonce,
+		expiry,
+		offer.Cancelled,
+		offer.Filled,
 	)
 	if err != nil {
 		return err
@@ -991,6 +1152,157 @@ func (rdb *RelDB) SetNFTOffer(offer dia.NFTOffer) error {
 	return nil
 }
 
+// UpdateNFTOfferState sets the cancellation and fill state of offers on the marketplace @offer.Exchange.
+// If @offer.OrderHash is set, only the offer with this order hash is updated. If @offer.OrderNonce is set,
+// the offer from @offer.FromAddress with this nonce is updated. Otherwise, all open offers from
+// @offer.FromAddress made before @offer.Timestamp are updated, as it is the case when a maker
+// invalidates all of its orders at once.
+// @offer.Timestamp is stored as the time the offers were closed.
+func (rdb *RelDB) UpdateNFTOfferState(offer dia.NFTOffer) (err error) {
+	stateVars := "cancelled=(cancelled OR $1),filled=(filled OR $2),closed_time=COALESCE(closed_time,$3)"
+	switch {
+	case offer.OrderHash != "":
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE marketplace=$4 AND order_hash=$5", nftofferTable, stateVars)
+		_, err = rdb.postgresClient.Exec(context.Background(), query, offer.Cancelled, offer.Filled, offer.Timestamp, offer.Exchange, offer.OrderHash)
+	case offer.OrderNonce != nil:
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE marketplace=$4 AND from_address=$5 AND order_nonce=$6", nftofferTable, stateVars)
+		_, err = rdb.postgresClient.Exec(context.Background(), query, offer.Cancelled, offer.Filled, offer.Timestamp, offer.Exchange, offer.FromAddress, offer.OrderNonce.String())
+	default:
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE marketplace=$4 AND from_address=$5 AND offer_time<$3 AND NOT cancelled AND NOT filled", nftofferTable, stateVars)
+		_, err = rdb.postgresClient.Exec(context.Background(), query, offer.Cancelled, offer.Filled, offer.Timestamp, offer.Exchange, offer.FromAddress)
+	}
+	return
+}
+
+// GetNFTActiveOffers returns all offers of type @auctionType on the collection given by @address and @blockchain
+// which were active at @timestamp, i.e. which were made before, did not expire and were neither cancelled nor filled
+// before @timestamp. The Timestamp of each returned offer is the time the offer was made.
+func (rdb *RelDB) GetNFTActiveOffers(address string, blockchain string, auctionType string, timestamp time.Time) (offers []dia.NFTOffer, err error) {
+	var rows pgx.Rows
+	nftclassID, err := rdb.GetNFTClassID(address, blockchain)
+	if err != nil {
+		return
+	}
+	nftclass, err := rdb.GetNFTClassByID(nftclassID)
+	if err != nil {
+		return
+	}
+
+	offerVars := "o.start_value,o.end_value,o.from_address,o.auction_type,o.currency_symbol,o.currency_address,o.currency_decimals,o.blocknumber,o.offer_time,o.tx_hash,o.marketplace,o.order_hash,o.expiry_time,n.token_id"
+	query := fmt.Sprintf(`
+	SELECT %s 
+	FROM %s o 
+	LEFT JOIN %s n ON o.nft_id=n.nft_id 
+	WHERE o.nftclass_id=$1 AND o.auction_type=$2 AND o.offer_time<=$3 AND (o.expiry_time IS NULL OR o.expiry_time>$3) 
+	AND ((o.closed_time IS NULL AND NOT o.cancelled AND NOT o.filled) OR o.closed_time>$3) 
+	ORDER BY o.offer_time DESC`,
+		offerVars,
+		nftofferTable,
+		nftTable,
+	)
+	rows, err = rdb.postgresClient.Query(context.Background(), query, nftclassID, auctionType, timestamp)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			offer      dia.NFTOffer
+			startvalue string
+			endvalue   string
+			orderHash  sql.NullString
+			expiry     sql.NullTime
+			tokenID    sql.NullString
+		)
+		err = rows.Scan(
+			&startvalue,
+			&endvalue,
+			&offer.FromAddress,
+			&offer.AuctionType,
+			&offer.CurrencySymbol,
+			&offer.CurrencyAddress,
+			&offer.CurrencyDecimals,
+			&offer.BlockNumber,
+			&offer.Timestamp,
+			&offer.TxHash,
+			&offer.Exchange,
+			&orderHash,
+			&expiry,
+			&tokenID,
+		)
+		if err != nil {
+			return
+		}
+		var ok bool
+		offer.StartValue, ok = new(big.Int).SetString(startvalue, 10)
+		if !ok {
+			log.Errorf("parse start value %s of offer %s", startvalue, orderHash.String)
+			continue
+		}
+		if e, ok := new(big.Int).SetString(endvalue, 10); ok {
+			offer.EndValue = e
+		}
+		if orderHash.Valid {
+			offer.OrderHash = orderHash.String
+		}
+		if expiry.Valid {
+			offer.Expiry = expiry.Time
+		}
+		offer.NFT.NFTClass = nftclass
+		if tokenID.Valid {
+			offer.NFT.TokenID = tokenID.String
+		}
+		offers = append(offers, offer)
+	}
+	return
+}
+
+// GetNFTListingBook returns the book of active listings on the collection given by @address and @blockchain
+// at @timestamp. Only listings in the native currency are taken into account. Listings with a declining
+// price are valued at their start value. For each entry in @distances, the number of listings priced
+// within this relative distance from the floor ask is returned.
+func (rdb *RelDB) GetNFTListingBook(address string, blockchain string, timestamp time.Time, distances []float64) (book dia.NFTListingBook, err error) {
+	listings, err := rdb.GetNFTActiveOffers(address, blockchain, dia.NFTOfferListing, timestamp)
+	if err != nil {
+		return
+	}
+	book.Time = timestamp
+
+	var prices []float64
+	for _, listing := range listings {
+		book.NFTClass = listing.NFT.NFTClass
+		if listing.CurrencyAddress != "0x0000000000000000000000000000000000000000" {
+			continue
+		}
+		price, _ := new(big.Float).Quo(new(big.Float).SetInt(listing.StartValue), new(big.Float).SetFloat64(math.Pow10(int(listing.CurrencyDecimals)))).Float64()
+		if price > 0 {
+			prices = append(prices, price)
+		}
+	}
+	if len(prices) == 0 {
+		return
+	}
+
+	book.NumListings = len(prices)
+	book.FloorAsk = prices[0]
+	for _, price := range prices {
+		if price < book.FloorAsk {
+			book.FloorAsk = price
+		}
+	}
+	for _, distance := range distances {
+		depth := dia.NFTListingDepth{Distance: distance}
+		for _, price := range prices {
+			if price <= book.FloorAsk*(1+distance) {
+				depth.NumListings++
+			}
+		}
+		book.Depth = append(book.Depth, depth)
+	}
+	return
+}
+
 // GetLastNFTOffer returns the last offer on the nft with @address and @tokenID.
 // Here, 'last' refers to block number and block position smaller or equal
 // (in the case of block number) than @blockNumber and @blockPosition resp.
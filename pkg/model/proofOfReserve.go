@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// SaveProofOfReserve stores the proof of reserve of a bridged asset in influx.
+func (datastore *DB) SaveProofOfReserve(por dia.ProofOfReserve) error {
+	tags := map[string]string{
+		"symbol":     por.Asset.Symbol,
+		"address":    por.Asset.Address,
+		"blockchain": por.Asset.Blockchain,
+	}
+	fields := map[string]interface{}{
+		"reserves":        por.Reserves,
+		"supply":          por.Supply,
+		"collateralRatio": por.CollateralRatio,
+	}
+	if len(por.Balances) > 0 {
+		balances, err := json.Marshal(por.Balances)
+		if err != nil {
+			log.Error("marshal reserve balances: ", err)
+		} else {
+			fields["balances"] = string(balances)
+		}
+	}
+	return datastore.addDerivativePoint("SaveProofOfReserve", influxDbProofOfReserveTable, tags, fields, por.Time)
+}
+
+// GetProofOfReserve returns the proofs of reserve of the asset with @address on @blockchain in the time range
+// (@starttime, @endtime] in descending order.
+func (datastore *DB) GetProofOfReserve(blockchain string, address string, starttime time.Time, endtime time.Time) ([]dia.ProofOfReserve, error) {
+	pors := []dia.ProofOfReserve{}
+	q := fmt.Sprintf("SELECT time,symbol,reserves,supply,collateralRatio,balances FROM %s WHERE blockchain='%s' AND address='%s' AND time>%d AND time<=%d ORDER BY DESC",
+		influxDbProofOfReserveTable,
+		blockchain,
+		address,
+		starttime.UnixNano(),
+		endtime.UnixNano(),
+	)
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return pors, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return pors, nil
+	}
+	for _, row := range res[0].Series[0].Values {
+		var por dia.ProofOfReserve
+		por.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return pors, err
+		}
+		por.Asset = dia.Asset{Symbol: influxString(row[1]), Address: address, Blockchain: blockchain}
+		por.Reserves = influxFloat(row[2])
+		por.Supply = influxFloat(row[3])
+		por.CollateralRatio = influxFloat(row[4])
+		if balances := influxString(row[5]); balances != "" {
+			if err = json.Unmarshal([]byte(balances), &por.Balances); err != nil {
+				log.Error("unmarshal reserve balances: ", err)
+			}
+		}
+		pors = append(pors, por)
+	}
+	return pors, nil
+}
@@ -5,15 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-redis/redis"
 	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
 )
 
 const (
+	// Number of assets quoted concurrently in GetAssetQuotationsBatch.
+	batchQuotationWorkers = 16
+
 	WindowYesterday       = 24 * 60 * 60
 	Window1h              = 60 * 60
 	Window7d              = 7 * 24 * 60 * 60
@@ -263,22 +269,17 @@ func (datastore *DB) GetAssetPriceUSDCache(asset dia.Asset) (price float64, err
 func (datastore *DB) GetSortedAssetQuotations(assets []dia.Asset) ([]AssetQuotation, error) {
 	var quotations []AssetQuotation
 	var volumes []float64
-	for _, asset := range assets {
-		var quotation *AssetQuotation
-		var volume *float64
-		var err error
-		quotation, err = datastore.GetAssetQuotationLatest(asset)
-		if err != nil {
-			log.Errorf("get quotation for symbol %s with address %s on blockchain %s: %v", asset.Symbol, asset.Address, asset.Blockchain, err)
+	for _, result := range datastore.GetAssetQuotationsBatch(assets, time.Time{}) {
+		asset := result.Asset
+		if result.Err != nil {
+			log.Errorf("get quotation for symbol %s with address %s on blockchain %s: %v", asset.Symbol, asset.Address, asset.Blockchain, result.Err)
 			continue
 		}
-		volume, err = datastore.Get24HoursAssetVolume(asset)
-		if err != nil {
-			log.Errorf("get volume for symbol %s with address %s on blockchain %s: %v", asset.Symbol, asset.Address, asset.Blockchain, err)
+		if result.Volume == nil {
 			continue
 		}
-		quotations = append(quotations, *quotation)
-		volumes = append(volumes, *volume)
+		quotations = append(quotations, *result.Quotation)
+		volumes = append(volumes, *result.Volume)
 	}
 	if len(quotations) == 0 {
 		return quotations, errors.New("no quotations available")
@@ -293,6 +294,150 @@ func (datastore *DB) GetSortedAssetQuotations(assets []dia.Asset) ([]AssetQuotat
 	return quotationsSorted, nil
 }
 
+// GetAssetQuotationsBatch returns quotations and 24h volumes of all @assets at @timestamp in the order of @assets.
+// If @timestamp is zero, the latest quotations are returned. Failures are reported per asset, so that
+// a single asset without quotation does not fail the whole batch.
+func (datastore *DB) GetAssetQuotationsBatch(assets []dia.Asset, timestamp time.Time) []AssetQuotationResult {
+	results := make([]AssetQuotationResult, len(assets))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, batchQuotationWorkers)
+	for i := range assets {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			results[i] = datastore.getAssetQuotationResult(assets[i], timestamp)
+			<-semaphore
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// GetAssetQuotationBatchItems returns quotations of all assets given by blockchain and address in @assets
+// at @timestamp, or the latest quotations if @timestamp is zero. Items are returned in the order of @assets.
+// Addresses on EVM chains are EIP55 normalized. Assets which are unknown or cannot be quoted get an error
+// message in their item.
+func (datastore *DB) GetAssetQuotationBatchItems(assets []dia.Asset, timestamp time.Time, relDB RelDatastore) []AssetQuotationBatchItem {
+	assets = normalizeAssetAddresses(assets, relDB)
+	fullAssets, err := relDB.GetAssetsBatch(assets)
+	if err != nil {
+		log.Error("get assets of quotation batch: ", err)
+		items := make([]AssetQuotationBatchItem, len(assets))
+		for i, asset := range assets {
+			items[i].Address = asset.Address
+			items[i].Blockchain = asset.Blockchain
+			items[i].Error = "asset lookup failed"
+		}
+		return items
+	}
+	return makeAssetQuotationBatchItems(assets, fullAssets, timestamp, time.Now(), datastore.GetAssetQuotationsBatch)
+}
+
+// normalizeAssetAddresses returns @assets with EIP55 compliant addresses on all chains with an Ethereum ChainID.
+func normalizeAssetAddresses(assets []dia.Asset, relDB RelDatastore) []dia.Asset {
+	evmChains := make(map[string]bool)
+	normalized := make([]dia.Asset, len(assets))
+	for i, asset := range assets {
+		isEVM, ok := evmChains[asset.Blockchain]
+		if !ok {
+			chain, err := relDB.GetBlockchain(asset.Blockchain)
+			if err != nil {
+				log.Warnf("get blockchain %s: %v", asset.Blockchain, err)
+			}
+			isEVM = strings.Contains(chain.ChainID, "Ethereum")
+			evmChains[asset.Blockchain] = isEVM
+		}
+		normalized[i] = asset
+		if isEVM {
+			normalized[i].Address = common.HexToAddress(asset.Address).Hex()
+		}
+	}
+	return normalized
+}
+
+// makeAssetQuotationBatchItems returns the batch items of @assets. @fullAssets are the known assets among @assets,
+// @quote returns their quotations. Data ages are computed relative to @timestamp, or @now for latest quotations.
+func makeAssetQuotationBatchItems(
+	assets []dia.Asset,
+	fullAssets []dia.Asset,
+	timestamp time.Time,
+	now time.Time,
+	quote func(assets []dia.Asset, timestamp time.Time) []AssetQuotationResult,
+) []AssetQuotationBatchItem {
+	known := make(map[string]dia.Asset)
+	for _, asset := range fullAssets {
+		known[asset.Blockchain+"-"+asset.Address] = asset
+	}
+
+	items := make([]AssetQuotationBatchItem, len(assets))
+	var (
+		quotedAssets []dia.Asset
+		indices      []int
+	)
+	for i, asset := range assets {
+		items[i].Address = asset.Address
+		items[i].Blockchain = asset.Blockchain
+		fullAsset, ok := known[asset.Blockchain+"-"+asset.Address]
+		if !ok {
+			items[i].Error = "asset not found"
+			continue
+		}
+		quotedAssets = append(quotedAssets, fullAsset)
+		indices = append(indices, i)
+	}
+	if len(quotedAssets) == 0 {
+		return items
+	}
+
+	referenceTime := timestamp
+	if referenceTime.IsZero() {
+		referenceTime = now
+	}
+	for j, result := range quote(quotedAssets, timestamp) {
+		item := &items[indices[j]]
+		item.Symbol = result.Asset.Symbol
+		item.Name = result.Asset.Name
+		if result.Err != nil {
+			item.Error = "no quotation available"
+			continue
+		}
+		item.Price = result.Quotation.Price
+		item.Time = result.Quotation.Time
+		item.Source = result.Quotation.Source
+		item.DataAgeSeconds = int64(referenceTime.Sub(result.Quotation.Time).Seconds())
+		if result.Volume != nil {
+			item.VolumeYesterdayUSD = *result.Volume
+		}
+	}
+	return items
+}
+
+func (datastore *DB) getAssetQuotationResult(asset dia.Asset, timestamp time.Time) (result AssetQuotationResult) {
+	result.Asset = asset
+	var err error
+	if timestamp.IsZero() {
+		result.Quotation, err = datastore.GetAssetQuotationLatest(asset)
+	} else {
+		result.Quotation, err = datastore.GetAssetQuotation(asset, timestamp)
+	}
+	if err != nil {
+		result.Err = err
+		return
+	}
+
+	if timestamp.IsZero() {
+		result.Volume, err = datastore.Get24HoursAssetVolume(asset)
+	} else {
+		result.Volume, err = datastore.GetVolumeInflux(asset, "", timestamp.AddDate(0, 0, -1), timestamp)
+	}
+	if err != nil {
+		log.Errorf("get volume for symbol %s with address %s on blockchain %s: %v", asset.Symbol, asset.Address, asset.Blockchain, err)
+		result.Volume = nil
+	}
+	return
+}
+
 // ------------------------------------------------------------------------------
 // MARKET MEASURES
 // ------------------------------------------------------------------------------
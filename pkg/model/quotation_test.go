@@ -0,0 +1,102 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// chainStore is a RelDatastore which only knows blockchains.
+type chainStore struct {
+	RelDatastore
+	chains map[string]dia.BlockChain
+}
+
+func (cs chainStore) GetBlockchain(name string) (dia.BlockChain, error) {
+	chain, ok := cs.chains[name]
+	if !ok {
+		return dia.BlockChain{}, errors.New("no rows in result set")
+	}
+	return chain, nil
+}
+
+func TestNormalizeAssetAddresses(t *testing.T) {
+	store := chainStore{chains: map[string]dia.BlockChain{
+		dia.ETHEREUM: {Name: dia.ETHEREUM, ChainID: "Ethereum-1"},
+		dia.SOLANA:   {Name: dia.SOLANA},
+	}}
+	assets := []dia.Asset{
+		{Blockchain: dia.ETHEREUM, Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"},
+		{Blockchain: dia.SOLANA, Address: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"},
+		{Blockchain: "Unknown", Address: "0xabc"},
+	}
+	want := []string{
+		"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+		"0xabc",
+	}
+	for i, asset := range normalizeAssetAddresses(assets, store) {
+		if asset.Address != want[i] {
+			t.Errorf("asset %d: got address %s, want %s", i, asset.Address, want[i])
+		}
+	}
+	if assets[0].Address != "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48" {
+		t.Error("input assets were modified")
+	}
+}
+
+func TestMakeAssetQuotationBatchItems(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	usdc := dia.Asset{Symbol: "USDC", Name: "USD Coin", Blockchain: dia.ETHEREUM, Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"}
+	weth := dia.Asset{Symbol: "WETH", Name: "Wrapped Ether", Blockchain: dia.ETHEREUM, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"}
+	unknown := dia.Asset{Blockchain: dia.ETHEREUM, Address: "0x0000000000000000000000000000000000000001"}
+	volume := 1e6
+
+	var quoted []dia.Asset
+	quote := func(assets []dia.Asset, timestamp time.Time) (results []AssetQuotationResult) {
+		quoted = assets
+		for _, asset := range assets {
+			if asset == weth {
+				results = append(results, AssetQuotationResult{Asset: asset, Err: errors.New("no quotation")})
+				continue
+			}
+			results = append(results, AssetQuotationResult{
+				Asset:     asset,
+				Quotation: &AssetQuotation{Asset: asset, Price: 1, Time: now.Add(-time.Minute), Source: dia.Diadata},
+				Volume:    &volume,
+			})
+		}
+		return
+	}
+
+	requested := []dia.Asset{
+		{Blockchain: unknown.Blockchain, Address: unknown.Address},
+		{Blockchain: usdc.Blockchain, Address: usdc.Address},
+		{Blockchain: weth.Blockchain, Address: weth.Address},
+	}
+	items := makeAssetQuotationBatchItems(requested, []dia.Asset{weth, usdc}, time.Time{}, now, quote)
+
+	if len(quoted) != 2 {
+		t.Fatalf("quoted %d assets, want 2", len(quoted))
+	}
+	if len(items) != len(requested) {
+		t.Fatalf("got %d items, want %d", len(items), len(requested))
+	}
+	if items[0].Error != "asset not found" || items[0].Address != unknown.Address {
+		t.Errorf("unknown asset: got %+v", items[0])
+	}
+	if items[1].Error != "" || items[1].Symbol != "USDC" || items[1].Price != 1 || items[1].VolumeYesterdayUSD != volume || items[1].DataAgeSeconds != 60 {
+		t.Errorf("quoted asset: got %+v", items[1])
+	}
+	if items[2].Error != "no quotation available" || items[2].Symbol != "WETH" {
+		t.Errorf("asset without quotation: got %+v", items[2])
+	}
+
+	// Data ages of historical quotations are relative to the requested time.
+	items = makeAssetQuotationBatchItems(requested[1:2], []dia.Asset{usdc}, now.Add(time.Hour), now, quote)
+	if items[0].DataAgeSeconds != 3660 {
+		t.Errorf("got data age %d, want 3660", items[0].DataAgeSeconds)
+	}
+}
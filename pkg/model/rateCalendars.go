@@ -0,0 +1,61 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ratederivatives "github.com/diadata-org/diadata/internal/pkg/rateDerivatives"
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// SetRateHoliday stores @holiday in the calendar given by @holiday.Calendar.
+// The name of an existing holiday on the same day is overwritten.
+func (rdb *RelDB) SetRateHoliday(holiday dia.Holiday) error {
+	query := fmt.Sprintf("INSERT INTO %s (calendar,day,name) VALUES ($1,$2,$3) ON CONFLICT (calendar,day) DO UPDATE SET name=EXCLUDED.name", rateHolidayTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, holiday.Calendar, holiday.Date, holiday.Name)
+	return err
+}
+
+// DeleteRateHoliday removes the holiday on @date from @calendar.
+func (rdb *RelDB) DeleteRateHoliday(calendar string, date time.Time) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE calendar=$1 AND day=$2", rateHolidayTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, calendar, date)
+	return err
+}
+
+// GetRateHolidays returns all holidays of @calendar in increasing order.
+func (rdb *RelDB) GetRateHolidays(calendar string) (holidays []dia.Holiday, err error) {
+	query := fmt.Sprintf("SELECT calendar,day,name FROM %s WHERE calendar=$1 ORDER BY day", rateHolidayTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, calendar)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var holiday dia.Holiday
+		err = rows.Scan(&holiday.Calendar, &holiday.Date, &holiday.Name)
+		if err != nil {
+			return
+		}
+		holidays = append(holidays, holiday)
+	}
+	return holidays, rows.Err()
+}
+
+// GetRateCalendar returns the business day calendar of the rate @symbol with the holidays stored in postgres.
+func (rdb *RelDB) GetRateCalendar(symbol string) (*dia.Calendar, error) {
+	name, err := ratederivatives.RateCalendar(symbol)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := rdb.GetRateHolidays(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(holidays) == 0 && name != dia.CalendarCrypto {
+		log.Warnf("calendar %s of %s has no holidays.", name, symbol)
+	}
+	return dia.NewCalendar(name, holidays), nil
+}
@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/diadata-org/diadata/internal/pkg/rateDerivatives"
 	"math"
 	"sort"
 	"strconv"
 	"time"
 
+	ratederivatives "github.com/diadata-org/diadata/internal/pkg/rateDerivatives"
+	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/go-redis/redis"
 )
@@ -234,465 +235,153 @@ func (datastore *DB) GetFirstDate(symbol string) (time.Time, error) {
 // Risk-free rates methods
 // ---------------------------------------------------------------------------------------
 
-// GetCompoundedRate returns the compounded rate for the period @dateInit to @date. It computes the rate for all
-// days for which an entry is present in the database. All other days are assumed to be holidays (or weekends).
-func (datastore *DB) GetCompoundedRate(symbol string, dateInit, date time.Time, daysPerYear int, rounding int) (*InterestRate, error) {
-
-	// Get first publication date for the rate with @symbol in order to check feasibility of dateInit
-	firstPublication, err := datastore.GetFirstDate(symbol)
+// compoundingRates returns the rates of @symbol published between @dateInit and @dateFinal mapped by their
+// effective dates in the format 2006-01-02, along with the source of the rate. Rates of the @margin calendar
+// days before @dateInit are included for lookbacks and interest periods starting on non-business days.
+func (datastore *DB) compoundingRates(symbol string, dateInit, dateFinal time.Time, margin int) (map[string]float64, string, error) {
+	ratesAPI, err := datastore.GetInterestRateRange(symbol, dateInit.AddDate(0, 0, -margin).Format("2006-01-02"), dateFinal.Format("2006-01-02"))
 	if err != nil {
-		return &InterestRate{}, err
-	}
-	if utils.AfterDay(firstPublication, dateInit) {
-		log.Error("dateInit cannot be earlier than first publication date.")
-		err = errors.New("dateInit cannot be earlier than first publication date")
-		return &InterestRate{}, err
-	}
-
-	ratesAPI, err := datastore.GetInterestRateRange(symbol, dateInit.Format("2006-01-02"), date.Format("2006-01-02"))
-	if err != nil {
-		return &InterestRate{}, err
+		return nil, "", err
 	}
 	if len(ratesAPI) == 0 {
-		err = errors.New("no rate information for this period")
-		return &InterestRate{}, err
+		return nil, "", errors.New("no rate information for this period")
 	}
-
-	// Determine holidays through missing database entries
-	existDates := []time.Time{}
-	for _, entry := range ratesAPI {
-		existDates = append(existDates, (*entry).EffectiveDate)
+	rates := make(map[string]float64)
+	for _, ir := range ratesAPI {
+		rates[ir.EffectiveDate.Format("2006-01-02")] = ir.Value
 	}
-	holidays := utils.GetHolidays(existDates, dateInit, date)
+	return rates, ratesAPI[0].Source, nil
+}
 
-	// Sort ratesApi (type []*InterestRates) in increasing order according to date
-	// and remove the data for the final date, as only past values are compounded.
-	sort.Slice(ratesAPI, func(i, j int) bool {
-		return (ratesAPI[i].EffectiveDate).Before(ratesAPI[j].EffectiveDate)
-	})
-	ratesAPI = ratesAPI[:len(ratesAPI)-1]
+// compoundingMargin returns the number of calendar days before an interest period whose rates are
+// needed for compounding with @convention.
+func compoundingMargin(convention dia.CompoundingConvention) int {
+	// Business days are at most 5 out of 7 days, with a buffer for holidays and a preceding weekend.
+	return convention.Lookback*7/5 + 10
+}
 
-	// Extract rates' values
-	rates := []float64{}
-	for i := range ratesAPI {
-		rates = append(rates, ratesAPI[i].Value)
+// GetCompoundedIndex returns the compounded index of @symbol on @date over the maximal period of existence
+// of @symbol. Rates are compounded in arrears on the business days of @calendar. On holidays and weekends,
+// the index of the previous business day is returned.
+func (datastore *DB) GetCompoundedIndex(symbol string, calendar *dia.Calendar, date time.Time, convention dia.CompoundingConvention) (*InterestRate, error) {
+	// Consider previous business day if @date is holiday or weekend
+	if !calendar.IsBusinessDay(date) {
+		date = calendar.AddBusinessDays(date, -1)
 	}
-
-	// Check, whether first day is a holiday or weekend. If so, prepend rate of
-	// preceding business day (outside the considered time range!).
-	if utils.ContainsDay(holidays, dateInit) || !utils.CheckWeekDay(dateInit) {
-		var firstRate *InterestRate
-		firstRate, err = datastore.GetInterestRate(symbol, dateInit.Format("2006-01-02"))
-		if err != nil {
-			return &InterestRate{}, err
-		}
-		rates = append([]float64{firstRate.Value}, rates...)
-	}
-
-	// Get compounded rate
-	compRate, err := ratederivatives.CompoundedRate(rates, dateInit, date, holidays, daysPerYear, rounding)
+	values, err := datastore.GetCompoundedIndexRange(symbol, calendar, date, date, convention)
 	if err != nil {
 		return &InterestRate{}, err
 	}
-
-	// Fill InterestRate type for return
-	ir := &InterestRate{}
-	ir.Symbol = symbol + "_compounded_by_DIA"
-	ir.Value = compRate
-	ir.EffectiveDate = date
-	ir.Source = ratesAPI[0].Source
-
-	return ir, nil
-}
-
-// GetCompoundedIndex returns the compounded index over the maximal period of existence of @symbol
-func (datastore *DB) GetCompoundedIndex(symbol string, date time.Time, daysPerYear int, rounding int) (*InterestRate, error) {
-	// Get initial date for the rate with @symbol
-	dateInit, err := datastore.GetFirstDate(symbol)
-	if err != nil {
-		return &InterestRate{}, err
+	if len(values) == 0 {
+		return &InterestRate{}, errors.New("no business day in period")
 	}
-	return datastore.GetCompoundedRate(symbol, dateInit, date, daysPerYear, rounding)
+	return values[0], nil
 }
 
-// GetCompoundedIndexRange returns the compounded average of the index @symbol over rolling @calDays calendar days.
-func (datastore *DB) GetCompoundedIndexRange(symbol string, dateInit, dateFinal time.Time, daysPerYear int, rounding int) (values []*InterestRate, err error) {
-
-	// Get first publication date for the rate with @symbol in order to check feasibility of dateInit
+// GetCompoundedIndexRange returns the compounded index of @symbol on all business days of @calendar
+// in the period from @dateInit to @dateFinal. The index starts at 1 on the first day on which the
+// rates observed with @convention are available.
+func (datastore *DB) GetCompoundedIndexRange(symbol string, calendar *dia.Calendar, dateInit, dateFinal time.Time, convention dia.CompoundingConvention) (values []*InterestRate, err error) {
 	firstPublication, err := datastore.GetFirstDate(symbol)
 	if err != nil {
 		return []*InterestRate{}, err
 	}
-	if utils.AfterDay(firstPublication, dateInit) {
-		log.Error("dateStart cannot be earlier than first publication date.")
-		err = errors.New("dateStart cannot be earlier than first publication date")
-		return []*InterestRate{}, err
+	base := calendar.AddBusinessDays(firstPublication, convention.Lookback)
+	if utils.AfterDay(base, dateInit) {
+		return []*InterestRate{}, errors.New("dateInit cannot be earlier than the first publication date plus lookback")
 	}
 
-	// Get rate data from database for the computation of the compounded values
-	ratesAPI, err := datastore.GetInterestRateRange(symbol, dateInit.Format("2006-01-02"), dateFinal.Format("2006-01-02"))
+	rates, source, err := datastore.compoundingRates(symbol, firstPublication, dateFinal, 0)
 	if err != nil {
 		return []*InterestRate{}, err
 	}
-	if len(ratesAPI) == 0 {
-		err = errors.New("no rate information for this period")
-		return []*InterestRate{}, err
-	}
-	// Sort ratesApi (type []*InterestRates) in increasing order according to date.
-	sort.Slice(ratesAPI, func(i, j int) bool {
-		return (ratesAPI[i].EffectiveDate).Before(ratesAPI[j].EffectiveDate)
-	})
-
-	// Determine holidays through missing database entries
-	existDates := []time.Time{}
-	for _, entry := range ratesAPI {
-		existDates = append(existDates, (*entry).EffectiveDate)
-	}
-	holidays := utils.GetHolidays(existDates, firstPublication, dateFinal)
-
-	// Consider previous business day if @dateFinal is holiday or weekend
-	for utils.ContainsDay(holidays, dateFinal) || !utils.CheckWeekDay(dateFinal) {
-		dateFinal = dateFinal.AddDate(0, 0, -1)
-	}
-	// Consider next business day if @dateInit is holiday or weekend
-	for utils.ContainsDay(holidays, dateInit) || !utils.CheckWeekDay(dateInit) {
-		dateInit = dateInit.AddDate(0, 0, 1)
-	}
-
-	// Initialize return values
-	compRate, err := datastore.GetCompoundedRate(symbol, firstPublication, dateInit, daysPerYear, 0)
-	if err != nil {
-		return
-	}
-	value := compRate.Value
-	values = append(values, compRate)
-
-	// Iterate through all remaining business days
-	for i := 0; i < len(ratesAPI)-1; i++ {
-
-		n, _ := ratederivatives.RateFactor(ratesAPI[i].EffectiveDate, holidays)
-		factor := 1 + (ratesAPI[i].Value/100)*float64(n)/float64(daysPerYear)
-		value *= factor
-
-		// Fill return struct
-		compAvg := &InterestRate{}
-		compAvg.Symbol = symbol + "_compounded_by_DIA"
-		compAvg.Value = value
-		compAvg.EffectiveDate = ratesAPI[i+1].EffectiveDate
-		compAvg.Source = ratesAPI[0].Source
-
-		// Append data and increment initial date
-		values = append(values, compAvg)
-
-	}
-	if rounding != 0 {
-		for i := range values {
-			values[i].Value = math.Round(values[i].Value*math.Pow(10, float64(rounding))) / math.Pow(10, float64(rounding))
+	// The index is compounded from one business day to the next and rounded for the return values only.
+	unrounded := convention
+	unrounded.Rounding = 0
+	index, previous := 1.0, base
+	for _, date := range calendar.BusinessDays(dateInit, dateFinal.AddDate(0, 0, 1)) {
+		if date.After(previous) {
+			factor, _, err := ratederivatives.CompoundInArrears(rates, calendar, previous, date, unrounded)
+			if err != nil {
+				return []*InterestRate{}, err
+			}
+			index *= factor
+			previous = date
+		}
+		value := index
+		if convention.Rounding != 0 {
+			value = math.Round(index*math.Pow(10, float64(convention.Rounding))) / math.Pow(10, float64(convention.Rounding))
 		}
-		return values, nil
+		values = append(values, &InterestRate{
+			Symbol:        symbol + "_compounded_by_DIA",
+			Value:         value,
+			EffectiveDate: date,
+			Source:        source,
+		})
 	}
 	return values, nil
 }
 
-// GetCompoundedAvg returns the compounded average of the index @symbol over rolling @calDays calendar days.
-func (datastore *DB) GetCompoundedAvg(symbol string, date time.Time, calDays, daysPerYear int, rounding int) (*InterestRate, error) {
-
-	dateInit := date.AddDate(0, 0, -calDays)
-
-	index, err := datastore.GetCompoundedRate(symbol, dateInit, date, daysPerYear, rounding)
+// GetCompoundedAvg returns the compounded average of @symbol in percent over the @calDays calendar days before @date.
+// Rates are compounded in arrears on the business days of @calendar.
+func (datastore *DB) GetCompoundedAvg(symbol string, calendar *dia.Calendar, date time.Time, calDays int, convention dia.CompoundingConvention) (*InterestRate, error) {
+	rates, source, err := datastore.compoundingRates(symbol, date.AddDate(0, 0, -calDays), date, compoundingMargin(convention))
 	if err != nil {
 		return &InterestRate{}, err
 	}
-
-	// Fill return struct
-	compAvg := &InterestRate{}
-	compAvg.Symbol = symbol + strconv.Itoa(calDays) + "_compounded_by_DIA"
-	compAvg.Value = 100 * (index.Value - 1) * float64(daysPerYear) / float64(calDays)
-	compAvg.EffectiveDate = date
-	compAvg.Source = index.Source
-
-	return compAvg, nil
-}
-
-// --------------------------------------------------------------------------------------------
-// Computation of compounded average range as done by FED and BOE, i.e. neglecting higher order
-// terms accounting for holidays and weekends
-// --------------------------------------------------------------------------------------------
-
-// WeightedRates returns a map which maps a rate to each business day in the time period given by
-// @dateInit and @dateFinal.
-// Rates are weighted by the rate factor. intRates must be sorted by date in increasing order.
-func WeightedRates(intRates []*InterestRate, dateInit, dateFinal time.Time, holidays []time.Time, startIndex int) (map[time.Time]float64, int) {
-
-	rateMap := make(map[time.Time]float64)
-
-	// Adjust rate if dateInit is not a business day
-	initialFactor := 0
-	auxDate := dateInit
-	for !utils.CheckWeekDay(auxDate) || utils.ContainsDay(holidays, auxDate) {
-		initialFactor++
-		auxDate = auxDate.AddDate(0, 0, 1)
-	}
-
-	// Get index for first date inside global range
-	for utils.AfterDay(dateInit, intRates[startIndex].EffectiveDate) {
-		startIndex++
-	}
-	// Return index as cursor inside of entire range requested in API call
-	index := startIndex
-
-	// If first dateInit is non-business day, get previous rate
-	if !utils.CheckWeekDay(dateInit) || utils.ContainsDay(holidays, dateInit) {
-		startIndex = startIndex - 1
-		rateMap[dateInit] = float64(initialFactor) * intRates[startIndex].Value
-		startIndex++
-	}
-
-	// Compute compounded rate for period [dateInit, dateFinal]
-	for utils.AfterDay(dateFinal, intRates[startIndex].EffectiveDate) && startIndex < len(intRates)-1 {
-		ratefactor, _ := ratederivatives.RateFactor(intRates[startIndex].EffectiveDate, holidays)
-		rateMap[intRates[startIndex].EffectiveDate] = float64(ratefactor) * intRates[startIndex].Value
-		startIndex++
-	}
-
-	return rateMap, index
-}
-
-// GetCompoundedAvgRange returns the compounded average of the index @symbol over rolling @calDays calendar days.
-func (datastore *DB) GetCompoundedAvgRange(symbol string, dateInit, dateFinal time.Time, calDays, daysPerYear int, rounding int) (values []*InterestRate, err error) {
-
-	dateStart := dateInit.AddDate(0, 0, -calDays)
-
-	// Get first publication date for the rate with @symbol in order to check feasibility of dateInit
-	firstPublication, err := datastore.GetFirstDate(symbol)
+	_, rate, err := ratederivatives.CompoundInArrears(rates, calendar, date.AddDate(0, 0, -calDays), date, convention)
 	if err != nil {
-		return []*InterestRate{}, err
-	}
-	if utils.AfterDay(firstPublication, dateStart) {
-		log.Error("dateStart cannot be earlier than first publication date.")
-		err = errors.New("dateStart cannot be earlier than first publication date")
-		return []*InterestRate{}, err
+		return &InterestRate{}, err
 	}
+	return &InterestRate{
+		Symbol:        symbol + strconv.Itoa(calDays) + "_compounded_by_DIA",
+		Value:         rate,
+		EffectiveDate: date,
+		Source:        source,
+	}, nil
+}
 
-	// Get rate data from database
-	ratesAPI, err := datastore.GetInterestRateRange(symbol, dateStart.Format("2006-01-02"), dateFinal.Format("2006-01-02"))
+// GetCompoundedAvgRange returns the compounded averages of @symbol over rolling @calDays calendar days on all
+// business days of @calendar in the period from @dateInit to @dateFinal, as published by FED and BOE.
+func (datastore *DB) GetCompoundedAvgRange(symbol string, calendar *dia.Calendar, dateInit, dateFinal time.Time, calDays int, convention dia.CompoundingConvention) (values []*InterestRate, err error) {
+	rates, source, err := datastore.compoundingRates(symbol, dateInit.AddDate(0, 0, -calDays), dateFinal, compoundingMargin(convention))
 	if err != nil {
 		return []*InterestRate{}, err
 	}
-	if len(ratesAPI) == 0 {
-		err = errors.New("no rate information for this period")
-		return []*InterestRate{}, err
-	}
-
-	// Check, whether first day is a holiday or weekend. If so, prepend rate of
-	// preceding business day (outside the considered time range!).
-	// Determine holidays through missing database entries
-	existDates := []time.Time{}
-	for _, entry := range ratesAPI {
-		existDates = append(existDates, (*entry).EffectiveDate)
-	}
-	holidays := utils.GetHolidays(existDates, dateStart, dateFinal)
-	if utils.ContainsDay(holidays, dateStart) || !utils.CheckWeekDay(dateStart) {
-		firstRate, err := datastore.GetInterestRate(symbol, dateStart.Format("2006-01-02"))
+	for _, date := range calendar.BusinessDays(dateInit, dateFinal.AddDate(0, 0, 1)) {
+		_, rate, err := ratederivatives.CompoundInArrears(rates, calendar, date.AddDate(0, 0, -calDays), date, convention)
 		if err != nil {
 			return []*InterestRate{}, err
 		}
-		ratesAPI = append([]*InterestRate{firstRate}, ratesAPI...)
-	}
-
-	// Consider last business day if last given day is holiday or weekend
-	for utils.ContainsDay(holidays, dateFinal) || !utils.CheckWeekDay(dateFinal) {
-		dateFinal = dateFinal.AddDate(0, 0, -1)
-	}
-
-	// Sort ratesApi (type []*InterestRates) in increasing order according to date
-	// and remove the data for the final date, as only past values are compounded.
-	sort.Slice(ratesAPI, func(i, j int) bool {
-		return (ratesAPI[i].EffectiveDate).Before(ratesAPI[j].EffectiveDate)
-	})
-	ratesAPI = ratesAPI[:len(ratesAPI)-1]
-
-	// Iterate through interest periods of length @calDays
-	cursor := 0
-	for utils.AfterDay(dateFinal, dateInit) {
-
-		// Get starting date of compounding period
-		dateStart := dateInit.AddDate(0, 0, -calDays)
-
-		if utils.ContainsDay(holidays, dateInit) || !utils.CheckWeekDay(dateInit) {
-			// No rate information on holidays and weekends
-			dateInit = dateInit.AddDate(0, 0, 1)
-		} else {
-
-			// get a weighted rate for each business day in period of interest
-			mapRates, index := WeightedRates(ratesAPI, dateStart, dateInit, holidays, cursor)
-			cursor = index
-
-			auxDate := dateStart
-			ratesPeriod := []float64{}
-			for utils.AfterDay(dateInit, auxDate) {
-				val, ok := mapRates[auxDate]
-				if ok {
-					ratesPeriod = append(ratesPeriod, val)
-					auxDate = auxDate.AddDate(0, 0, 1)
-				} else {
-					auxDate = auxDate.AddDate(0, 0, 1)
-				}
-			}
-
-			compRate, err := ratederivatives.CompoundedRateSimple(ratesPeriod, dateStart, dateInit, daysPerYear, 0)
-
-			if err != nil || utils.ContainsDay(holidays, dateInit) {
-				dateInit = dateInit.AddDate(0, 0, 1)
-			} else {
-
-				// Fill return struct
-				compAvg := &InterestRate{}
-				compAvg.Symbol = symbol + strconv.Itoa(calDays) + "_compounded_by_DIA"
-				compAvg.Value = 100 * (compRate - 1) * float64(daysPerYear) / float64(calDays)
-				compAvg.EffectiveDate = dateInit
-				compAvg.Source = ratesAPI[0].Source
-
-				// Append data and increment initial date
-				values = append(values, compAvg)
-				dateInit = dateInit.AddDate(0, 0, 1)
-			}
-		}
-
-	}
-	if rounding != 0 {
-		for i := range values {
-			values[i].Value = math.Round(values[i].Value*math.Pow(10, float64(rounding))) / math.Pow(10, float64(rounding))
-		}
-		return values, nil
+		values = append(values, &InterestRate{
+			Symbol:        symbol + strconv.Itoa(calDays) + "_compounded_by_DIA",
+			Value:         rate,
+			EffectiveDate: date,
+			Source:        source,
+		})
 	}
 	return values, nil
 }
 
-// ---------------------------------------------------------------------------------------------
-// Computation of compounded averages in conservative way, i.e. including higher order terms
-// ---------------------------------------------------------------------------------------------
-
-// StraightRates returns a map which maps a rate to each day in the time period. This includes (artificial)
-// rate values for non-business days. intRates must be sorted by date in increasing order.
-func StraightRates(intRates []*InterestRate) map[time.Time]float64 {
-
-	finalDay := intRates[len(intRates)-1].EffectiveDate
-	count := 0
-	day := intRates[count].EffectiveDate
-
-	rateMap := make(map[time.Time]float64)
-	rateMap[day] = intRates[count].Value
-	day = day.AddDate(0, 0, 1)
-	count++
-	for utils.AfterDay(finalDay, day) {
-		if utils.SameDays(day, intRates[count].EffectiveDate) {
-			// For business day assign rate and increment day
-			rateMap[day] = intRates[count].Value
-			day = day.AddDate(0, 0, 1)
-			count++
-		} else {
-			// holiday or weekend gets the previous rate
-			rateMap[day] = intRates[count-1].Value
-			day = day.AddDate(0, 0, 1)
-		}
-	}
-	return rateMap
-}
-
-// GetCompoundedAvgDIARange returns the compounded average DIA index of @symbol over rolling @calDays calendar days.
-func (datastore *DB) GetCompoundedAvgDIARange(symbol string, dateInit, dateFinal time.Time, calDays, daysPerYear int, rounding int) (values []*InterestRate, err error) {
-
-	dateStart := dateInit.AddDate(0, 0, -calDays)
-
-	// Get first publication date for the rate with @symbol in order to check feasibility of dateInit
-	firstPublication, err := datastore.GetFirstDate(symbol)
-	if err != nil {
-		return []*InterestRate{}, err
-	}
-	if utils.AfterDay(firstPublication, dateStart) {
-		log.Error("dateStart cannot be earlier than first publication date.")
-		err = errors.New("dateStart cannot be earlier than first publication date")
-		return []*InterestRate{}, err
-	}
-
-	// Get rate data from database
-	ratesAPI, err := datastore.GetInterestRateRange(symbol, dateStart.Format("2006-01-02"), dateFinal.Format("2006-01-02"))
+// GetCompoundedAvgDIARange returns the compounded averages DIA index of @symbol over rolling @calDays calendar
+// days on all days in [@dateInit, @dateFinal). In contrast to GetCompoundedAvgRange, rates are compounded on
+// every calendar day, where non-business days of @calendar are assigned the rate of the preceding business day.
+func (datastore *DB) GetCompoundedAvgDIARange(symbol string, calendar *dia.Calendar, dateInit, dateFinal time.Time, calDays, daysPerYear int, rounding int) (values []*InterestRate, err error) {
+	rates, source, err := datastore.compoundingRates(symbol, dateInit.AddDate(0, 0, -calDays), dateFinal, compoundingMargin(dia.CompoundingConvention{}))
 	if err != nil {
 		return []*InterestRate{}, err
 	}
-	if len(ratesAPI) == 0 {
-		err = errors.New("no rate information for this period")
-		return []*InterestRate{}, err
-	}
-
-	// Check, whether first day is a holiday or weekend. If so, prepend rate of
-	// preceding business day (outside the considered time range!).
-	// Determine holidays through missing database entries
-	existDates := []time.Time{}
-	for _, entry := range ratesAPI {
-		existDates = append(existDates, (*entry).EffectiveDate)
-	}
-	holidays := utils.GetHolidays(existDates, dateStart, dateFinal)
-	if utils.ContainsDay(holidays, dateStart) || !utils.CheckWeekDay(dateStart) {
-		firstRate, err := datastore.GetInterestRate(symbol, dateStart.Format("2006-01-02"))
+	for date := dateInit; date.Before(dateFinal); date = date.AddDate(0, 0, 1) {
+		_, rate, err := ratederivatives.CompoundDaily(rates, calendar, date.AddDate(0, 0, -calDays), date, daysPerYear, rounding)
 		if err != nil {
 			return []*InterestRate{}, err
 		}
-		ratesAPI = append([]*InterestRate{firstRate}, ratesAPI...)
-	}
-
-	// Consider last business day if last given day is holiday or weekend
-	for utils.ContainsDay(holidays, dateFinal) || !utils.CheckWeekDay(dateFinal) {
-		dateFinal = dateFinal.AddDate(0, 0, -1)
-	}
-
-	// Sort ratesApi (type []*InterestRates) in increasing order according to date
-	// and remove the data for the final date, as only past values are compounded.
-	sort.Slice(ratesAPI, func(i, j int) bool {
-		return (ratesAPI[i].EffectiveDate).Before(ratesAPI[j].EffectiveDate)
-	})
-	ratesAPI = ratesAPI[:len(ratesAPI)-1]
-
-	// get a rate for each calendar day in period of interest
-	mapRates := StraightRates(ratesAPI)
-
-	// Iterate through interest period
-	count := 0
-	for utils.AfterDay(dateFinal, dateInit) {
-		// Get compounded rate
-		dateStart := dateInit.AddDate(0, 0, -calDays)
-		auxDate := dateStart
-		ratesPeriod := []float64{}
-		for i := count; i < count+calDays; i++ {
-			ratesPeriod = append(ratesPeriod, mapRates[auxDate])
-			auxDate = auxDate.AddDate(0, 0, 1)
-		}
-		compRate, err := ratederivatives.CompoundedRateSimple(ratesPeriod, dateStart, dateInit, daysPerYear, 0)
-
-		if err != nil {
-			dateInit = dateInit.AddDate(0, 0, 1)
-		} else {
-
-			// Fill return struct
-			compAvg := &InterestRate{}
-			compAvg.Symbol = symbol + strconv.Itoa(calDays) + "_compounded_by_DIA"
-			compAvg.Value = 100 * (compRate - 1) * float64(daysPerYear) / float64(calDays)
-			compAvg.EffectiveDate = dateInit
-			compAvg.Source = ratesAPI[0].Source
-
-			// Append data and increment initial date
-			values = append(values, compAvg)
-			dateInit = dateInit.AddDate(0, 0, 1)
-			count++
-		}
-
-	}
-	if rounding != 0 {
-		for i := range values {
-			values[i].Value = math.Round(values[i].Value*math.Pow(10, float64(rounding))) / math.Pow(10, float64(rounding))
-		}
-		return values, nil
+		values = append(values, &InterestRate{
+			Symbol:        symbol + strconv.Itoa(calDays) + "_compounded_by_DIA",
+			Value:         rate,
+			EffectiveDate: date,
+			Source:        source,
+		})
 	}
 	return values, nil
 }
@@ -20,6 +20,7 @@ type RelDatastore interface {
 	// --------- Persistent ---------
 	SetAsset(asset dia.Asset) error
 	GetAsset(address, blockchain string) (dia.Asset, error)
+	GetAssetsBatch(assets []dia.Asset) ([]dia.Asset, error)
 	GetAssetByID(ID string) (dia.Asset, error)
 	GetAssetsBySymbolName(symbol, name string) ([]dia.Asset, error)
 	GetAllAssets(blockchain string) ([]dia.Asset, error)
@@ -82,6 +83,7 @@ type RelDatastore interface {
 	SetNFTClass(nftClass dia.NFTClass) error
 	GetAllNFTClasses(blockchain string) ([]dia.NFTClass, error)
 	GetNFTClasses(limit, offset uint64) ([]dia.NFTClass, error)
+	GetNFTClassesAfter(blockchain string, afterID string, limit uint64) ([]dia.NFTClass, []string, error)
 	GetNFTClass(address string, blockchain string) (dia.NFTClass, error)
 	GetNFTClassID(address string, blockchain string) (string, error)
 	GetNFTClassByID(id string) (dia.NFTClass, error)
@@ -146,6 +148,23 @@ type RelDatastore interface {
 	GetCollectionCountByExchange(exchange string) (int64, error)
 	Get24HoursNFTExchangeVolume(exchange dia.NFTExchange) (float64, error)
 	Get24HoursNFTExchangeTrades(exchange dia.NFTExchange) (int64, error)
+
+	// Interest rate calendar methods
+	SetRateHoliday(holiday dia.Holiday) error
+	DeleteRateHoliday(calendar string, date time.Time) error
+	GetRateHolidays(calendar string) ([]dia.Holiday, error)
+	GetRateCalendar(symbol string) (*dia.Calendar, error)
+
+	// Index engine methods
+	SetIndexDefinition(definition dia.IndexDefinition) error
+	GetIndexDefinitions() ([]dia.IndexDefinition, error)
+	SetIndexRebalance(rebalance dia.IndexRebalance) error
+	GetIndexRebalances(symbol string, starttime time.Time, endtime time.Time) ([]dia.IndexRebalance, error)
+	GetLatestIndexRebalance(symbol string) (dia.IndexRebalance, error)
+	SetIndexSelectionRules(rules dia.IndexSelectionRules) error
+	GetIndexSelectionRules() ([]dia.IndexSelectionRules, error)
+	SetIndexCandidates(symbol string, candidates []dia.Asset) error
+	GetIndexCandidates(symbol string) ([]dia.Asset, error)
 }
 
 const (
@@ -168,16 +187,27 @@ const (
 	// cache keys
 	keyAssetCache        = "dia_asset_"
 	keyExchangePairCache = "dia_exchangepair_"
-
-	blockdataTable       = "blockdata"
-	nftcategoryTable     = "nftcategory"
-	nftclassTable        = "nftclass"
-	nftTable             = "nft"
-	NfttradeCurrTable    = "nfttradecurrent"
-	NfttradeSumeriaTable = "nfttradesumeria"
-	nftbidTable          = "nftbid"
-	nftofferTable        = "nftoffer"
-	scrapersTable        = "scrapers"
+	keyAPIKeyRequests    = "dia_apikey_requests_"
+
+	blockdataTable          = "blockdata"
+	nftcategoryTable        = "nftcategory"
+	nftclassTable           = "nftclass"
+	nftTable                = "nft"
+	NfttradeCurrTable       = "nfttradecurrent"
+	NfttradeSumeriaTable    = "nfttradesumeria"
+	nftbidTable             = "nftbid"
+	nftofferTable           = "nftoffer"
+	scrapersTable           = "scrapers"
+	apikeyTable             = "apikey"
+	apikeyUsageTable        = "apikeyusage"
+	exportJobTable          = "exportjob"
+	rateHolidayTable        = "rateholiday"
+	indexDefinitionTable    = "indexdefinition"
+	indexConstituentTable   = "indexconstituent"
+	indexRebalanceTable     = "indexrebalance"
+	indexWeightTable        = "indexweight"
+	indexSelectionRuleTable = "indexselectionrule"
+	indexCandidateTable     = "indexcandidate"
 
 	// time format for blockchain genesis dates
 	// timeFormatBlockchain = "2006-01-02"
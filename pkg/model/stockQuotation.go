@@ -1,7 +1,6 @@
 package models
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -36,58 +35,94 @@ func (datastore *DB) SetStockQuotation(sq StockQuotation) error {
 	return err
 }
 
-// GetStockQuotationInflux returns the last quotation of @symbol before @timestamp.
+// GetStockQuotation returns the quotations of @symbol from @source in the time range (@timeInit, @timeFinal]
+// in descending order.
 func (db *DB) GetStockQuotation(source string, symbol string, timeInit time.Time, timeFinal time.Time) ([]StockQuotation, error) {
-	stockQuotations := []StockQuotation{}
+	query := "SELECT priceAsk,priceBid,sizeAsk,sizeBid,source,\"isin\",\"name\" FROM %s WHERE source='%s' and \"symbol\"='%s' and time>%d and time<=%d order by time desc"
+	q := fmt.Sprintf(query, influxDbStockQuotationsTable, source, symbol, timeInit.UnixNano(), timeFinal.UnixNano())
+	return db.queryStockQuotations(q, symbol)
+}
 
-	unixtimeInit := timeInit.UnixNano()
-	unixtimeFinal := timeFinal.UnixNano()
+// GetStockQuotationLatest returns the latest quotation of @symbol from @source before @timestamp.
+func (db *DB) GetStockQuotationLatest(source string, symbol string, timestamp time.Time) (StockQuotation, error) {
+	query := "SELECT priceAsk,priceBid,sizeAsk,sizeBid,source,\"isin\",\"name\" FROM %s WHERE source='%s' and \"symbol\"='%s' and time<=%d order by time desc limit 1"
+	q := fmt.Sprintf(query, influxDbStockQuotationsTable, source, symbol, timestamp.UnixNano())
+	stockQuotations, err := db.queryStockQuotations(q, symbol)
+	if err != nil {
+		return StockQuotation{}, err
+	}
+	if len(stockQuotations) == 0 {
+		return StockQuotation{}, fmt.Errorf("no quotation of %s from %s", symbol, source)
+	}
+	return stockQuotations[0], nil
+}
 
-	query := "SELECT priceAsk,priceBid,sizeAsk,sizeBid,source,\"isin\",\"name\" FROM %s WHERE source='%s' and \"symbol\"='%s' and time>%d and time<=%d order by time desc"
-	q := fmt.Sprintf(query, influxDbStockQuotationsTable, source, symbol, unixtimeInit, unixtimeFinal)
+// GetStockQuotationSeries returns the last quotation of @symbol from @source in each time-range of the
+// time-series in (@timeInit, @timeFinal] in ascending order. Time-ranges without quotations are omitted.
+// @grouping defines the time-ranges in the notation of influx such as 30s, 40m, 2h,...
+// The time of a quotation is the end of its time-range, so that it is never used before it was quoted.
+func (db *DB) GetStockQuotationSeries(source string, symbol string, timeInit time.Time, timeFinal time.Time, grouping string) ([]StockQuotation, error) {
+	duration, err := time.ParseDuration(grouping)
+	if err != nil {
+		return []StockQuotation{}, err
+	}
+	query := "SELECT LAST(priceAsk),LAST(priceBid) FROM %s WHERE source='%s' and \"symbol\"='%s' and time>%d and time<=%d GROUP BY time(%s) fill(none) ORDER BY ASC"
+	q := fmt.Sprintf(query, influxDbStockQuotationsTable, source, symbol, timeInit.UnixNano(), timeFinal.UnixNano(), grouping)
 	res, err := queryInfluxDB(db.influxClient, q)
 	if err != nil {
-		fmt.Println("Error querying influx")
+		log.Error("query stock quotation series from influx: ", err)
+		return []StockQuotation{}, err
+	}
+
+	stockQuotations := []StockQuotation{}
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, val := range res[0].Series[0].Values {
+			var stockQuotation StockQuotation
+			stockQuotation.Time, err = time.Parse(time.RFC3339, val[0].(string))
+			if err != nil {
+				return stockQuotations, err
+			}
+			stockQuotation.Time = stockQuotation.Time.Add(duration)
+			stockQuotation.PriceAsk = influxFloat(val[1])
+			stockQuotation.PriceBid = influxFloat(val[2])
+			stockQuotation.Source = source
+			stockQuotation.Symbol = symbol
+			stockQuotations = append(stockQuotations, stockQuotation)
+		}
+	}
+	return stockQuotations, nil
+}
+
+func (db *DB) queryStockQuotations(q string, symbol string) ([]StockQuotation, error) {
+	stockQuotations := []StockQuotation{}
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		log.Error("query stock quotations from influx: ", err)
 		return stockQuotations, err
 	}
 
 	if len(res) > 0 && len(res[0].Series) > 0 {
-		layout := "2006-01-02T15:04:05Z"
 		vals := res[0].Series[0].Values
 
 		for i := 0; i < len(vals); i++ {
 			var stockQuotation StockQuotation
-			stockQuotation.Time, err = time.Parse(layout, vals[i][0].(string))
+			stockQuotation.Time, err = time.Parse(time.RFC3339, vals[i][0].(string))
 			if err != nil {
 				log.Error(err)
 			}
-			stockQuotation.PriceAsk, err = vals[i][1].(json.Number).Float64()
-			if err != nil {
-				log.Error(err)
-			}
-			stockQuotation.PriceBid, err = vals[i][2].(json.Number).Float64()
-			if err != nil {
-				log.Error(err)
-			}
-			stockQuotation.SizeAskLot, err = vals[i][3].(json.Number).Float64()
-			if err != nil {
-				log.Error(err)
-			}
-			stockQuotation.SizeBidLot, err = vals[i][4].(json.Number).Float64()
-			if err != nil {
-				log.Error(err)
-			}
-			stockQuotation.Source = vals[i][5].(string)
-			stockQuotation.ISIN = vals[i][6].(string)
-			stockQuotation.Name = vals[i][7].(string)
+			stockQuotation.PriceAsk = influxFloat(vals[i][1])
+			stockQuotation.PriceBid = influxFloat(vals[i][2])
+			stockQuotation.SizeAskLot = influxFloat(vals[i][3])
+			stockQuotation.SizeBidLot = influxFloat(vals[i][4])
+			stockQuotation.Source = influxString(vals[i][5])
+			stockQuotation.ISIN = influxString(vals[i][6])
+			stockQuotation.Name = influxString(vals[i][7])
 			stockQuotation.Symbol = symbol
 
 			stockQuotations = append(stockQuotations, stockQuotation)
 		}
-		return stockQuotations, nil
-
 	}
-	return stockQuotations, err
+	return stockQuotations, nil
 }
 
 // GetStockSymbols returns all symbols available from @source.
@@ -31,6 +31,14 @@ func (datastore *DB) SaveSupplyInflux(supply *dia.Supply) error {
 		"circulatingsupply": supply.CirculatingSupply,
 		"source":            supply.Source,
 	}
+	if len(supply.Exclusions) > 0 {
+		exclusions, err := json.Marshal(supply.Exclusions)
+		if err != nil {
+			log.Error("marshal supply exclusions: ", err)
+		} else {
+			fields["exclusions"] = string(exclusions)
+		}
+	}
 	tags := map[string]string{
 		"symbol":     supply.Asset.Symbol,
 		"name":       supply.Asset.Name,
@@ -58,10 +66,10 @@ func (datastore *DB) GetSupplyInflux(asset dia.Asset, starttime time.Time, endti
 	retval := []dia.Supply{}
 	var q string
 	if starttime.IsZero() || endtime.IsZero() {
-		queryString := "SELECT supply,circulatingsupply,source,\"name\",\"symbol\" FROM %s WHERE \"address\" = '%s' AND \"blockchain\"='%s' AND time<now() ORDER BY DESC LIMIT 1"
+		queryString := "SELECT supply,circulatingsupply,source,\"name\",\"symbol\",exclusions FROM %s WHERE \"address\" = '%s' AND \"blockchain\"='%s' AND time<now() ORDER BY DESC LIMIT 1"
 		q = fmt.Sprintf(queryString, influxDbSupplyTable, asset.Address, asset.Blockchain)
 	} else {
-		queryString := "SELECT supply,circulatingsupply,source,\"name\",\"symbol\" FROM %s WHERE time > %d AND time < %d AND \"address\" = '%s' AND \"blockchain\"='%s' ORDER BY DESC"
+		queryString := "SELECT supply,circulatingsupply,source,\"name\",\"symbol\",exclusions FROM %s WHERE time > %d AND time < %d AND \"address\" = '%s' AND \"blockchain\"='%s' ORDER BY DESC"
 		q = fmt.Sprintf(queryString, influxDbSupplyTable, starttime.UnixNano(), endtime.UnixNano(), asset.Address, asset.Blockchain)
 	}
 	res, err := queryInfluxDB(datastore.influxClient, q)
@@ -103,6 +111,12 @@ func (datastore *DB) GetSupplyInflux(asset dia.Asset, starttime time.Time, endti
 					log.Error("error getting symbol name from influx: ", err)
 				}
 			}
+			if len(res[0].Series[0].Values[i]) > 6 && res[0].Series[0].Values[i][6] != nil {
+				err = json.Unmarshal([]byte(res[0].Series[0].Values[i][6].(string)), &currentSupply.Exclusions)
+				if err != nil {
+					log.Error("unmarshal supply exclusions: ", err)
+				}
+			}
 			retval = append(retval, currentSupply)
 		}
 	} else {
@@ -102,8 +102,8 @@ func (datastore *DB) GetSynthSupplyInflux(blockchain, protocol, address string,
 		queryString = queryString + `AND protocol='` + protocol + `'`
 	}
 	if address != "" && address != "0x0000000000000000000000000000000000000000" {
-		queryString = queryString + `AND underlyingtokenaddress='` + address + `'`
-		queryString = queryString + ` OR synthtokenaddress='` + address + `'`
+		queryString = queryString + `AND (underlyingtokenaddress='` + address + `'`
+		queryString = queryString + ` OR synthtokenaddress='` + address + `')`
 
 	}
 
@@ -233,3 +233,90 @@ func parseSynthAsset(row []interface{}) *dia.SynthAssetSupply {
 	}
 	return nil
 }
+
+func getKeySynthQuotation(blockchain, address string) string {
+	return "dia_synthquotation_USD_" + blockchain + "_" + address
+}
+
+// SaveSynthAssetValuation stores the valuation of a synthetic asset in influx and its fair value as
+// AssetQuotation with source dia.SynthFairValueSource in the cache. The fair value is kept apart from the
+// quotations from trades, so it does not overwrite the market price, and is served by /assetQuotation
+// with source=fairvalue.
+func (datastore *DB) SaveSynthAssetValuation(valuation dia.SynthAssetValuation) error {
+	tags := map[string]string{
+		"synthassetsymbol":       valuation.Asset.Symbol,
+		"underlyingassetsymbol":  valuation.AssetUnderlying.Symbol,
+		"synthtokenaddress":      valuation.Asset.Address,
+		"underlyingtokenaddress": valuation.AssetUnderlying.Address,
+		"blockchain":             valuation.Asset.Blockchain,
+		"protocol":               valuation.Protocol,
+	}
+	fields := map[string]interface{}{
+		"underlyingPrice": valuation.UnderlyingPrice,
+		"collateralRatio": valuation.CollateralRatio,
+		"fairValue":       valuation.FairValue,
+		"marketPrice":     valuation.MarketPrice,
+		"deviation":       valuation.Deviation,
+		"depeg":           valuation.Depeg,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbSynthValuationTable, tags, fields, valuation.Time)
+	if err != nil {
+		log.Errorln("SaveSynthAssetValuation:", err)
+		return err
+	}
+	datastore.addPoint(pt)
+
+	quotation := &AssetQuotation{
+		Asset:  valuation.Asset,
+		Price:  valuation.FairValue,
+		Source: dia.SynthFairValueSource,
+		Time:   valuation.Time,
+	}
+	key := getKeySynthQuotation(valuation.Asset.Blockchain, valuation.Asset.Address)
+	return datastore.redisPipe.Set(key, quotation, TimeOutAssetQuotation).Err()
+}
+
+// GetSynthAssetQuotationCache returns the latest fair value quotation of the synthetic asset @asset from the cache.
+func (datastore *DB) GetSynthAssetQuotationCache(asset dia.Asset) (*AssetQuotation, error) {
+	quotation := &AssetQuotation{}
+	err := datastore.redisClient.Get(getKeySynthQuotation(asset.Blockchain, asset.Address)).Scan(quotation)
+	return quotation, err
+}
+
+// GetSynthAssetValuations returns the valuations of the synthetic asset with @address on @blockchain in the
+// time range (@starttime, @endtime] in descending order.
+func (datastore *DB) GetSynthAssetValuations(blockchain, address string, starttime, endtime time.Time) ([]dia.SynthAssetValuation, error) {
+	valuations := []dia.SynthAssetValuation{}
+	q := fmt.Sprintf("SELECT time,synthassetsymbol,underlyingassetsymbol,underlyingtokenaddress,protocol,underlyingPrice,collateralRatio,fairValue,marketPrice,deviation,depeg FROM %s WHERE blockchain='%s' AND synthtokenaddress='%s' AND time>%d AND time<=%d ORDER BY DESC",
+		influxDbSynthValuationTable,
+		blockchain,
+		address,
+		starttime.UnixNano(),
+		endtime.UnixNano(),
+	)
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return valuations, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return valuations, nil
+	}
+	for _, row := range res[0].Series[0].Values {
+		var valuation dia.SynthAssetValuation
+		valuation.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return valuations, err
+		}
+		valuation.Asset = dia.Asset{Symbol: influxString(row[1]), Address: address, Blockchain: blockchain}
+		valuation.AssetUnderlying = dia.Asset{Symbol: influxString(row[2]), Address: influxString(row[3]), Blockchain: blockchain}
+		valuation.Protocol = influxString(row[4])
+		valuation.UnderlyingPrice = influxFloat(row[5])
+		valuation.CollateralRatio = influxFloat(row[6])
+		valuation.FairValue = influxFloat(row[7])
+		valuation.MarketPrice = influxFloat(row[8])
+		valuation.Deviation = influxFloat(row[9])
+		valuation.Depeg, _ = row[10].(bool)
+		valuations = append(valuations, valuation)
+	}
+	return valuations, nil
+}
@@ -326,6 +326,31 @@ func (datastore *DB) GetTradesByExchangesFull(asset dia.Asset, baseassets []dia.
 	return r, nil
 }
 
+// GetTradesInRange returns all trades of @asset as quote token in the time range (@starttime, @endtime] in ascending order.
+// If @exchange is not empty, only trades on @exchange are returned. Base tokens are returned as well.
+func (datastore *DB) GetTradesInRange(asset dia.Asset, exchange string, starttime time.Time, endtime time.Time) ([]dia.Trade, error) {
+	var trades []dia.Trade
+	exchangeQuery := ""
+	if exchange != "" {
+		exchangeQuery = fmt.Sprintf("AND exchange='%s'", exchange)
+	}
+	query := fmt.Sprintf("SELECT time,estimatedUSDPrice,exchange,foreignTradeID,pair,price,symbol,volume,verified,basetokenblockchain,basetokenaddress FROM %s WHERE quotetokenaddress='%s' AND quotetokenblockchain='%s' %s AND time>%d AND time<=%d ORDER BY ASC", influxDbTradesTable, asset.Address, asset.Blockchain, exchangeQuery, starttime.UnixNano(), endtime.UnixNano())
+	res, err := queryInfluxDB(datastore.influxClient, query)
+	if err != nil {
+		return trades, err
+	}
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, row := range res[0].Series[0].Values {
+			t := parseTrade(row, true)
+			if t != nil {
+				t.QuoteToken = asset
+				trades = append(trades, *t)
+			}
+		}
+	}
+	return trades, nil
+}
+
 // GetTradesByExchangesBatched executes multiple select queries on the trades table in one batch.
 // The time ranges of the queries are given by the intervals [startTimes[i], endTimes[i]].
 func (datastore *DB) GetTradesByExchangesBatched(quoteasset dia.Asset, baseassets []dia.Asset, exchanges []string, startTimes, endTimes []time.Time) ([]dia.Trade, error) {
@@ -461,6 +486,64 @@ func (datastore *DB) GetLastTrades(asset dia.Asset, exchange string, maxTrades i
 	return r, nil
 }
 
+// GetLastTradePair returns the latest trade of @pair on @exchange in the time range (@endtime-@window, @endtime].
+// Only time, pair, exchange and price are returned.
+func (datastore *DB) GetLastTradePair(exchange string, pair string, endtime time.Time, window time.Duration) (dia.Trade, error) {
+	queryString := "SELECT price FROM %s WHERE exchange='%s' AND pair='%s' AND time>%d AND time<=%d ORDER BY DESC LIMIT 1"
+	q := fmt.Sprintf(queryString, influxDbTradesTable, exchange, pair, endtime.Add(-window).UnixNano(), endtime.UnixNano())
+	trades, err := datastore.queryPairPrices(q, exchange, pair, 0)
+	if err != nil {
+		return dia.Trade{}, err
+	}
+	if len(trades) == 0 {
+		return dia.Trade{}, fmt.Errorf("no trade of %s on %s", pair, exchange)
+	}
+	return trades[0], nil
+}
+
+// GetLastTradesPairSeries returns the last trade of @pair on @exchange in each time-range of the
+// time-series in (@starttime, @endtime] in ascending order. Time-ranges without trades are omitted.
+// @grouping defines the time-ranges in the notation of influx such as 30s, 40m, 2h,...
+// The time of a trade is the end of its time-range, so that it is never used before it happened.
+func (datastore *DB) GetLastTradesPairSeries(exchange string, pair string, starttime time.Time, endtime time.Time, grouping string) ([]dia.Trade, error) {
+	duration, err := time.ParseDuration(grouping)
+	if err != nil {
+		return []dia.Trade{}, err
+	}
+	queryString := "SELECT LAST(price) FROM %s WHERE exchange='%s' AND pair='%s' AND time>%d AND time<=%d GROUP BY time(%s) fill(none) ORDER BY ASC"
+	q := fmt.Sprintf(queryString, influxDbTradesTable, exchange, pair, starttime.UnixNano(), endtime.UnixNano(), grouping)
+	return datastore.queryPairPrices(q, exchange, pair, duration)
+}
+
+// queryPairPrices returns the trades of @pair on @exchange from the time and price columns of @q.
+// The times are shifted by @shift.
+func (datastore *DB) queryPairPrices(q string, exchange string, pair string, shift time.Duration) ([]dia.Trade, error) {
+	trades := []dia.Trade{}
+	res, err := queryInfluxDB(datastore.influxClient, q)
+	if err != nil {
+		return trades, err
+	}
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, val := range res[0].Series[0].Values {
+			timestamp, err := time.Parse(time.RFC3339, val[0].(string))
+			if err != nil {
+				return trades, err
+			}
+			price, ok := val[1].(json.Number)
+			if !ok {
+				continue
+			}
+			trade := dia.Trade{Pair: pair, Source: exchange, Time: timestamp.Add(shift)}
+			trade.Price, err = price.Float64()
+			if err != nil {
+				return trades, err
+			}
+			trades = append(trades, trade)
+		}
+	}
+	return trades, nil
+}
+
 // GetNumTradesExchange24H returns the number of trades on @exchange in the last 24 hours.
 func (datastore *DB) GetNumTradesExchange24H(exchange string) (numTrades int64, err error) {
 	endtime := time.Now()
@@ -38,6 +38,8 @@ type StockQuotation struct {
 	Source     string
 	Time       time.Time
 	ISIN       string
+	// MarketClosed is true if the market of the symbol was closed at the time of the request.
+	MarketClosed bool `json:"MarketClosed,omitempty"`
 }
 
 type Stock struct {
@@ -101,6 +103,47 @@ func (aq *AssetQuotation) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// AssetQuotationResult is the result of quoting a single asset in a batch.
+// If the asset could not be quoted, Err is set and Quotation is nil.
+// Volume is nil if the 24h volume is not available.
+type AssetQuotationResult struct {
+	Asset     dia.Asset
+	Quotation *AssetQuotation
+	Volume    *float64
+	Err       error
+}
+
+// AssetQuotationBatchItem is the quotation of an asset in a batch request. DataAgeSeconds is the age of
+// the quotation relative to the requested time. If the asset cannot be quoted, only Error is set.
+type AssetQuotationBatchItem struct {
+	Symbol             string
+	Name               string
+	Address            string
+	Blockchain         string
+	Price              float64
+	VolumeYesterdayUSD float64
+	Time               time.Time
+	DataAgeSeconds     int64
+	Source             string
+	Error              string `json:",omitempty"`
+}
+
+// HistoricalQuotation is the price of an asset at Time according to Policy. The price is computed from the
+// values of Filter at PointTimes. DataAgeSeconds is the largest distance of these points from Time.
+type HistoricalQuotation struct {
+	Symbol         string
+	Name           string
+	Address        string
+	Blockchain     string
+	Price          float64
+	Time           time.Time
+	Policy         string
+	Filter         string
+	PointTimes     []time.Time
+	DataAgeSeconds int64
+	Source         string
+}
+
 type AssetQuotationFull struct {
 	Symbol             string
 	Name               string
@@ -111,6 +154,10 @@ type AssetQuotationFull struct {
 	VolumeYesterdayUSD float64
 	Time               time.Time
 	Source             string
+	// Currency is the fiat currency of the prices if requested in another currency than USD.
+	Currency string `json:"Currency,omitempty"`
+	// FiatRate is the USD price of Currency the price is converted with.
+	FiatRate *dia.FiatRate `json:"FiatRate,omitempty"`
 }
 
 // MarshalBinary for quotations
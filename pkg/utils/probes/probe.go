@@ -4,6 +4,7 @@ import (
 	"github.com/diadata-org/diadata/pkg/http/restApi"
 	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 )
@@ -12,6 +13,7 @@ type probe func() bool
 
 var livenessProbe probe
 var readinessProbe probe
+var readinessStatus func() interface{}
 
 func Start(liveness probe, readiness probe) {
 
@@ -25,6 +27,7 @@ func Start(liveness probe, readiness probe) {
 
 	engine.GET("/ready", execReadiness)
 	engine.GET("/live", execLiveness)
+	engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	// This environment variable is either set in docker-compose or empty
 	go func() {
 		err := engine.Run(utils.Getenv("LISTEN_PORT_PROBES", ":2345"))
@@ -35,8 +38,23 @@ func Start(liveness probe, readiness probe) {
 	log.Infoln("Ready and Live probes starting")
 }
 
+// StartWithStatus starts the probes like Start. Responses of the readiness probe
+// additionally contain the result of @status, for instance the health of scraped pairs.
+func StartWithStatus(liveness probe, readiness probe, status func() interface{}) {
+	readinessStatus = status
+	Start(liveness, readiness)
+}
+
 func execReadiness(context *gin.Context) {
-	executeProbe(context, readinessProbe)
+	if readinessStatus == nil {
+		executeProbe(context, readinessProbe)
+		return
+	}
+	code, message := http.StatusOK, "success"
+	if !readinessProbe() {
+		code, message = http.StatusServiceUnavailable, "not ready"
+	}
+	context.JSON(code, gin.H{"message": message, "status": readinessStatus()})
 }
 
 func execLiveness(context *gin.Context) {